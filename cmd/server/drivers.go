@@ -0,0 +1,6 @@
+package main
+
+import (
+	_ "github.com/jackc/pgx/v5/stdlib" // Registers "pgx" for DATA_BACKEND=postgres.
+	_ "github.com/mattn/go-sqlite3"    // Registers "sqlite3" for DATA_BACKEND=sqlite; needs cgo.
+)
@@ -3,26 +3,27 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log/slog"
-	"net/http"
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
+	"github.com/codeGROOVE-dev/slacker"
 	"github.com/codeGROOVE-dev/slacker/pkg/bot"
 	"github.com/codeGROOVE-dev/slacker/pkg/config"
-	"github.com/codeGROOVE-dev/slacker/pkg/github"
-	"github.com/codeGROOVE-dev/slacker/pkg/notify"
-	"github.com/codeGROOVE-dev/slacker/pkg/slack"
-	"github.com/codeGROOVE-dev/slacker/pkg/state"
-	"github.com/gorilla/mux"
-	"golang.org/x/sync/errgroup"
 )
 
 func main() {
+	selfTest := flag.Bool("selftest", false, "check Slack, GitHub, sprinkler, storage, and config access, print a report, and exit")
+	selfTestOrg := flag.String("selftest-org", "", "org whose GitHub App installation and config the self-test checks; defaults to the first routed org")
+	flag.Parse()
+
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
@@ -43,43 +44,20 @@ func main() {
 		os.Exit(1)
 	}
 
-	// Initialize state manager with file persistence.
-	stateManager := state.New(cfg.DataDir)
-
-	// Initialize config manager for repo configs.
-	configManager := config.New(ctx)
-
-	// Initialize GitHub client.
-	githubClient, err := github.New(ctx, cfg.GitHubAppID, cfg.GitHubPrivateKey, cfg.GitHubInstallationID)
-	if err != nil {
-		slog.Error("failed to initialize GitHub client", "error", err)
+	// Apply the configured umask before any state files are created.
+	if err := applyUmask(os.Getenv("UMASK")); err != nil {
+		slog.Error("failed to apply umask", "error", err)
 		cancel()
 		os.Exit(1)
 	}
 
-	// Initialize Slack client.
-	slackClient := slack.New(cfg.SlackToken, cfg.SlackSigningSecret)
-
-	// Initialize notification manager.
-	notifier := notify.New(slackClient, stateManager)
-
-	// Initialize bot coordinator.
-	botCoordinator := bot.New(
-		ctx,
-		slackClient,
-		githubClient,
-		stateManager,
-		configManager,
-		notifier,
-		cfg.SprinklerURL,
-	)
-
-	// Setup HTTP routes.
-	router := mux.NewRouter()
-	router.HandleFunc("/health", healthHandler).Methods("GET")
-	router.HandleFunc("/slack/events", slackClient.EventsHandler).Methods("POST")
-	router.HandleFunc("/slack/interactions", slackClient.InteractionsHandler).Methods("POST")
-	router.HandleFunc("/slack/slash", slackClient.SlashCommandHandler).Methods("POST")
+	if *selfTest {
+		// Scopes are checked by the self-test, which reports them alongside everything else.
+		cfg.SkipScopeCheck = true
+		code := runSelfTest(ctx, cfg, *selfTestOrg)
+		cancel()
+		os.Exit(code)
+	}
 
 	// Determine port.
 	port := os.Getenv("PORT")
@@ -87,53 +65,42 @@ func main() {
 		port = "9119"
 	}
 
-	// Start server and bot services.
-	eg, ctx := errgroup.WithContext(ctx)
-
-	// HTTP server.
-	server := &http.Server{
-		Addr:         ":" + port,
-		Handler:      router,
-		ReadTimeout:  15 * time.Second,
-		WriteTimeout: 15 * time.Second,
-		IdleTimeout:  60 * time.Second,
+	server, err := slacker.New(ctx, cfg, slacker.WithAddr(":"+port))
+	if err != nil {
+		slog.Error("failed to initialize server", "error", err)
+		cancel()
+		os.Exit(1)
 	}
 
-	eg.Go(func() error {
-		slog.Info("starting server", "port", port)
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			return err
-		}
-		return nil
-	})
-
-	eg.Go(func() error {
-		<-ctx.Done()
-		shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
-		defer shutdownCancel()
-		if err := server.Shutdown(shutdownCtx); err != nil {
-			return fmt.Errorf("server shutdown failed: %w", err)
-		}
-		return nil
-	})
-
-	// Start bot coordinator.
-	eg.Go(func() error {
-		return botCoordinator.Run(ctx)
-	})
-
-	// Start notification scheduler.
-	eg.Go(func() error {
-		return notifier.Run(ctx)
-	})
+	// Start server and bot services.
+	if err := server.Start(ctx); err != nil {
+		slog.Error("failed to start server", "error", err)
+		cancel()
+		os.Exit(1)
+	}
 
 	// Wait for all services.
-	if err := eg.Wait(); err != nil {
+	if err := server.Wait(); err != nil {
 		slog.Error("server error", "error", err)
 	}
 	slog.Info("server stopped")
 }
 
+// runSelfTest prints a pass/fail report for the server's dependencies, returning the exit code.
+func runSelfTest(ctx context.Context, cfg *config.ServerConfig, org string) int {
+	server, err := slacker.New(ctx, cfg)
+	if err != nil {
+		fmt.Printf("FAIL startup: %v\nself-test failed\n", err)
+		return 1
+	}
+	report, passed := bot.FormatSelfTest(server.SelfTest(ctx, org))
+	fmt.Println(report)
+	if !passed {
+		return 1
+	}
+	return 0
+}
+
 func loadConfig() (*config.ServerConfig, error) {
 	// Get environment variables with defaults
 	dataDir := os.Getenv("DATA_DIR")
@@ -149,21 +116,112 @@ func loadConfig() (*config.ServerConfig, error) {
 		sprinklerURL = "wss://hook.g.robot-army.dev/ws"
 	}
 
+	var durations [8]time.Duration
+	for i, name := range []string{
+		"HTTP_TIMEOUT", "HTTP_KEEPALIVE", "HTTP_IDLE_CONN_TIMEOUT", "STATE_IDLE_EVICTION", "STATE_MAX_PR_AGE",
+		"METRICS_PUSH_INTERVAL", "EVENT_HANDOFF_WAIT", "TELEMETRY_INTERVAL",
+	} {
+		if v := os.Getenv(name); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", name, err)
+			}
+			durations[i] = d
+		}
+	}
+
 	cfg := &config.ServerConfig{
 		DataDir:              dataDir,
+		DataBackend:          os.Getenv("DATA_BACKEND"),
+		DatabaseURL:          os.Getenv("DATABASE_URL"),
+		DatabaseDriver:       os.Getenv("DATABASE_DRIVER"),
 		SlackToken:           os.Getenv("SLACK_BOT_TOKEN"),
 		SlackSigningSecret:   os.Getenv("SLACK_SIGNING_SECRET"),
 		GitHubAppID:          os.Getenv("GITHUB_APP_ID"),
 		GitHubPrivateKey:     os.Getenv("GITHUB_PRIVATE_KEY"),
 		GitHubInstallationID: os.Getenv("GITHUB_INSTALLATION_ID"),
 		SprinklerURL:         sprinklerURL,
+		RoutingFile:          os.Getenv("ROUTING_CONFIG"),
+		AdminToken:           os.Getenv("ADMIN_TOKEN"),
+		APIToken:             os.Getenv("API_TOKEN"),
+		EventSinkURL:         os.Getenv("EVENT_SINK_URL"),
+		EventSinkSecret:      os.Getenv("EVENT_SINK_SECRET"),
+		MetricsPushURL:       os.Getenv("METRICS_PUSH_URL"),
+		TelemetryURL:         os.Getenv("TELEMETRY_URL"),
+		PluginWebhookURL:     os.Getenv("PLUGIN_WEBHOOK_URL"),
+		TurnURL:              os.Getenv("TURN_URL"),
+		TurnToken:            os.Getenv("TURN_TOKEN"),
+		HTTPProxy:            os.Getenv("OUTBOUND_PROXY"),
+		TLSCertFile:          os.Getenv("TLS_CERT_FILE"),
+		TLSKeyFile:           os.Getenv("TLS_KEY_FILE"),
+		TLSClientCAFile:      os.Getenv("TLS_CLIENT_CA_FILE"),
+		IPAllowlist:          os.Getenv("IP_ALLOWLIST") == "true",
+		TrustProxyHeaders:    os.Getenv("TRUST_PROXY_HEADERS") == "true",
+		AllowSharedDataDir:   os.Getenv("ALLOW_SHARED_DATA_DIR") == "true",
+		SkipScopeCheck:       os.Getenv("SKIP_SCOPE_CHECK") == "true",
+		EventHandoff:         os.Getenv("EVENT_HANDOFF") == "true",
+		TenantKey:            os.Getenv("TENANT_KEY"),
+		HTTPTimeout:          durations[0],
+		HTTPKeepAlive:        durations[1],
+		HTTPIdleConnTimeout:  durations[2],
+		StateIdleEviction:    durations[3],
+		StateMaxPRAge:        durations[4],
+		MetricsPushInterval:  durations[5],
+		EventHandoffWait:     durations[6],
+		TelemetryInterval:    durations[7],
+	}
+
+	for name, target := range map[string]*int{
+		"EVENT_WORKERS":          &cfg.EventWorkers,
+		"EVENT_QUEUE_SIZE":       &cfg.EventQueueSize,
+		"EVENT_WORKERS_PER_ORG":  &cfg.EventWorkersPerOrg,
+		"ORG_API_RATE":           &cfg.OrgAPIRate,
+		"STATE_MEMORY_BUDGET_MB": &cfg.StateMemoryBudgetMB,
+		"STATE_BACKUPS":          &cfg.StateBackups,
+		"STATE_MAX_PRS":          &cfg.StateMaxPRs,
+	} {
+		if v := os.Getenv(name); v != "" {
+			n, err := strconv.Atoi(v)
+			if err != nil || n <= 0 {
+				return nil, fmt.Errorf("invalid %s: %q", name, v)
+			}
+			*target = n
+		}
 	}
 
-	// Validate required fields
-	if cfg.SlackToken == "" {
+	if ranges := os.Getenv("SLACK_IP_RANGES"); ranges != "" {
+		cfg.SlackIPRanges = strings.Split(ranges, ",")
+	}
+	if types := os.Getenv("EVENT_TYPES"); types != "" {
+		cfg.EventTypes = strings.Split(types, ",")
+	}
+	if admins := os.Getenv("SLACK_ADMINS"); admins != "" {
+		cfg.SlackAdmins = strings.Split(admins, ",")
+	}
+	if commands := os.Getenv("SLASH_COMMANDS"); commands != "" {
+		cfg.SlashCommands = strings.Split(commands, ",")
+		for _, command := range cfg.SlashCommands {
+			if err := config.ValidateCommand(command); err != nil {
+				return nil, fmt.Errorf("invalid SLASH_COMMANDS: %w", err)
+			}
+		}
+	}
+
+	switch cfg.DataBackend {
+	case "", config.DataBackendFile, config.DataBackendSQLite:
+	case config.DataBackendPostgres:
+		if cfg.DatabaseURL == "" {
+			return nil, fmt.Errorf("DATA_BACKEND=postgres requires DATABASE_URL")
+		}
+	default:
+		return nil, fmt.Errorf("invalid DATA_BACKEND: %q", cfg.DataBackend)
+	}
+
+	// Validate required fields; routed workspaces carry their own Slack credentials.
+	if cfg.SlackToken == "" && cfg.RoutingFile == "" && cfg.TenantKey == "" {
 		return nil, fmt.Errorf("missing required environment variable: SLACK_BOT_TOKEN")
 	}
-	if cfg.SlackSigningSecret == "" {
+	if cfg.SlackSigningSecret == "" && cfg.RoutingFile == "" && cfg.TenantKey == "" {
 		return nil, fmt.Errorf("missing required environment variable: SLACK_SIGNING_SECRET")
 	}
 	if cfg.GitHubAppID == "" {
@@ -176,9 +234,15 @@ func loadConfig() (*config.ServerConfig, error) {
 	return cfg, nil
 }
 
-func healthHandler(w http.ResponseWriter, _ *http.Request) {
-	w.WriteHeader(http.StatusOK)
-	if _, err := w.Write([]byte("OK")); err != nil {
-		slog.Error("failed to write health response", "error", err)
+// applyUmask sets the process umask from an octal string such as "077". An empty
+// string keeps the inherited umask.
+func applyUmask(value string) error {
+	if value == "" {
+		return nil
+	}
+	mask, err := strconv.ParseUint(value, 8, 32)
+	if err != nil || mask > 0o777 {
+		return fmt.Errorf("invalid UMASK: %q", value)
 	}
+	return setUmask(int(mask))
 }
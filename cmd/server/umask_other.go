@@ -0,0 +1,10 @@
+//go:build !unix
+
+package main
+
+import "errors"
+
+// setUmask reports that umasks aren't supported on this platform.
+func setUmask(int) error {
+	return errors.New("UMASK is not supported on this platform")
+}
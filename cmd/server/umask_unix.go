@@ -0,0 +1,11 @@
+//go:build unix
+
+package main
+
+import "syscall"
+
+// setUmask sets the process umask, which applies to every file created afterwards.
+func setUmask(mask int) error {
+	syscall.Umask(mask)
+	return nil
+}
@@ -0,0 +1,105 @@
+// Package main implements slackerctl, the slacker operator tool.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+)
+
+const usage = `Usage: slackerctl <command> [flags]
+
+Commands:
+  manifest  Print a Slack app manifest for this deployment
+`
+
+func main() {
+	if len(os.Args) < 2 {
+		fmt.Fprint(os.Stderr, usage)
+		os.Exit(2)
+	}
+
+	var err error
+	switch os.Args[1] {
+	case "manifest":
+		err = manifest(os.Args[2:], os.Stdout)
+	case "help", "-h", "-help", "--help":
+		fmt.Fprint(os.Stdout, usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", os.Args[1], usage)
+		os.Exit(2)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "slackerctl:", err)
+		os.Exit(1)
+	}
+}
+
+// manifest prints the Slack app manifest for the server's base URL, slash commands,
+// and features. Defaults come from the same environment as the server.
+func manifest(args []string, out io.Writer) error {
+	fs := flag.NewFlagSet("manifest", flag.ExitOnError)
+	baseURL := fs.String("base-url", os.Getenv("BASE_URL"), "public https URL of the server (env BASE_URL)")
+	workspace := fs.String("workspace", "", "routed workspace the app is for, from ROUTING_CONFIG; empty for the default workspace")
+	commands := fs.String("commands", os.Getenv("SLASH_COMMANDS"), "comma-separated slash command names (env SLASH_COMMANDS)")
+	without := fs.String("without", "", "comma-separated optional features to leave out")
+	name := fs.String("name", "", "app display name")
+	list := fs.Bool("features", false, "list the features and the scopes and events they need, then exit")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *list {
+		for _, f := range slack.Features() {
+			optional := ""
+			if f.Optional {
+				optional = " (optional)"
+			}
+			fmt.Fprintf(out, "%s%s: %s\n  scopes: %s\n  events: %s\n",
+				f.Name, optional, f.Description, strings.Join(f.Scopes, ", "), strings.Join(f.Events, ", "))
+		}
+		return nil
+	}
+
+	opts := slack.ManifestOptions{
+		Name:      *name,
+		BaseURL:   *baseURL,
+		Workspace: *workspace,
+		Commands:  split(*commands),
+		Without:   split(*without),
+	}
+	if opts.BaseURL == "" {
+		return fmt.Errorf("missing base URL: set -base-url or BASE_URL")
+	}
+	for _, command := range opts.Commands {
+		if err := config.ValidateCommand(command); err != nil {
+			return err
+		}
+	}
+
+	m, err := slack.BuildManifest(opts)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(m)
+}
+
+// split splits a comma-separated list, dropping blanks.
+func split(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
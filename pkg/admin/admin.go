@@ -0,0 +1,88 @@
+// Package admin provides the authenticated /admin route group for operational endpoints,
+// and the bearer-token authentication other route groups share.
+package admin
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Options configures admin authentication.
+type Options struct {
+	// Token is the bearer token required on every request. The routes are disabled if empty.
+	Token string
+	// RequireClientCert additionally requires a verified TLS client certificate.
+	RequireClientCert bool
+}
+
+// NewRouter mounts an authenticated sub-router under /admin on parent.
+// Features register their endpoints on the returned router.
+func NewRouter(parent *mux.Router, opts Options) *mux.Router {
+	return Mount(parent, "/admin", opts)
+}
+
+// Mount mounts a sub-router under prefix on parent that requires opts' credentials.
+func Mount(parent *mux.Router, prefix string, opts Options) *mux.Router {
+	router := parent.PathPrefix(prefix).Subrouter()
+	realm := strings.TrimPrefix(prefix, "/")
+	router.Use(func(next http.Handler) http.Handler {
+		return authenticate(opts, realm, next)
+	})
+	return router
+}
+
+// authenticate enforces bearer authentication: 401 when credentials are missing, 403 when they are wrong.
+func authenticate(opts Options, realm string, next http.Handler) http.Handler {
+	challenge := fmt.Sprintf("Bearer realm=%q", realm)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if opts.Token == "" {
+			http.NotFound(w, r)
+			return
+		}
+
+		if opts.RequireClientCert && (r.TLS == nil || len(r.TLS.VerifiedChains) == 0) {
+			WriteError(w, http.StatusForbidden, "client certificate required")
+			return
+		}
+
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			w.Header().Set("WWW-Authenticate", challenge)
+			WriteError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok {
+			w.Header().Set("WWW-Authenticate", challenge)
+			WriteError(w, http.StatusUnauthorized, "authorization must use the Bearer scheme")
+			return
+		}
+		if subtle.ConstantTimeCompare([]byte(token), []byte(opts.Token)) != 1 {
+			slog.Warn("rejected request with invalid token", "realm", realm, "path", r.URL.Path, "remote", r.RemoteAddr)
+			WriteError(w, http.StatusForbidden, "invalid token")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// WriteJSON writes v as a JSON response.
+func WriteJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Error("failed to encode admin response", "error", err)
+	}
+}
+
+// WriteError writes a JSON error response.
+func WriteError(w http.ResponseWriter, status int, message string) {
+	WriteJSON(w, status, map[string]string{"error": message})
+}
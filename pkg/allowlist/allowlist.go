@@ -0,0 +1,192 @@
+// Package allowlist restricts HTTP endpoints to periodically refreshed IP ranges.
+package allowlist
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+// Fetcher returns the current list of allowed CIDR ranges.
+type Fetcher func(ctx context.Context) ([]string, error)
+
+// loadRetryInterval is how often an allowlist that has never loaded is retried, since
+// it rejects every request until then.
+const loadRetryInterval = 30 * time.Second
+
+// Allowlist admits requests only from a set of IP ranges.
+// Until the first successful refresh every request is rejected, so the endpoints stay
+// restricted when the ranges can't be fetched.
+type Allowlist struct {
+	fetch             Fetcher
+	name              string
+	nets              []*net.IPNet
+	mu                sync.RWMutex
+	loaded            bool
+	trustProxyHeaders bool
+}
+
+// New creates an allowlist named for logs and metrics. If trustProxyHeaders is set,
+// the client IP is taken from the last X-Forwarded-For entry, as needed behind one
+// load balancer.
+func New(name string, fetch Fetcher, trustProxyHeaders bool) *Allowlist {
+	return &Allowlist{
+		name:              name,
+		fetch:             fetch,
+		trustProxyHeaders: trustProxyHeaders,
+	}
+}
+
+// Refresh fetches the allowed ranges, keeping the previous ranges on failure.
+func (a *Allowlist) Refresh(ctx context.Context) error {
+	cidrs, err := a.fetch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to fetch %s ranges: %w", a.name, err)
+	}
+
+	nets := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr))
+		if err != nil {
+			slog.Warn("ignoring invalid CIDR", "allowlist", a.name, "cidr", cidr, "error", err)
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+	if len(nets) == 0 {
+		return fmt.Errorf("no valid %s ranges", a.name)
+	}
+
+	a.mu.Lock()
+	a.nets = nets
+	a.loaded = true
+	a.mu.Unlock()
+
+	slog.Info("refreshed IP allowlist", "allowlist", a.name, "ranges", len(nets))
+	return nil
+}
+
+// Run refreshes the allowlist at the given interval until the context is cancelled,
+// retrying sooner until it first loads.
+func (a *Allowlist) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		wait := interval
+		if err := a.Refresh(ctx); err != nil {
+			slog.Warn("failed to refresh IP allowlist", "allowlist", a.name, "error", err)
+			if !a.isLoaded() {
+				wait = min(interval, loadRetryInterval)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// isLoaded reports whether the allowlist has ever refreshed successfully.
+func (a *Allowlist) isLoaded() bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.loaded
+}
+
+// Allowed reports whether ip is within the allowed ranges.
+func (a *Allowlist) Allowed(ip net.IP) bool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if !a.loaded {
+		return false
+	}
+	for _, ipNet := range a.nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Middleware rejects requests from outside the allowed ranges with 403 Forbidden.
+func (a *Allowlist) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ip := a.clientIP(r)
+		if ip == nil || !a.Allowed(ip) {
+			slog.Warn("rejected request from disallowed IP", "allowlist", a.name, "ip", ip, "path", r.URL.Path)
+			metrics.IncCounter("slacker_allowlist_rejected_total", "allowlist", a.name)
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// clientIP returns the IP the request originated from. Behind a load balancer, that's
+// the last X-Forwarded-For entry, which the load balancer appended; the client can
+// write anything in the entries before it.
+func (a *Allowlist) clientIP(r *http.Request) net.IP {
+	if a.trustProxyHeaders {
+		if values := r.Header.Values("X-Forwarded-For"); len(values) > 0 {
+			entries := strings.Split(values[len(values)-1], ",")
+			return net.ParseIP(strings.TrimSpace(entries[len(entries)-1]))
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return net.ParseIP(host)
+}
+
+// Static returns a fetcher for a fixed list of ranges.
+func Static(cidrs []string) Fetcher {
+	return func(context.Context) ([]string, error) {
+		return cidrs, nil
+	}
+}
+
+// GitHubHooks returns a fetcher for the webhook delivery ranges published by GitHub's meta API.
+func GitHubHooks(client *http.Client) Fetcher {
+	return func(ctx context.Context) ([]string, error) {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://api.github.com/meta", http.NoBody)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/vnd.github+json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		defer func() {
+			if err := resp.Body.Close(); err != nil {
+				slog.Debug("failed to close response body", "error", err)
+			}
+		}()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+		}
+
+		var meta struct {
+			Hooks []string `json:"hooks"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&meta); err != nil {
+			return nil, fmt.Errorf("failed to decode meta response: %w", err)
+		}
+		if len(meta.Hooks) == 0 {
+			return nil, errors.New("meta response has no hook ranges")
+		}
+		return meta.Hooks, nil
+	}
+}
@@ -0,0 +1,97 @@
+package bot
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/admin"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/gorilla/mux"
+)
+
+// dashboardPR is a PR as the web dashboard receives it.
+type dashboardPR struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Owner     string    `json:"owner"`
+	Repo      string    `json:"repo"`
+	Title     string    `json:"title"`
+	Author    string    `json:"author"`
+	State     string    `json:"state"`
+	Label     string    `json:"state_label"`
+	URL       string    `json:"url"`
+	Activity  string    `json:"activity,omitempty"` // Age and time-in-state, as shown in Slack.
+	BlockedOn []string  `json:"blocked_on"`
+	Number    int       `json:"number"`
+}
+
+// dashboardResponse is a user's PRs, grouped into the sections of the Slack dashboard.
+type dashboardResponse struct {
+	User            string        `json:"user"`
+	BlockedOnYou    []dashboardPR `json:"blocked_on_you"`
+	WaitingOnOthers []dashboardPR `json:"waiting_on_others"`
+	Other           []dashboardPR `json:"other"`
+}
+
+// UserPRsHandler serves the PRs on a Slack user's dashboard as JSON, from the
+// workspace named by the workspace query parameter. It may be left out when only
+// one workspace is routed.
+func (c *Coordinator) UserPRsHandler(w http.ResponseWriter, r *http.Request) {
+	userID := mux.Vars(r)["slackID"]
+	workspaceID, problem := c.requestWorkspace(r.URL.Query().Get("workspace"))
+	if problem != "" {
+		admin.WriteError(w, http.StatusBadRequest, problem)
+		return
+	}
+	prs := c.stateManager.GetUserPRs(workspaceID, userID)
+
+	now := time.Now()
+	sections := slack.GroupDashboard(prs)
+	admin.WriteJSON(w, http.StatusOK, dashboardResponse{
+		User:            userID,
+		BlockedOnYou:    dashboardPRs(sections.BlockedOnYou, now),
+		WaitingOnOthers: dashboardPRs(sections.WaitingOnOthers, now),
+		Other:           dashboardPRs(sections.Other, now),
+	})
+}
+
+// requestWorkspace returns the routed workspace a request names, or the only routed
+// workspace if it names none, or explains why there's no such workspace.
+func (c *Coordinator) requestWorkspace(name string) (string, string) {
+	ids := c.workspaceIDs()
+	if name == "" {
+		if len(ids) != 1 {
+			return "", "workspace is required when several workspaces are routed"
+		}
+		return ids[0], ""
+	}
+	if !slices.Contains(ids, name) {
+		return "", "workspace " + name + " is not routed"
+	}
+	return name, ""
+}
+
+// dashboardPRs converts PRs for the dashboard response. The result is never nil, so
+// empty sections encode as [].
+func dashboardPRs(prs []*state.PRState, now time.Time) []dashboardPR {
+	out := make([]dashboardPR, 0, len(prs))
+	for _, pr := range prs {
+		out = append(out, dashboardPR{
+			CreatedAt: pr.CreatedAt,
+			UpdatedAt: pr.UpdatedAt,
+			Owner:     pr.Owner,
+			Repo:      pr.Repo,
+			Title:     pr.Title,
+			Author:    pr.Author,
+			State:     pr.State,
+			Label:     slack.StateLabel(pr.State),
+			URL:       githubPRURL(pr),
+			Activity:  slack.FormatActivity(pr, now, slack.DefaultAgeThresholds),
+			BlockedOn: pr.BlockedOn,
+			Number:    pr.Number,
+		})
+	}
+	return out
+}
@@ -0,0 +1,111 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// maxAway bounds how far ahead a vacation may start and how long it may last.
+const maxAway = 180 * 24 * time.Hour
+
+// awayUsage explains the away command.
+const awayUsage = "Usage: `away 2024-07-01..2024-07-14`, `away 2024-07-01` for one day, `away off`, or `away` to see your vacation"
+
+// SetAway sets, clears, or describes a user's vacation. The dates are whole days in
+// the user's timezone. While a user is away they aren't notified, PR threads that
+// start waiting on them say so, and their review claims can be taken over at once.
+func (c *Coordinator) SetAway(ctx context.Context, workspaceID, userID string, args []string) string {
+	prefs := c.stateManager.GetUserPreferences(workspaceID, userID)
+	now := c.clock.Now()
+
+	switch {
+	case len(args) == 0:
+		switch {
+		case prefs.AwayUntil.IsZero() || !now.Before(prefs.AwayUntil):
+			return "You're not away. " + awayUsage
+		case prefs.Away(now):
+			return fmt.Sprintf("🌴 You're away until %s.", slack.FormatTime(prefs.AwayUntil, slack.DateTime))
+		default:
+			return fmt.Sprintf("🌴 You'll be away from %s until %s.",
+				slack.FormatTime(prefs.AwayFrom, slack.DateTime), slack.FormatTime(prefs.AwayUntil, slack.DateTime))
+		}
+	case len(args) != 1:
+		return awayUsage
+	case strings.EqualFold(args[0], "off"):
+		if prefs.AwayUntil.IsZero() {
+			return "You weren't away."
+		}
+		prefs.AwayFrom, prefs.AwayUntil = time.Time{}, time.Time{}
+		c.stateManager.SetUserPreferences(workspaceID, userID, prefs)
+		slog.Info("user back from vacation", "workspace", workspaceID, "user", userID)
+		return "👋 Welcome back! Your notifications are on again."
+	}
+
+	from, until, ok := parseAwayRange(args[0], c.userLocation(ctx, workspaceID, userID))
+	switch {
+	case !ok:
+		return awayUsage
+	case !until.After(now):
+		return "Those days have already passed."
+	case from.Sub(now) > maxAway || until.Sub(from) > maxAway:
+		return "Vacations can start up to 180 days ahead and last up to 180 days."
+	}
+
+	prefs.AwayFrom, prefs.AwayUntil = from, until
+	c.stateManager.SetUserPreferences(workspaceID, userID, prefs)
+	slog.Info("user set vacation", "workspace", workspaceID, "user", userID, "from", from, "until", until)
+
+	reply := fmt.Sprintf("🌴 You're away from %s until %s. I won't notify you, and PR threads waiting on you will say you're away.",
+		slack.FormatTime(from, slack.DateTime), slack.FormatTime(until, slack.DateTime))
+	if prefs.Away(now) && prefs.GitHubLogin != "" {
+		c.noteAwayOnBlockedPRs(ctx, workspaceID, userID, prefs.GitHubLogin)
+	}
+	return reply
+}
+
+// noteAwayOnBlockedPRs tells the threads of the open PRs already waiting on a user
+// who just went away, in the background, since it outlasts a slash command's deadline.
+func (c *Coordinator) noteAwayOnBlockedPRs(ctx context.Context, workspaceID, userID, login string) {
+	var blocked []*state.PRState
+	for _, pr := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+		if isOpenState(pr.State) && slices.ContainsFunc(pr.BlockedOn, func(l string) bool { return strings.EqualFold(l, login) }) {
+			blocked = append(blocked, pr)
+		}
+	}
+	if len(blocked) == 0 {
+		return
+	}
+
+	go func(ctx context.Context) {
+		for _, pr := range blocked {
+			if err := c.notifier.Deliver(ctx, workspaceID, userID, pr); err != nil {
+				slog.Warn("failed to note away user", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+			}
+		}
+	}(context.WithoutCancel(ctx))
+}
+
+// parseAwayRange parses a day or a range of days, such as 2024-07-01..2024-07-14,
+// into the start of the first day and the end of the last in loc.
+func parseAwayRange(s string, loc *time.Location) (from, until time.Time, ok bool) {
+	first, last, isRange := strings.Cut(s, "..")
+	if !isRange {
+		last = first
+	}
+	from, err := time.ParseInLocation(time.DateOnly, first, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err := time.ParseInLocation(time.DateOnly, last, loc)
+	if err != nil || end.Before(from) {
+		return time.Time{}, time.Time{}, false
+	}
+	return from, end.AddDate(0, 0, 1), true
+}
@@ -0,0 +1,56 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// backportPattern matches the reference backport and cherry-pick tools leave to the
+// original PR, such as "Backport of #123", "Backport 1a2b3c from #123", or
+// "This is an automated cherry-pick of #123".
+var backportPattern = regexp.MustCompile(`(?i)\b(?:backport|cherry[- ]?pick)(?:ed)?(?:\s+\S+)?\s+(?:of|from)\s+#(\d+)`)
+
+// backportOf returns the number of the PR that pr backports, or 0.
+func backportOf(pr prPayload) int {
+	for _, text := range []string{pr.Body, pr.Title} {
+		if match := backportPattern.FindStringSubmatch(text); match != nil {
+			if number, err := strconv.Atoi(match[1]); err == nil && number != pr.Number {
+				return number
+			}
+		}
+	}
+	return 0
+}
+
+// wantsBackport reports whether any of a PR's labels requests a backport.
+func (c *Coordinator) wantsBackport(owner string, labels []string) bool {
+	prefix := strings.ToLower(c.configManager.GetBackportLabel(owner))
+	for _, label := range labels {
+		if strings.HasPrefix(strings.ToLower(label), prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportBackport posts a backport PR's state to the thread of the PR it backports,
+// so release managers can follow every backport from the original thread.
+func (c *Coordinator) reportBackport(ctx context.Context, workspaceID string, backport *state.PRState) {
+	original, exists := c.stateManager.GetPRState(workspaceID, backport.Owner, backport.Repo, backport.BackportOf)
+	if !exists || !original.Backport || original.ThreadTS == "" {
+		return
+	}
+
+	message := fmt.Sprintf("🍒 Backport to `%s` <%s|#%d>: %s %s",
+		backport.BaseRef, githubPRURL(backport), backport.Number, slack.StateEmoji(backport.State), slack.StateLabel(backport.State))
+	if err := c.notifier.SendThreadUpdate(ctx, workspaceID, original, message); err != nil {
+		slog.Warn("failed to post backport status", "original", original.Number, "backport", backport.Number, "error", err)
+	}
+}
@@ -7,31 +7,70 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
-	"net/http"
+	"slices"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/codeGROOVE-dev/retry"
+	"github.com/codeGROOVE-dev/slacker/pkg/clock"
+	"github.com/codeGROOVE-dev/slacker/pkg/command"
 	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/events"
 	"github.com/codeGROOVE-dev/slacker/pkg/github"
+	"github.com/codeGROOVE-dev/slacker/pkg/hooks"
+	"github.com/codeGROOVE-dev/slacker/pkg/httpclient"
+	"github.com/codeGROOVE-dev/slacker/pkg/identity"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
 	"github.com/codeGROOVE-dev/slacker/pkg/notify"
 	"github.com/codeGROOVE-dev/slacker/pkg/slack"
 	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/codeGROOVE-dev/slacker/pkg/turn"
 	"github.com/gorilla/websocket"
 )
 
 // Coordinator coordinates between GitHub, Slack, and notifications.
 type Coordinator struct {
 	slack         *slack.Client
+	workspaces    map[string]*slack.Client
+	routing       *config.Routing
 	github        *github.Client
+	orgGitHub     map[string]*github.Client // Orgs served by their own GitHub App.
 	stateManager  *state.Manager
 	configManager *config.Manager
 	notifier      *notify.Manager
 	sprinklerURL  string
+	dialer        *websocket.Dialer
 	wsConn        *websocket.Conn
+	wsMu          sync.Mutex // Guards wsConn against a concurrent handoff.
+	handlers      map[string]EventHandler
+	accepted      map[string]bool
+	middleware    []Middleware
+	mentionCmds   *command.Registry[slack.Mention, string] // Commands given by mentioning the bot.
+	threadCmds    *command.Registry[threadMention, string] // Commands given in PR threads.
+	deliveries    *deliveryTracker
+	failures      *failureTracker
+	members       *memberCache
+	journal       *state.Journal
+	bus           *events.Bus
+	hooks         *hooks.Registry
+	identities    *identity.Resolver
+	turn          *turn.Client
+	topics        *topicTracker
+	queue         *fairQueue
+	orgLimits     *orgLimiters
+	workers       int
+	clock         clock.Clock
+	handoffDir    string        // Where handoffs between instances are left; empty disables them.
+	handoffWait   time.Duration // How long to wait at startup for a previous instance's handoff.
+	draining      atomic.Bool   // Set once the event stream is handed off.
 }
 
+// maxMessageSize is the largest sprinkler message accepted. GitHub caps webhook
+// payloads at 25MB, and sprinkler messages are smaller still.
+const maxMessageSize = 25 << 20
+
 // New creates a new bot coordinator.
 func New(
 	ctx context.Context,
@@ -41,6 +80,7 @@ func New(
 	configManager *config.Manager,
 	notifier *notify.Manager,
 	sprinklerURL string,
+	dialer *websocket.Dialer,
 ) *Coordinator {
 	c := &Coordinator{
 		slack:         slackClient,
@@ -49,18 +89,139 @@ func New(
 		configManager: configManager,
 		notifier:      notifier,
 		sprinklerURL:  sprinklerURL,
+		dialer:        dialer,
+		handlers:      make(map[string]EventHandler),
+		deliveries:    newDeliveryTracker(),
+		failures:      newFailureTracker(),
+		members:       newMemberCache(),
+		topics:        newTopicTracker(),
+		queue:         newFairQueue(defaultQueueSize, defaultWorkers/2),
+		orgLimits:     newOrgLimiters(defaultOrgAPIRate, defaultOrgAPIBurst),
+		workers:       defaultWorkers,
+		clock:         clock.Real,
 	}
 
+	metrics.SetBuckets("slacker_pr_state_duration_seconds", stateDurationBuckets)
+	c.Use(withRecovery, withLogging, withMetrics, c.withOrgRateLimit)
+	c.registerDefaultHandlers()
+	c.mentionCmds = c.newMentionCommands()
+	c.threadCmds = c.newThreadCommands()
+
 	// Set GitHub client in config manager.
 	configManager.SetGitHubClient(githubClient.GetClient())
 
 	return c
 }
 
+// SetClock sets the clock that schedules digests and times review claims, reminders,
+// and how long PRs spend in each state.
+func (c *Coordinator) SetClock(clk clock.Clock) {
+	c.clock = clk
+}
+
+// SetJournal sets the write-ahead journal used to replay events interrupted by a crash.
+func (c *Coordinator) SetJournal(journal *state.Journal) {
+	c.journal = journal
+}
+
+// SetBus sets the bus that PR state changes and newly blocked users are published to.
+func (c *Coordinator) SetBus(b *events.Bus) {
+	c.bus = b
+}
+
+// SetIdentities sets the resolver that finds the Slack users of GitHub logins, and
+// has it look them up with the org's GitHub client and the workspace's Slack client.
+func (c *Coordinator) SetIdentities(r *identity.Resolver) {
+	c.identities = r
+	r.SetLookups(
+		func(org string) identity.GitHubUsers { return c.githubFor(org) },
+		func(workspaceID string) identity.SlackUsers { return c.slackFor(workspaceID) })
+}
+
+// slackUser returns the Slack user a GitHub login in an org belongs to.
+func (c *Coordinator) slackUser(ctx context.Context, workspaceID, org, login string) (string, bool) {
+	if c.identities == nil {
+		return c.stateManager.FindUserByGitHubLogin(workspaceID, login)
+	}
+	return c.identities.Resolve(ctx, workspaceID, org, login)
+}
+
+// verifiedSlackUser returns the Slack user a GitHub login in an org belongs to, going
+// only by the org's slack.yaml and email, never by a login a user linked themselves.
+func (c *Coordinator) verifiedSlackUser(ctx context.Context, workspaceID, org, login string) (string, bool) {
+	if c.identities == nil {
+		mapped := c.configManager.SlackUserFor(org, login)
+		return mapped, mapped != "" && !strings.Contains(mapped, "@")
+	}
+	return c.identities.ResolveVerified(ctx, workspaceID, org, login)
+}
+
+// SetHooks sets the hooks run when PRs are opened or change state.
+func (c *Coordinator) SetHooks(r *hooks.Registry) {
+	c.hooks = r
+}
+
+// stateDurationBuckets are histogram bounds in seconds for time spent in a PR state,
+// from a minute to two weeks.
+var stateDurationBuckets = []float64{60, 300, 900, 3600, 4 * 3600, 8 * 3600, 24 * 3600, 2 * 24 * 3600, 4 * 24 * 3600, 7 * 24 * 3600, 14 * 24 * 3600}
+
+// recordStateChange adds the PR's current state to its history and records how
+// long it spent in the state it left, for time-in-state SLOs. Time in the unknown
+// state is left out, since it says nothing about the PR.
+func (c *Coordinator) recordStateChange(pr *state.PRState) {
+	left, spent, changed := pr.RecordStateChange(c.clock.Now())
+	if changed && left != "" && left != state.Unknown {
+		metrics.Observe("slacker_pr_state_duration_seconds", spent.Seconds(), "state", left)
+	}
+}
+
+// emitStateChange schedules a channel topic update, publishes the users the PR
+// newly waits on, refreshes the Home tabs that list it, and on a move from previous
+// to its current state runs state change hooks and publishes the change.
+func (c *Coordinator) emitStateChange(ctx context.Context, workspaceID string, pr *state.PRState, previous string, previouslyBlocked []string) {
+	c.markTopic(workspaceID, pr)
+	now := c.clock.Now()
+	for _, user := range pr.BlockedOn {
+		if !slices.Contains(previouslyBlocked, user) {
+			c.bus.Publish(ctx, events.UserBlocked{
+				Time:      now,
+				Workspace: workspaceID,
+				Owner:     pr.Owner,
+				Repo:      pr.Repo,
+				Number:    pr.Number,
+				User:      user,
+			})
+		}
+	}
+	if pr.State != previous || !slices.Equal(pr.BlockedOn, previouslyBlocked) {
+		c.refreshHomes(workspaceID, pr)
+	}
+	if pr.State == previous {
+		return
+	}
+	if pr.State == "check" {
+		c.announceFreeze(ctx, workspaceID, pr)
+	}
+	c.hooks.RunStateChange(ctx, pr, previous)
+	c.bus.Publish(ctx, events.PRStateChanged{
+		Time:      now,
+		Workspace: workspaceID,
+		PR:        pr.Clone(),
+		Previous:  previous,
+	})
+}
+
 // Run starts the bot coordinator.
 func (c *Coordinator) Run(ctx context.Context) error {
 	slog.Info("starting bot coordinator")
 
+	c.replayJournal(ctx)
+	c.startWorkers(ctx)
+	if c.handoffDir != "" {
+		c.importHandoff(ctx)
+		defer c.handOffOnExit()
+	}
+
 	var reconnectMu sync.Mutex
 	reconnectCount := 0
 
@@ -76,8 +237,14 @@ func (c *Coordinator) Run(ctx context.Context) error {
 			return ctx.Err()
 		default:
 		}
+		// Once handed off, the stream belongs to the next instance.
+		if c.draining.Load() {
+			<-ctx.Done()
+			return ctx.Err()
+		}
 
-		// Connect with exponential backoff
+		// Connect with exponential backoff, failing fast while sprinkler's breaker is open.
+		breaker := httpclient.BreakerFor("sprinkler")
 		err := retry.Do(
 			func() error {
 				reconnectMu.Lock()
@@ -89,7 +256,12 @@ func (c *Coordinator) Run(ctx context.Context) error {
 					}
 				}
 
-				if err := c.connectToSprinkler(ctx); err != nil {
+				if err := breaker.Allow(); err != nil {
+					return err
+				}
+				err := c.connectToSprinkler(ctx)
+				breaker.Record(err != nil)
+				if err != nil {
 					slog.Warn("failed to connect to sprinkler, retrying", "error", err)
 					return err
 				}
@@ -119,30 +291,49 @@ func (c *Coordinator) Run(ctx context.Context) error {
 			default:
 			}
 
-			var msg SprinklerMessage
 			if err := c.wsConn.SetReadDeadline(time.Now().Add(60 * time.Second)); err != nil {
 				slog.Debug("failed to set read deadline", "error", err)
 			}
 
-			if err := c.wsConn.ReadJSON(&msg); err != nil {
+			_, data, err := c.wsConn.ReadMessage()
+			if err != nil {
 				if websocket.IsCloseError(err, websocket.CloseNormalClosure) {
 					slog.Info("WebSocket closed normally")
 					return nil
 				}
-				if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
+				switch {
+				case errors.Is(err, websocket.ErrReadLimit):
+					// The connection can't be read past an oversized message, so drop it and reconnect.
+					metrics.IncCounter("slacker_websocket_messages_dropped_total", "reason", "oversized")
+					slog.Warn("dropped oversized WebSocket message, will reconnect", "limit", maxMessageSize)
+				case websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure):
 					slog.Warn("WebSocket unexpected close, will reconnect", "error", err)
-				} else {
+				default:
 					slog.Warn("failed to read WebSocket message, will reconnect", "error", err)
 				}
 				break // Break inner loop to reconnect
 			}
 
-			// Process the event asynchronously
-			go func(msg SprinklerMessage) {
-				if err := c.processEventSafely(ctx, msg); err != nil {
-					slog.Error("error processing event", "error", err, "event", msg.Event)
-				}
-			}(msg)
+			var msg SprinklerMessage
+			if err := json.Unmarshal(data, &msg); err != nil {
+				metrics.IncCounter("slacker_websocket_messages_dropped_total", "reason", "malformed")
+				slog.Warn("dropped malformed WebSocket message", "bytes", len(data), "error", err)
+				continue
+			}
+
+			msg.ReceivedAt = time.Now()
+
+			if !c.acceptsEvent(msg.Event) {
+				metrics.IncCounter("slacker_events_filtered_total", "event", msg.Event)
+				continue
+			}
+			if c.ignoresEvent(msg.Event) {
+				metrics.IncCounter("slacker_events_ignored_total", "event", msg.Event)
+				continue
+			}
+
+			// Hand the event to the worker pool.
+			c.enqueue(ctx, msg)
 		}
 	}
 }
@@ -151,12 +342,7 @@ func (c *Coordinator) Run(ctx context.Context) error {
 func (c *Coordinator) connectToSprinkler(ctx context.Context) error {
 	slog.Info("connecting to sprinkler", "url", c.sprinklerURL)
 
-	dialer := websocket.Dialer{
-		HandshakeTimeout: 10 * time.Second,
-		Proxy:            http.ProxyFromEnvironment,
-	}
-
-	conn, resp, err := dialer.DialContext(ctx, c.sprinklerURL, nil)
+	conn, resp, err := c.dialer.DialContext(ctx, c.sprinklerURL, nil)
 	if err != nil {
 		if resp != nil {
 			slog.Error("WebSocket connection failed", "status", resp.StatusCode)
@@ -173,6 +359,7 @@ func (c *Coordinator) connectToSprinkler(ctx context.Context) error {
 	}
 
 	// Set connection parameters
+	conn.SetReadLimit(maxMessageSize)
 	conn.SetPingHandler(func(message string) error {
 		slog.Debug("received ping from sprinkler")
 		return conn.WriteControl(websocket.PongMessage, []byte(message), time.Now().Add(10*time.Second))
@@ -183,7 +370,9 @@ func (c *Coordinator) connectToSprinkler(ctx context.Context) error {
 		return nil
 	})
 
+	c.wsMu.Lock()
 	c.wsConn = conn
+	c.wsMu.Unlock()
 	slog.Info("successfully connected to sprinkler")
 
 	// Start ping ticker to keep connection alive
@@ -207,30 +396,55 @@ func (c *Coordinator) connectToSprinkler(ctx context.Context) error {
 	return nil
 }
 
-// SprinklerMessage represents a message from sprinkler.
+// SprinklerMessage represents a message from sprinkler, along with delivery metadata
+// recorded as it moves through the processing pipeline.
 type SprinklerMessage struct {
-	Event   string          `json:"event"`
-	Repo    string          `json:"repo"`
-	Payload json.RawMessage `json:"payload"`
+	Timestamp  time.Time       `json:"timestamp,omitempty"`   // When sprinkler sent the event, if provided.
+	ReceivedAt time.Time       `json:"received_at,omitempty"` // When the bot read the event.
+	Event      string          `json:"event"`
+	Repo       string          `json:"repo"`
+	DeliveryID string          `json:"delivery_id,omitempty"`
+	Payload    json.RawMessage `json:"payload"`
+	Attempts   int             `json:"attempts,omitempty"`
 }
 
-// processEventSafely processes a GitHub webhook event with error recovery.
-func (c *Coordinator) processEventSafely(ctx context.Context, msg SprinklerMessage) (err error) {
-	// Recover from panics
-	defer func() {
-		if r := recover(); r != nil {
-			err = fmt.Errorf("panic recovered in processEvent: %v", r)
-			slog.Error("panic recovered in processEvent", "panic", r)
+// maxEventAttempts bounds how often an event is replayed, so an event that crashes
+// the process can't do so forever.
+const maxEventAttempts = 3
+
+// replayJournal reprocesses events that were in flight when the process last stopped.
+func (c *Coordinator) replayJournal(ctx context.Context) {
+	if c.journal == nil {
+		return
+	}
+
+	for _, entry := range c.journal.Pending() {
+		var msg SprinklerMessage
+		if err := json.Unmarshal(entry.Record, &msg); err != nil {
+			slog.Error("dropping unreadable journaled event", "delivery_id", entry.ID, "error", err)
+			if err := c.journal.Complete(entry.ID); err != nil {
+				slog.Warn("failed to mark journaled event complete", "delivery_id", entry.ID, "error", err)
+			}
+			continue
+		}
+		if msg.Attempts >= maxEventAttempts {
+			slog.Error("dropping journaled event after repeated attempts",
+				"event", msg.Event, "repo", msg.Repo, "delivery_id", entry.ID, "attempts", msg.Attempts)
+			if err := c.journal.Complete(entry.ID); err != nil {
+				slog.Warn("failed to mark journaled event complete", "delivery_id", entry.ID, "error", err)
+			}
+			continue
 		}
-	}()
 
-	return c.processEvent(ctx, msg)
+		slog.Info("replaying interrupted event", "event", msg.Event, "repo", msg.Repo, "delivery_id", entry.ID)
+		if err := c.processEvent(ctx, msg); err != nil {
+			slog.Error("error replaying event", "error", err, "event", msg.Event)
+		}
+	}
 }
 
-// processEvent processes a GitHub webhook event.
+// processEvent parses the repo from a sprinkler message and dispatches it to the registered handler.
 func (c *Coordinator) processEvent(ctx context.Context, msg SprinklerMessage) error {
-	slog.Info("processing event", "event", msg.Event, "repo", msg.Repo)
-
 	// Parse repo owner and name.
 	parts := strings.Split(msg.Repo, "/")
 	if len(parts) != 2 {
@@ -245,256 +459,76 @@ func (c *Coordinator) processEvent(ctx context.Context, msg SprinklerMessage) er
 		return errors.New("empty owner or repo name")
 	}
 
-	// Load config for this org if not already loaded.
-	if _, exists := c.configManager.GetConfig(owner); !exists {
-		if err := c.configManager.LoadConfig(ctx, owner); err != nil {
-			slog.Warn("failed to load config for org", "org", owner, "error", err)
-		}
-	}
-
-	// Handle different event types.
-	switch msg.Event {
-	case "pull_request":
-		c.handlePullRequestEvent(ctx, owner, repo, msg.Payload)
-	case "pull_request_review":
-		c.handlePullRequestReviewEvent(ctx, owner, repo, msg.Payload)
-	case "check_run", "check_suite":
-		c.handleCheckEvent(ctx, owner, repo, msg.Payload)
-	case "push":
-		// Check if this is a push to .github repo.
-		if repo == ".github" {
-			c.handleConfigUpdate(ctx, owner)
-		}
-	default:
+	handler, ok := c.handlers[msg.Event]
+	if !ok {
 		slog.Debug("unhandled event type", "event", msg.Event)
+		metrics.IncCounter("slacker_events_unhandled_total", "event", msg.Event)
+		return nil
 	}
 
-	return nil
-}
-
-// handlePullRequestEvent handles pull request events.
-func (c *Coordinator) handlePullRequestEvent(ctx context.Context, owner, repo string, payload json.RawMessage) {
-	var event struct {
-		Action      string `json:"action"`
-		Number      int    `json:"number"`
-		PullRequest struct {
-			Number int    `json:"number"`
-			Title  string `json:"title"`
-			User   struct {
-				Login string `json:"login"`
-			} `json:"user"`
-			HTMLURL string `json:"html_url"`
-		} `json:"pull_request"`
-	}
-
-	if err := json.Unmarshal(payload, &event); err != nil {
-		slog.Warn("failed to unmarshal PR event", "error", err)
-		return
-	}
-
-	slog.Info("PR event", "owner", owner, "repo", repo, "number", event.Number, "action", event.Action)
-
-	// Get channels for this repo.
-	channels := c.configManager.GetChannelsForRepo(owner, repo)
-	if len(channels) == 0 {
-		slog.Debug("no channels configured", "owner", owner, "repo", repo)
-		return
-	}
-
-	// Get PR state.
-	prState, blockedOn, err := c.github.GetPRState(ctx, owner, repo, event.Number)
-	if err != nil {
-		slog.Warn("failed to get PR state", "error", err)
-		return
+	if msg.DeliveryID == "" {
+		msg.DeliveryID = deriveDeliveryID(msg)
 	}
-
-	// For now, use a default workspace ID.
-	// In production, this would map channels to workspaces.
-	workspaceID := "default"
-
-	// Update or create PR state.
-	pr := &state.PRState{
-		Owner:       owner,
-		Repo:        repo,
-		Number:      event.Number,
-		Title:       event.PullRequest.Title,
-		Author:      event.PullRequest.User.Login,
-		State:       prState,
-		BlockedOn:   blockedOn,
-		LastUpdated: time.Now(),
+	if msg.ReceivedAt.IsZero() {
+		msg.ReceivedAt = time.Now()
 	}
+	msg.Attempts++
 
-	// Check if we already have a thread for this PR.
-	existingPR, exists := c.stateManager.GetPRState(workspaceID, owner, repo, event.Number)
-	if exists {
-		pr.ThreadTS = existingPR.ThreadTS
-		pr.ChannelID = existingPR.ChannelID
+	if !c.deliveries.begin(msg.DeliveryID, time.Now()) {
+		slog.Info("skipping duplicate delivery", "event", msg.Event, "repo", msg.Repo, "delivery_id", msg.DeliveryID)
+		metrics.IncCounter("slacker_events_duplicate_total", "event", msg.Event)
+		return nil
 	}
 
-	// Handle based on action.
-	switch event.Action {
-	case "opened", "reopened":
-		// Create threads in configured channels.
-		for _, channel := range channels {
-			if pr.ThreadTS != "" {
-				continue
-			}
-			// Create new thread.
-			threadTS, err := c.createPRThread(ctx, channel, owner, repo, event.Number, event.PullRequest)
-			if err != nil {
-				slog.Warn("failed to create thread", "channel", channel, "error", err)
-				continue
-			}
-			pr.ThreadTS = threadTS
-			pr.ChannelID = channel
-			slog.Info("created thread", "channel", channel, "owner", owner, "repo", repo, "number", event.Number)
+	// Load config for this org if not already loaded.
+	if _, exists := c.configManager.GetConfig(owner); !exists {
+		if err := c.configManager.LoadConfig(ctx, owner); err != nil {
+			slog.Warn("failed to load config for org", "org", owner, "error", err)
 		}
+	}
 
-	case "closed":
-		// Update state in existing thread.
-		if pr.ThreadTS != "" {
-			if err := c.notifier.UpdateThreadReaction(ctx, pr.ChannelID, pr.ThreadTS, prState); err != nil {
-				slog.Warn("failed to update reaction", "error", err)
-			}
+	if c.journal != nil {
+		record, err := json.Marshal(msg)
+		if err != nil {
+			return fmt.Errorf("failed to encode event for journal: %w", err)
 		}
-
-	case "synchronize", "edited":
-		// Update state.
-		if pr.ThreadTS != "" {
-			if err := c.notifier.UpdateThreadReaction(ctx, pr.ChannelID, pr.ThreadTS, prState); err != nil {
-				slog.Warn("failed to update reaction", "error", err)
-			}
+		if err := c.journal.Begin(msg.DeliveryID, record); err != nil {
+			slog.Warn("failed to journal event, processing anyway", "delivery_id", msg.DeliveryID, "error", err)
 		}
-	default:
-		// Other PR actions are not handled
-		slog.Debug("unhandled PR action", "action", event.Action)
 	}
 
-	// Save PR state.
-	c.stateManager.SetPRState(workspaceID, pr)
-
-	// Check if we need to notify blocked users.
-	for _, userID := range blockedOn {
-		// In production, map GitHub username to Slack user ID.
-		// Then update their app home view.
-		slog.Info("PR blocked on user", "owner", owner, "repo", repo, "number", event.Number, "user", userID)
-		// Would call: c.updateUserHome(ctx, workspaceID, slackUserID)
+	ctx = notify.WithDelivery(ctx, msg.DeliveryID)
+	err := c.wrap(handler).Handle(ctx, &Event{
+		Type:       msg.Event,
+		Owner:      owner,
+		Repo:       repo,
+		Payload:    msg.Payload,
+		DeliveryID: msg.DeliveryID,
+		SentAt:     msg.Timestamp,
+		ReceivedAt: msg.ReceivedAt,
+		Attempts:   msg.Attempts,
+	})
+	if err != nil {
+		// Allow a redelivery to be processed again.
+		c.deliveries.forget(msg.DeliveryID)
 	}
+	c.completeJournal(ctx, msg.DeliveryID, err)
+	c.recordOutcome(ctx, owner, repo, msg.Event, err)
+	return err
 }
 
-// handlePullRequestReviewEvent handles PR review events.
-func (c *Coordinator) handlePullRequestReviewEvent(ctx context.Context, owner, repo string, payload json.RawMessage) {
-	var event struct {
-		Action string `json:"action"`
-		Review struct {
-			User struct {
-				Login string `json:"login"`
-			} `json:"user"`
-			State string `json:"state"`
-		} `json:"review"`
-		PullRequest struct {
-			Number int `json:"number"`
-		} `json:"pull_request"`
-	}
-
-	if err := json.Unmarshal(payload, &event); err != nil {
-		slog.Warn("failed to unmarshal review event", "error", err)
+// completeJournal marks an event's journal entry complete once its handler returned,
+// unless the handler was cut short by shutdown or a deadline, so the event is replayed
+// on the next start.
+func (c *Coordinator) completeJournal(ctx context.Context, deliveryID string, err error) {
+	if c.journal == nil {
 		return
 	}
-
-	workspaceID := "default"
-	pr, exists := c.stateManager.GetPRState(workspaceID, owner, repo, event.PullRequest.Number)
-	if !exists {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		slog.Info("leaving interrupted event for replay", "delivery_id", deliveryID, "error", err)
 		return
 	}
-
-	// Update thread with review status.
-	if pr.ThreadTS != "" && event.Action == "submitted" {
-		message := fmt.Sprintf("@%s reviewed the PR", event.Review.User.Login)
-		switch event.Review.State {
-		case "approved":
-			message += " ✅"
-		case "changes_requested":
-			message += " 🔧"
-		default:
-			// Other review states (commented, dismissed, etc.)
-			message += fmt.Sprintf(" (%s)", event.Review.State)
-		}
-		if err := c.notifier.SendThreadUpdate(ctx, pr.ChannelID, pr.ThreadTS, message); err != nil {
-			slog.Warn("failed to send thread update", "error", err)
-		}
-	}
-
-	// Update PR state.
-	prState, blockedOn, err := c.github.GetPRState(ctx, owner, repo, event.PullRequest.Number)
-	if err == nil {
-		pr.State = prState
-		pr.BlockedOn = blockedOn
-		pr.LastUpdated = time.Now()
-		c.stateManager.SetPRState(workspaceID, pr)
-
-		// Update reaction.
-		if pr.ThreadTS != "" {
-			if err := c.notifier.UpdateThreadReaction(ctx, pr.ChannelID, pr.ThreadTS, prState); err != nil {
-				slog.Warn("failed to update reaction", "error", err)
-			}
-		}
-	}
-}
-
-// handleCheckEvent handles check run/suite events.
-func (c *Coordinator) handleCheckEvent(ctx context.Context, owner, repo string, payload json.RawMessage) {
-	// Parse to get PR number.
-	// This is simplified - in production, we'd need to map commits to PRs.
-	slog.Debug("received check event", "owner", owner, "repo", repo)
-}
-
-// handleConfigUpdate handles updates to org config.
-func (c *Coordinator) handleConfigUpdate(ctx context.Context, owner string) {
-	slog.Info("reloading config", "org", owner)
-	if err := c.configManager.ReloadConfig(ctx, owner); err != nil {
-		slog.Warn("failed to reload config", "error", err)
+	if err := c.journal.Complete(deliveryID); err != nil {
+		slog.Warn("failed to mark journaled event complete", "delivery_id", deliveryID, "error", err)
 	}
 }
-
-// createPRThread creates a new thread in Slack for a PR.
-func (c *Coordinator) createPRThread(ctx context.Context, channel, owner, repo string, number int, pr struct {
-	Number int    `json:"number"`
-	Title  string `json:"title"`
-	User   struct {
-		Login string `json:"login"`
-	} `json:"user"`
-	HTMLURL string `json:"html_url"`
-},
-) (string, error) {
-	// Get prefix for this org.
-	prefix := c.configManager.GetPrefix(owner)
-
-	// Format message.
-	text := fmt.Sprintf(
-		"%s %s • <%s|%s/%s#%d> by @%s",
-		prefix,
-		pr.Title,
-		pr.HTMLURL,
-		owner,
-		repo,
-		number,
-		pr.User.Login,
-	)
-
-	// Create thread.
-	threadTS, err := c.slack.PostThread(ctx, channel, text, nil)
-	if err != nil {
-		return "", fmt.Errorf("failed to post thread: %w", err)
-	}
-
-	// Add initial reaction based on state.
-	prState, _, err := c.github.GetPRState(ctx, owner, repo, number)
-	if err == nil {
-		if err := c.slack.UpdateReactions(ctx, channel, threadTS, prState); err != nil {
-			slog.Warn("failed to add initial reaction", "error", err)
-		}
-	}
-
-	return threadTS, nil
-}
@@ -0,0 +1,14 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// handleCheckEvent handles check run/suite events.
+func (c *Coordinator) handleCheckEvent(_ context.Context, ev *Event) error {
+	// Parse to get PR number.
+	// This is simplified - in production, we'd need to map commits to PRs.
+	slog.Debug("received check event", "owner", ev.Owner, "repo", ev.Repo)
+	return nil
+}
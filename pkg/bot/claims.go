@@ -0,0 +1,145 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/identity"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// HandleAction responds to a button pressed on a PR thread, or a form opened from one.
+func (c *Coordinator) HandleAction(ctx context.Context, a slack.Action) string {
+	pr, exists := c.stateManager.FindPRByThread(a.Workspace, a.ChannelID, a.MessageTS)
+	if !exists {
+		slog.Debug("action on unknown thread", "channel", a.ChannelID, "ts", a.MessageTS, "action", a.ActionID)
+		return ""
+	}
+
+	switch a.ActionID {
+	case slack.ClaimReviewAction:
+		return c.claimReview(ctx, a.Workspace, pr, a.UserID)
+	case slack.ScheduleReviewAction:
+		today := c.clock.Now().In(c.userLocation(ctx, a.Workspace, a.UserID))
+		if err := c.slackFor(a.Workspace).OpenScheduleModal(ctx, a.TriggerID, a.ChannelID, a.MessageTS, today); err != nil {
+			slog.Warn("failed to open schedule form", "error", err)
+			return fmt.Sprintf("<@%s>, I couldn't open the scheduling form. Please try again.", a.UserID)
+		}
+		return ""
+	case slack.ScheduleReviewSubmission:
+		return c.scheduleReview(ctx, a.Workspace, pr, a.UserID, a.Values)
+	case slack.SubscribeAction, slack.UnsubscribeAction:
+		c.setSubscribed(ctx, a, pr, a.ActionID == slack.SubscribeAction)
+		return ""
+	default:
+		slog.Debug("unhandled action", "action", a.ActionID)
+		return ""
+	}
+}
+
+// claimReview records a user as the PR's active reviewer and requests their review
+// on GitHub. Other reviewers are not nudged about the PR until the claim times out.
+func (c *Coordinator) claimReview(ctx context.Context, workspaceID string, pr *state.PRState, userID string) string {
+	now := c.clock.Now()
+	// A claim by someone who has since gone away can be taken over right away.
+	timeout := time.Duration(float64(state.ClaimTimeout) * c.configManager.SeverityScale(pr.Owner, pr.Repo))
+	if pr.ClaimActive(now, timeout) && !c.stateManager.GetUserPreferences(workspaceID, pr.ClaimedBy).Away(now) {
+		if pr.ClaimedBy == userID {
+			return fmt.Sprintf("<@%s>, you're already reviewing this.", userID)
+		}
+		return fmt.Sprintf("<@%s> is already reviewing this (claimed %s ago).",
+			pr.ClaimedBy, now.Sub(pr.ClaimedAt).Round(time.Minute))
+	}
+
+	// Someone else may have claimed it since it was checked.
+	claimedBy, claimedAt := pr.ClaimedBy, pr.ClaimedAt
+	var claimed bool
+	updated, exists := c.stateManager.UpdatePRState(workspaceID, pr.Owner, pr.Repo, pr.Number, func(stored *state.PRState) {
+		if stored.ClaimedBy == claimedBy && stored.ClaimedAt.Equal(claimedAt) {
+			stored.ClaimedBy = userID
+			stored.ClaimedAt = now
+			claimed = true
+		}
+	})
+	if !exists {
+		return ""
+	}
+	if !claimed {
+		return fmt.Sprintf("<@%s> is already reviewing this.", updated.ClaimedBy)
+	}
+	slog.Info("review claimed", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "user", userID)
+
+	reply := fmt.Sprintf("👀 <@%s> is reviewing this.", userID)
+	login := c.stateManager.GetUserPreferences(workspaceID, userID).GitHubLogin
+	if login == "" {
+		return reply + " Mention me with `github your-login` so I can request your review on GitHub next time."
+	}
+	if err := c.githubFor(pr.Owner).RequestReviewers(ctx, pr.Owner, pr.Repo, pr.Number, []string{login}); err != nil {
+		slog.Warn("failed to request review for claim", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return reply + " I couldn't request the review from " + login + " on GitHub."
+	}
+	return reply + " Requested a review from " + login + " on GitHub."
+}
+
+// setSubscribed subscribes a user to the PR's state changes, or unsubscribes them,
+// and confirms it to just them.
+func (c *Coordinator) setSubscribed(ctx context.Context, a slack.Action, pr *state.PRState, subscribe bool) {
+	ref := fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, pr.Number)
+	var changed bool
+	reply := fmt.Sprintf("🔔 I'll DM you when %s changes state.", ref)
+	if !subscribe {
+		reply = fmt.Sprintf("🔕 I'll stop DMing you about %s, unless it's waiting on you.", ref)
+	}
+	c.stateManager.UpdatePRState(a.Workspace, pr.Owner, pr.Repo, pr.Number, func(stored *state.PRState) {
+		if subscribe {
+			changed = stored.Subscribe(a.UserID)
+		} else {
+			changed = stored.Unsubscribe(a.UserID)
+		}
+	})
+	if changed {
+		slog.Info("PR subscription changed", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "user", a.UserID, "subscribed", subscribe)
+	}
+	if err := c.slackFor(a.Workspace).PostEphemeral(ctx, a.ChannelID, a.UserID, reply); err != nil {
+		slog.Warn("failed to confirm PR subscription", "user", a.UserID, "error", err)
+	}
+}
+
+// linkGitHubCommand records the GitHub login of the Slack user.
+func (c *Coordinator) linkGitHubCommand(workspaceID, userID, login string) string {
+	login = strings.TrimPrefix(login, "@")
+	if login == "" {
+		return "Usage: `github your-login`"
+	}
+
+	prefs := c.stateManager.GetUserPreferences(workspaceID, userID)
+	if previous := prefs.GitHubLogin; previous != "" && !strings.EqualFold(previous, login) {
+		// Let the login they no longer claim be resolved afresh.
+		c.stateManager.SetIdentity(workspaceID, previous, state.Identity{})
+	}
+	prefs.GitHubLogin = login
+	c.stateManager.SetUserPreferences(workspaceID, userID, prefs)
+	c.stateManager.SetIdentity(workspaceID, login, state.Identity{ResolvedAt: c.clock.Now(), SlackID: userID, Source: identity.SourceLinked})
+	return fmt.Sprintf("OK, you're %s on GitHub.", login)
+}
+
+// notifyViaCommand records how the Slack user wants to hear about PRs waiting on them.
+func (c *Coordinator) notifyViaCommand(workspaceID, userID, via string) string {
+	prefs := c.stateManager.GetUserPreferences(workspaceID, userID)
+	switch via {
+	case state.DeliveryDM:
+		prefs.NotifyVia = ""
+		c.stateManager.SetUserPreferences(workspaceID, userID, prefs)
+		return "OK, I'll DM you about PRs waiting on you, or mention you in their threads when I can't."
+	case state.DeliveryThread:
+		prefs.NotifyVia = state.DeliveryThread
+		c.stateManager.SetUserPreferences(workspaceID, userID, prefs)
+		return "OK, I'll only mention you in the threads of PRs waiting on you."
+	default:
+		return "Usage: `notify dm` or `notify thread`"
+	}
+}
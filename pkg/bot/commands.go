@@ -0,0 +1,233 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/command"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// mentionCommand is a command given by mentioning the bot.
+type mentionCommand = command.Command[slack.Mention, string]
+
+// newMentionCommands builds the commands available by mentioning the bot, in the order help lists them.
+func (c *Coordinator) newMentionCommands() *command.Registry[slack.Mention, string] {
+	r := command.NewRegistry[slack.Mention](func(text string) string { return text })
+	r.SetRepos(func(ctx context.Context, m slack.Mention) []string {
+		return c.ChannelRepos(ctx, m.Workspace, m.ChannelID)
+	})
+	r.Register(
+		mentionCommand{
+			Name:    "status",
+			Summary: "Show the state of a PR",
+			Args:    []command.Arg{{Name: "pr", Kind: command.PR}},
+			Run: func(_ context.Context, m slack.Mention, in *command.Input) string {
+				ref, _ := in.PR("pr")
+				return c.prStatus(m.Workspace, ref)
+			},
+		},
+		mentionCommand{
+			Name:    "list",
+			Summary: "List open PRs posted to this channel",
+			Run: func(ctx context.Context, m slack.Mention, _ *command.Input) string {
+				return c.listChannelPRs(ctx, m.Workspace, m.ChannelID)
+			},
+		},
+		mentionCommand{
+			Name:    "mute",
+			Summary: "Stop posting new PRs to this channel",
+			Run: func(ctx context.Context, m slack.Mention, _ *command.Input) string {
+				c.setChannelMuted(ctx, m.Workspace, m.ChannelID, true)
+				return "Muted. New PRs will not be posted to this channel; mention me with `unmute` to resume."
+			},
+		},
+		mentionCommand{
+			Name:    "unmute",
+			Summary: "Resume posting new PRs to this channel",
+			Run: func(ctx context.Context, m slack.Mention, _ *command.Input) string {
+				c.setChannelMuted(ctx, m.Workspace, m.ChannelID, false)
+				return "Unmuted. New PRs will be posted to this channel again."
+			},
+		},
+		mentionCommand{
+			Name:    "github",
+			Summary: "Link your GitHub account, so claiming a review requests it on GitHub",
+			Args:    []command.Arg{{Name: "your-login"}},
+			Run: func(_ context.Context, m slack.Mention, in *command.Input) string {
+				return c.linkGitHubCommand(m.Workspace, m.UserID, in.Arg("your-login"))
+			},
+		},
+		mentionCommand{
+			Name:    "notify",
+			Summary: "Hear about PRs waiting on you by DM, or only by mentions in their threads",
+			Args:    []command.Arg{{Name: "via", Choices: []string{state.DeliveryDM, state.DeliveryThread}}},
+			Run: func(_ context.Context, m slack.Mention, in *command.Input) string {
+				return c.notifyViaCommand(m.Workspace, m.UserID, in.Arg("via"))
+			},
+		},
+		mentionCommand{
+			Name:    "help",
+			Summary: "Show this help message, or how to use one command",
+			Args:    []command.Arg{{Name: "command", Kind: command.Rest, Optional: true}},
+			Run: func(_ context.Context, _ slack.Mention, in *command.Input) string {
+				return c.helpReply(in, false)
+			},
+		},
+	)
+	return r
+}
+
+// helpReply answers a help command: every command, with those of PR threads if
+// inThread, or how to use the one named.
+func (c *Coordinator) helpReply(in *command.Input, inThread bool) string {
+	if len(in.Words) > 0 {
+		if inThread {
+			if help, ok := c.threadCmds.CommandHelp("", in.Words); ok {
+				return help
+			}
+		}
+		if help, ok := c.mentionCmds.CommandHelp("", in.Words); ok {
+			return help
+		}
+		return fmt.Sprintf("There's no `%s` command.\n%s", in.Arg("command"), c.mentionHelp())
+	}
+	if inThread {
+		return c.mentionHelp() + "\n\nIn a PR thread you can also use:\n" + c.threadCmds.Help("")
+	}
+	return c.mentionHelp()
+}
+
+// mentionHelp lists the commands available by mentioning the bot.
+func (c *Coordinator) mentionHelp() string {
+	return "Commands:\n" + c.mentionCmds.Help("")
+}
+
+// HandleMention runs a command addressed to the bot in a channel.
+func (c *Coordinator) HandleMention(ctx context.Context, m slack.Mention) string {
+	words := command.Fields(m.Text)
+	if len(words) == 0 {
+		return c.mentionHelp()
+	}
+	slog.Info("mention command", "channel", m.ChannelID, "user", m.UserID, "command", words[0])
+
+	if pr, exists := c.stateManager.FindPRByThread(m.Workspace, m.ChannelID, m.ThreadTS); exists {
+		if reply, ok := c.threadCmds.Run(ctx, threadMention{Mention: m, pr: pr}, "", m.Text); ok {
+			return reply
+		}
+	}
+	if reply, ok := c.mentionCmds.Run(ctx, m, "", m.Text); ok {
+		return reply
+	}
+	return fmt.Sprintf("Unknown command `%s`.\n%s", words[0], c.mentionHelp())
+}
+
+// prStatus describes the tracked state of the PR named by ref.
+func (c *Coordinator) prStatus(workspaceID string, ref command.PRRef) string {
+	pr, exists := c.stateManager.GetPRState(workspaceID, ref.Owner, ref.Repo, ref.Number)
+	if !exists {
+		return fmt.Sprintf("I'm not tracking %s.", ref)
+	}
+	now := time.Now()
+	return formatPRLine(pr, c.thresholds(ref.Owner), now, c.stateManager.AwayLogins(workspaceID, now))
+}
+
+// listChannelPRs lists the open PRs tracked for a channel.
+func (c *Coordinator) listChannelPRs(ctx context.Context, workspaceID, channelID string) string {
+	prs := c.ChannelPRs(ctx, workspaceID, channelID)
+	if len(prs) == 0 {
+		return "No open PRs in this channel. 🎉"
+	}
+
+	now := time.Now()
+	away := c.stateManager.AwayLogins(workspaceID, now)
+	lines := make([]string, 0, len(prs)+1)
+	lines = append(lines, fmt.Sprintf("%d open pull requests:", len(prs)))
+	for _, pr := range prs {
+		lines = append(lines, formatPRLine(pr, c.thresholds(pr.Owner), now, away))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// ChannelPRs returns the open PRs tracked for a channel, oldest first: those whose
+// threads live in it, and those from repos configured to post to it.
+func (c *Coordinator) ChannelPRs(ctx context.Context, workspaceID, channelID string) []*state.PRState {
+	keys := c.channelKeys(ctx, workspaceID, channelID)
+	repos := make(map[string]bool)
+	for _, repo := range c.reposForChannel(workspaceID, keys) {
+		repos[repo] = true
+	}
+
+	var prs []*state.PRState
+	for _, pr := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+		if !isOpenState(pr.State) {
+			continue
+		}
+		if keys[strings.TrimPrefix(pr.ChannelID, "#")] || repos[pr.Owner+"/"+pr.Repo] {
+			prs = append(prs, pr)
+		}
+	}
+	sort.Slice(prs, func(i, j int) bool {
+		return prs[i].CreatedAt.Before(prs[j].CreatedAt)
+	})
+	return prs
+}
+
+// setChannelMuted mutes a channel under both its ID and name, since repo
+// configuration may route PRs to either.
+func (c *Coordinator) setChannelMuted(ctx context.Context, workspaceID, channelID string, muted bool) {
+	for key := range c.channelKeys(ctx, workspaceID, channelID) {
+		c.stateManager.SetChannelMuted(workspaceID, key, muted)
+	}
+}
+
+// channelMuted reports whether a configured channel, by ID or name, is muted.
+func (c *Coordinator) channelMuted(workspaceID, channel string) bool {
+	return c.stateManager.ChannelMuted(workspaceID, strings.TrimPrefix(channel, "#"))
+}
+
+// channelKeys returns the identifiers a channel may be configured by: its ID and, if known, its name.
+func (c *Coordinator) channelKeys(ctx context.Context, workspaceID, channelID string) map[string]bool {
+	keys := map[string]bool{channelID: true}
+	name, err := c.slackFor(workspaceID).ChannelName(ctx, channelID)
+	if err != nil {
+		slog.Debug("unable to resolve channel name", "channel", channelID, "error", err)
+		return keys
+	}
+	keys[name] = true
+	return keys
+}
+
+// thresholds returns the staleness thresholds configured for an org, scaled by its repos'
+// severity tiers and, for deferred PRs, by its deferral scale.
+func (c *Coordinator) thresholds(org string) slack.AgeThresholds {
+	open, idle := c.configManager.GetStaleness(org)
+	return slack.AgeThresholds{Open: open, Idle: idle, Scales: c.configManager.SeverityScales(org),
+		Deferral: c.configManager.GetDeferral(org).Scale}
+}
+
+// formatPRLine renders a one-line PR summary for a chat reply, marking the blocking
+// users in away, by lowercased GitHub login, with when they're back.
+func formatPRLine(pr *state.PRState, thresholds slack.AgeThresholds, now time.Time, away map[string]time.Time) string {
+	line := fmt.Sprintf("%s <https://github.com/%s/%s/pull/%d|%s/%s#%d> %s by @%s",
+		slack.StateEmoji(pr.State), pr.Owner, pr.Repo, pr.Number, pr.Owner, pr.Repo, pr.Number, pr.Title, pr.Author)
+	if activity := slack.FormatActivity(pr, now, thresholds); activity != "" {
+		line += " • " + activity
+	}
+	if len(pr.BlockedOn) > 0 {
+		blockers := make([]string, 0, len(pr.BlockedOn))
+		for _, login := range pr.BlockedOn {
+			if until, ok := away[strings.ToLower(login)]; ok {
+				login += " (🌴 away until " + slack.FormatTime(until, slack.DateTime) + ")"
+			}
+			blockers = append(blockers, login)
+		}
+		line += " • blocked on " + strings.Join(blockers, ", ")
+	}
+	return line
+}
@@ -0,0 +1,40 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+)
+
+// handleReviewThreadEvent re-resolves a tracked PR when one of its review conversations
+// is resolved or reopened, updating its unresolved count and, for orgs that hold for
+// conversations, its state.
+func (c *Coordinator) handleReviewThreadEvent(ctx context.Context, ev *Event) error {
+	var event struct {
+		Action      string `json:"action"`
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(ev.Payload, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal review thread event: %w", err)
+	}
+	if event.Action != "resolved" && event.Action != "unresolved" {
+		return nil
+	}
+
+	workspaceID, routed := c.workspaceFor(ev.Owner)
+	if !routed {
+		return nil
+	}
+	pr, exists := c.stateManager.GetPRState(workspaceID, ev.Owner, ev.Repo, event.PullRequest.Number)
+	if !exists || !isOpenState(pr.State) {
+		return nil
+	}
+	if err := c.resyncPR(ctx, workspaceID, pr); err != nil {
+		slog.Warn("failed to update PR after review conversation change",
+			"owner", ev.Owner, "repo", ev.Repo, "number", event.PullRequest.Number, "error", err)
+	}
+	return nil
+}
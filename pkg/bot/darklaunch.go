@@ -0,0 +1,81 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/github"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/turn"
+)
+
+const (
+	// disagreementLogRate is the fraction of disagreements logged in full.
+	disagreementLogRate = 0.1
+	// shadowTimeout bounds each shadow call to the turn server.
+	shadowTimeout = 10 * time.Second
+)
+
+// SetTurnClient enables dark-launch comparison against the turn server. The local
+// heuristic remains the source of truth; the turn server's answer is only measured.
+func (c *Coordinator) SetTurnClient(client *turn.Client) {
+	c.turn = client
+}
+
+// prState determines a PR's state with the local heuristic, the repo's required
+// reviewers, and the org's policy for data that couldn't be fetched. The heuristic's answer is compared against the turn server in the
+// background when one is configured.
+func (c *Coordinator) prState(ctx context.Context, owner, repo string, number int) (*github.PRStatus, error) {
+	status, err := c.githubFor(owner).GetPRState(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.turn != nil {
+		heuristic := *status
+		go c.compareTurn(context.WithoutCancel(ctx), owner, repo, number, &heuristic)
+	}
+	c.applyRequiredReviewers(ctx, owner, repo, status)
+	c.expandTeams(ctx, owner, repo, number, status)
+	c.applyConversationHold(owner, status)
+	c.applyFetchPolicy(owner, repo, number, status)
+	return status, nil
+}
+
+// compareTurn records whether the turn server agrees with the local heuristic.
+func (c *Coordinator) compareTurn(ctx context.Context, owner, repo string, number int, local *github.PRStatus) {
+	ctx, cancel := context.WithTimeout(ctx, shadowTimeout)
+	defer cancel()
+
+	remote, err := c.turn.GetPRState(ctx, owner, repo, number)
+	if err != nil {
+		metrics.IncCounter("slacker_state_source_comparisons_total", "result", "error")
+		slog.Debug("turn server comparison failed", "owner", owner, "repo", repo, "number", number, "error", err)
+		return
+	}
+
+	stateMatch := remote.State == local.State
+	localBlocked := slices.Sorted(slices.Values(local.BlockedOn))
+	blockedMatch := slices.Equal(localBlocked, remote.BlockedOn)
+
+	result := "match"
+	switch {
+	case !stateMatch && !blockedMatch:
+		result = "both_mismatch"
+	case !stateMatch:
+		result = "state_mismatch"
+	case !blockedMatch:
+		result = "blocked_on_mismatch"
+	}
+	metrics.IncCounter("slacker_state_source_comparisons_total", "result", result)
+
+	if result != "match" && rand.Float64() < disagreementLogRate {
+		slog.Info("turn server disagrees with local state",
+			"owner", owner, "repo", repo, "number", number,
+			"local_state", local.State, "turn_state", remote.State,
+			"local_blocked_on", localBlocked, "turn_blocked_on", remote.BlockedOn)
+	}
+}
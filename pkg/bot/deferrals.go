@@ -0,0 +1,70 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// HandleReaction defers a PR when an org member reacts to its thread with the org's
+// deferral emoji, relaxing its nudges, and drops them from the deferral when they
+// remove the reaction.
+func (c *Coordinator) HandleReaction(ctx context.Context, r slack.Reaction) {
+	pr, exists := c.stateManager.FindPRByThread(r.Workspace, r.ChannelID, r.MessageTS)
+	if !exists {
+		return
+	}
+	emoji, _, _ := strings.Cut(r.Emoji, "::") // Drop any skin tone.
+	if deferral := c.configManager.GetDeferral(pr.Owner); deferral.Emoji == "" || emoji != deferral.Emoji {
+		return
+	}
+	if !c.orgMember(r.Workspace, pr.Owner, r.UserID) {
+		slog.Info("ignored deferral from non-member", "org", pr.Owner, "user", r.UserID)
+		return
+	}
+
+	var changed bool
+	updated, exists := c.stateManager.UpdatePRState(r.Workspace, pr.Owner, pr.Repo, pr.Number, func(stored *state.PRState) {
+		if r.Added {
+			changed = stored.Defer(r.UserID, c.clock.Now())
+		} else {
+			changed = stored.Undefer(r.UserID)
+		}
+	})
+	if !exists || !changed {
+		return
+	}
+	pr = updated
+	if !r.Added {
+		slog.Info("PR deferral withdrawn", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number,
+			"user", r.UserID, "deferred", pr.DeferralActive(c.clock.Now()))
+		return
+	}
+	metrics.IncCounter("slacker_deferrals_total")
+	slog.Info("PR deferred", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "user", r.UserID, "deferred_by", pr.DeferredBy)
+
+	note := fmt.Sprintf("📅 <@%s> deferred this to later this week, so nudges are relaxed until %s.",
+		r.UserID, slack.FormatTime(pr.DeferredAt.Add(state.DeferralTimeout), slack.DateTime))
+	if len(pr.DeferredBy) > 1 {
+		note = fmt.Sprintf("📅 <@%s> also deferred this (%d votes).", r.UserID, len(pr.DeferredBy))
+	}
+	if err := c.notifier.SendThreadUpdate(ctx, r.Workspace, pr, note); err != nil {
+		slog.Warn("failed to post deferral note", "error", err)
+	}
+}
+
+// orgMember reports whether a Slack user maps to a member of an org's GitHub org.
+// Until the org's members have been synced, anyone counts.
+func (c *Coordinator) orgMember(workspaceID, org, userID string) bool {
+	synced, ok := c.orgMembers(org)
+	if !ok {
+		return true
+	}
+	login, found := c.verifiedLoginFor(workspaceID, org, userID)
+	return found && synced.logins[strings.ToLower(login)]
+}
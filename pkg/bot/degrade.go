@@ -0,0 +1,45 @@
+package bot
+
+import (
+	"log/slog"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/github"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// applyFetchPolicy adjusts a status derived from incomplete GitHub data according
+// to the org's policy: keep the derived state, hold the previous one, or mark it unknown.
+func (c *Coordinator) applyFetchPolicy(owner, repo string, number int, status *github.PRStatus) {
+	if len(status.Incomplete) == 0 {
+		return
+	}
+
+	policy := c.configManager.GetFetchErrorPolicy(owner)
+	metrics.IncCounter("slacker_pr_state_incomplete_total", "policy", policy)
+	slog.Warn("PR state derived from incomplete data",
+		"owner", owner, "repo", repo, "number", number, "missing", status.Incomplete, "policy", policy)
+
+	switch policy {
+	case config.FetchErrorsHold:
+		workspaceID, routed := c.workspaceFor(owner)
+		if !routed {
+			return
+		}
+		previous, exists := c.stateManager.GetPRState(workspaceID, owner, repo, number)
+		if !exists || previous.State == "" {
+			// Nothing to hold; the derived state is the best available.
+			return
+		}
+		status.State = previous.State
+		status.BlockedOn = previous.BlockedOn
+		status.ChangesRequestedBy = previous.ChangesRequestedBy
+		status.UnresolvedConversations = previous.UnresolvedConversations
+	case config.FetchErrorsUnknown:
+		status.State = state.Unknown
+		status.BlockedOn = nil
+	default:
+		// FetchErrorsDegrade keeps the derived state.
+	}
+}
@@ -0,0 +1,138 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// digestCatchUpWindow is how long after its scheduled time a missed digest is still posted.
+const digestCatchUpWindow = time.Hour
+
+// RunDigests posts scheduled open PR digests to channels until the context is cancelled.
+func (c *Coordinator) RunDigests(ctx context.Context) error {
+	ticker := c.clock.NewTicker(1 * time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case now := <-ticker.C():
+			c.checkDigests(ctx, now)
+		}
+	}
+}
+
+// checkDigests posts any digests that are due.
+func (c *Coordinator) checkDigests(ctx context.Context, now time.Time) {
+	for _, org := range c.configManager.Orgs() {
+		workspaceID, routed := c.workspaceFor(org)
+		if !routed || c.stateManager.Paused(workspaceID, org) {
+			continue
+		}
+		cfg, exists := c.configManager.GetConfig(org)
+		if !exists || !cfg.Global.Digest.Enabled {
+			continue
+		}
+
+		due, err := lastDigestTime(cfg.Global.Digest, now)
+		if err != nil {
+			slog.Warn("invalid digest schedule", "org", org, "error", err)
+			continue
+		}
+		if now.Sub(due) > digestCatchUpWindow {
+			continue
+		}
+
+		for _, channel := range c.configManager.GetChannels(org) {
+			key := org + ":" + channel
+			if c.channelMuted(workspaceID, channel) {
+				continue
+			}
+			if !c.stateManager.LastDigest(workspaceID, key).Before(due) {
+				continue
+			}
+			if err := c.postDigest(ctx, workspaceID, org, channel, now); err != nil {
+				slog.Warn("failed to post digest", "org", org, "channel", channel, "error", err)
+				continue
+			}
+			c.stateManager.RecordDigest(workspaceID, key, now)
+		}
+	}
+}
+
+// postDigest posts the open PR digest for the repos an org routes to a channel.
+func (c *Coordinator) postDigest(ctx context.Context, workspaceID, org, channel string, now time.Time) error {
+	repos := make(map[string]bool)
+	for _, repo := range c.configManager.GetReposForChannel(org, channel) {
+		repos[repo] = true
+	}
+
+	var prs []*state.PRState
+	for _, pr := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+		if pr.Owner == org && repos[pr.Repo] && isOpenState(pr.State) {
+			prs = append(prs, pr)
+		}
+	}
+
+	slog.Info("posting digest", "org", org, "channel", channel, "prs", len(prs))
+	text := fmt.Sprintf("%d open pull requests", len(prs))
+	return c.slackFor(workspaceID).PostBlocks(ctx, channel, text, slack.BuildDigestBlocks(channel, prs, now, c.thresholds(org),
+		slack.CatalogFor(c.configManager.GetTone(org))))
+}
+
+// isOpenState reports whether a PR state represents an open PR.
+func isOpenState(prState string) bool {
+	return prState != "pray" && prState != "face_palm"
+}
+
+// lastDigestTime returns the most recent scheduled digest time at or before now.
+func lastDigestTime(cfg config.DigestConfig, now time.Time) (time.Time, error) {
+	loc := time.UTC
+	if cfg.Timezone != "" {
+		var err error
+		loc, err = time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid timezone %q: %w", cfg.Timezone, err)
+		}
+	}
+
+	day := strings.ToLower(cfg.Day)
+	if day == "" {
+		day = "monday"
+	}
+	weekday := -1
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if strings.ToLower(d.String()) == day {
+			weekday = int(d)
+			break
+		}
+	}
+	if weekday < 0 {
+		return time.Time{}, fmt.Errorf("invalid day %q", cfg.Day)
+	}
+
+	clock := cfg.Time
+	if clock == "" {
+		clock = "09:00"
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: %w", cfg.Time, err)
+	}
+
+	local := now.In(loc)
+	daysBack := (int(local.Weekday()) - weekday + 7) % 7
+	scheduled := time.Date(local.Year(), local.Month(), local.Day()-daysBack, t.Hour(), t.Minute(), 0, 0, loc)
+	if scheduled.After(local) {
+		scheduled = scheduled.AddDate(0, 0, -7)
+	}
+	return scheduled, nil
+}
@@ -0,0 +1,101 @@
+package bot
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+)
+
+// deliveryTTL is how long a delivery ID is remembered for deduplication.
+const deliveryTTL = time.Hour
+
+// deriveDeliveryID returns a stable ID for messages that arrive without one,
+// so redeliveries of the same payload are still recognized.
+func deriveDeliveryID(msg SprinklerMessage) string {
+	h := sha256.New()
+	h.Write([]byte(msg.Event))
+	h.Write([]byte{0})
+	h.Write([]byte(msg.Repo))
+	h.Write([]byte{0})
+	h.Write(msg.Payload)
+	return "derived-" + hex.EncodeToString(h.Sum(nil)[:12])
+}
+
+// deliveryTracker remembers recently processed delivery IDs. They're also kept in
+// the order they were processed, so expiring them only looks at the oldest.
+type deliveryTracker struct {
+	seen  map[string]time.Time
+	order []seenDelivery
+	mu    sync.Mutex
+}
+
+// seenDelivery is a delivery ID and when it was processed.
+type seenDelivery struct {
+	at time.Time
+	id string
+}
+
+func newDeliveryTracker() *deliveryTracker {
+	return &deliveryTracker{seen: make(map[string]time.Time)}
+}
+
+// begin marks a delivery as being processed, returning false if it was already seen.
+func (t *deliveryTracker) begin(id string, now time.Time) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.expireLocked(now)
+	if _, exists := t.seen[id]; exists {
+		return false
+	}
+	t.seen[id] = now
+	t.order = append(t.order, seenDelivery{at: now, id: id})
+	return true
+}
+
+// expireLocked forgets deliveries processed more than deliveryTTL before now (must
+// hold lock). Entries for deliveries forgotten, or processed again since, are skipped.
+func (t *deliveryTracker) expireLocked(now time.Time) {
+	n := 0
+	for ; n < len(t.order) && now.Sub(t.order[n].at) > deliveryTTL; n++ {
+		if at, exists := t.seen[t.order[n].id]; exists && at.Equal(t.order[n].at) {
+			delete(t.seen, t.order[n].id)
+		}
+	}
+	t.order = t.order[n:]
+}
+
+// forget removes a delivery so a later redelivery is processed again, e.g. after a failure.
+func (t *deliveryTracker) forget(id string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.seen, id)
+}
+
+// snapshot returns the remembered delivery IDs and when each was processed.
+func (t *deliveryTracker) snapshot() map[string]time.Time {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return maps.Clone(t.seen)
+}
+
+// seed remembers deliveries processed elsewhere, such as by a previous instance,
+// dropping those already past deliveryTTL.
+func (t *deliveryTracker) seed(seen map[string]time.Time, now time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for id, at := range seen {
+		if now.Sub(at) <= deliveryTTL {
+			t.seen[id] = at
+		}
+	}
+	// Seeded deliveries may be older than those already processed here.
+	t.order = t.order[:0]
+	for id, at := range t.seen {
+		t.order = append(t.order, seenDelivery{at: at, id: id})
+	}
+	slices.SortFunc(t.order, func(a, b seenDelivery) int { return a.at.Compare(b.at) })
+}
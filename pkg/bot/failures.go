@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+const (
+	// failureIssueThreshold is how many events in a row must fail for a repo before an issue is filed.
+	failureIssueThreshold = 5
+	// failureIssueInterval is how long after filing, or finding, a repo's issue it's checked for again
+	// while failures continue, so an issue closed while the repo is still failing is refiled.
+	failureIssueInterval = 24 * time.Hour
+)
+
+// repoFailures is a repo's run of failed events.
+type repoFailures struct {
+	since     time.Time // When the first failure in the run happened.
+	filed     time.Time // When an issue was last filed or found for the run; zero if not yet.
+	event     string    // The type of the last event that failed.
+	lastError string
+	count     int
+}
+
+// failureTracker counts each repo's consecutive event failures.
+type failureTracker struct {
+	repos map[string]*repoFailures
+	mu    sync.Mutex
+}
+
+func newFailureTracker() *failureTracker {
+	return &failureTracker{repos: make(map[string]*repoFailures)}
+}
+
+// failed records a failed event, returning a copy of the repo's run when it's time
+// to file an issue about it.
+func (t *failureTracker) failed(fullName, event string, err error, now time.Time) (repoFailures, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	f, exists := t.repos[fullName]
+	if !exists {
+		f = &repoFailures{since: now}
+		t.repos[fullName] = f
+	}
+	f.count++
+	f.event = event
+	f.lastError = err.Error()
+	if f.count < failureIssueThreshold || (!f.filed.IsZero() && now.Sub(f.filed) < failureIssueInterval) {
+		return repoFailures{}, false
+	}
+	f.filed = now
+	return *f, true
+}
+
+// succeeded ends a repo's run of failures.
+func (t *failureTracker) succeeded(fullName string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.repos, fullName)
+}
+
+// recordOutcome tracks whether a repo's events are failing, and once enough fail in
+// a row, files an issue about it in the org's .github repo if the org opts in.
+func (c *Coordinator) recordOutcome(ctx context.Context, owner, repo, event string, err error) {
+	fullName := owner + "/" + repo
+	if err == nil {
+		c.failures.succeeded(fullName)
+		return
+	}
+	run, due := c.failures.failed(fullName, event, err, c.clock.Now())
+	if !due || !c.configManager.FailureIssuesEnabled(owner) {
+		return
+	}
+	go c.fileFailureIssue(context.WithoutCancel(ctx), owner, repo, run)
+}
+
+// fileFailureIssue opens an issue describing a repo's failing events, unless one is already open.
+func (c *Coordinator) fileFailureIssue(ctx context.Context, owner, repo string, run repoFailures) {
+	title := fmt.Sprintf("Slack notifications are failing for %s", repo)
+	body := fmt.Sprintf("The last %d GitHub events for %s/%s failed to process, so its PRs may be missing "+
+		"from Slack or out of date there.\n\n"+
+		"- **First failure:** %s\n"+
+		"- **Last event:** `%s`\n"+
+		"- **Last error:**\n\n```\n%s\n```\n\n"+
+		"This is often a permission the GitHub App lacks for the repo, or a problem with `slack.yaml`. "+
+		"Close this issue once it's fixed; it's filed again if the failures continue.",
+		run.count, owner, repo, run.since.UTC().Format(time.RFC3339), run.event, run.lastError)
+
+	url, created, err := c.githubFor(owner).FileIssue(ctx, owner, ".github", title, body)
+	if err != nil {
+		slog.Warn("failed to file issue about failing events", "owner", owner, "repo", repo, "error", err)
+		metrics.IncCounter("slacker_failure_issues_total", "result", "error")
+		return
+	}
+	if !created {
+		slog.Info("issue about failing events is already open", "owner", owner, "repo", repo, "issue", url)
+		return
+	}
+	slog.Warn("filed issue about failing events", "owner", owner, "repo", repo, "failures", run.count, "issue", url)
+	metrics.IncCounter("slacker_failure_issues_total", "result", "filed")
+}
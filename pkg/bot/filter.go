@@ -0,0 +1,44 @@
+package bot
+
+import "strings"
+
+// ignoredEvents are repository events that never concern a PR. Orgs that route every
+// event through sprinkler deliver them in bulk, so they are dropped quietly rather
+// than logged as unhandled.
+var ignoredEvents = map[string]bool{
+	"discussion":         true,
+	"discussion_comment": true,
+	"fork":               true,
+	"gollum":             true, // Wiki page edits.
+	"member":             true,
+	"public":             true,
+	"star":               true,
+	"watch":              true, // Stars, under their legacy name.
+}
+
+// SetEventFilter limits processing to the given event types; events of other types
+// are dropped as they arrive, before they are queued or parsed. An empty list
+// accepts every event type. It must be called before Run.
+func (c *Coordinator) SetEventFilter(eventTypes []string) {
+	c.accepted = nil
+	for _, eventType := range eventTypes {
+		if eventType = strings.TrimSpace(eventType); eventType == "" {
+			continue
+		}
+		if c.accepted == nil {
+			c.accepted = make(map[string]bool)
+		}
+		c.accepted[eventType] = true
+	}
+}
+
+// ignoresEvent reports whether an event type is known noise with no registered handler.
+func (c *Coordinator) ignoresEvent(eventType string) bool {
+	_, handled := c.handlers[eventType]
+	return ignoredEvents[eventType] && !handled
+}
+
+// acceptsEvent reports whether the event filter lets an event type through.
+func (c *Coordinator) acceptsEvent(eventType string) bool {
+	return c.accepted == nil || c.accepted[eventType]
+}
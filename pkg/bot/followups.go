@@ -0,0 +1,66 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// followUpPattern matches a line of a review comment that records agreed follow-up
+// work, such as "TODO: add metrics" or "follow-up: split this handler".
+var followUpPattern = regexp.MustCompile(`(?i)^[\s>*-]*(?:TODO|follow[- ]?up)\b[\s:,-]*(.*)$`)
+
+// followUp is a follow-up task found in review feedback.
+type followUp struct {
+	text   string
+	author string
+	url    string
+}
+
+// extractFollowUps returns the follow-up lines in a comment body.
+func extractFollowUps(body, author, url string) []followUp {
+	var found []followUp
+	for _, line := range strings.Split(body, "\n") {
+		match := followUpPattern.FindStringSubmatch(strings.TrimSpace(line))
+		if match == nil || strings.TrimSpace(match[1]) == "" {
+			continue
+		}
+		found = append(found, followUp{text: strings.TrimSpace(match[1]), author: author, url: url})
+	}
+	return found
+}
+
+// postFollowUps collects the follow-ups marked in a merged PR's review comments and
+// posts them to its thread as a checklist, so agreed work isn't lost.
+func (c *Coordinator) postFollowUps(ctx context.Context, workspaceID string, pr *state.PRState) {
+	var items []followUp
+	reviews, err := c.githubFor(pr.Owner).GetPRReviews(ctx, pr.Owner, pr.Repo, pr.Number)
+	if err != nil {
+		slog.Warn("failed to get reviews for follow-ups", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+	}
+	for _, review := range reviews {
+		items = append(items, extractFollowUps(review.GetBody(), review.GetUser().GetLogin(), review.GetHTMLURL())...)
+	}
+	comments, err := c.githubFor(pr.Owner).GetPRReviewComments(ctx, pr.Owner, pr.Repo, pr.Number)
+	if err != nil {
+		slog.Warn("failed to get review comments for follow-ups", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+	}
+	for _, comment := range comments {
+		items = append(items, extractFollowUps(comment.GetBody(), comment.GetUser().GetLogin(), comment.GetHTMLURL())...)
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	lines := []string{fmt.Sprintf("📝 Follow-ups from review (%d):", len(items))}
+	for _, item := range items {
+		lines = append(lines, fmt.Sprintf("☐ %s — @%s (<%s|comment>)", item.text, item.author, item.url))
+	}
+	if err := c.notifier.SendThreadUpdate(ctx, workspaceID, pr, strings.Join(lines, "\n")); err != nil {
+		slog.Warn("failed to post follow-ups", "error", err)
+	}
+}
@@ -0,0 +1,33 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// frozen reports whether a freeze window is in effect for an org.
+func (c *Coordinator) frozen(org string) bool {
+	_, active := c.configManager.ActiveFreeze(org, time.Now())
+	return active
+}
+
+// announceFreeze notes on an approved PR's thread that a freeze window is in effect,
+// so it isn't merged until the window ends.
+func (c *Coordinator) announceFreeze(ctx context.Context, workspaceID string, pr *state.PRState) {
+	freeze, active := c.configManager.ActiveFreeze(pr.Owner, time.Now())
+	if !active {
+		return
+	}
+	note := fmt.Sprintf("🧊 Freeze in effect until %s. Hold the merge until it lifts.", slack.FormatTime(freeze.End, slack.DateTime))
+	if freeze.Reason != "" {
+		note += " Reason: " + freeze.Reason
+	}
+	if err := c.notifier.SendThreadUpdate(ctx, workspaceID, pr, note); err != nil {
+		slog.Warn("failed to post freeze note", "error", err)
+	}
+}
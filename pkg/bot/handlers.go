@@ -0,0 +1,136 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+// Event is a GitHub webhook event delivered through sprinkler, with its repo parsed.
+type Event struct {
+	SentAt     time.Time // When sprinkler sent the event; zero if unknown.
+	ReceivedAt time.Time // When the bot read the event.
+	Type       string
+	Owner      string
+	Repo       string
+	DeliveryID string
+	Payload    json.RawMessage
+	Attempts   int // Processing attempts, including this one.
+}
+
+// lag returns how long the event took to reach the bot, or zero if unknown.
+func (ev *Event) lag() time.Duration {
+	if ev.SentAt.IsZero() || ev.ReceivedAt.IsZero() {
+		return 0
+	}
+	return ev.ReceivedAt.Sub(ev.SentAt)
+}
+
+// EventHandler handles one GitHub event type.
+type EventHandler interface {
+	Handle(ctx context.Context, ev *Event) error
+}
+
+// HandlerFunc adapts a function to an EventHandler.
+type HandlerFunc func(ctx context.Context, ev *Event) error
+
+// Handle calls f.
+func (f HandlerFunc) Handle(ctx context.Context, ev *Event) error {
+	return f(ctx, ev)
+}
+
+// Middleware wraps an EventHandler with cross-cutting behavior.
+type Middleware func(next EventHandler) EventHandler
+
+// Register sets the handler for an event type, replacing any existing handler.
+func (c *Coordinator) Register(eventType string, h EventHandler) {
+	c.handlers[eventType] = h
+}
+
+// Use appends middleware applied to every handler; the first middleware is outermost.
+func (c *Coordinator) Use(mw ...Middleware) {
+	c.middleware = append(c.middleware, mw...)
+}
+
+// registerDefaultHandlers registers the built-in event handlers.
+func (c *Coordinator) registerDefaultHandlers() {
+	c.Register("pull_request", HandlerFunc(c.handlePullRequestEvent))
+	c.Register("pull_request_review", HandlerFunc(c.handlePullRequestReviewEvent))
+	c.Register("pull_request_review_thread", HandlerFunc(c.handleReviewThreadEvent))
+	c.Register("check_run", HandlerFunc(c.handleCheckEvent))
+	c.Register("check_suite", HandlerFunc(c.handleCheckEvent))
+	c.Register("push", HandlerFunc(c.handlePushEvent))
+	c.Register("repository", HandlerFunc(c.handleRepositoryEvent))
+}
+
+// wrap applies the registered middleware to a handler.
+func (c *Coordinator) wrap(h EventHandler) EventHandler {
+	for i := len(c.middleware) - 1; i >= 0; i-- {
+		h = c.middleware[i](h)
+	}
+	return h
+}
+
+// withRecovery converts handler panics into errors.
+func withRecovery(next EventHandler) EventHandler {
+	return HandlerFunc(func(ctx context.Context, ev *Event) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				err = fmt.Errorf("panic recovered in %s handler: %v", ev.Type, r)
+				slog.Error("panic recovered in event handler", "event", ev.Type, "panic", r)
+				metrics.IncCounter("slacker_event_panics_total", "event", ev.Type)
+			}
+		}()
+		return next.Handle(ctx, ev)
+	})
+}
+
+// withLogging logs each event and writes an audit entry with its delivery metadata and outcome.
+func withLogging(next EventHandler) EventHandler {
+	return HandlerFunc(func(ctx context.Context, ev *Event) error {
+		slog.Info("processing event", "event", ev.Type, "owner", ev.Owner, "repo", ev.Repo, "delivery_id", ev.DeliveryID)
+		start := time.Now()
+		err := next.Handle(ctx, ev)
+
+		result := "ok"
+		if err != nil {
+			result = err.Error()
+		}
+		slog.Info("event audit",
+			"event", ev.Type,
+			"owner", ev.Owner,
+			"repo", ev.Repo,
+			"delivery_id", ev.DeliveryID,
+			"attempts", ev.Attempts,
+			"lag", ev.lag(),
+			"queued", start.Sub(ev.ReceivedAt),
+			"duration", time.Since(start),
+			"result", result)
+		return err
+	})
+}
+
+// withMetrics records event counts and handler latency.
+func withMetrics(next EventHandler) EventHandler {
+	return HandlerFunc(func(ctx context.Context, ev *Event) error {
+		start := time.Now()
+		if lag := ev.lag(); lag > 0 {
+			metrics.Observe("slacker_event_lag_seconds", lag.Seconds(), "event", ev.Type)
+		}
+		if !ev.ReceivedAt.IsZero() {
+			metrics.Observe("slacker_event_queue_seconds", start.Sub(ev.ReceivedAt).Seconds(), "event", ev.Type)
+		}
+		err := next.Handle(ctx, ev)
+		result := "ok"
+		if err != nil {
+			result = "error"
+		}
+		metrics.IncCounter("slacker_events_processed_total", "event", ev.Type, "result", result)
+		metrics.Observe("slacker_event_duration_seconds", time.Since(start).Seconds(), "event", ev.Type)
+		return err
+	})
+}
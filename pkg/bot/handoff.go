@@ -0,0 +1,158 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/admin"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/gorilla/mux"
+)
+
+// handoffDrainTimeout bounds how long a handoff waits for queued and in-flight events
+// to finish before passing the rest to the next instance.
+const handoffDrainTimeout = 30 * time.Second
+
+// SetHandoff enables handing the event stream between instances that share dataDir
+// during a blue/green rollout. At startup the coordinator waits up to wait for the
+// previous instance to hand off before connecting to sprinkler, and it hands off
+// itself when it stops. It must be called before Run.
+func (c *Coordinator) SetHandoff(dataDir string, wait time.Duration) {
+	c.handoffDir = dataDir
+	c.handoffWait = wait
+}
+
+// HandOff stops reading events from sprinkler, waits for those already received to
+// be processed, and leaves what's left for the next instance: the events still
+// unfinished, and the delivery IDs recently processed so the next instance skips
+// their redeliveries. The coordinator processes no further events.
+func (c *Coordinator) HandOff(ctx context.Context) (*state.Handoff, error) {
+	if c.handoffDir == "" {
+		return nil, errors.New("handoffs are not enabled")
+	}
+	if !c.draining.CompareAndSwap(false, true) {
+		return nil, errors.New("events were already handed off")
+	}
+	c.wsMu.Lock()
+	if c.wsConn != nil {
+		if err := c.wsConn.Close(); err != nil {
+			slog.Debug("failed to close WebSocket for handoff", "error", err)
+		}
+	}
+	c.wsMu.Unlock()
+
+	drainCtx, cancel := context.WithTimeout(ctx, handoffDrainTimeout)
+	defer cancel()
+	if !c.queue.waitIdle(drainCtx) {
+		slog.Warn("handing off before queued events finished", "timeout", handoffDrainTimeout)
+	}
+	return c.exportHandoff()
+}
+
+// handOffOnExit hands off what's left when the coordinator stops, unless it already has.
+func (c *Coordinator) handOffOnExit() {
+	if !c.draining.CompareAndSwap(false, true) {
+		return
+	}
+	if _, err := c.exportHandoff(); err != nil {
+		slog.Error("failed to hand off events on shutdown", "error", err)
+	}
+}
+
+// exportHandoff writes the handoff: queued events not yet started, then those
+// journaled as in flight, and the recently processed delivery IDs.
+func (c *Coordinator) exportHandoff() (*state.Handoff, error) {
+	h := &state.Handoff{At: c.clock.Now(), Seen: c.deliveries.snapshot()}
+	if c.journal != nil {
+		h.Pending = c.journal.Pending()
+	}
+	for _, msg := range c.queue.takeAll() {
+		if msg.DeliveryID == "" {
+			msg.DeliveryID = deriveDeliveryID(msg)
+		}
+		record, err := json.Marshal(msg)
+		if err != nil {
+			slog.Error("dropping queued event that can't be handed off", "event", msg.Event, "repo", msg.Repo, "error", err)
+			continue
+		}
+		h.Pending = append(h.Pending, state.JournalEntry{ID: msg.DeliveryID, Record: record})
+	}
+	// Unfinished events must be processed by the next instance, not skipped as seen.
+	for _, entry := range h.Pending {
+		delete(h.Seen, entry.ID)
+	}
+
+	if err := state.WriteHandoff(c.handoffDir, h); err != nil {
+		return nil, err
+	}
+	// The next instance replays them, so they're no longer this instance's to finish.
+	if c.journal != nil {
+		for _, entry := range h.Pending {
+			if err := c.journal.Complete(entry.ID); err != nil {
+				slog.Warn("failed to mark handed off event complete", "delivery_id", entry.ID, "error", err)
+			}
+		}
+	}
+	slog.Info("handed off events", "pending", len(h.Pending), "seen", len(h.Seen))
+	return h, nil
+}
+
+// importHandoff takes over from the previous instance, waiting up to handoffWait for
+// it to hand off: its recently processed deliveries are skipped if redelivered, and
+// its unfinished events are queued ahead of anything new.
+func (c *Coordinator) importHandoff(ctx context.Context) {
+	deadline := time.Now().Add(c.handoffWait)
+	for {
+		h, found, err := state.TakeHandoff(c.handoffDir)
+		if err != nil {
+			slog.Error("failed to take handoff, starting without it", "error", err)
+			return
+		}
+		if found {
+			c.resumeFrom(ctx, h)
+			return
+		}
+		if !time.Now().Before(deadline) {
+			if c.handoffWait > 0 {
+				slog.Warn("no handoff from a previous instance, starting without it", "waited", c.handoffWait)
+			}
+			return
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// resumeFrom applies a handoff taken from the previous instance.
+func (c *Coordinator) resumeFrom(ctx context.Context, h *state.Handoff) {
+	c.deliveries.seed(h.Seen, time.Now())
+	for _, entry := range h.Pending {
+		var msg SprinklerMessage
+		if err := json.Unmarshal(entry.Record, &msg); err != nil {
+			slog.Error("dropping unreadable handed off event", "delivery_id", entry.ID, "error", err)
+			continue
+		}
+		c.enqueue(ctx, msg)
+	}
+	slog.Info("took over events from previous instance", "handed_off", h.At, "pending", len(h.Pending), "seen", len(h.Seen))
+}
+
+// HandoffRoutes registers the admin endpoint a rollout calls on the old instance to
+// hand its event stream to the new one.
+func (c *Coordinator) HandoffRoutes(router *mux.Router) {
+	router.HandleFunc("/handoff", func(w http.ResponseWriter, r *http.Request) {
+		h, err := c.HandOff(r.Context())
+		if err != nil {
+			admin.WriteError(w, http.StatusConflict, err.Error())
+			return
+		}
+		admin.WriteJSON(w, http.StatusOK, map[string]any{"at": h.At, "pending": len(h.Pending), "seen": len(h.Seen)})
+	}).Methods("POST")
+}
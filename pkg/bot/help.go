@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"context"
+	"sort"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// ChannelRepos returns the repos, as owner/repo, whose PRs are posted to a channel.
+func (c *Coordinator) ChannelRepos(ctx context.Context, workspaceID, channelID string) []string {
+	return c.reposForChannel(workspaceID, c.channelKeys(ctx, workspaceID, channelID))
+}
+
+// reposForChannel returns the repos, as owner/repo, configured to post to a channel
+// known by any of keys.
+func (c *Coordinator) reposForChannel(workspaceID string, keys map[string]bool) []string {
+	seen := make(map[string]bool)
+	var repos []string
+	for _, org := range c.configManager.Orgs() {
+		if ws, ok := c.workspaceFor(org); !ok || ws != workspaceID {
+			continue
+		}
+		for key := range keys {
+			for _, channel := range []string{key, "#" + key} {
+				for _, repo := range c.configManager.GetReposForChannel(org, channel) {
+					if name := org + "/" + repo; !seen[name] {
+						seen[name] = true
+						repos = append(repos, name)
+					}
+				}
+			}
+		}
+	}
+	sort.Strings(repos)
+	return repos
+}
+
+// UserPRs returns the PRs on a user's dashboard in a workspace.
+func (c *Coordinator) UserPRs(_ context.Context, workspaceID, userID string) []*state.PRState {
+	return c.stateManager.GetUserPRs(workspaceID, userID)
+}
@@ -0,0 +1,13 @@
+package bot
+
+import "github.com/codeGROOVE-dev/slacker/pkg/state"
+
+// refreshHomes republishes the Home tab dashboards that list a PR after it changes.
+// Entries for GitHub logins rather than Slack users are skipped by the Slack client,
+// which only refreshes users who have opened the Home tab.
+func (c *Coordinator) refreshHomes(workspaceID string, pr *state.PRState) {
+	client := c.slackFor(workspaceID)
+	for _, userID := range c.stateManager.DashboardUsers(workspaceID, pr.Owner, pr.Repo, pr.Number) {
+		client.RefreshHome(userID)
+	}
+}
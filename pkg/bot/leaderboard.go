@@ -0,0 +1,59 @@
+package bot
+
+import (
+	"context"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+)
+
+// Leaderboard summarizes the reviews submitted on an org's PRs over the last 30 days,
+// if the org has opted in. Ranked boards order reviewers by reviews completed, then
+// by fastest median response; plain boards list them alphabetically.
+func (c *Coordinator) Leaderboard(_ context.Context, workspaceID, org string) (stats []slack.ReviewerStat, ranked, enabled bool) {
+	cfg := c.configManager.GetLeaderboard(org)
+	if !cfg.Enabled {
+		return nil, false, false
+	}
+
+	counts := make(map[string]int)
+	responses := make(map[string][]time.Duration)
+	for _, review := range c.stateManager.Snapshot(workspaceID).ReviewsFor(org, time.Now().Add(-slack.LeaderboardWindow)) {
+		counts[review.Reviewer]++
+		if review.Response > 0 {
+			responses[review.Reviewer] = append(responses[review.Reviewer], review.Response)
+		}
+	}
+
+	for login, n := range counts {
+		stats = append(stats, slack.ReviewerStat{Login: login, Reviews: n, MedianResponse: median(responses[login])})
+	}
+	ranked = !cfg.Plain
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i], stats[j]
+		if ranked && a.Reviews != b.Reviews {
+			return a.Reviews > b.Reviews
+		}
+		if ranked && a.MedianResponse != b.MedianResponse {
+			return a.MedianResponse < b.MedianResponse
+		}
+		return strings.ToLower(a.Login) < strings.ToLower(b.Login)
+	})
+	return stats, ranked, true
+}
+
+// median returns the median of durations, or zero if there are none.
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := slices.Sorted(slices.Values(durations))
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
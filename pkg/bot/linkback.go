@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// linkBack comments on the PR with a link to its Slack thread, once.
+func (c *Coordinator) linkBack(ctx context.Context, workspaceID string, pr *state.PRState) {
+	link, err := c.slackFor(workspaceID).Permalink(ctx, pr.ChannelID, pr.ThreadTS)
+	if err != nil {
+		slog.Warn("failed to link PR to its thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return
+	}
+	if err := c.githubFor(pr.Owner).Comment(ctx, pr.Owner, pr.Repo, pr.Number, "💬 Discussion: "+link); err != nil {
+		slog.Warn("failed to link PR to its thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return
+	}
+	pr.LinkedBack = true
+}
+
+// publishSlackCheck adds a check run to the PR's head commit that links to its Slack thread.
+func (c *Coordinator) publishSlackCheck(ctx context.Context, workspaceID string, pr *state.PRState, sha string) {
+	link, err := c.slackFor(workspaceID).Permalink(ctx, pr.ChannelID, pr.ThreadTS)
+	if err != nil {
+		slog.Warn("failed to publish Slack check", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return
+	}
+	if err := c.githubFor(pr.Owner).PublishSlackCheck(ctx, pr.Owner, pr.Repo, sha, link); err != nil {
+		slog.Warn("failed to publish Slack check", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return
+	}
+	pr.CheckSHA = sha
+}
@@ -0,0 +1,68 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+)
+
+// SyncAll re-resolves the state of every open PR in a workspace from GitHub. The
+// work continues in the background, since it outlasts a slash command's deadline.
+func (c *Coordinator) SyncAll(ctx context.Context, workspaceID string) string {
+	var open int
+	prs := c.stateManager.Snapshot(workspaceID).ListPRs()
+	for _, pr := range prs {
+		if isOpenState(pr.State) {
+			open++
+		}
+	}
+
+	go func(ctx context.Context) {
+		var failed int
+		for _, pr := range prs {
+			if !isOpenState(pr.State) {
+				continue
+			}
+			if err := c.resyncPR(ctx, workspaceID, pr); err != nil {
+				failed++
+				slog.Warn("failed to sync PR", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+			}
+		}
+		slog.Info("synced all PRs", "workspace", workspaceID, "prs", open, "failed", failed)
+	}(context.WithoutCancel(ctx))
+
+	return fmt.Sprintf("🔄 Syncing %d open PRs from GitHub in the background.", open)
+}
+
+// ReloadConfigs re-reads slack.yaml for every org routed to a workspace, in the background.
+func (c *Coordinator) ReloadConfigs(ctx context.Context, workspaceID string) string {
+	var orgs []string
+	for _, org := range c.configManager.Orgs() {
+		if ws, ok := c.workspaceFor(org); ok && ws == workspaceID {
+			orgs = append(orgs, org)
+		}
+	}
+	if len(orgs) == 0 {
+		return "No org configs are loaded for this workspace."
+	}
+	sort.Strings(orgs)
+
+	go func(ctx context.Context) {
+		for _, org := range orgs {
+			if err := c.configManager.ReloadConfig(ctx, org); err != nil {
+				slog.Warn("failed to reload config", "org", org, "error", err)
+			}
+		}
+	}(context.WithoutCancel(ctx))
+
+	return fmt.Sprintf("🔄 Reloading slack.yaml for %d orgs in the background.", len(orgs))
+}
+
+// ForgetUser deletes what the bot stores about a Slack user in a workspace.
+func (c *Coordinator) ForgetUser(_ context.Context, workspaceID, userID string) string {
+	if !c.stateManager.ForgetUser(workspaceID, userID) {
+		return fmt.Sprintf("Nothing is stored about <@%s>.", userID)
+	}
+	return fmt.Sprintf("🗑️ Forgot <@%s>: their preferences, PR associations, pending notifications, and reminders are deleted.", userID)
+}
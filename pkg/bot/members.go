@@ -0,0 +1,187 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/identity"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+const (
+	// memberSyncInterval is how often each org's members are fetched from GitHub.
+	memberSyncInterval = time.Hour
+	// memberStaleAfter is how long a synced member list is trusted once syncs start failing.
+	memberStaleAfter = 3 * memberSyncInterval
+	// permissionTTL is how long a user's permission on a repo is cached.
+	permissionTTL = time.Hour
+)
+
+// orgMembers is an org's member list as last synced.
+type orgMembers struct {
+	syncedAt time.Time
+	logins   map[string]bool   // Lowercased.
+	users    map[string]string // Slack user to GitHub login, for members whose Slack user is verified.
+}
+
+// cachedPermission is a user's permission on a repo, as last fetched.
+type cachedPermission struct {
+	fetchedAt  time.Time
+	permission string
+}
+
+// cachedTeam is a team's members, as last fetched.
+type cachedTeam struct {
+	fetchedAt time.Time
+	logins    []string
+}
+
+// memberCache holds synced org members, and fetched repo permissions and team members.
+type memberCache struct {
+	orgs  map[string]orgMembers
+	perms map[string]cachedPermission // By lowercased owner/repo|login.
+	teams map[string]cachedTeam       // By org/slug.
+	mu    sync.Mutex
+}
+
+func newMemberCache() *memberCache {
+	return &memberCache{
+		orgs:  make(map[string]orgMembers),
+		perms: make(map[string]cachedPermission),
+		teams: make(map[string]cachedTeam),
+	}
+}
+
+// RunMemberSync syncs each org's GitHub members, and the Slack users they map to,
+// until the context is cancelled.
+func (c *Coordinator) RunMemberSync(ctx context.Context) error {
+	c.syncMembers(ctx)
+
+	ticker := time.NewTicker(memberSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.syncMembers(ctx)
+		}
+	}
+}
+
+// syncMembers fetches the members of every configured org.
+func (c *Coordinator) syncMembers(ctx context.Context) {
+	for _, org := range c.configManager.Orgs() {
+		workspaceID, routed := c.workspaceFor(org)
+		if !routed {
+			continue
+		}
+		logins, err := c.githubFor(org).OrgMembers(ctx, org)
+		if err != nil {
+			slog.Warn("failed to sync org members", "org", org, "error", err)
+			metrics.IncCounter("slacker_member_syncs_total", "result", "error")
+			continue
+		}
+
+		synced := orgMembers{
+			syncedAt: c.clock.Now(),
+			logins:   make(map[string]bool, len(logins)),
+			users:    make(map[string]string),
+		}
+		for _, login := range logins {
+			synced.logins[strings.ToLower(login)] = true
+			if userID, found := c.verifiedSlackUser(ctx, workspaceID, org, login); found {
+				synced.users[userID] = login
+			}
+		}
+		c.members.mu.Lock()
+		c.members.orgs[org] = synced
+		c.members.mu.Unlock()
+
+		metrics.IncCounter("slacker_member_syncs_total", "result", "ok")
+		metrics.SetGauge("slacker_org_members", float64(len(logins)), "org", org)
+		slog.Debug("synced org members", "org", org, "members", len(logins), "slack_users", len(synced.users))
+	}
+}
+
+// orgMembers returns an org's synced members, if a recent enough sync succeeded.
+func (c *Coordinator) orgMembers(org string) (orgMembers, bool) {
+	c.members.mu.Lock()
+	defer c.members.mu.Unlock()
+
+	synced, exists := c.members.orgs[org]
+	if !exists || c.clock.Now().Sub(synced.syncedAt) > memberStaleAfter {
+		return orgMembers{}, false
+	}
+	return synced, true
+}
+
+// verifiedLoginFor returns the GitHub login of a Slack user as the org's slack.yaml,
+// an email match, or the last member sync has it, to decide what they may do. A login
+// users link themselves is never used, since anyone can claim any login that way.
+func (c *Coordinator) verifiedLoginFor(workspaceID, org, userID string) (string, bool) {
+	if login := c.configManager.GitHubLoginFor(org, userID); login != "" {
+		return login, true
+	}
+	if synced, ok := c.orgMembers(org); ok {
+		if login, found := synced.users[userID]; found {
+			return login, true
+		}
+	}
+	return c.stateManager.ResolvedGitHubLoginFor(workspaceID, userID, identity.SourceConfig, identity.SourceEmail)
+}
+
+// repoPermission returns a user's permission on a repo, cached for permissionTTL.
+func (c *Coordinator) repoPermission(ctx context.Context, owner, repo, login string) (string, error) {
+	key := strings.ToLower(owner + "/" + repo + "|" + login)
+	now := c.clock.Now()
+	c.members.mu.Lock()
+	cached, exists := c.members.perms[key]
+	c.members.mu.Unlock()
+	if exists && now.Sub(cached.fetchedAt) < permissionTTL {
+		return cached.permission, nil
+	}
+
+	permission, err := c.githubFor(owner).RepoPermission(ctx, owner, repo, login)
+	if err != nil {
+		return "", err
+	}
+	c.members.mu.Lock()
+	for k, p := range c.members.perms {
+		if now.Sub(p.fetchedAt) >= permissionTTL {
+			delete(c.members.perms, k)
+		}
+	}
+	c.members.perms[key] = cachedPermission{fetchedAt: now, permission: permission}
+	c.members.mu.Unlock()
+	return permission, nil
+}
+
+// canWrite checks that a Slack user is a GitHub org member with write access to a
+// repo before the bot acts on their behalf. It returns their login, or a reply
+// explaining why they may not.
+func (c *Coordinator) canWrite(ctx context.Context, workspaceID, userID, owner, repo string) (string, string) {
+	login, found := c.verifiedLoginFor(workspaceID, owner, userID)
+	if !found {
+		return "", "I can't verify your GitHub account. Make your Slack email the public email on your GitHub profile, or ask an org admin to add you to `users` in slack.yaml."
+	}
+	if synced, ok := c.orgMembers(owner); ok && !synced.logins[strings.ToLower(login)] {
+		slog.Info("refused PR action from non-member", "org", owner, "user", userID, "login", login)
+		return "", fmt.Sprintf("@%s isn't a member of the %s GitHub org.", login, owner)
+	}
+	permission, err := c.repoPermission(ctx, owner, repo, login)
+	if err != nil {
+		slog.Warn("failed to check repo permission", "owner", owner, "repo", repo, "login", login, "error", err)
+		return "", "I couldn't check your access to this repo on GitHub. Try again later."
+	}
+	if permission != "admin" && permission != "write" {
+		slog.Info("refused PR action without write access", "owner", owner, "repo", repo, "user", userID, "login", login)
+		return "", fmt.Sprintf("@%s doesn't have write access to %s/%s.", login, owner, repo)
+	}
+	return login, ""
+}
@@ -0,0 +1,124 @@
+package bot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// milestoneInterval is how often pinned milestone summaries are refreshed.
+const milestoneInterval = 10 * time.Minute
+
+// RunMilestoneSummaries keeps pinned milestone summaries current until the context is cancelled.
+func (c *Coordinator) RunMilestoneSummaries(ctx context.Context) error {
+	ticker := time.NewTicker(milestoneInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.refreshMilestones(ctx)
+		}
+	}
+}
+
+// refreshMilestones posts or updates the summary for each milestone in repos that enable them.
+func (c *Coordinator) refreshMilestones(ctx context.Context) {
+	for _, workspaceID := range c.workspaceIDs() {
+		c.refreshWorkspaceMilestones(ctx, workspaceID)
+	}
+}
+
+// refreshWorkspaceMilestones refreshes the milestone summaries of one workspace.
+func (c *Coordinator) refreshWorkspaceMilestones(ctx context.Context, workspaceID string) {
+	groups := make(map[string][]*state.PRState)
+	for _, pr := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+		if pr.Milestone == "" || !c.configManager.MilestoneSummariesEnabled(pr.Owner, pr.Repo) || c.stateManager.Paused(workspaceID, pr.Owner) {
+			continue
+		}
+		key := pr.Owner + "/" + pr.Repo + ":" + pr.Milestone
+		groups[key] = append(groups[key], pr)
+	}
+
+	for key, prs := range groups {
+		owner, repo := prs[0].Owner, prs[0].Repo
+		text := milestoneText(prs)
+		for _, channel := range c.configManager.GetChannelsForRepo(owner, repo) {
+			if c.channelMuted(workspaceID, channel) {
+				continue
+			}
+			if err := c.postMilestoneSummary(ctx, workspaceID, key+":"+channel, channel, text); err != nil {
+				slog.Warn("failed to update milestone summary", "milestone", key, "channel", channel, "error", err)
+			}
+		}
+	}
+}
+
+// postMilestoneSummary posts and pins a milestone summary on first use, and edits it when the text changes.
+func (c *Coordinator) postMilestoneSummary(ctx context.Context, workspaceID, key, channel, text string) error {
+	sum := sha256.Sum256([]byte(text))
+	hash := hex.EncodeToString(sum[:8])
+
+	summary, exists := c.stateManager.GetMilestoneSummary(workspaceID, key)
+	if exists {
+		if summary.Hash == hash {
+			return nil
+		}
+		if err := c.slackFor(workspaceID).UpdateMessage(ctx, summary.ChannelID, summary.TS, text); err != nil {
+			return err
+		}
+		summary.Hash = hash
+		c.stateManager.SetMilestoneSummary(workspaceID, key, summary)
+		return nil
+	}
+
+	channelID, ts, err := c.slackFor(workspaceID).PostThread(ctx, channel, text, nil)
+	if err != nil {
+		return err
+	}
+	c.stateManager.SetMilestoneSummary(workspaceID, key, state.MilestoneSummary{ChannelID: channelID, TS: ts, Hash: hash})
+	if err := c.slackFor(workspaceID).PinMessage(ctx, channelID, ts); err != nil {
+		slog.Warn("failed to pin milestone summary", "channel", channelID, "error", err)
+	}
+	return nil
+}
+
+// milestoneText formats a milestone's progress: merged, open, and blocked counts, then a line per open PR.
+func milestoneText(prs []*state.PRState) string {
+	sort.Slice(prs, func(i, j int) bool {
+		return prs[i].Number < prs[j].Number
+	})
+
+	var merged, open, blocked int
+	var lines []string
+	for _, pr := range prs {
+		switch {
+		case pr.State == "pray":
+			merged++
+			continue
+		case !isOpenState(pr.State):
+			continue
+		}
+		open++
+		line := fmt.Sprintf("%s <%s|#%d> %s by @%s", slack.StateEmoji(pr.State), githubPRURL(pr), pr.Number, pr.Title, pr.Author)
+		if len(pr.BlockedOn) > 0 {
+			blocked++
+			line += " • waiting on " + strings.Join(pr.BlockedOn, ", ")
+		}
+		lines = append(lines, line)
+	}
+
+	header := fmt.Sprintf("🏁 *%s* (%s/%s): %d merged • %d open • %d blocked",
+		prs[0].Milestone, prs[0].Owner, prs[0].Repo, merged, open, blocked)
+	return strings.Join(append([]string{header}, lines...), "\n")
+}
@@ -0,0 +1,225 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/admin"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/gorilla/mux"
+)
+
+// maxCatchUpLines bounds the PRs listed in one channel's catch-up summary.
+const maxCatchUpLines = 25
+
+// PausePosting stops posting to Slack for an org routed to a workspace, or for all of
+// its orgs with "all". Events are still processed and state kept current, so resuming
+// can catch up. With no args it lists the workspace's pauses.
+func (c *Coordinator) PausePosting(_ context.Context, workspaceID string, args []string) string {
+	if len(args) == 0 {
+		return c.describePauses(workspaceID)
+	}
+	org, reply := c.pauseTarget(workspaceID, args)
+	if reply != "" {
+		return reply
+	}
+	if !c.stateManager.Pause(workspaceID, org, c.clock.Now()) {
+		return fmt.Sprintf("Posting is already paused for %s.", pauseName(org))
+	}
+	slog.Info("paused posting", "workspace", workspaceID, "org", org)
+	return fmt.Sprintf("⏸️ Paused posting for %s. I'll keep tracking PRs and catch up when you resume.", pauseName(org))
+}
+
+// ResumePosting lifts a pause set by PausePosting and, in the background, posts what
+// happened while it was in effect.
+func (c *Coordinator) ResumePosting(ctx context.Context, workspaceID string, args []string) string {
+	org, reply := c.pauseTarget(workspaceID, args)
+	if reply != "" {
+		return reply
+	}
+	if !c.resume(ctx, workspaceID, org) {
+		return fmt.Sprintf("Posting isn't paused for %s.", pauseName(org))
+	}
+	return fmt.Sprintf("▶️ Resumed posting for %s. Catching up on what happened meanwhile.", pauseName(org))
+}
+
+// pauseTarget returns the org a pause or resume command names, or a reply explaining
+// why it can't be paused from this workspace.
+func (c *Coordinator) pauseTarget(workspaceID string, args []string) (org, reply string) {
+	if len(args) != 1 {
+		return "", "Usage: `pause <org>`, `pause all`, `resume <org>`, or `resume all`"
+	}
+	if strings.EqualFold(args[0], "all") {
+		return state.AllOrgs, ""
+	}
+	if ws, routed := c.workspaceFor(args[0]); !routed || ws != workspaceID {
+		return "", fmt.Sprintf("%s isn't routed to this workspace.", args[0])
+	}
+	return args[0], ""
+}
+
+// pauseName names a paused org for a reply.
+func pauseName(org string) string {
+	if org == state.AllOrgs {
+		return "all orgs"
+	}
+	return org
+}
+
+// describePauses lists a workspace's pauses.
+func (c *Coordinator) describePauses(workspaceID string) string {
+	pauses := c.stateManager.Pauses(workspaceID)
+	if len(pauses) == 0 {
+		return "Posting isn't paused."
+	}
+	lines := []string{"⏸️ Posting is paused for:"}
+	for _, org := range slices.Sorted(maps.Keys(pauses)) {
+		lines = append(lines, fmt.Sprintf("• %s, since %s", pauseName(org), slack.FormatTime(pauses[org], slack.DateTime)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// resume lifts a pause and catches up in the background, reporting false if there was no pause.
+func (c *Coordinator) resume(ctx context.Context, workspaceID, org string) bool {
+	since, paused := c.stateManager.Resume(workspaceID, org)
+	if !paused {
+		return false
+	}
+	slog.Info("resumed posting", "workspace", workspaceID, "org", org, "paused_for", c.clock.Now().Sub(since).Round(time.Second))
+	go c.catchUp(context.WithoutCancel(ctx), workspaceID, org, since)
+	return true
+}
+
+// catchUp posts what happened to an org's PRs, or to all of the workspace's with
+// state.AllOrgs, since posting was paused: PRs opened meanwhile get their threads,
+// the threads of PRs that changed are brought up to date, and each channel gets a
+// summary listing them. PRs still covered by another pause are left for its resume.
+func (c *Coordinator) catchUp(ctx context.Context, workspaceID, org string, since time.Time) {
+	now := c.clock.Now()
+	away := c.stateManager.AwayLogins(workspaceID, now)
+	lines := make(map[string][]string)
+
+	prs := c.stateManager.Snapshot(workspaceID).ListPRs()
+	sort.Slice(prs, func(i, j int) bool { return prs[i].CreatedAt.Before(prs[j].CreatedAt) })
+	for _, pr := range prs {
+		if (org != state.AllOrgs && pr.Owner != org) || pr.LastUpdated.Before(since) || c.stateManager.Paused(workspaceID, pr.Owner) {
+			continue
+		}
+
+		before, existed := stateAt(pr, since)
+		var note string
+		switch {
+		case pr.ThreadTS == "" && isOpenState(pr.State) && !pr.CreatedAt.Before(since):
+			payload := prPayload{Title: pr.Title, HTMLURL: githubPRURL(pr)}
+			payload.User.Login = pr.Author
+			c.startThread(ctx, workspaceID, pr, c.configManager.GetChannelsForRepo(pr.Owner, pr.Repo), payload)
+			if pr.ThreadTS == "" {
+				continue
+			}
+			c.stateManager.SetPRState(workspaceID, pr)
+			note = "🆕 "
+		case pr.ThreadTS == "":
+			continue
+		default:
+			if err := c.showThreadState(ctx, workspaceID, pr, pr.State); err != nil {
+				slog.Warn("failed to catch up PR thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+			}
+			if existed && before.State == pr.State {
+				continue
+			}
+		}
+		c.markTopic(workspaceID, pr)
+
+		line := note + formatPRLine(pr, c.thresholds(pr.Owner), now, away)
+		if existed && before.State != pr.State {
+			line += " (was " + slack.StateEmoji(before.State) + ")"
+		}
+		lines[pr.ChannelID] = append(lines[pr.ChannelID], line)
+	}
+
+	header := fmt.Sprintf("▶️ Posting resumed. Since it was paused %s:", slack.FormatTime(since, slack.DateTime))
+	for channelID, prLines := range lines {
+		if len(prLines) > maxCatchUpLines {
+			more := len(prLines) - maxCatchUpLines
+			prLines = append(prLines[:maxCatchUpLines], fmt.Sprintf("…and %d more", more))
+		}
+		text := strings.Join(append([]string{header}, prLines...), "\n")
+		if _, _, err := c.slackFor(workspaceID).PostThread(ctx, channelID, text, nil); err != nil {
+			slog.Warn("failed to post catch-up summary", "channel", channelID, "error", err)
+		}
+	}
+	slog.Info("caught up after pause", "workspace", workspaceID, "org", org, "channels", len(lines))
+}
+
+// PauseRoutes registers the admin endpoints that pause and resume posting. An org
+// of "all" pauses or resumes every workspace.
+func (c *Coordinator) PauseRoutes(router *mux.Router) {
+	router.HandleFunc("/pauses", c.listPauses).Methods("GET")
+	router.HandleFunc("/pauses/{org}", c.putPause).Methods("PUT")
+	router.HandleFunc("/pauses/{org}", c.deletePause).Methods("DELETE")
+}
+
+// pauseScope returns the workspaces and org a pause endpoint applies to.
+func (c *Coordinator) pauseScope(r *http.Request) (workspaces []string, org string, ok bool) {
+	org = mux.Vars(r)["org"]
+	if org == "all" {
+		return c.workspaceIDs(), state.AllOrgs, true
+	}
+	workspaceID, routed := c.workspaceFor(org)
+	if !routed {
+		return nil, "", false
+	}
+	return []string{workspaceID}, org, true
+}
+
+// listPauses lists the pauses in effect, by workspace and org.
+func (c *Coordinator) listPauses(w http.ResponseWriter, _ *http.Request) {
+	pauses := make(map[string]map[string]time.Time)
+	for _, workspaceID := range c.workspaceIDs() {
+		if p := c.stateManager.Pauses(workspaceID); len(p) > 0 {
+			pauses[workspaceID] = p
+		}
+	}
+	admin.WriteJSON(w, http.StatusOK, map[string]any{"pauses": pauses})
+}
+
+// putPause pauses posting for an org, or for every org with "all".
+func (c *Coordinator) putPause(w http.ResponseWriter, r *http.Request) {
+	workspaces, org, ok := c.pauseScope(r)
+	if !ok {
+		admin.WriteError(w, http.StatusNotFound, "org is not routed to a workspace")
+		return
+	}
+	var paused []string
+	for _, workspaceID := range workspaces {
+		if c.stateManager.Pause(workspaceID, org, c.clock.Now()) {
+			slog.Info("paused posting", "workspace", workspaceID, "org", org)
+			paused = append(paused, workspaceID)
+		}
+	}
+	admin.WriteJSON(w, http.StatusOK, map[string]any{"org": org, "paused": paused})
+}
+
+// deletePause resumes posting for an org, or for every org with "all", and catches up in the background.
+func (c *Coordinator) deletePause(w http.ResponseWriter, r *http.Request) {
+	workspaces, org, ok := c.pauseScope(r)
+	if !ok {
+		admin.WriteError(w, http.StatusNotFound, "org is not routed to a workspace")
+		return
+	}
+	var resumed []string
+	for _, workspaceID := range workspaces {
+		if c.resume(r.Context(), workspaceID, org) {
+			resumed = append(resumed, workspaceID)
+		}
+	}
+	admin.WriteJSON(w, http.StatusOK, map[string]any{"org": org, "resumed": resumed})
+}
@@ -0,0 +1,48 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// isUrgent reports whether an open PR carries one of the org's urgent labels.
+func (c *Coordinator) isUrgent(pr *state.PRState, labels []string) bool {
+	if !isOpenState(pr.State) {
+		return false
+	}
+	urgent := c.configManager.GetUrgentLabels(pr.Owner)
+	return slices.ContainsFunc(labels, func(label string) bool {
+		return slices.ContainsFunc(urgent, func(u string) bool {
+			return strings.EqualFold(u, label)
+		})
+	})
+}
+
+// updatePin pins an urgent PR's thread so it stays visible above the channel's
+// scroll, and unpins it once the PR is no longer urgent. Stacked PRs share their
+// root's thread, which is left to the root.
+func (c *Coordinator) updatePin(ctx context.Context, workspaceID string, pr *state.PRState, urgent bool) {
+	if pr.ThreadTS == "" || pr.StackRoot != 0 || pr.Pinned == urgent {
+		return
+	}
+
+	client := c.slackFor(workspaceID)
+	if urgent {
+		if err := client.PinMessage(ctx, pr.ChannelID, pr.ThreadTS); err != nil {
+			slog.Warn("failed to pin urgent PR thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+			return
+		}
+		slog.Info("pinned urgent PR thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number)
+	} else {
+		if err := client.UnpinMessage(ctx, pr.ChannelID, pr.ThreadTS); err != nil {
+			slog.Warn("failed to unpin PR thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+			return
+		}
+		slog.Info("unpinned resolved PR thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number)
+	}
+	pr.Pinned = urgent
+}
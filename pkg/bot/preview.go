@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/command"
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// PreviewPR renders the thread message and notification DM the PR named by ref
+// would get with the org's current config, if the org is routed to the workspace.
+// Nothing is posted or recorded.
+func (c *Coordinator) PreviewPR(ctx context.Context, workspaceID, ref string) string {
+	parsed, err := command.ParsePR(ref, nil)
+	if err != nil {
+		return err.Error() + "."
+	}
+	owner, repo, number := parsed.Owner, parsed.Repo, parsed.Number
+	if ws, routed := c.workspaceFor(owner); !routed || ws != workspaceID {
+		return fmt.Sprintf("%s isn't routed to this workspace.", owner)
+	}
+
+	pr, err := c.previewState(ctx, workspaceID, owner, repo, number)
+	if err != nil {
+		return fmt.Sprintf("Couldn't load %s/%s#%d: %v", owner, repo, number, err)
+	}
+
+	mode := c.configManager.GetReactionMode(owner)
+	lines := []string{
+		fmt.Sprintf("*Preview for %s/%s#%d* (state: %s)", owner, repo, number, stateOrUnknown(pr.State)),
+		"",
+		"*Thread message:*",
+		threadText(c.configManager.GetPrefix(owner), pr, githubPRURL(pr), mode, pr.State),
+	}
+	if mode != config.ReactionsNone && pr.State != "" {
+		lines = append(lines, fmt.Sprintf("with reaction :%s: (reaction mode: %s)", pr.State, mode))
+	}
+	if channels := c.configManager.GetChannelsForRepo(owner, repo); len(channels) > 0 {
+		lines = append(lines, "posted to #"+strings.Join(channels, ", #"))
+	} else {
+		lines = append(lines, "not posted: no channels are configured for this repo")
+	}
+
+	lines = append(lines, "", "*Notification DM:*", c.notifier.FormatNotification(pr))
+	if len(pr.BlockedOn) > 0 {
+		lines = append(lines, "sent to "+strings.Join(pr.BlockedOn, ", "))
+	} else {
+		lines = append(lines, "not sent: the PR is not blocked on anyone")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// LintConfig validates an org's slack.yaml, if the org is routed to the workspace.
+func (c *Coordinator) LintConfig(ctx context.Context, workspaceID, org string) error {
+	if ws, routed := c.workspaceFor(org); !routed || ws != workspaceID {
+		return slack.ErrNotRouted
+	}
+	return c.configManager.LintConfig(ctx, org)
+}
+
+// previewState returns the tracked state of a PR, or builds it from GitHub when the PR is not tracked.
+func (c *Coordinator) previewState(ctx context.Context, workspaceID, owner, repo string, number int) (*state.PRState, error) {
+	if pr, exists := c.stateManager.GetPRState(workspaceID, owner, repo, number); exists {
+		return pr, nil
+	}
+
+	ghPR, err := c.githubFor(owner).GetPR(ctx, owner, repo, number)
+	if err != nil {
+		return nil, err
+	}
+	pr := &state.PRState{
+		Owner:  owner,
+		Repo:   repo,
+		Number: number,
+		Title:  ghPR.GetTitle(),
+		Author: ghPR.GetUser().GetLogin(),
+	}
+	if status, err := c.prState(ctx, owner, repo, number); err == nil {
+		pr.State = status.State
+		pr.BlockedOn = status.BlockedOn
+	}
+	return pr, nil
+}
+
+// stateOrUnknown names a PR state for display.
+func stateOrUnknown(prState string) string {
+	if prState == "" {
+		return "unknown"
+	}
+	return prState
+}
@@ -0,0 +1,365 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// prPayload is the subset of a webhook pull_request object used by the bot.
+type prPayload struct {
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	User      struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	Milestone *struct {
+		Title string `json:"title"`
+	} `json:"milestone"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	Head struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	} `json:"head"`
+	Base struct {
+		Ref string `json:"ref"`
+	} `json:"base"`
+	Title        string `json:"title"`
+	Body         string `json:"body"`
+	HTMLURL      string `json:"html_url"`
+	Number       int    `json:"number"`
+	Additions    int    `json:"additions"`
+	Deletions    int    `json:"deletions"`
+	ChangedFiles int    `json:"changed_files"`
+}
+
+// milestoneTitle returns the PR's milestone title, or "" if it has none.
+func (p *prPayload) milestoneTitle() string {
+	if p.Milestone == nil {
+		return ""
+	}
+	return p.Milestone.Title
+}
+
+// labelNames returns the names of the PR's labels.
+func (p *prPayload) labelNames() []string {
+	names := make([]string, 0, len(p.Labels))
+	for _, label := range p.Labels {
+		names = append(names, label.Name)
+	}
+	return names
+}
+
+// handlePullRequestEvent handles pull request events.
+func (c *Coordinator) handlePullRequestEvent(ctx context.Context, ev *Event) error {
+	owner, repo := ev.Owner, ev.Repo
+
+	var event struct {
+		Action      string    `json:"action"`
+		Before      string    `json:"before"`
+		After       string    `json:"after"`
+		PullRequest prPayload `json:"pull_request"`
+		Number      int       `json:"number"`
+	}
+
+	if err := json.Unmarshal(ev.Payload, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal PR event: %w", err)
+	}
+
+	slog.Info("PR event", "owner", owner, "repo", repo, "number", event.Number, "action", event.Action)
+
+	workspaceID, routed := c.workspaceFor(owner)
+	if !routed {
+		slog.Debug("org not routed to a workspace", "owner", owner)
+		return nil
+	}
+
+	// Get channels for this repo. A PR that already has a thread keeps it up to date
+	// without any, such as one whose repo was renamed before a restart.
+	channels := c.configManager.GetChannelsForRepo(owner, repo)
+	if len(channels) == 0 {
+		if known, exists := c.stateManager.GetPRState(workspaceID, owner, repo, event.Number); !exists || known.ThreadTS == "" {
+			slog.Debug("no channels configured", "owner", owner, "repo", repo)
+			return nil
+		}
+	}
+
+	// Get PR state.
+	status, err := c.prState(ctx, owner, repo, event.Number)
+	if err != nil {
+		slog.Warn("failed to get PR state", "error", err)
+		return nil
+	}
+	prState, blockedOn := status.State, status.BlockedOn
+
+	// Update or create PR state.
+	pr := &state.PRState{
+		Owner:                   owner,
+		Repo:                    repo,
+		Number:                  event.Number,
+		Title:                   event.PullRequest.Title,
+		Author:                  event.PullRequest.User.Login,
+		CreatedAt:               event.PullRequest.CreatedAt,
+		UpdatedAt:               event.PullRequest.UpdatedAt,
+		State:                   prState,
+		HeadRef:                 event.PullRequest.Head.Ref,
+		BaseRef:                 event.PullRequest.Base.Ref,
+		Milestone:               event.PullRequest.milestoneTitle(),
+		BlockedOn:               blockedOn,
+		LastUpdated:             time.Now(),
+		ChangesRequestedBy:      status.ChangesRequestedBy,
+		Uncertain:               status.Incomplete,
+		UnresolvedConversations: status.UnresolvedConversations,
+		Large:                   c.isLarge(owner, event.PullRequest),
+		Backport:                c.wantsBackport(owner, event.PullRequest.labelNames()),
+		BackportOf:              backportOf(event.PullRequest),
+	}
+
+	// Check if we already have a thread for this PR.
+	var previousState string
+	var previouslyBlocked []string
+	existingPR, exists := c.stateManager.GetPRState(workspaceID, owner, repo, event.Number)
+	if exists {
+		previousState = existingPR.State
+		previouslyBlocked = existingPR.BlockedOn
+		pr.ThreadTS = existingPR.ThreadTS
+		pr.ChannelID = existingPR.ChannelID
+		pr.ThreadHashes = existingPR.ThreadHashes
+		pr.ThreadReplies = existingPR.ThreadReplies
+		pr.StackRoot = existingPR.StackRoot
+		pr.Pinned = existingPR.Pinned
+		pr.ClaimedBy = existingPR.ClaimedBy
+		pr.ClaimedAt = existingPR.ClaimedAt
+		pr.StateChanges = existingPR.StateChanges
+		pr.LinkedBack = existingPR.LinkedBack
+		pr.CheckSHA = existingPR.CheckSHA
+		pr.Subscribers = existingPR.Subscribers
+		pr.DeferredBy = existingPR.DeferredBy
+		pr.DeferredAt = existingPR.DeferredAt
+	}
+
+	// While posting is paused, keep the state current for the catch-up but post nothing.
+	if c.stateManager.Paused(workspaceID, owner) {
+		slog.Debug("posting paused, recording PR state only", "owner", owner, "repo", repo, "number", event.Number)
+		c.recordStateChange(pr)
+		c.stateManager.SetPRState(workspaceID, pr)
+		c.emitStateChange(ctx, workspaceID, pr, previousState, previouslyBlocked)
+		return nil
+	}
+
+	// Handle based on action.
+	switch event.Action {
+	case "opened", "reopened":
+		c.startThread(ctx, workspaceID, pr, channels, event.PullRequest)
+
+	case "closed":
+		// Update state in existing thread.
+		if pr.ThreadTS != "" {
+			if err := c.showThreadState(ctx, workspaceID, pr, prState); err != nil {
+				slog.Warn("failed to update reaction", "error", err)
+			}
+			if prState == "pray" {
+				c.postFollowUps(ctx, workspaceID, pr)
+			}
+		}
+
+	case "synchronize", "edited":
+		if event.Action == "synchronize" && pr.ThreadTS != "" && event.Before != "" && event.After != "" {
+			c.notifyForcePush(ctx, workspaceID, owner, repo, pr, event.Before, event.After)
+		}
+		if event.Action == "synchronize" && event.Before != "" && event.After != "" && isOpenState(pr.State) {
+			c.rerequestStaleApprovals(ctx, workspaceID, pr, status.Approvers, event.Before, event.After)
+		}
+		// Update state.
+		if pr.ThreadTS != "" {
+			if err := c.showThreadState(ctx, workspaceID, pr, prState); err != nil {
+				slog.Warn("failed to update reaction", "error", err)
+			}
+		}
+	default:
+		// Other PR actions are not handled
+		slog.Debug("unhandled PR action", "action", event.Action)
+	}
+
+	// Warn once when a PR grows past the size thresholds.
+	if pr.Large && (!exists || !existingPR.Large) && pr.ThreadTS != "" && isOpenState(pr.State) {
+		c.warnLargePR(ctx, workspaceID, pr, event.PullRequest)
+	}
+
+	// Point people reading the PR on GitHub to its thread.
+	if pr.ThreadTS != "" && !pr.LinkedBack && c.configManager.ThreadLinksEnabled(owner) {
+		c.linkBack(ctx, workspaceID, pr)
+	}
+
+	// List the thread in the PR's checks, once per head commit.
+	sha := event.PullRequest.Head.SHA
+	if pr.ThreadTS != "" && sha != "" && pr.CheckSHA != sha && isOpenState(pr.State) && c.configManager.SlackCheckEnabled(owner) {
+		c.publishSlackCheck(ctx, workspaceID, pr, sha)
+	}
+
+	// Thread a backport's progress under the PR it backports.
+	if pr.BackportOf != 0 && pr.State != previousState {
+		c.reportBackport(ctx, workspaceID, pr)
+	}
+
+	// Keep urgent PRs pinned until they are resolved or lose their urgent label.
+	c.updatePin(ctx, workspaceID, pr, c.isUrgent(pr, event.PullRequest.labelNames()))
+
+	// Save PR state.
+	c.recordStateChange(pr)
+	c.stateManager.SetPRState(workspaceID, pr)
+	c.emitStateChange(ctx, workspaceID, pr, previousState, previouslyBlocked)
+
+	return nil
+}
+
+// startThread gives a new PR a thread: the thread of the stack it builds on, or a new
+// one in the first of channels that hooks leave it and that isn't muted.
+func (c *Coordinator) startThread(ctx context.Context, workspaceID string, pr *state.PRState, channels []string, payload prPayload) {
+	// Stacked PRs join the thread of the PR they build on.
+	if pr.ThreadTS == "" {
+		c.joinStack(ctx, workspaceID, pr)
+	}
+	// Hooks may reroute or suppress the PR's thread.
+	if pr.ThreadTS == "" {
+		channels = c.hooks.RunPROpened(ctx, pr, channels)
+	}
+	// Create threads in configured channels.
+	for _, channel := range channels {
+		if pr.ThreadTS != "" {
+			continue
+		}
+		if c.channelMuted(workspaceID, channel) {
+			slog.Debug("channel muted, not posting PR", "channel", channel)
+			continue
+		}
+		// Create new thread.
+		channelID, threadTS, err := c.createPRThread(ctx, workspaceID, channel, pr, payload)
+		if err != nil {
+			slog.Warn("failed to create thread", "channel", channel, "error", err)
+			continue
+		}
+		pr.ThreadTS = threadTS
+		pr.ChannelID = channelID
+		slog.Info("created thread", "channel", channel, "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number)
+	}
+}
+
+// notifyForcePush posts a thread note when a push rewrote the PR's history,
+// since earlier line comments may no longer point at the right code.
+func (c *Coordinator) notifyForcePush(ctx context.Context, workspaceID, owner, repo string, pr *state.PRState, before, after string) {
+	forced, err := c.githubFor(owner).IsForcePush(ctx, owner, repo, before, after)
+	if err != nil {
+		// Without a comparison we cannot tell, so stay quiet rather than guess.
+		slog.Debug("unable to compare pushed commits", "owner", owner, "repo", repo, "number", pr.Number, "error", err)
+		return
+	}
+	if !forced {
+		return
+	}
+
+	message := fmt.Sprintf("⚠️ Force push rewrote history (`%s` → `%s`). Earlier line comments may be outdated.",
+		shortSHA(before), shortSHA(after))
+	if err := c.notifier.SendThreadUpdate(ctx, workspaceID, pr, message); err != nil {
+		slog.Warn("failed to send force push note", "error", err)
+	}
+}
+
+// shortSHA abbreviates a commit SHA for display.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// createPRThread creates a new thread in Slack for a PR, showing the state the caller
+// already determined, and returns the channel ID and thread timestamp.
+func (c *Coordinator) createPRThread(ctx context.Context, workspaceID, channel string, pr *state.PRState, payload prPayload) (channelID, threadTS string, err error) {
+	mode := c.configManager.GetReactionMode(pr.Owner)
+
+	// Without reactions, the state is shown in the message itself.
+	prState := pr.State
+	text := threadText(c.configManager.GetPrefix(pr.Owner), pr, payload.HTMLURL, mode, prState)
+
+	// Create thread.
+	client := c.slackFor(workspaceID)
+	channelID, threadTS, err = client.PostThread(ctx, channel, text, slack.ThreadAttachments(prState, c.frozen(pr.Owner)))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to post thread: %w", err)
+	}
+
+	// Add initial reaction based on state.
+	if prState != "" && mode != config.ReactionsNone {
+		if err := client.UpdateReactions(ctx, channelID, threadTS, prState); err != nil {
+			slog.Warn("failed to add initial reaction", "error", err)
+		}
+	}
+
+	return channelID, threadTS, nil
+}
+
+// showThreadState shows a PR's new state on its thread in the org's configured reaction mode,
+// and recolors the parent message's attachment bar. For stacks, the shared parent message's
+// status lines are refreshed as well.
+func (c *Coordinator) showThreadState(ctx context.Context, workspaceID string, pr *state.PRState, newState string) error {
+	if pr.StackRoot != 0 {
+		// The parent message and its reactions belong to the stack's root.
+		return c.refreshStack(ctx, workspaceID, pr)
+	}
+	stacked := len(c.stackMembers(workspaceID, pr)) > 0
+
+	var err error
+	mode := c.configManager.GetReactionMode(pr.Owner)
+	switch mode {
+	case config.ReactionsAccumulate:
+		err = c.notifier.AddThreadReaction(ctx, workspaceID, pr, newState)
+	case config.ReactionsNone:
+		// The state is shown in the message itself.
+	default:
+		err = c.notifier.UpdateThreadReaction(ctx, workspaceID, pr, newState)
+	}
+	if err != nil {
+		return err
+	}
+	if stacked {
+		return c.refreshStack(ctx, workspaceID, pr)
+	}
+	text := threadText(c.configManager.GetPrefix(pr.Owner), pr, githubPRURL(pr), mode, newState)
+	return c.notifier.EditThreadMessage(ctx, workspaceID, pr, text, newState)
+}
+
+// githubPRURL returns the GitHub URL for a PR.
+func githubPRURL(pr *state.PRState) string {
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d", pr.Owner, pr.Repo, pr.Number)
+}
+
+// threadText formats a PR thread's parent message. When reactions are off the
+// state is shown as an emoji after the prefix.
+func threadText(prefix string, pr *state.PRState, url, mode, prState string) string {
+	if mode == config.ReactionsNone && prState != "" {
+		prefix += " " + slack.StateEmoji(prState)
+	}
+	text := fmt.Sprintf(
+		"%s %s • <%s|%s/%s#%d> by @%s",
+		prefix,
+		pr.Title,
+		url,
+		pr.Owner,
+		pr.Repo,
+		pr.Number,
+		pr.Author,
+	)
+	if conversations := slack.FormatConversations(pr.UnresolvedConversations); conversations != "" && isOpenState(prState) {
+		text += " • " + conversations
+	}
+	return text
+}
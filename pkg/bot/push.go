@@ -0,0 +1,22 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// handlePushEvent handles push events, reloading org config when the .github repo changes.
+func (c *Coordinator) handlePushEvent(ctx context.Context, ev *Event) error {
+	if ev.Repo == ".github" {
+		c.handleConfigUpdate(ctx, ev.Owner)
+	}
+	return nil
+}
+
+// handleConfigUpdate handles updates to org config.
+func (c *Coordinator) handleConfigUpdate(ctx context.Context, owner string) {
+	slog.Info("reloading config", "org", owner)
+	if err := c.configManager.ReloadConfig(ctx, owner); err != nil {
+		slog.Warn("failed to reload config", "error", err)
+	}
+}
@@ -0,0 +1,216 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+const (
+	defaultWorkers   = 10
+	defaultQueueSize = 100
+)
+
+// SetConcurrency sets how many events are processed at once, how many may wait,
+// and how many workers a single org may occupy. It must be called before Run.
+func (c *Coordinator) SetConcurrency(workers, queueSize, workersPerOrg int) {
+	if workers > 0 {
+		c.workers = workers
+	}
+	if queueSize > 0 {
+		c.queue.capacity = queueSize
+	}
+	c.queue.maxPerOrg = max(1, c.workers/2)
+	if workersPerOrg > 0 {
+		c.queue.maxPerOrg = workersPerOrg
+	}
+}
+
+// startWorkers launches the pool that processes queued events until the context is cancelled.
+func (c *Coordinator) startWorkers(ctx context.Context) {
+	metrics.SetGauge("slacker_event_queue_capacity", float64(c.queue.capacity))
+	for range c.workers {
+		go func() {
+			for {
+				org, msg, ok := c.queue.pop(ctx)
+				if !ok {
+					return
+				}
+				metrics.AddGauge("slacker_event_workers_busy", 1, "org", org)
+				if err := c.processEvent(ctx, msg); err != nil {
+					slog.Error("error processing event", "error", err, "event", msg.Event)
+				}
+				metrics.AddGauge("slacker_event_workers_busy", -1, "org", org)
+				c.queue.done(org)
+			}
+		}()
+	}
+}
+
+// enqueue queues an event for processing. When the queue is full it blocks, which stops
+// reads from the sprinkler socket and lets TCP flow control push back on the sender
+// instead of buffering unbounded goroutines.
+func (c *Coordinator) enqueue(ctx context.Context, msg SprinklerMessage) {
+	org, _, _ := strings.Cut(msg.Repo, "/")
+	c.queue.push(ctx, org, msg)
+}
+
+// fairQueue holds events per org and hands them to workers round-robin across orgs,
+// capping how many workers any one org may occupy, so a noisy org can't starve others.
+type fairQueue struct {
+	queues    map[string][]SprinklerMessage
+	inflight  map[string]int
+	changed   chan struct{} // Closed and replaced whenever the queue changes.
+	orgs      []string      // Orgs with queued events, in round-robin order.
+	next      int
+	size      int
+	capacity  int
+	maxPerOrg int
+	mu        sync.Mutex
+}
+
+func newFairQueue(capacity, maxPerOrg int) *fairQueue {
+	return &fairQueue{
+		queues:    make(map[string][]SprinklerMessage),
+		inflight:  make(map[string]int),
+		changed:   make(chan struct{}),
+		capacity:  capacity,
+		maxPerOrg: maxPerOrg,
+	}
+}
+
+// broadcastLocked wakes every waiter (must hold lock).
+func (q *fairQueue) broadcastLocked() {
+	close(q.changed)
+	q.changed = make(chan struct{})
+}
+
+// push adds an event, blocking while the queue is full. It returns false if the context ends first.
+func (q *fairQueue) push(ctx context.Context, org string, msg SprinklerMessage) bool {
+	var blockedSince time.Time
+	for {
+		q.mu.Lock()
+		if q.size < q.capacity {
+			if _, exists := q.queues[org]; !exists {
+				q.orgs = append(q.orgs, org)
+			}
+			q.queues[org] = append(q.queues[org], msg)
+			q.size++
+			metrics.SetGauge("slacker_event_queue_depth", float64(q.size))
+			metrics.SetGauge("slacker_event_queue_org_depth", float64(len(q.queues[org])), "org", org)
+			q.broadcastLocked()
+			q.mu.Unlock()
+			if !blockedSince.IsZero() {
+				metrics.Observe("slacker_event_backpressure_seconds", time.Since(blockedSince).Seconds())
+			}
+			return true
+		}
+		changed := q.changed
+		q.mu.Unlock()
+
+		if blockedSince.IsZero() {
+			blockedSince = time.Now()
+			slog.Warn("event queue full, applying backpressure", "capacity", q.capacity, "org", org, "event", msg.Event)
+			metrics.IncCounter("slacker_event_queue_full_total", "org", org)
+		}
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// pop returns the next event from the next org in round-robin order that is under its worker cap.
+// It blocks until one is available, returning false if the context ends first.
+func (q *fairQueue) pop(ctx context.Context) (string, SprinklerMessage, bool) {
+	for {
+		q.mu.Lock()
+		for i := range q.orgs {
+			idx := (q.next + i) % len(q.orgs)
+			org := q.orgs[idx]
+			if q.inflight[org] >= q.maxPerOrg {
+				continue
+			}
+
+			msg := q.queues[org][0]
+			q.queues[org] = q.queues[org][1:]
+			if len(q.queues[org]) == 0 {
+				delete(q.queues, org)
+				q.orgs = append(q.orgs[:idx], q.orgs[idx+1:]...)
+				q.next = idx
+			} else {
+				q.next = idx + 1
+			}
+			q.inflight[org]++
+			q.size--
+			metrics.SetGauge("slacker_event_queue_depth", float64(q.size))
+			metrics.SetGauge("slacker_event_queue_org_depth", float64(len(q.queues[org])), "org", org)
+			q.broadcastLocked()
+			q.mu.Unlock()
+			return org, msg, true
+		}
+		changed := q.changed
+		q.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return "", SprinklerMessage{}, false
+		}
+	}
+}
+
+// done records that a worker finished an org's event.
+func (q *fairQueue) done(org string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	q.inflight[org]--
+	if q.inflight[org] <= 0 {
+		delete(q.inflight, org)
+	}
+	q.broadcastLocked()
+}
+
+// waitIdle blocks until no events are queued or being processed, returning false if the context ends first.
+func (q *fairQueue) waitIdle(ctx context.Context) bool {
+	for {
+		q.mu.Lock()
+		idle := q.size == 0 && len(q.inflight) == 0
+		changed := q.changed
+		q.mu.Unlock()
+		if idle {
+			return true
+		}
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+}
+
+// takeAll removes and returns every queued event, each org's in arrival order.
+func (q *fairQueue) takeAll() []SprinklerMessage {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var msgs []SprinklerMessage
+	for _, org := range q.orgs {
+		msgs = append(msgs, q.queues[org]...)
+		metrics.SetGauge("slacker_event_queue_org_depth", 0, "org", org)
+	}
+	q.queues = make(map[string][]SprinklerMessage)
+	q.orgs = nil
+	q.next = 0
+	q.size = 0
+	metrics.SetGauge("slacker_event_queue_depth", 0)
+	q.broadcastLocked()
+	return msgs
+}
@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"context"
+	"sync"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/httpclient"
+)
+
+const (
+	// defaultOrgAPIRate is how many GitHub and Slack calls per second one org's events may make.
+	defaultOrgAPIRate = 10
+	// defaultOrgAPIBurst is how many calls one org's events may make in a burst.
+	defaultOrgAPIBurst = 20
+)
+
+// orgLimiters holds a rate limiter per GitHub org.
+type orgLimiters struct {
+	limiters map[string]*httpclient.Limiter
+	rate     float64
+	burst    int
+	mu       sync.Mutex
+}
+
+func newOrgLimiters(rate float64, burst int) *orgLimiters {
+	return &orgLimiters{
+		limiters: make(map[string]*httpclient.Limiter),
+		rate:     rate,
+		burst:    burst,
+	}
+}
+
+// get returns the limiter for an org, creating it if needed.
+func (o *orgLimiters) get(org string) *httpclient.Limiter {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	l, exists := o.limiters[org]
+	if !exists {
+		l = httpclient.NewLimiter(o.rate, o.burst)
+		o.limiters[org] = l
+	}
+	return l
+}
+
+// SetOrgAPIRate sets the per-org limit on GitHub and Slack calls made while handling events.
+// It must be called before Run.
+func (c *Coordinator) SetOrgAPIRate(rate float64, burst int) {
+	if rate > 0 && burst > 0 {
+		c.orgLimits = newOrgLimiters(rate, burst)
+	}
+}
+
+// withOrgRateLimit attaches the event's org rate limiter to the context, so GitHub
+// and Slack calls made while handling it are limited per org.
+func (c *Coordinator) withOrgRateLimit(next EventHandler) EventHandler {
+	return HandlerFunc(func(ctx context.Context, ev *Event) error {
+		return next.Handle(httpclient.WithLimiter(ctx, c.orgLimits.get(ev.Owner)), ev)
+	})
+}
@@ -0,0 +1,88 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// reconcileInterval is how often PRs with unknown or uncertain states are re-resolved.
+const reconcileInterval = 5 * time.Minute
+
+// RunReconciliation re-resolves PRs whose state is unknown or was derived from incomplete
+// data, until the context is cancelled.
+func (c *Coordinator) RunReconciliation(ctx context.Context) error {
+	ticker := time.NewTicker(reconcileInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			for _, workspaceID := range c.workspaceIDs() {
+				c.reconcileWorkspace(ctx, workspaceID)
+			}
+		}
+	}
+}
+
+// reconcileWorkspace re-resolves the unknown or uncertain PRs of one workspace.
+func (c *Coordinator) reconcileWorkspace(ctx context.Context, workspaceID string) {
+	for _, pr := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+		if !isOpenState(pr.State) || (pr.State != state.Unknown && len(pr.Uncertain) == 0) {
+			continue
+		}
+
+		if err := c.resyncPR(ctx, workspaceID, pr); err != nil {
+			metrics.IncCounter("slacker_pr_state_reconciled_total", "result", "error")
+			slog.Debug("failed to reconcile PR state", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+			continue
+		}
+		result := "resolved"
+		if len(pr.Uncertain) > 0 {
+			result = "incomplete"
+		}
+		metrics.IncCounter("slacker_pr_state_reconciled_total", "result", result)
+	}
+}
+
+// resyncPR re-resolves a PR's state from GitHub and applies any change to its thread.
+// Only the resolved fields are written to the stored PR, which may have changed while
+// GitHub was asked, such as by a new thread or claim. pr must be a copy, such as one
+// from a snapshot; it's updated to the stored PR's new state.
+func (c *Coordinator) resyncPR(ctx context.Context, workspaceID string, pr *state.PRState) error {
+	status, err := c.prState(ctx, pr.Owner, pr.Repo, pr.Number)
+	if err != nil {
+		return err
+	}
+
+	var previousState string
+	var previouslyBlocked []string
+	var previousConversations int
+	updated, exists := c.stateManager.UpdatePRState(workspaceID, pr.Owner, pr.Repo, pr.Number, func(stored *state.PRState) {
+		previousState, previouslyBlocked, previousConversations = stored.State, stored.BlockedOn, stored.UnresolvedConversations
+		stored.State = status.State
+		stored.BlockedOn = status.BlockedOn
+		stored.ChangesRequestedBy = status.ChangesRequestedBy
+		stored.UnresolvedConversations = status.UnresolvedConversations
+		stored.Uncertain = status.Incomplete
+		stored.LastUpdated = time.Now()
+		c.recordStateChange(stored)
+	})
+	if !exists {
+		return nil // Pruned meanwhile.
+	}
+	*pr = *updated
+	c.emitStateChange(ctx, workspaceID, pr, previousState, previouslyBlocked)
+
+	if pr.ThreadTS != "" && (pr.State != previousState || pr.UnresolvedConversations != previousConversations) {
+		if err := c.showThreadState(ctx, workspaceID, pr, pr.State); err != nil {
+			slog.Warn("failed to show resynced state", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		}
+	}
+	return nil
+}
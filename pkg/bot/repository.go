@@ -0,0 +1,113 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// handleRepositoryEvent moves a renamed or transferred repo's PRs to its new name,
+// so events under the new name find their existing threads. A transfer also loads
+// the destination org's config and is announced in the open PRs' threads, while a
+// rename keeps the repo's settings under its old name until slack.yaml moves them.
+func (c *Coordinator) handleRepositoryEvent(ctx context.Context, ev *Event) error {
+	var event struct {
+		Action  string `json:"action"`
+		Changes struct {
+			Repository struct {
+				Name struct {
+					From string `json:"from"`
+				} `json:"name"`
+			} `json:"repository"`
+			Owner struct {
+				From struct {
+					Organization struct {
+						Login string `json:"login"`
+					} `json:"organization"`
+					User struct {
+						Login string `json:"login"`
+					} `json:"user"`
+				} `json:"from"`
+			} `json:"owner"`
+		} `json:"changes"`
+		Repository struct {
+			Name  string `json:"name"`
+			Owner struct {
+				Login string `json:"login"`
+			} `json:"owner"`
+		} `json:"repository"`
+	}
+	if err := json.Unmarshal(ev.Payload, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal repository event: %w", err)
+	}
+
+	newOwner, newRepo := event.Repository.Owner.Login, event.Repository.Name
+	oldOwner, oldRepo := newOwner, newRepo
+	switch event.Action {
+	case "renamed":
+		oldRepo = event.Changes.Repository.Name.From
+	case "transferred":
+		oldOwner = event.Changes.Owner.From.Organization.Login
+		if oldOwner == "" {
+			oldOwner = event.Changes.Owner.From.User.Login
+		}
+	default:
+		return nil
+	}
+	if oldOwner == "" || oldRepo == "" || newOwner == "" || newRepo == "" {
+		return nil
+	}
+
+	if event.Action == "transferred" {
+		// The destination org's slack.yaml decides where the repo's PRs post now.
+		c.handleConfigUpdate(ctx, newOwner)
+	}
+
+	workspaceID, routed := c.workspaceFor(oldOwner)
+	if !routed {
+		return nil
+	}
+	if newWorkspace, ok := c.workspaceFor(newOwner); !ok || newWorkspace != workspaceID {
+		// The threads live in a workspace the new owner doesn't post to, so they stay
+		// behind and say where the repo went.
+		slog.Info("repo moved to an org in another workspace, leaving its PRs", "from", oldOwner+"/"+oldRepo,
+			"to", newOwner+"/"+newRepo, "workspace", workspaceID)
+		var prs []*state.PRState
+		for _, pr := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+			if strings.EqualFold(pr.Owner, oldOwner) && strings.EqualFold(pr.Repo, oldRepo) {
+				prs = append(prs, pr)
+			}
+		}
+		c.announceMove(ctx, workspaceID, prs, newOwner+"/"+newRepo, " Updates for it are no longer posted here.")
+		return nil
+	}
+
+	moved := c.stateManager.RenameRepo(workspaceID, oldOwner, oldRepo, newOwner, newRepo)
+	slog.Info("repo renamed, moved its PRs", "from", oldOwner+"/"+oldRepo, "to", newOwner+"/"+newRepo,
+		"workspace", workspaceID, "prs", len(moved))
+	if event.Action == "transferred" {
+		c.announceMove(ctx, workspaceID, moved, newOwner+"/"+newRepo, "")
+	}
+	if event.Action == "renamed" {
+		// Until slack.yaml is updated, the repo keeps the settings under its old name.
+		c.configManager.RecordRename(newOwner, oldRepo, newRepo)
+	}
+	return nil
+}
+
+// announceMove notes in the threads of the open PRs among prs that their repo moved to newName.
+func (c *Coordinator) announceMove(ctx context.Context, workspaceID string, prs []*state.PRState, newName, suffix string) {
+	for _, pr := range prs {
+		if pr.ThreadTS == "" || pr.StackRoot != 0 || !isOpenState(pr.State) {
+			continue
+		}
+		note := fmt.Sprintf("📦 This repo moved to *%s*.%s", newName, suffix)
+		if err := c.notifier.SendThreadUpdate(ctx, workspaceID, pr, note); err != nil {
+			slog.Warn("failed to post repo move note", "pr", state.PRKey(pr.Owner, pr.Repo, pr.Number), "error", err)
+		}
+	}
+}
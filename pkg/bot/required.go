@@ -0,0 +1,77 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/github"
+)
+
+// applyRequiredReviewers holds a PR in review until every reviewer its repo's config
+// requires has approved, adding those still missing to BlockedOn. Required teams are
+// satisfied by an approval from any of their members.
+func (c *Coordinator) applyRequiredReviewers(ctx context.Context, owner, repo string, status *github.PRStatus) {
+	if status.State != "check" && status.State != "hourglass" {
+		return
+	}
+	required := c.configManager.GetRequiredReviewers(owner, repo)
+	if len(required) == 0 {
+		return
+	}
+
+	var missing []string
+	for _, reviewer := range required {
+		if !c.signedOff(ctx, owner, reviewer, status.Approvers) {
+			missing = append(missing, reviewer)
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+
+	var blockedOn []string
+	if status.State == "hourglass" {
+		blockedOn = slices.Clone(status.BlockedOn)
+	}
+	for _, reviewer := range missing {
+		if !slices.Contains(blockedOn, reviewer) {
+			blockedOn = append(blockedOn, reviewer)
+		}
+	}
+	status.State = "hourglass"
+	status.BlockedOn = blockedOn
+}
+
+// applyConversationHold keeps an approved PR waiting on its author while review
+// conversations are unresolved, for orgs that hold for them.
+func (c *Coordinator) applyConversationHold(owner string, status *github.PRStatus) {
+	if status.State != "check" || status.UnresolvedConversations == 0 || !c.configManager.HoldsForConversations(owner) {
+		return
+	}
+	// Approved PRs are already blocked on their author, who has the conversations to address.
+	status.State = "carpentry_saw"
+}
+
+// signedOff reports whether a required reviewer, a login or "team:slug", has approved.
+func (c *Coordinator) signedOff(ctx context.Context, org, reviewer string, approvers []string) bool {
+	slug, isTeam := strings.CutPrefix(reviewer, "team:")
+	if !isTeam {
+		return slices.ContainsFunc(approvers, func(login string) bool {
+			return strings.EqualFold(login, reviewer)
+		})
+	}
+
+	for _, login := range approvers {
+		member, err := c.githubFor(org).IsTeamMember(ctx, org, slug, login)
+		if err != nil {
+			slog.Warn("failed to check required team membership", "org", org, "team", slug, "user", login, "error", err)
+			continue
+		}
+		if member {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// handlePullRequestReviewEvent handles PR review events.
+func (c *Coordinator) handlePullRequestReviewEvent(ctx context.Context, ev *Event) error {
+	owner, repo := ev.Owner, ev.Repo
+
+	var event struct {
+		Action string `json:"action"`
+		Review struct {
+			User struct {
+				Login string `json:"login"`
+			} `json:"user"`
+			State string `json:"state"`
+		} `json:"review"`
+		PullRequest struct {
+			Number int `json:"number"`
+		} `json:"pull_request"`
+	}
+
+	if err := json.Unmarshal(ev.Payload, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal review event: %w", err)
+	}
+
+	workspaceID, routed := c.workspaceFor(owner)
+	if !routed {
+		return nil
+	}
+	pr, exists := c.stateManager.GetPRState(workspaceID, owner, repo, event.PullRequest.Number)
+	if !exists {
+		return nil
+	}
+
+	// Update thread with review status.
+	if pr.ThreadTS != "" && event.Action == "submitted" {
+		message := fmt.Sprintf("@%s reviewed the PR", event.Review.User.Login)
+		switch event.Review.State {
+		case "approved":
+			message += " ✅"
+		case "changes_requested":
+			message += " 🔧"
+		default:
+			// Other review states (commented, dismissed, etc.)
+			message += fmt.Sprintf(" (%s)", event.Review.State)
+		}
+		if err := c.notifier.SendThreadUpdate(ctx, workspaceID, pr, message); err != nil {
+			slog.Warn("failed to send thread update", "error", err)
+		}
+	}
+
+	// Record the review for reviewer stats, with how long the PR had waited for it.
+	if event.Action == "submitted" && event.Review.User.Login != pr.Author {
+		now := time.Now()
+		var response time.Duration
+		if pr.State == "hourglass" {
+			response = pr.TimeInState(now)
+		}
+		c.stateManager.AddReview(workspaceID, state.ReviewRecord{
+			At:       now,
+			Owner:    owner,
+			Repo:     repo,
+			Number:   pr.Number,
+			Reviewer: event.Review.User.Login,
+			Response: response,
+		})
+	}
+
+	// Update PR state.
+	status, err := c.prState(ctx, owner, repo, event.PullRequest.Number)
+	if err == nil {
+		var previousState string
+		var previouslyBlocked []string
+		updated, exists := c.stateManager.UpdatePRState(workspaceID, owner, repo, pr.Number, func(stored *state.PRState) {
+			previousState, previouslyBlocked = stored.State, stored.BlockedOn
+			stored.State = status.State
+			stored.BlockedOn = status.BlockedOn
+			stored.ChangesRequestedBy = status.ChangesRequestedBy
+			stored.UnresolvedConversations = status.UnresolvedConversations
+			stored.Uncertain = status.Incomplete
+			stored.LastUpdated = time.Now()
+			stored.UpdatedAt = stored.LastUpdated
+			c.recordStateChange(stored)
+		})
+		if !exists {
+			return nil // Pruned meanwhile.
+		}
+		pr = updated
+		c.emitStateChange(ctx, workspaceID, pr, previousState, previouslyBlocked)
+
+		// Update reaction.
+		if pr.ThreadTS != "" {
+			if err := c.showThreadState(ctx, workspaceID, pr, status.State); err != nil {
+				slog.Warn("failed to update reaction", "error", err)
+			}
+		}
+	}
+
+	return nil
+}
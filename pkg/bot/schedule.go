@@ -0,0 +1,52 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// maxScheduleAhead is how far ahead Slack accepts scheduled messages.
+const maxScheduleAhead = 120 * 24 * time.Hour
+
+// scheduleReview posts a pair review proposal to the PR's thread and schedules a
+// reminder DM to both reviewers at the chosen time.
+func (c *Coordinator) scheduleReview(ctx context.Context, workspaceID string, pr *state.PRState, userID string, values map[string]string) string {
+	partnerID := values[slack.SchedulePartner]
+	loc := c.userLocation(ctx, workspaceID, userID)
+	at, err := time.ParseInLocation("2006-01-02 15:04", values[slack.ScheduleDate]+" "+values[slack.ScheduleTime], loc)
+	if err != nil || partnerID == "" {
+		return fmt.Sprintf("<@%s>, I couldn't read that review time. Please try again.", userID)
+	}
+	now := c.clock.Now()
+	if !at.After(now) {
+		return fmt.Sprintf("<@%s>, %s has already passed. Please pick a later time.", userID, slack.FormatTime(at, slack.DateTime))
+	}
+	if at.Sub(now) > maxScheduleAhead {
+		return fmt.Sprintf("<@%s>, reviews can be scheduled up to 120 days ahead.", userID)
+	}
+
+	link := fmt.Sprintf("<%s|%s/%s#%d>", githubPRURL(pr), pr.Owner, pr.Repo, pr.Number)
+	reminders := map[string]string{userID: partnerID, partnerID: userID}
+	for recipient, other := range reminders {
+		text := fmt.Sprintf("⏰ Time to review %s %s with <@%s>.", link, pr.Title, other)
+		if other == recipient {
+			text = fmt.Sprintf("⏰ Time to review %s %s.", link, pr.Title)
+		}
+		if err := c.slackFor(workspaceID).ScheduleDirectMessage(ctx, recipient, at, text); err != nil {
+			slog.Warn("failed to schedule review reminder", "user", recipient, "error", err)
+		}
+	}
+
+	slog.Info("review scheduled", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "at", at)
+	return fmt.Sprintf("📅 *Pair review scheduled*\n"+
+		"*When:* %s\n"+
+		"*Who:* <@%s> and <@%s>\n"+
+		"*What:* %s %s\n"+
+		"Both of you will get a reminder then.",
+		slack.FormatTime(at, slack.LongDateTime), userID, partnerID, link, pr.Title)
+}
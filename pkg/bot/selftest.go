@@ -0,0 +1,121 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/admin"
+	"github.com/gorilla/mux"
+)
+
+// selfTestTimeout bounds each self-test check, so one unreachable upstream can't stall the rest.
+const selfTestTimeout = 15 * time.Second
+
+// SelfTestResult is the outcome of one self-test check.
+type SelfTestResult struct {
+	Name   string `json:"name"`
+	Detail string `json:"detail"`
+	Passed bool   `json:"passed"`
+}
+
+// SelfTest checks everything the bot depends on, for deploy pipelines to run before
+// switching traffic: each workspace's Slack token and scopes, the GitHub App's
+// installation and the config for org, the sprinkler connection, and storage. An
+// empty org uses the first routed org.
+func (c *Coordinator) SelfTest(ctx context.Context, org string) []SelfTestResult {
+	var results []SelfTestResult
+	check := func(name string, fn func(ctx context.Context) (string, error)) {
+		ctx, cancel := context.WithTimeout(ctx, selfTestTimeout)
+		defer cancel()
+		detail, err := fn(ctx)
+		if err != nil {
+			detail = err.Error()
+		}
+		results = append(results, SelfTestResult{Name: name, Detail: detail, Passed: err == nil})
+	}
+
+	for _, workspaceID := range c.workspaceIDs() {
+		check("slack auth ("+workspaceID+")", func(ctx context.Context) (string, error) {
+			return "token valid with the required scopes", c.slackFor(workspaceID).CheckScopes(ctx)
+		})
+	}
+
+	if org == "" && c.routing != nil {
+		if orgs := c.routing.OrgNames(); len(orgs) > 0 {
+			org = orgs[0]
+		}
+	}
+	if org == "" {
+		results = append(results,
+			SelfTestResult{Name: "github auth", Detail: "no org to check; name one, since no orgs are routed"},
+			SelfTestResult{Name: "config fetch", Detail: "no org to check; name one, since no orgs are routed"})
+	} else {
+		check("github auth ("+org+")", func(ctx context.Context) (string, error) {
+			return "app installed with the required permissions", c.githubFor(org).CheckInstallation(ctx, org)
+		})
+		check("config fetch ("+org+")", func(ctx context.Context) (string, error) {
+			repos, err := c.configManager.CheckConfig(ctx, org)
+			return fmt.Sprintf("fetched and valid, %d repos configured", repos), err
+		})
+	}
+
+	check("sprinkler", func(ctx context.Context) (string, error) {
+		conn, resp, err := c.dialer.DialContext(ctx, c.sprinklerURL, nil)
+		if resp != nil {
+			if err := resp.Body.Close(); err != nil {
+				slog.Debug("failed to close response body", "error", err)
+			}
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to connect to %s: %w", c.sprinklerURL, err)
+		}
+		if err := conn.Close(); err != nil {
+			slog.Debug("failed to close sprinkler self-test connection", "error", err)
+		}
+		return "connected to " + c.sprinklerURL, nil
+	})
+
+	check("storage", func(context.Context) (string, error) {
+		return "data directory is readable and writable", c.stateManager.CheckStorage()
+	})
+	return results
+}
+
+// FormatSelfTest renders self-test results as a pass/fail report, reporting whether every check passed.
+func FormatSelfTest(results []SelfTestResult) (string, bool) {
+	lines := make([]string, 0, len(results)+1)
+	passed := true
+	for _, r := range results {
+		mark := "PASS"
+		if !r.Passed {
+			mark = "FAIL"
+			passed = false
+		}
+		lines = append(lines, fmt.Sprintf("%s %s: %s", mark, r.Name, r.Detail))
+	}
+	if passed {
+		lines = append(lines, "self-test passed")
+	} else {
+		lines = append(lines, "self-test failed")
+	}
+	return strings.Join(lines, "\n"), passed
+}
+
+// SelfTestRoutes registers the admin endpoint that runs the self-test, for the org
+// named by the org query parameter or the first routed org. It responds 503 if any
+// check fails.
+func (c *Coordinator) SelfTestRoutes(router *mux.Router) {
+	router.HandleFunc("/selftest", func(w http.ResponseWriter, r *http.Request) {
+		results := c.SelfTest(r.Context(), r.URL.Query().Get("org"))
+		_, passed := FormatSelfTest(results)
+		status := http.StatusOK
+		if !passed {
+			status = http.StatusServiceUnavailable
+		}
+		admin.WriteJSON(w, status, map[string]any{"passed": passed, "checks": results})
+	}).Methods("GET")
+}
@@ -0,0 +1,297 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/clock"
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+const (
+	// defaultSimulationDays is how much history a simulation replays unless told otherwise.
+	defaultSimulationDays = 7
+	// maxSimulationDays bounds the history a simulation replays.
+	maxSimulationDays = 90
+	// maxSimulationUsers bounds the users listed in a simulation report.
+	maxSimulationUsers = 25
+)
+
+// simulation is a candidate notification config to replay an org's history against.
+type simulation struct {
+	org    string
+	digest config.DigestConfig
+	days   int
+	// notifyDelay replaces each user's own notify delay when set.
+	notifyDelay time.Duration
+}
+
+// simulatedUser tallies what a GitHub user would have received during a replay.
+type simulatedUser struct {
+	slackID  string
+	dms      int
+	mentions int
+}
+
+// simEvent is a recorded state change or a scheduled digest on the replay timeline.
+type simEvent struct {
+	at     time.Time
+	pr     *state.PRState
+	change state.StateChange
+	digest bool
+}
+
+// Simulate replays an org's recorded PR state changes from the last few days against
+// a candidate notify delay and digest schedule, reporting how many DMs and channel
+// mentions each user would have received next to what the current config gives.
+// Nothing is sent or recorded. args are the org followed by optional settings:
+// a number of days, delay=<duration>, and digest=<day>@<HH:MM> or digest=off.
+func (c *Coordinator) Simulate(_ context.Context, workspaceID string, args []string) string {
+	current, candidate, problem := c.parseSimulation(args)
+	if problem != "" {
+		return problem
+	}
+
+	snap := c.stateManager.Snapshot(workspaceID)
+	end := c.clock.Now()
+	start := end.AddDate(0, 0, -candidate.days)
+	before, _, _ := c.replay(snap, current, start, end)
+	after, changes, digests := c.replay(snap, candidate, start, end)
+
+	lines := []string{
+		fmt.Sprintf("*Simulated %s over the last %d days* (notify delay: %s; digest: %s)",
+			candidate.org, candidate.days, describeDelay(candidate.notifyDelay), describeDigest(candidate.digest)),
+		fmt.Sprintf("Replayed %d state changes and %d digests. Current config in parentheses.", changes, digests),
+	}
+	if len(after) == 0 {
+		return strings.Join(append(lines, "No one would have been notified or mentioned."), "\n")
+	}
+
+	logins := make([]string, 0, len(after))
+	for login := range after {
+		logins = append(logins, login)
+	}
+	sort.Slice(logins, func(i, j int) bool {
+		a, b := after[logins[i]], after[logins[j]]
+		if a.dms+a.mentions != b.dms+b.mentions {
+			return a.dms+a.mentions > b.dms+b.mentions
+		}
+		return strings.ToLower(logins[i]) < strings.ToLower(logins[j])
+	})
+
+	for i, login := range logins {
+		if i == maxSimulationUsers {
+			lines = append(lines, fmt.Sprintf("…and %d more users", len(logins)-i))
+			break
+		}
+		u, was := after[login], before[login]
+		if was == nil {
+			was = &simulatedUser{}
+		}
+		who := login + " (not linked to Slack, so no DMs)"
+		if u.slackID != "" {
+			who = fmt.Sprintf("%s (<@%s>)", login, u.slackID)
+		}
+		lines = append(lines, fmt.Sprintf("• %s: %d DMs (%d), %d mentions (%d)", who, u.dms, was.dms, u.mentions, was.mentions))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// parseSimulation reads the org's current notification config and the candidate described
+// by args, or explains what is wrong with args.
+func (c *Coordinator) parseSimulation(args []string) (current, candidate simulation, problem string) {
+	if len(args) == 0 {
+		return current, candidate, "Name the org to simulate."
+	}
+	current = simulation{org: args[0], days: defaultSimulationDays}
+	if cfg, exists := c.configManager.GetConfig(current.org); exists {
+		current.digest = cfg.Global.Digest
+	}
+	candidate = current
+
+	for _, arg := range args[1:] {
+		key, value, _ := strings.Cut(arg, "=")
+		switch key {
+		case "delay":
+			delay, err := time.ParseDuration(value)
+			if err != nil || delay < 0 {
+				return current, candidate, fmt.Sprintf("`%s` is not a delay; use a duration such as `1h`.", value)
+			}
+			candidate.notifyDelay = delay
+		case "digest":
+			if value == "off" {
+				candidate.digest.Enabled = false
+				continue
+			}
+			day, at, _ := strings.Cut(value, "@")
+			digest := config.DigestConfig{Day: day, Time: at, Timezone: candidate.digest.Timezone, Enabled: true}
+			if _, err := lastDigestTime(digest, c.clock.Now()); err != nil {
+				return current, candidate, fmt.Sprintf("`%s` is not a digest schedule (%v); use `monday@09:00` or `off`.", value, err)
+			}
+			candidate.digest = digest
+		default:
+			days, err := strconv.Atoi(arg)
+			if err != nil || days <= 0 || days > maxSimulationDays {
+				return current, candidate, fmt.Sprintf("`%s` is not a setting; use a number of days up to %d, `delay=1h`, or `digest=monday@09:00`.",
+					arg, maxSimulationDays)
+			}
+			current.days, candidate.days = days, days
+		}
+	}
+	return current, candidate, ""
+}
+
+// replay walks an org's state changes and digests between start and end on a simulated
+// clock, applying the same preference and notify delay checks as live notifications.
+// Each state change DMs the users it blocks, or mentions them in the PR's thread if
+// it can't, and each digest mentions the users blocking each open PR in every channel the PR's repo posts to.
+// It returns the tallies by GitHub login, with how many state changes and digests were replayed.
+func (c *Coordinator) replay(snap *state.Snapshot, sim simulation, start, end time.Time) (users map[string]*simulatedUser, changes, digests int) {
+	var prs []*state.PRState
+	var events []simEvent
+	for _, pr := range snap.ListPRs() {
+		if pr.Owner != sim.org {
+			continue
+		}
+		prs = append(prs, pr)
+		for _, change := range pr.StateChanges {
+			if change.At.After(start) && !change.At.After(end) {
+				events = append(events, simEvent{at: change.At, pr: pr, change: change})
+			}
+		}
+	}
+	changes = len(events)
+	if sim.digest.Enabled {
+		for at, err := lastDigestTime(sim.digest, end); err == nil && at.After(start); at = at.AddDate(0, 0, -7) {
+			events = append(events, simEvent{at: at, digest: true})
+			digests++
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].at.Before(events[j].at) })
+
+	users = make(map[string]*simulatedUser)
+	user := func(login string) *simulatedUser {
+		if u, exists := users[login]; exists {
+			return u
+		}
+		u := &simulatedUser{}
+		for userID, prefs := range snap.Users {
+			if prefs.GitHubLogin != "" && strings.EqualFold(prefs.GitHubLogin, login) {
+				u.slackID = userID
+				break
+			}
+		}
+		users[login] = u
+		return u
+	}
+
+	clk := clock.NewSim(start)
+	lastDM := make(map[string]time.Time)
+	for _, ev := range events {
+		clk.Set(ev.at)
+		now := clk.Now()
+
+		if ev.digest {
+			for _, pr := range prs {
+				change, ok := stateAt(pr, now)
+				if !ok || !isOpenState(change.State) {
+					continue
+				}
+				channels := len(c.configManager.GetChannelsForRepo(pr.Owner, pr.Repo))
+				for _, login := range change.BlockedOn {
+					user(login).mentions += channels
+				}
+			}
+			continue
+		}
+
+		posted := len(c.configManager.GetChannelsForRepo(ev.pr.Owner, ev.pr.Repo)) > 0
+		low := c.configManager.GetSeverity(ev.pr.Owner, ev.pr.Repo) == config.SeverityLow
+		scale := c.configManager.SeverityScale(ev.pr.Owner, ev.pr.Repo)
+		for _, login := range ev.change.BlockedOn {
+			u := user(login)
+			// Each transition reaches a user once: by DM when the checks pass, otherwise by a
+			// thread mention, which low severity repos only make for users who prefer them.
+			if c.simulateDM(snap, sim, u, login, ev.change.State, scale, now, lastDM) {
+				u.dms++
+				continue
+			}
+			if posted && (!low || snap.Users[u.slackID].NotifyVia == state.DeliveryThread) {
+				u.mentions++
+			}
+		}
+	}
+
+	// Drop users who would have received nothing, such as blockers of PRs posted to no channel.
+	for login, u := range users {
+		if u.dms == 0 && u.mentions == 0 {
+			delete(users, login)
+		}
+	}
+	return users, changes, digests
+}
+
+// stateAt returns the state a PR was in at a moment, from its recorded history.
+func stateAt(pr *state.PRState, at time.Time) (state.StateChange, bool) {
+	var found state.StateChange
+	ok := false
+	for _, change := range pr.StateChanges {
+		if change.At.After(at) {
+			break
+		}
+		found, ok = change, true
+	}
+	return found, ok
+}
+
+// describeDelay names a simulated notify delay for display.
+func describeDelay(delay time.Duration) string {
+	if delay == 0 {
+		return "each user's own"
+	}
+	return delay.String()
+}
+
+// describeDigest names a digest schedule for display.
+func describeDigest(cfg config.DigestConfig) string {
+	if !cfg.Enabled {
+		return "off"
+	}
+	day, at, tz := cfg.Day, cfg.Time, cfg.Timezone
+	if day == "" {
+		day = "monday"
+	}
+	if at == "" {
+		at = "09:00"
+	}
+	if tz == "" {
+		tz = "UTC"
+	}
+	return fmt.Sprintf("%s %s %s", strings.ToLower(day), at, tz)
+}
+
+// simulateDM reports whether a user blocked by a state change would be sent a DM,
+// recording when for the notify delay, which scale stretches for the repo's severity tier.
+func (*Coordinator) simulateDM(snap *state.Snapshot, sim simulation, u *simulatedUser, login, prState string, scale float64, now time.Time, lastDM map[string]time.Time) bool {
+	if prState == state.Unknown || u.slackID == "" {
+		return false
+	}
+	prefs := snap.Users[u.slackID]
+	if !prefs.RealTimeNotifications || prefs.NotifyVia == state.DeliveryThread {
+		return false
+	}
+	delay := prefs.ChannelNotifyDelay
+	if sim.notifyDelay > 0 {
+		delay = sim.notifyDelay
+	}
+	if last, sent := lastDM[login]; sent && now.Sub(last) < time.Duration(float64(delay)*scale) {
+		return false
+	}
+	lastDM[login] = now
+	return true
+}
@@ -0,0 +1,36 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// isLarge reports whether a PR exceeds its org's configured size thresholds.
+func (c *Coordinator) isLarge(owner string, pr prPayload) bool {
+	files, lines := c.configManager.GetLargePR(owner)
+	return (files > 0 && pr.ChangedFiles > files) || (lines > 0 && pr.Additions+pr.Deletions > lines)
+}
+
+// warnLargePR notes on the thread that a PR has grown past the size thresholds,
+// and suggests to its author that it be split.
+func (c *Coordinator) warnLargePR(ctx context.Context, workspaceID string, pr *state.PRState, size prPayload) {
+	summary := fmt.Sprintf("%d files, +%d/-%d lines", size.ChangedFiles, size.Additions, size.Deletions)
+	note := fmt.Sprintf("⚠️ This is a large PR (%s). Expect reviews to take longer.", summary)
+	if err := c.notifier.SendThreadUpdate(ctx, workspaceID, pr, note); err != nil {
+		slog.Warn("failed to post large PR note", "error", err)
+	}
+
+	userID, found := c.slackUser(ctx, workspaceID, pr.Owner, pr.Author)
+	if !found {
+		slog.Debug("large PR author has no known Slack account", "author", pr.Author)
+		return
+	}
+	message := fmt.Sprintf("⚠️ <%s|%s/%s#%d> %s is large (%s). Smaller PRs get faster, more careful reviews; "+
+		"consider splitting it into a stack of focused changes.", githubPRURL(pr), pr.Owner, pr.Repo, pr.Number, pr.Title, summary)
+	if err := c.slackFor(workspaceID).SendDirectMessage(ctx, userID, message); err != nil {
+		slog.Warn("failed to suggest splitting large PR", "user", userID, "error", err)
+	}
+}
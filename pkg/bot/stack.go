@@ -0,0 +1,102 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// joinStack threads a PR under the PR it is stacked on, if its base branch is
+// another open PR's head branch. It reports whether the PR joined a stack.
+func (c *Coordinator) joinStack(ctx context.Context, workspaceID string, pr *state.PRState) bool {
+	if pr.BaseRef == "" {
+		return false
+	}
+
+	var parent *state.PRState
+	for _, candidate := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+		if candidate.Owner == pr.Owner && candidate.Repo == pr.Repo && candidate.Number != pr.Number &&
+			candidate.HeadRef == pr.BaseRef && candidate.ThreadTS != "" && isOpenState(candidate.State) {
+			parent = candidate
+			break
+		}
+	}
+	if parent == nil {
+		return false
+	}
+
+	root := parent
+	if parent.StackRoot != 0 {
+		if r, exists := c.stateManager.GetPRState(workspaceID, pr.Owner, pr.Repo, parent.StackRoot); exists {
+			root = r
+		}
+	}
+
+	pr.ThreadTS = root.ThreadTS
+	pr.ChannelID = root.ChannelID
+	pr.StackRoot = root.Number
+	slog.Info("PR joined stack", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "parent", parent.Number, "root", root.Number)
+
+	message := fmt.Sprintf("📚 <%s|#%d> %s by @%s is stacked on #%d",
+		githubPRURL(pr), pr.Number, pr.Title, pr.Author, parent.Number)
+	if err := c.notifier.SendThreadUpdate(ctx, workspaceID, pr, message); err != nil {
+		slog.Warn("failed to announce stacked PR", "error", err)
+	}
+	if err := c.refreshStack(ctx, workspaceID, pr); err != nil {
+		slog.Warn("failed to update stack message", "error", err)
+	}
+	return true
+}
+
+// stackMembers returns the PRs threaded under root, ordered by number.
+func (c *Coordinator) stackMembers(workspaceID string, root *state.PRState) []*state.PRState {
+	var members []*state.PRState
+	for _, pr := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+		if pr.Owner == root.Owner && pr.Repo == root.Repo && pr.StackRoot == root.Number {
+			members = append(members, pr)
+		}
+	}
+	sort.Slice(members, func(i, j int) bool {
+		return members[i].Number < members[j].Number
+	})
+	return members
+}
+
+// refreshStack rewrites the stack's parent message with a status line per member PR.
+// updated is a stack PR whose latest state may not have been saved yet.
+func (c *Coordinator) refreshStack(ctx context.Context, workspaceID string, updated *state.PRState) error {
+	root := updated
+	if updated.StackRoot != 0 {
+		r, exists := c.stateManager.GetPRState(workspaceID, updated.Owner, updated.Repo, updated.StackRoot)
+		if !exists {
+			return fmt.Errorf("stack root #%d not found", updated.StackRoot)
+		}
+		root = r
+	}
+
+	members := c.stackMembers(workspaceID, root)
+	if updated != root {
+		found := false
+		for i, m := range members {
+			if m.Number == updated.Number {
+				members[i], found = updated, true
+			}
+		}
+		if !found {
+			members = append(members, updated)
+		}
+	}
+
+	mode := c.configManager.GetReactionMode(root.Owner)
+	lines := []string{threadText(c.configManager.GetPrefix(root.Owner), root, githubPRURL(root), mode, root.State)}
+	for _, m := range members {
+		lines = append(lines, fmt.Sprintf("↳ %s <%s|#%d> %s by @%s",
+			slack.StateEmoji(m.State), githubPRURL(m), m.Number, m.Title, m.Author))
+	}
+	return c.notifier.EditThreadMessage(ctx, workspaceID, root, strings.Join(lines, "\n"), root.State)
+}
@@ -0,0 +1,54 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// rerequestStaleApprovals asks a PR's approvers to review again when a push from
+// before to after changes enough lines, for orgs that want fresh eyes on approvals
+// their repos don't dismiss.
+func (c *Coordinator) rerequestStaleApprovals(ctx context.Context, workspaceID string, pr *state.PRState, approvers []string, before, after string) {
+	cfg := c.configManager.GetStaleApprovals(pr.Owner)
+	if !cfg.Rerequest {
+		return
+	}
+	var logins []string
+	for _, login := range approvers {
+		if login != pr.Author && !strings.HasSuffix(login, "[bot]") {
+			logins = append(logins, login)
+		}
+	}
+	if len(logins) == 0 {
+		return
+	}
+
+	lines, err := c.githubFor(pr.Owner).ChangedLines(ctx, pr.Owner, pr.Repo, before, after)
+	if err != nil {
+		// Without a comparison we cannot tell, so leave the approvals be.
+		slog.Debug("unable to measure pushed changes", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return
+	}
+	if lines < cfg.Lines {
+		return
+	}
+
+	if err := c.githubFor(pr.Owner).RequestReviewers(ctx, pr.Owner, pr.Repo, pr.Number, logins); err != nil {
+		slog.Warn("failed to re-request review from approvers", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return
+	}
+	slog.Info("re-requested review from approvers", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number,
+		"approvers", logins, "lines", lines)
+
+	if pr.ThreadTS == "" {
+		return
+	}
+	note := fmt.Sprintf("🔁 New commits changed %d lines since approval; re-requested review from @%s.", lines, strings.Join(logins, ", @"))
+	if err := c.notifier.SendThreadUpdate(ctx, workspaceID, pr, note); err != nil {
+		slog.Warn("failed to post re-requested review note", "error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package bot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/github"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+const (
+	// teamTTL is how long a team's members are cached.
+	teamTTL = time.Hour
+	// maxTeamExpansion is the largest team whose members a PR waits on individually
+	// unless slack.yaml sets the team's fanout. Larger teams stay a single team:slug
+	// entry, so a review request to a whole department doesn't DM everyone in it.
+	maxTeamExpansion = 25
+)
+
+// expandTeams replaces the team:slug entries a PR waits on with the members the
+// team's fanout policy picks, so each gets the PR on their dashboard and is notified
+// as they prefer. The PR's author is left out. A team whose members can't be listed,
+// that has more than maxTeamExpansion under the default policy, or that mentions its
+// usergroup instead, is kept as it is.
+func (c *Coordinator) expandTeams(ctx context.Context, owner, repo string, number int, status *github.PRStatus) {
+	if !slices.ContainsFunc(status.BlockedOn, func(entry string) bool { return strings.HasPrefix(entry, "team:") }) {
+		return
+	}
+
+	var blockedOn []string
+	add := func(login string) {
+		if strings.EqualFold(login, status.Author) {
+			return
+		}
+		if !slices.ContainsFunc(blockedOn, func(l string) bool { return strings.EqualFold(l, login) }) {
+			blockedOn = append(blockedOn, login)
+		}
+	}
+	for _, entry := range status.BlockedOn {
+		slug, isTeam := strings.CutPrefix(entry, "team:")
+		if !isTeam {
+			add(entry)
+			continue
+		}
+		members := c.fanout(ctx, owner, repo, number, slug, status.Author)
+		if len(members) == 0 {
+			add(entry)
+			continue
+		}
+		for _, login := range members {
+			add(login)
+		}
+	}
+	status.BlockedOn = blockedOn
+}
+
+// fanout returns the members of a team a PR waits on that its slack.yaml fanout
+// policy notifies, or nil to keep waiting on the team as a whole.
+func (c *Coordinator) fanout(ctx context.Context, owner, repo string, number int, slug, author string) []string {
+	settings := c.configManager.GetTeamSettings(owner, slug)
+	switch settings.Fanout {
+	case config.FanoutUsergroup:
+		return nil
+	case config.FanoutLead:
+		if settings.Lead == "" || strings.EqualFold(settings.Lead, author) {
+			return nil
+		}
+		return []string{settings.Lead}
+	case config.FanoutRandom:
+		members, ok := c.teamMembers(ctx, owner, slug)
+		if !ok {
+			return nil
+		}
+		count := settings.Count
+		if count <= 0 {
+			count = config.DefaultFanoutCount
+		}
+		return pickMembers(members, author, state.PRKey(owner, repo, number), count)
+	default:
+		members, ok := c.teamMembers(ctx, owner, slug)
+		if !ok || len(members) > maxTeamExpansion {
+			return nil
+		}
+		return members
+	}
+}
+
+// pickMembers picks count of a team's members other than the author for a PR. The
+// pick is by a hash of each login with the PR's key rather than at random, so the same
+// members are kept each time the PR is refreshed while different PRs spread the load.
+func pickMembers(members []string, author, key string, count int) []string {
+	rank := func(login string) string {
+		sum := sha256.Sum256([]byte(key + "|" + strings.ToLower(login)))
+		return hex.EncodeToString(sum[:8])
+	}
+	candidates := slices.DeleteFunc(slices.Clone(members), func(l string) bool { return strings.EqualFold(l, author) })
+	slices.SortFunc(candidates, func(a, b string) int { return strings.Compare(rank(a), rank(b)) })
+	return candidates[:min(count, len(candidates))]
+}
+
+// teamMembers returns a team's members, cached for teamTTL.
+func (c *Coordinator) teamMembers(ctx context.Context, org, slug string) ([]string, bool) {
+	key := org + "/" + slug
+	now := c.clock.Now()
+	c.members.mu.Lock()
+	cached, exists := c.members.teams[key]
+	c.members.mu.Unlock()
+	if exists && now.Sub(cached.fetchedAt) < teamTTL {
+		return cached.logins, true
+	}
+
+	logins, err := c.githubFor(org).TeamMembers(ctx, org, slug)
+	if err != nil {
+		slog.Warn("failed to expand team", "org", org, "team", slug, "error", err)
+		return nil, false
+	}
+	c.members.mu.Lock()
+	c.members.teams[key] = cachedTeam{fetchedAt: now, logins: logins}
+	c.members.mu.Unlock()
+	return logins, true
+}
@@ -0,0 +1,206 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/command"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// threadMention is a mention in a PR's thread.
+type threadMention struct {
+	pr *state.PRState
+	slack.Mention
+}
+
+// threadCommand is a command available only in a PR's thread.
+type threadCommand = command.Command[threadMention, string]
+
+// newThreadCommands builds the commands available in PR threads, besides those
+// available by any mention, in the order help lists them.
+func (c *Coordinator) newThreadCommands() *command.Registry[threadMention, string] {
+	r := command.NewRegistry[threadMention](func(text string) string { return text })
+	r.SetRepos(func(ctx context.Context, t threadMention) []string {
+		return c.ChannelRepos(ctx, t.Workspace, t.ChannelID)
+	})
+	r.Register(
+		threadCommand{
+			Name:    "remind",
+			Summary: "Get a DM about this PR later",
+			Usage:   "me tomorrow|in 2h|in 3d",
+			Args:    []command.Arg{{Name: "when", Kind: command.Rest}},
+			Run: func(ctx context.Context, t threadMention, in *command.Input) string {
+				return c.remindCommand(ctx, t.Workspace, t.pr, t.UserID, in.Words)
+			},
+		},
+		threadCommand{
+			Name:    "assign",
+			Summary: "Request a review on GitHub, by GitHub login or @mention",
+			Args:    []command.Arg{{Name: "github-login", Kind: command.Rest}},
+			Run: func(ctx context.Context, t threadMention, in *command.Input) string {
+				return c.assignCommand(ctx, t.Workspace, t.pr, t.UserID, in.Words)
+			},
+		},
+		threadCommand{
+			Name:    "approve",
+			Summary: "Approve the PR on GitHub",
+			Run: func(ctx context.Context, t threadMention, _ *command.Input) string {
+				return c.approveCommand(ctx, t.Workspace, t.pr, t.UserID)
+			},
+		},
+		threadCommand{
+			Name:    "status",
+			Summary: "Show the state of this PR, or of another one",
+			Args:    []command.Arg{{Name: "pr", Kind: command.PR, Optional: true}},
+			Run: func(_ context.Context, t threadMention, in *command.Input) string {
+				if ref, ok := in.PR("pr"); ok {
+					return c.prStatus(t.Workspace, ref)
+				}
+				now := c.clock.Now()
+				return formatPRLine(t.pr, c.thresholds(t.pr.Owner), now, c.stateManager.AwayLogins(t.Workspace, now))
+			},
+		},
+		threadCommand{
+			Name:    "help",
+			Summary: "Show this help message, or how to use one command",
+			Args:    []command.Arg{{Name: "command", Kind: command.Rest, Optional: true}},
+			Run: func(_ context.Context, _ threadMention, in *command.Input) string {
+				return c.helpReply(in, true)
+			},
+		},
+	)
+	return r
+}
+
+// remindCommand schedules a DM to the user about the PR.
+func (c *Coordinator) remindCommand(ctx context.Context, workspaceID string, pr *state.PRState, userID string, args []string) string {
+	if len(args) > 0 && strings.EqualFold(args[0], "me") {
+		args = args[1:]
+	}
+	loc := c.userLocation(ctx, workspaceID, userID)
+	due, ok := parseRemindTime(args, c.clock.Now().In(loc))
+	if !ok {
+		return "Usage: `remind me tomorrow`, `remind me in 2h`, or `remind me in 3d`"
+	}
+
+	key := fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, pr.Number)
+	c.stateManager.AddReminder(workspaceID, state.Reminder{
+		Due:    due,
+		UserID: userID,
+		PRKey:  key,
+		Text: fmt.Sprintf("⏰ Reminder: <https://github.com/%s/%s/pull/%d|%s> %s",
+			pr.Owner, pr.Repo, pr.Number, key, pr.Title),
+	})
+	return fmt.Sprintf("OK, I'll remind you about %s %s.", key, slack.FormatTime(due, slack.DateTime))
+}
+
+// userLocation returns the user's timezone, from stored preferences or their Slack profile.
+func (c *Coordinator) userLocation(ctx context.Context, workspaceID, userID string) *time.Location {
+	tz := c.stateManager.GetUserPreferences(workspaceID, userID).Timezone
+	if tz == "" {
+		if user, err := c.slackFor(workspaceID).GetUserInfo(ctx, userID); err == nil {
+			tz = user.TZ
+		}
+	}
+	loc, err := time.LoadLocation(tz)
+	if tz == "" || err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// parseRemindTime parses "tomorrow" (9am local) or "in 2h" / "in 3d" / "2h" relative to now.
+func parseRemindTime(args []string, now time.Time) (time.Time, bool) {
+	if len(args) == 0 {
+		return time.Time{}, false
+	}
+	if strings.EqualFold(args[0], "tomorrow") {
+		return time.Date(now.Year(), now.Month(), now.Day()+1, 9, 0, 0, 0, now.Location()), true
+	}
+	if strings.EqualFold(args[0], "in") {
+		args = args[1:]
+	}
+	if len(args) == 0 {
+		return time.Time{}, false
+	}
+
+	spec := strings.ToLower(strings.Join(args, ""))
+	if days, ok := strings.CutSuffix(spec, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return time.Time{}, false
+		}
+		return now.AddDate(0, 0, n), true
+	}
+	d, err := time.ParseDuration(spec)
+	if err != nil || d <= 0 {
+		return time.Time{}, false
+	}
+	return now.Add(d), true
+}
+
+// assignCommand requests reviews from GitHub users, given by login or as Slack
+// mentions of users with a verified GitHub login, if the Slack user asking could on
+// GitHub.
+func (c *Coordinator) assignCommand(ctx context.Context, workspaceID string, pr *state.PRState, userID string, args []string) string {
+	var logins []string
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "<@") {
+			mentioned, ok := command.ParseUser(arg)
+			if !ok {
+				return "Please give a GitHub username or @mention, like `assign octocat`."
+			}
+			login, found := c.verifiedLoginFor(workspaceID, pr.Owner, mentioned)
+			if !found {
+				return fmt.Sprintf("I don't know <@%s>'s GitHub account. Please give their GitHub username, like `assign octocat`.", mentioned)
+			}
+			logins = append(logins, login)
+			continue
+		}
+		if login := strings.TrimPrefix(arg, "@"); login != "" {
+			logins = append(logins, login)
+		}
+	}
+	if len(logins) == 0 {
+		return "Usage: `assign github-login`"
+	}
+	if _, refusal := c.canWrite(ctx, workspaceID, userID, pr.Owner, pr.Repo); refusal != "" {
+		return refusal
+	}
+
+	if err := c.githubFor(pr.Owner).RequestReviewers(ctx, pr.Owner, pr.Repo, pr.Number, logins); err != nil {
+		slog.Warn("failed to request reviewers from thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return "I couldn't request that review on GitHub. Are they a collaborator on the repo?"
+	}
+	return "Requested a review from " + strings.Join(logins, ", ") + "."
+}
+
+// approveCommand approves the PR on GitHub, attributing the approval to the Slack
+// user, if they are an org member with write access to the repo who didn't author it.
+func (c *Coordinator) approveCommand(ctx context.Context, workspaceID string, pr *state.PRState, userID string) string {
+	login, refusal := c.canWrite(ctx, workspaceID, userID, pr.Owner, pr.Repo)
+	if refusal != "" {
+		return refusal
+	}
+	if strings.EqualFold(login, pr.Author) {
+		slog.Info("refused approval of own PR", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "user", userID, "login", login)
+		return "You can't approve your own PR."
+	}
+	name := userID
+	if user, err := c.slackFor(workspaceID).GetUserInfo(ctx, userID); err == nil && user.RealName != "" {
+		name = user.RealName
+	}
+	body := fmt.Sprintf("Approved from Slack by %s (@%s).", name, login)
+	if err := c.githubFor(pr.Owner).Approve(ctx, pr.Owner, pr.Repo, pr.Number, body); err != nil {
+		slog.Warn("failed to approve from thread", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "error", err)
+		return "I couldn't approve this PR on GitHub."
+	}
+	slog.Info("approved PR from Slack", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "user", userID)
+	return "Approved ✅"
+}
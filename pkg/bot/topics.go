@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+const (
+	// topicInterval is how often pending channel topic updates are checked.
+	topicInterval = 10 * time.Second
+	// topicQuiet is how long a channel's PRs must go unchanged before its topic is
+	// updated, so a burst of state changes results in a single edit.
+	topicQuiet = 30 * time.Second
+)
+
+// topicKey identifies a channel whose topic shows PR counts.
+type topicKey struct {
+	workspaceID string
+	channelID   string
+}
+
+// topicTracker records channels whose PR counts changed since their topic was last updated.
+type topicTracker struct {
+	dirty map[topicKey]time.Time
+	mu    sync.Mutex
+}
+
+func newTopicTracker() *topicTracker {
+	return &topicTracker{dirty: make(map[topicKey]time.Time)}
+}
+
+// markTopic schedules an update of the topic of the channel holding a PR's thread.
+func (c *Coordinator) markTopic(workspaceID string, pr *state.PRState) {
+	if pr.ChannelID == "" || !c.configManager.TopicCountsEnabled(pr.Owner) || c.stateManager.Paused(workspaceID, pr.Owner) {
+		return
+	}
+	c.topics.mu.Lock()
+	defer c.topics.mu.Unlock()
+	c.topics.dirty[topicKey{workspaceID: workspaceID, channelID: pr.ChannelID}] = time.Now()
+}
+
+// RunTopicCounts keeps channel topics showing open and blocked PR counts until the context is cancelled.
+func (c *Coordinator) RunTopicCounts(ctx context.Context) error {
+	ticker := time.NewTicker(topicInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			c.flushTopics(ctx)
+		}
+	}
+}
+
+// flushTopics updates the topics of channels whose PRs have settled.
+func (c *Coordinator) flushTopics(ctx context.Context) {
+	c.topics.mu.Lock()
+	var ready []topicKey
+	for key, changed := range c.topics.dirty {
+		if time.Since(changed) >= topicQuiet {
+			ready = append(ready, key)
+			delete(c.topics.dirty, key)
+		}
+	}
+	c.topics.mu.Unlock()
+
+	for _, key := range ready {
+		open, blocked := c.channelCounts(key.workspaceID, key.channelID)
+		suffix := fmt.Sprintf("%d open PRs • %d blocked", open, blocked)
+		if err := c.slackFor(key.workspaceID).SetTopicSuffix(ctx, key.channelID, suffix); err != nil {
+			slog.Warn("failed to update channel topic", "channel", key.channelID, "error", err)
+		}
+	}
+}
+
+// channelCounts counts the open PRs whose threads live in a channel, and how many of them are blocked.
+func (c *Coordinator) channelCounts(workspaceID, channelID string) (open, blocked int) {
+	for _, pr := range c.stateManager.Snapshot(workspaceID).ListPRs() {
+		if pr.ChannelID != channelID || !isOpenState(pr.State) {
+			continue
+		}
+		open++
+		if len(pr.BlockedOn) > 0 {
+			blocked++
+		}
+	}
+	return open, blocked
+}
@@ -0,0 +1,42 @@
+package bot
+
+import (
+	"context"
+	"log/slog"
+)
+
+// UserChanged keeps stored preferences in step with a user's Slack profile.
+func (c *Coordinator) UserChanged(_ context.Context, workspaceID, teamID, userID, timezone string) {
+	if c.stateManager.UpdateUserTimezone(workspaceID, userID, timezone) {
+		slog.Info("updated user timezone from Slack profile", "team", teamID, "user", userID, "timezone", timezone)
+	}
+}
+
+// TeamDomainChanged records the workspace's new subdomain.
+func (c *Coordinator) TeamDomainChanged(_ context.Context, workspaceID, _, domain string) {
+	c.stateManager.SetDomain(workspaceID, domain)
+}
+
+// GridMigrated remaps every stored user ID that Slack reassigned when the
+// workspace moved into an Enterprise Grid, so preferences and PR mappings
+// follow the user instead of being orphaned under the old ID.
+func (c *Coordinator) GridMigrated(ctx context.Context, workspaceID, teamID string) {
+	userIDs := c.stateManager.UserIDs(workspaceID)
+	if len(userIDs) == 0 {
+		return
+	}
+
+	mapping, err := c.slackFor(workspaceID).ExchangeUserIDs(ctx, userIDs)
+	if err != nil {
+		slog.Error("failed to remap users after grid migration", "team", teamID, "error", err)
+		return
+	}
+
+	remapped := 0
+	for oldID, newID := range mapping {
+		if c.stateManager.RemapUser(workspaceID, oldID, newID) {
+			remapped++
+		}
+	}
+	slog.Info("remapped users after grid migration", "team", teamID, "users", len(userIDs), "remapped", remapped)
+}
@@ -0,0 +1,60 @@
+package bot
+
+import (
+	"sort"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/github"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+)
+
+// SetRouting routes each org's PRs to its configured Slack workspace, posting with that workspace's client.
+func (c *Coordinator) SetRouting(routing *config.Routing, clients map[string]*slack.Client) {
+	c.routing = routing
+	c.workspaces = clients
+}
+
+// workspaceFor returns the workspace an org's PRs are posted to, and false if the org is not routed anywhere.
+func (c *Coordinator) workspaceFor(org string) (string, bool) {
+	if c.routing == nil {
+		return config.DefaultWorkspace, true
+	}
+	return c.routing.WorkspaceFor(org)
+}
+
+// SetOrgGitHubClient serves an org with its own GitHub App's client instead of the shared one.
+func (c *Coordinator) SetOrgGitHubClient(org string, client *github.Client) {
+	if c.orgGitHub == nil {
+		c.orgGitHub = make(map[string]*github.Client)
+	}
+	c.orgGitHub[org] = client
+}
+
+// githubFor returns the GitHub client for an org.
+func (c *Coordinator) githubFor(org string) *github.Client {
+	if client, exists := c.orgGitHub[org]; exists {
+		return client
+	}
+	return c.github
+}
+
+// slackFor returns the Slack client for a workspace.
+func (c *Coordinator) slackFor(workspaceID string) *slack.Client {
+	if client, exists := c.workspaces[workspaceID]; exists {
+		return client
+	}
+	return c.slack
+}
+
+// workspaceIDs returns every routed workspace in sorted order.
+func (c *Coordinator) workspaceIDs() []string {
+	if len(c.workspaces) == 0 {
+		return []string{config.DefaultWorkspace}
+	}
+	ids := make([]string, 0, len(c.workspaces))
+	for id := range c.workspaces {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,134 @@
+// Package clock abstracts the passage of time, so time-dependent behavior can be
+// tested deterministically and driven by simulated time.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and makes tickers.
+type Clock interface {
+	Now() time.Time
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// Real is the system clock.
+var Real Clock = realClock{}
+
+// realClock is the system clock.
+type realClock struct{}
+
+// Now implements Clock.
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+// NewTicker implements Clock.
+func (realClock) NewTicker(d time.Duration) Ticker {
+	return realTicker{time.NewTicker(d)}
+}
+
+// realTicker adapts time.Ticker to Ticker.
+type realTicker struct {
+	t *time.Ticker
+}
+
+// C implements Ticker.
+func (r realTicker) C() <-chan time.Time {
+	return r.t.C
+}
+
+// Stop implements Ticker.
+func (r realTicker) Stop() {
+	r.t.Stop()
+}
+
+// Sim is a simulated clock that only moves when advanced. Its tickers fire as
+// time passes them; like time.Ticker, a tick is dropped if the last one hasn't
+// been received.
+type Sim struct {
+	now     time.Time
+	tickers []*simTicker
+	mu      sync.Mutex
+}
+
+// NewSim returns a simulated clock set to start.
+func NewSim(start time.Time) *Sim {
+	return &Sim{now: start}
+}
+
+// Now implements Clock.
+func (s *Sim) Now() time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.now
+}
+
+// NewTicker implements Clock.
+func (s *Sim) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t := &simTicker{sim: s, c: make(chan time.Time, 1), interval: d, next: s.now.Add(d)}
+	s.tickers = append(s.tickers, t)
+	return t
+}
+
+// Advance moves the clock forward by d, firing each ticker whose time passes.
+func (s *Sim) Advance(d time.Duration) {
+	s.Set(s.Now().Add(d))
+}
+
+// Set moves the clock to t, firing each ticker whose time passes. The clock never
+// moves backward.
+func (s *Sim) Set(t time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !t.After(s.now) {
+		return
+	}
+	s.now = t
+	for _, tk := range s.tickers {
+		for !tk.next.After(t) {
+			select {
+			case tk.c <- tk.next:
+			default:
+			}
+			tk.next = tk.next.Add(tk.interval)
+		}
+	}
+}
+
+// simTicker is a ticker driven by a Sim.
+type simTicker struct {
+	sim      *Sim
+	c        chan time.Time
+	next     time.Time
+	interval time.Duration
+}
+
+// C implements Ticker.
+func (t *simTicker) C() <-chan time.Time {
+	return t.c
+}
+
+// Stop implements Ticker.
+func (t *simTicker) Stop() {
+	t.sim.mu.Lock()
+	defer t.sim.mu.Unlock()
+	for i, tk := range t.sim.tickers {
+		if tk == t {
+			t.sim.tickers = append(t.sim.tickers[:i], t.sim.tickers[i+1:]...)
+			return
+		}
+	}
+}
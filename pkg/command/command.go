@@ -0,0 +1,459 @@
+// Package command parses the commands people give the bot, whether typed after a
+// slash command, in an @mention, or in a PR's thread, and routes them to handlers.
+package command
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Kind is what an argument must look like.
+type Kind int
+
+const (
+	// Word is any single word.
+	Word Kind = iota
+	// PR is a PR reference: owner/repo#123, its GitHub URL, or #123 in a channel one
+	// repo posts to. See ParsePR.
+	PR
+	// User is a Slack user, mentioned as @user.
+	User
+	// Rest is every remaining word. It must be the last argument.
+	Rest
+)
+
+// Arg is a positional argument.
+type Arg struct {
+	Name     string   // Shown in usage, such as "org".
+	Choices  []string // Words the argument must be one of, case-insensitively; empty allows any.
+	Kind     Kind
+	Optional bool
+}
+
+// Flag is an optional setting, written name=value anywhere after the command's name.
+type Flag struct {
+	Name    string
+	Example string // A sample value shown in usage, such as "1h".
+}
+
+// Command is a command and how to run it. E is what the caller knows about where
+// the command was given, such as the Slack user and channel, and R is its reply.
+type Command[E, R any] struct {
+	Run     func(ctx context.Context, env E, in *Input) R
+	Name    string // One or more words, such as "config lint".
+	Summary string // One line, shown in help.
+	// Usage replaces the usage generated from Args and Flags, for commands whose
+	// arguments read as a phrase, such as "me tomorrow".
+	Usage   string
+	Aliases []string
+	Args    []Arg
+	Flags   []Flag
+}
+
+// names returns the command's name and aliases, each split into words.
+func (c *Command[E, R]) names() [][]string {
+	names := [][]string{strings.Fields(c.Name)}
+	for _, alias := range c.Aliases {
+		names = append(names, strings.Fields(alias))
+	}
+	return names
+}
+
+// usage renders how to invoke the command, such as "simulate <org> [days] [delay=1h]".
+func (c *Command[E, R]) usage() string {
+	if c.Usage != "" {
+		return c.Name + " " + c.Usage
+	}
+	parts := []string{c.Name}
+	for _, a := range c.Args {
+		var s string
+		switch {
+		case len(a.Choices) > 0:
+			s = strings.Join(a.Choices, "|")
+		case a.Kind == PR:
+			s = "owner/repo#123"
+		case a.Kind == User:
+			s = "@" + a.Name
+		case a.Kind == Rest:
+			s = a.Name + "..."
+		default:
+			s = a.Name
+		}
+		if a.Optional {
+			s = "[" + s + "]"
+		} else if len(a.Choices) == 0 && a.Kind != User {
+			s = "<" + s + ">"
+		}
+		parts = append(parts, s)
+	}
+	for _, f := range c.Flags {
+		parts = append(parts, "["+f.Name+"="+f.Example+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+// Registry holds a set of commands.
+type Registry[E, R any] struct {
+	reply    func(text string) R
+	repos    func(ctx context.Context, env E) []string
+	commands []*Command[E, R]
+}
+
+// NewRegistry creates an empty registry whose usage and argument errors are
+// replied with reply.
+func NewRegistry[E, R any](reply func(text string) R) *Registry[E, R] {
+	return &Registry[E, R]{reply: reply}
+}
+
+// SetRepos sets how to find the repos, as owner/repo, that a bare #123 given
+// where env says may refer to, such as those posting to the channel.
+func (r *Registry[E, R]) SetRepos(repos func(ctx context.Context, env E) []string) {
+	r.repos = repos
+}
+
+// Register adds commands, in the order help lists them.
+func (r *Registry[E, R]) Register(cmds ...Command[E, R]) {
+	for i := range cmds {
+		r.commands = append(r.commands, &cmds[i])
+	}
+}
+
+// Find returns the command words invoke and the words after its name, preferring
+// the longest matching name. It returns nil if no command matches.
+func (r *Registry[E, R]) Find(words []string) (*Command[E, R], []string) {
+	var found *Command[E, R]
+	var length int
+	for _, cmd := range r.commands {
+		for _, name := range cmd.names() {
+			if len(name) > length && len(name) <= len(words) && equalFold(name, words[:len(name)]) {
+				found, length = cmd, len(name)
+			}
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+	return found, words[length:]
+}
+
+// Run runs the command text names. It reports false if no command matches, leaving
+// the reply to the caller. Replies about bad arguments show the command's usage,
+// written after prefix, which is how commands are invoked, such as "/r2r ".
+func (r *Registry[E, R]) Run(ctx context.Context, env E, prefix, text string) (R, bool) {
+	words := Fields(text)
+	cmd, rest := r.Find(words)
+	if cmd == nil {
+		if usages := r.partial(prefix, words); len(usages) > 0 {
+			return r.reply("Usage: " + strings.Join(usages, " or ")), true
+		}
+		var zero R
+		return zero, false
+	}
+
+	in, problem := parse(cmd, rest, func() []string {
+		if r.repos == nil {
+			return nil
+		}
+		return r.repos(ctx, env)
+	})
+	if problem != "" {
+		return r.reply(problem + "\nUsage: `" + prefix + cmd.usage() + "`"), true
+	}
+	return cmd.Run(ctx, env, in), true
+}
+
+// partial returns the usages of the multi-word commands words begin, such as both
+// config subcommands for "config" alone.
+func (r *Registry[E, R]) partial(prefix string, words []string) []string {
+	if len(words) == 0 {
+		return nil
+	}
+	var usages []string
+	for _, cmd := range r.commands {
+		name := strings.Fields(cmd.Name)
+		if len(name) > len(words) && equalFold(name[:len(words)], words) {
+			usages = append(usages, "`"+prefix+cmd.usage()+"`")
+		}
+	}
+	return usages
+}
+
+// Names returns the first word of every command, in registration order, without duplicates.
+func (r *Registry[E, R]) Names() []string {
+	var names []string
+	for _, cmd := range r.commands {
+		if first := strings.Fields(cmd.Name)[0]; !slices.Contains(names, first) {
+			names = append(names, first)
+		}
+	}
+	return names
+}
+
+// Help lists every command's usage and summary, one per line.
+func (r *Registry[E, R]) Help(prefix string) string {
+	lines := make([]string, 0, len(r.commands))
+	for _, cmd := range r.commands {
+		lines = append(lines, "• `"+prefix+cmd.usage()+"` - "+cmd.Summary)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CommandHelp describes the commands words name, or begin, reporting false if none do.
+func (r *Registry[E, R]) CommandHelp(prefix string, words []string) (string, bool) {
+	if cmd, _ := r.Find(words); cmd != nil {
+		text := "`" + prefix + cmd.usage() + "`\n" + cmd.Summary
+		if len(cmd.Aliases) > 0 {
+			text += "\nAlso: " + strings.Join(cmd.Aliases, ", ")
+		}
+		return text, true
+	}
+	if usages := r.partial(prefix, words); len(usages) > 0 {
+		return strings.Join(usages, "\n"), true
+	}
+	return "", false
+}
+
+// Fields splits command text into words at spaces, keeping text in double quotes,
+// straight or curled as Slack sends them, together as one word without its quotes,
+// so an argument such as a team name can hold spaces. An unclosed quote runs to the
+// end of the text.
+func Fields(text string) []string {
+	var words []string
+	var word strings.Builder
+	var inWord, quoted bool
+	for _, r := range text {
+		switch {
+		case r == '"' || r == '“' || r == '”':
+			quoted = !quoted
+			inWord = true
+		case unicode.IsSpace(r) && !quoted:
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(r)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words
+}
+
+// equalFold reports whether two lists of words match, case-insensitively.
+func equalFold(a, b []string) bool {
+	return slices.EqualFunc(a, b, strings.EqualFold)
+}
+
+// Input is a command's parsed arguments.
+type Input struct {
+	args  map[string]string
+	flags map[string]string
+	prs   map[string]PRRef
+	Name  string // The command's registered name.
+	// Words are the words after the command's name, as typed, flags included.
+	Words []string
+}
+
+// Arg returns an argument by name, or "" if it was optional and not given. A Rest
+// argument's words are joined by spaces.
+func (in *Input) Arg(name string) string {
+	return in.args[name]
+}
+
+// PR returns a PR argument by name, and whether it was given.
+func (in *Input) PR(name string) (PRRef, bool) {
+	ref, ok := in.prs[name]
+	return ref, ok
+}
+
+// User returns the ID of a User argument by name, or "" if it was optional and not given.
+func (in *Input) User(name string) string {
+	return in.args[name]
+}
+
+// Flag returns a flag's value, and whether it was given.
+func (in *Input) Flag(name string) (string, bool) {
+	v, ok := in.flags[name]
+	return v, ok
+}
+
+// parse matches words against a command's arguments and flags, or explains what is
+// wrong with them. repos is called for the repos a bare #123 may refer to.
+func parse[E, R any](cmd *Command[E, R], words []string, repos func() []string) (*Input, string) {
+	in := &Input{
+		Name:  cmd.Name,
+		Words: words,
+		args:  make(map[string]string),
+		flags: make(map[string]string),
+		prs:   make(map[string]PRRef),
+	}
+
+	var positional []string
+	for _, w := range words {
+		name, value, isFlag := strings.Cut(w, "=")
+		if !isFlag || len(cmd.Flags) == 0 {
+			positional = append(positional, w)
+			continue
+		}
+		if !slices.ContainsFunc(cmd.Flags, func(f Flag) bool { return strings.EqualFold(f.Name, name) }) {
+			return nil, fmt.Sprintf("`%s` is not a setting of `%s`.", name, cmd.Name)
+		}
+		in.flags[strings.ToLower(name)] = value
+	}
+
+	for i, a := range cmd.Args {
+		if i >= len(positional) {
+			if !a.Optional {
+				return nil, fmt.Sprintf("`%s` needs %s.", cmd.Name, describe(a))
+			}
+			break
+		}
+		w := positional[i]
+		if a.Kind == Rest {
+			in.args[a.Name] = strings.Join(positional[i:], " ")
+			positional = positional[:i+1]
+			break
+		}
+		if len(a.Choices) > 0 {
+			j := slices.IndexFunc(a.Choices, func(c string) bool { return strings.EqualFold(c, w) })
+			if j < 0 {
+				return nil, fmt.Sprintf("`%s` is not one of %s.", w, strings.Join(a.Choices, ", "))
+			}
+			w = a.Choices[j]
+		}
+		switch a.Kind {
+		case PR:
+			var candidates []string
+			if strings.HasPrefix(w, "#") {
+				candidates = repos()
+			}
+			ref, err := ParsePR(w, candidates)
+			if err != nil {
+				return nil, err.Error() + "."
+			}
+			in.prs[a.Name] = ref
+		case User:
+			id, ok := ParseUser(w)
+			if !ok {
+				return nil, fmt.Sprintf("`%s` is not a Slack user; mention them as @user.", w)
+			}
+			w = id
+		default:
+		}
+		in.args[a.Name] = w
+	}
+	if len(positional) > len(cmd.Args) {
+		return nil, fmt.Sprintf("`%s` doesn't take `%s`.", cmd.Name, strings.Join(positional[len(cmd.Args):], " "))
+	}
+	return in, ""
+}
+
+// describe names what an argument should be, for an error about its absence.
+func describe(a Arg) string {
+	switch {
+	case len(a.Choices) > 0:
+		return strings.Join(a.Choices, " or ")
+	case a.Kind == PR:
+		return "a PR, like `owner/repo#123` or its link"
+	case a.Kind == User:
+		return "a user, mentioned as @user"
+	default:
+		return "`<" + a.Name + ">`"
+	}
+}
+
+// PRRef identifies a pull request.
+type PRRef struct {
+	Owner  string
+	Repo   string
+	Number int
+}
+
+// String formats the reference as owner/repo#123.
+func (p PRRef) String() string {
+	return fmt.Sprintf("%s/%s#%d", p.Owner, p.Repo, p.Number)
+}
+
+// ParsePR parses a PR reference: owner/repo#123, a GitHub URL such as
+// https://github.com/owner/repo/pull/123 (as Slack formats links, too), or a bare
+// #123 when repos, given as owner/repo, holds exactly one repo it can refer to.
+// Its errors explain what is wrong with ref and are meant to be shown to the user.
+func ParsePR(ref string, repos []string) (PRRef, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return PRRef{}, errors.New("no PR given; use `owner/repo#123` or the PR's link")
+	}
+	if strings.HasPrefix(ref, "<") && strings.HasSuffix(ref, ">") {
+		ref, _, _ = strings.Cut(strings.TrimSuffix(strings.TrimPrefix(ref, "<"), ">"), "|")
+	}
+	if strings.Contains(ref, "github.com/") {
+		return parsePRURL(ref)
+	}
+
+	path, num, found := strings.Cut(ref, "#")
+	if !found {
+		return PRRef{}, fmt.Errorf("`%s` is not a PR reference; use `owner/repo#123` or the PR's link", ref)
+	}
+	number, err := strconv.Atoi(num)
+	if err != nil || number <= 0 {
+		return PRRef{}, fmt.Errorf("`%s` doesn't end in a PR number, like `#123`", ref)
+	}
+	if path == "" {
+		switch len(repos) {
+		case 0:
+			return PRRef{}, fmt.Errorf("`%s` needs its repo, as no repo posts to this channel; use `owner/repo#%d`", ref, number)
+		case 1:
+			path = repos[0]
+		default:
+			return PRRef{}, fmt.Errorf("`%s` could be in %s; use `owner/repo#%d`", ref, strings.Join(repos, ", "), number)
+		}
+	}
+	owner, repo, found := strings.Cut(path, "/")
+	if !found || owner == "" || repo == "" || strings.Contains(repo, "/") {
+		return PRRef{}, fmt.Errorf("`%s` is not a repo; use `owner/repo#%d`", path, number)
+	}
+	return PRRef{Owner: owner, Repo: repo, Number: number}, nil
+}
+
+// parsePRURL parses a GitHub PR URL, ignoring any tab, query, or fragment after its number.
+func parsePRURL(url string) (PRRef, error) {
+	_, path, _ := strings.Cut(url, "github.com/")
+	path, _, _ = strings.Cut(path, "?")
+	path, _, _ = strings.Cut(path, "#")
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 || parts[0] == "" || parts[1] == "" || (parts[2] != "pull" && parts[2] != "pulls") {
+		return PRRef{}, fmt.Errorf("`%s` is not a link to a PR, like `https://github.com/owner/repo/pull/123`", url)
+	}
+	number, err := strconv.Atoi(parts[3])
+	if err != nil || number <= 0 {
+		return PRRef{}, fmt.Errorf("`%s` doesn't link to a PR number", url)
+	}
+	return PRRef{Owner: parts[0], Repo: parts[1], Number: number}, nil
+}
+
+// userIDPattern matches a Slack user ID, such as U024BE7LH.
+var userIDPattern = regexp.MustCompile(`^[UW][A-Z0-9]{8,}$`)
+
+// ParseUser extracts the user ID from a Slack mention such as <@U024BE7LH|name>,
+// also accepting a bare user ID. All-caps words that merely start with U or W, such
+// as USA or WIP, are not IDs.
+func ParseUser(arg string) (string, bool) {
+	if strings.HasPrefix(arg, "<@") && strings.HasSuffix(arg, ">") {
+		arg = strings.TrimSuffix(strings.TrimPrefix(arg, "<@"), ">")
+		arg, _, _ = strings.Cut(arg, "|")
+	}
+	if !userIDPattern.MatchString(arg) || !strings.ContainsAny(arg, "0123456789") {
+		return "", false
+	}
+	return arg, true
+}
@@ -0,0 +1,201 @@
+package command
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// testRegistry registers commands whose replies name the command and its input.
+func testRegistry() *Registry[string, string] {
+	r := NewRegistry[string](func(text string) string { return text })
+	run := func(_ context.Context, env string, in *Input) string {
+		note, _ := in.Flag("note")
+		return strings.Join([]string{env, in.Name, in.Arg("org"), in.Arg("team"), note}, "|")
+	}
+	r.Register(
+		Command[string, string]{Name: "config lint", Run: run, Args: []Arg{{Name: "org"}}},
+		Command[string, string]{Name: "config reload", Run: run},
+		Command[string, string]{Name: "config", Run: run, Args: []Arg{{Name: "org", Optional: true}}},
+		Command[string, string]{Name: "list", Aliases: []string{"ls"}, Run: run},
+		Command[string, string]{Name: "leaderboard", Run: run, Args: []Arg{{Name: "org"}}},
+		Command[string, string]{Name: "team set", Run: run, Args: []Arg{{Name: "team"}}, Flags: []Flag{{Name: "note", Example: "text"}}},
+		Command[string, string]{Name: "team add", Run: run, Args: []Arg{{Name: "team"}}},
+	)
+	return r
+}
+
+func TestRegistryRun(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		want   string
+		wantOK bool
+	}{
+		{name: "longest name wins", text: "config lint acme", want: "env|config lint|acme||", wantOK: true},
+		{name: "shorter name", text: "config acme", want: "env|config|acme||", wantOK: true},
+		{name: "any case", text: "CONFIG Reload", want: "env|config reload|||", wantOK: true},
+		{name: "alias", text: "ls", want: "env|list|||", wantOK: true},
+		{name: "extra spaces", text: "  leaderboard   acme ", want: "env|leaderboard|acme||", wantOK: true},
+		{name: "ambiguous prefix", text: "team", want: "Usage: `/r2r team set <team> [note=text]` or `/r2r team add <team>`", wantOK: true},
+		{name: "quoted argument", text: `team set "Platform Infra"`, want: "env|team set||Platform Infra|", wantOK: true},
+		{name: "curly quotes", text: "team add “Platform Infra”", want: "env|team add||Platform Infra|", wantOK: true},
+		{name: "quoted flag", text: `team set core note="back Monday"`, want: "env|team set||core|back Monday", wantOK: true},
+		{name: "unknown flag", text: "team set core owner=me", want: "`owner` is not a setting of `team set`.\nUsage: `/r2r team set <team> [note=text]`", wantOK: true},
+		{name: "missing argument", text: "leaderboard", want: "`leaderboard` needs `<org>`.\nUsage: `/r2r leaderboard <org>`", wantOK: true},
+		{name: "extra argument", text: "list acme", want: "`list` doesn't take `acme`.\nUsage: `/r2r list`", wantOK: true},
+		{name: "split quoted argument", text: "team add Platform Infra", want: "`team add` doesn't take `Infra`.\nUsage: `/r2r team add <team>`", wantOK: true},
+		{name: "unknown command", text: "frobnicate acme"},
+		{name: "word prefix is not a command", text: "leader acme"},
+		{name: "longer word is not a command", text: "configure acme"},
+		{name: "empty", text: ""},
+	}
+	r := testRegistry()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := r.Run(context.Background(), "env", "/r2r ", tt.text)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("Run(%q) = %q, %v, want %q, %v", tt.text, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{text: "", want: nil},
+		{text: "  list  ", want: []string{"list"}},
+		{text: "team set core", want: []string{"team", "set", "core"}},
+		{text: `team set "Platform Infra"`, want: []string{"team", "set", "Platform Infra"}},
+		{text: "team set “Platform Infra”", want: []string{"team", "set", "Platform Infra"}},
+		{text: `note="back Monday" now`, want: []string{"note=back Monday", "now"}},
+		{text: `say "unclosed quote`, want: []string{"say", "unclosed quote"}},
+		{text: `say ""`, want: []string{"say", ""}},
+		{text: "don't split", want: []string{"don't", "split"}},
+	}
+	for _, tt := range tests {
+		if got := Fields(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Fields(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCommandHelp(t *testing.T) {
+	r := testRegistry()
+	tests := []struct {
+		words  []string
+		want   string
+		wantOK bool
+	}{
+		{words: []string{"ls"}, want: "`/r2r list`\n\nAlso: ls", wantOK: true},
+		{words: []string{"team"}, want: "`/r2r team set <team> [note=text]`\n`/r2r team add <team>`", wantOK: true},
+		{words: []string{"nope"}},
+	}
+	for _, tt := range tests {
+		got, ok := r.CommandHelp("/r2r ", tt.words)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("CommandHelp(%q) = %q, %v, want %q, %v", tt.words, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParseUser(t *testing.T) {
+	tests := []struct {
+		arg    string
+		want   string
+		wantOK bool
+	}{
+		{arg: "<@U024BE7LH>", want: "U024BE7LH", wantOK: true},
+		{arg: "<@U024BE7LH|alice>", want: "U024BE7LH", wantOK: true},
+		{arg: "<@W012A3CDE>", want: "W012A3CDE", wantOK: true},
+		{arg: "U024BE7LH", want: "U024BE7LH", wantOK: true},
+		{arg: "USA"},
+		{arg: "WIP"},
+		{arg: "UNDERSTOOD"},
+		{arg: "U1234"},
+		{arg: "u024be7lh"},
+		{arg: "<@USA>"},
+		{arg: "<#C024BE91L>"},
+		{arg: "@alice"},
+		{arg: ""},
+	}
+	for _, tt := range tests {
+		got, ok := ParseUser(tt.arg)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("ParseUser(%q) = %q, %v, want %q, %v", tt.arg, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestParsePR(t *testing.T) {
+	want := PRRef{Owner: "acme", Repo: "api", Number: 42}
+	tests := []struct {
+		name    string
+		ref     string
+		repos   []string
+		want    PRRef
+		wantErr bool
+	}{
+		{name: "reference", ref: "acme/api#42", want: want},
+		{name: "padded reference", ref: "  acme/api#42 ", want: want},
+		{name: "URL", ref: "https://github.com/acme/api/pull/42", want: want},
+		{name: "URL with tab", ref: "https://github.com/acme/api/pull/42/files", want: want},
+		{name: "URL with query and fragment", ref: "https://github.com/acme/api/pull/42?w=1#discussion_r1", want: want},
+		{name: "pulls URL", ref: "github.com/acme/api/pulls/42", want: want},
+		{name: "Slack link", ref: "<https://github.com/acme/api/pull/42|acme/api#42>", want: want},
+		{name: "bare number in a one-repo channel", ref: "#42", repos: []string{"acme/api"}, want: want},
+		{name: "bare number without channel repos", ref: "#42", wantErr: true},
+		{name: "bare number in a multi-repo channel", ref: "#42", repos: []string{"acme/api", "acme/web"}, wantErr: true},
+		{name: "empty", ref: "", wantErr: true},
+		{name: "no number", ref: "acme/api", wantErr: true},
+		{name: "non-numeric number", ref: "acme/api#abc", wantErr: true},
+		{name: "zero", ref: "acme/api#0", wantErr: true},
+		{name: "negative", ref: "acme/api#-1", wantErr: true},
+		{name: "no repo", ref: "acme#42", wantErr: true},
+		{name: "empty owner", ref: "/api#42", wantErr: true},
+		{name: "nested path", ref: "acme/api/extra#42", wantErr: true},
+		{name: "issue URL", ref: "https://github.com/acme/api/issues/42", wantErr: true},
+		{name: "repo URL", ref: "https://github.com/acme/api", wantErr: true},
+		{name: "URL without number", ref: "https://github.com/acme/api/pull/new", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePR(tt.ref, tt.repos)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("ParsePR(%q) = %v, want an error", tt.ref, got)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ParsePR(%q) = %v, %v, want %v", tt.ref, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParsePRURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		want    PRRef
+		wantErr bool
+	}{
+		{url: "https://github.com/acme/api/pull/7", want: PRRef{Owner: "acme", Repo: "api", Number: 7}},
+		{url: "https://github.com/acme/api/pull/7/commits?page=2", want: PRRef{Owner: "acme", Repo: "api", Number: 7}},
+		{url: "https://github.com/acme/api/pull/7#issuecomment-1", want: PRRef{Owner: "acme", Repo: "api", Number: 7}},
+		{url: "https://github.com/acme/api/pull", wantErr: true},
+		{url: "https://github.com//api/pull/7", wantErr: true},
+		{url: "https://github.com/acme/api/tree/7", wantErr: true},
+		{url: "https://github.com/acme/api/pull/-7", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parsePRURL(tt.url)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parsePRURL(%q) = %v, %v, want %v, error %v", tt.url, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
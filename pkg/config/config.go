@@ -5,47 +5,283 @@ import (
 	"context"
 	"errors"
 	"log/slog"
-	"net/http"
+	"strings"
 	"sync"
 	"time"
 
-	"github.com/codeGROOVE-dev/retry"
+	"github.com/codeGROOVE-dev/slacker/pkg/events"
 	"github.com/google/go-github/v50/github"
-	"gopkg.in/yaml.v3"
 )
 
 // ServerConfig holds the server configuration from environment variables.
 type ServerConfig struct {
+	HTTPProxy            string
 	DataDir              string
+	DataBackend          string // DataBackendFile, DataBackendPostgres, or DataBackendSQLite.
+	DatabaseURL          string // Postgres connection string, or SQLite file path; empty uses DataDir/state.db.
+	DatabaseDriver       string // database/sql driver name; empty uses pgx or sqlite.
 	SlackToken           string
 	SlackSigningSecret   string
 	GitHubAppID          string
 	GitHubPrivateKey     string
 	GitHubInstallationID string
 	SprinklerURL         string
+	RoutingFile          string
+	AdminToken           string
+	APIToken             string
+	EventSinkURL         string
+	EventSinkSecret      string
+	MetricsPushURL       string // OTLP/HTTP endpoint or statsd://host:port to push metrics to; empty only serves /metrics.
+	TelemetryURL         string // Opts in to reporting anonymized usage to this endpoint; empty reports nothing.
+	PluginWebhookURL     string
+	TurnURL              string
+	TurnToken            string
+	TLSCertFile          string
+	TLSKeyFile           string
+	TLSClientCAFile      string
+	HTTPTimeout          time.Duration
+	HTTPKeepAlive        time.Duration
+	HTTPIdleConnTimeout  time.Duration
+	StateIdleEviction    time.Duration // Drop workspaces unused this long from memory; zero keeps them.
+	StateMaxPRAge        time.Duration // Stop tracking PRs without activity for this long; zero keeps them.
+	MetricsPushInterval  time.Duration // How often metrics are pushed; zero uses the default.
+	EventHandoffWait     time.Duration // How long to wait at startup for the previous instance's handoff.
+	TelemetryInterval    time.Duration // How often telemetry is reported; zero uses the default.
+	SlackIPRanges        []string
+	EventTypes           []string
+	SlashCommands        []string // Slash command names for workspaces that don't list their own.
+	SlackAdmins          []string // Slack user IDs allowed admin-only subcommands in every workspace.
+	EventWorkers         int
+	EventQueueSize       int
+	EventWorkersPerOrg   int
+	OrgAPIRate           int
+	StateMemoryBudgetMB  int // Evict least recently used workspaces past this size; zero is unlimited.
+	StateBackups         int // Previous copies kept of each state file; zero uses the default.
+	StateMaxPRs          int // PRs tracked per workspace before the oldest are dropped; zero is unlimited.
+	IPAllowlist          bool
+	TrustProxyHeaders    bool
+	AllowSharedDataDir   bool   // Start even if DataDir is group or world writable.
+	SkipScopeCheck       bool   // Start without checking that Slack bot tokens have the scopes features need.
+	EventHandoff         bool   // Hand the event stream between instances through DataDir during rollouts.
+	TenantKey            string // Base64 AES-256 key encrypting tenant credentials; empty disables tenants.
 }
 
+// Backends that bot state can be stored in.
+const (
+	// DataBackendFile stores each workspace as a gzipped JSON file in DataDir.
+	DataBackendFile = "file"
+	// DataBackendPostgres stores workspaces in the Postgres database at DatabaseURL.
+	DataBackendPostgres = "postgres"
+	// DataBackendSQLite stores workspaces in the SQLite database file at DatabaseURL.
+	DataBackendSQLite = "sqlite"
+)
+
 // RepoConfig represents the slack.yaml configuration for a GitHub org.
 type RepoConfig struct {
-	Repos map[string]struct {
-		Channels []string `yaml:"channels"`
-	} `yaml:"repos"`
-	Global struct {
-		Prefix string `yaml:"prefix"`
-	} `yaml:"global"`
+	Repos   map[string]RepoSettings `yaml:"repos"`
+	Global  GlobalConfig            `yaml:"global"`
+	Extends fileList                `yaml:"extends"` // Files in the .github repo this config builds on.
+	// Users maps GitHub logins to Slack users, each given as a user ID or an email address.
+	Users map[string]string `yaml:"users"`
+	// Teams sets how teams requested to review PRs are notified, by team slug.
+	Teams map[string]TeamSettings `yaml:"teams"`
+	// Fragments holds anchored blocks for reuse elsewhere in the file; it is otherwise ignored.
+	Fragments map[string]any `yaml:"fragments"`
+}
+
+// GlobalConfig holds org-wide settings from slack.yaml.
+type GlobalConfig struct {
+	Prefix    string          `yaml:"prefix"`
+	Reactions string          `yaml:"reactions"` // ReactionsReplace, ReactionsAccumulate, or ReactionsNone.
+	Tone      string          `yaml:"tone"`      // ToneNeutral, ToneFriendly, or ToneTerse.
+	Digest    DigestConfig    `yaml:"digest"`
+	Staleness StalenessConfig `yaml:"staleness"`
+	// OnFetchError is FetchErrorsDegrade, FetchErrorsHold, or FetchErrorsUnknown.
+	OnFetchError string `yaml:"on_fetch_error"`
+	// TopicCounts appends open and blocked PR counts to the topic of each channel with PR threads.
+	TopicCounts bool `yaml:"topic_counts"`
+	// ThreadLinks comments on each PR with a link to its Slack thread.
+	ThreadLinks bool `yaml:"thread_links"`
+	// SlackCheck adds a neutral check run to each PR whose details link to its Slack thread.
+	SlackCheck bool `yaml:"slack_check"`
+	// HoldForConversations keeps approved PRs with unresolved review conversations
+	// from being shown as approved.
+	HoldForConversations bool `yaml:"hold_for_conversations"`
+	// UrgentLabels are PR labels that mark a PR as urgent, pinning its thread until it is resolved.
+	UrgentLabels []string          `yaml:"urgent_labels"`
+	LargePR      LargePRConfig     `yaml:"large_pr"`
+	Leaderboard  LeaderboardConfig `yaml:"leaderboard"`
+	Freezes      []FreezeWindow    `yaml:"freeze_windows"`
+	// BackportLabel is the prefix of labels requesting a backport, such as "backport release-1.2".
+	BackportLabel string `yaml:"backport_label"`
+	// StaleApprovals re-requests review from approvers after significant new commits.
+	StaleApprovals StaleApprovalsConfig `yaml:"stale_approvals"`
+	// FailureIssues files an issue in the org's .github repo when a repo's events keep failing.
+	FailureIssues bool `yaml:"failure_issues"`
+	// Deferral lets people defer a PR to later in the week by reacting to its thread.
+	Deferral DeferralConfig `yaml:"defer"`
+}
+
+// FreezeWindow is a period, such as a deployment freeze, during which approved PRs should not be merged.
+type FreezeWindow struct {
+	Start  time.Time `yaml:"start"`
+	End    time.Time `yaml:"end"`
+	Reason string    `yaml:"reason"`
+}
+
+// LeaderboardConfig opts an org in to /r2r leaderboard.
+type LeaderboardConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Plain lists reviewers alphabetically, without ranks or medals.
+	Plain bool `yaml:"plain"`
+}
+
+// StaleApprovalsConfig re-requests review from a PR's approvers when new commits change
+// at least Lines added plus deleted lines, for repos that don't dismiss stale approvals.
+type StaleApprovalsConfig struct {
+	Rerequest bool `yaml:"rerequest"`
+	Lines     int  `yaml:"lines"` // Zero re-requests review after any push.
+}
+
+// DeferralConfig relaxes the nudges for a PR whose thread someone reacts to with Emoji,
+// stretching its notify delay and staleness thresholds by Scale.
+type DeferralConfig struct {
+	Emoji string  `yaml:"emoji"` // Reaction name, such as "calendar"; empty turns deferral off.
+	Scale float64 `yaml:"scale"` // Zero uses DefaultDeferralScale.
+}
+
+// DefaultDeferralScale is how much a deferral stretches nudge timings unless an org sets scale.
+const DefaultDeferralScale = 3
+
+// LargePRConfig sets the size past which a PR is flagged as large. Zero disables a threshold.
+type LargePRConfig struct {
+	Files int `yaml:"files"` // Changed files.
+	Lines int `yaml:"lines"` // Added plus deleted lines.
+}
+
+// Reaction modes for showing PR state on a thread's parent message.
+const (
+	// ReactionsReplace keeps a single reaction showing the current state.
+	ReactionsReplace = "replace"
+	// ReactionsAccumulate adds a reaction per state, leaving earlier ones as history.
+	ReactionsAccumulate = "accumulate"
+	// ReactionsNone edits the parent message to show the state instead of reacting.
+	ReactionsNone = "none"
+)
+
+// Tones for the phrasing of nudges and digests.
+const (
+	// ToneNeutral states what a PR is waiting on, such as "waiting for your review".
+	ToneNeutral = "neutral"
+	// ToneFriendly softens nudges, such as "could use your review when you have a moment".
+	ToneFriendly = "friendly"
+	// ToneTerse keeps nudges to the action, such as "review".
+	ToneTerse = "terse"
+)
+
+// Policies for a PR's state when some of its checks or reviews can't be fetched from GitHub.
+const (
+	// FetchErrorsDegrade derives the state from whatever was fetched.
+	FetchErrorsDegrade = "degrade"
+	// FetchErrorsHold keeps the PR's previous state.
+	FetchErrorsHold = "hold"
+	// FetchErrorsUnknown shows the state as unknown until it can be determined.
+	FetchErrorsUnknown = "unknown"
+)
+
+// StalenessConfig sets when PR age and inactivity are emphasized in messages.
+type StalenessConfig struct {
+	OpenDays int `yaml:"open_days"` // Emphasize PRs open longer than this, defaults to 7.
+	IdleDays int `yaml:"idle_days"` // Emphasize PRs idle longer than this, defaults to 3.
+}
+
+// RepoSettings holds per-repo settings from slack.yaml.
+type RepoSettings struct {
+	Channels []string `yaml:"channels"`
+	// RequiredReviewers must all approve before the PR is shown as approved, in addition
+	// to GitHub's own rules. Entries are GitHub logins or "team:slug".
+	RequiredReviewers []string `yaml:"required_reviewers"`
+	// MilestoneSummaries keeps a pinned progress summary per milestone in the repo's channels.
+	MilestoneSummaries bool `yaml:"milestone_summaries"`
+	// Severity is SeverityCritical, SeverityStandard, or SeverityLow.
+	Severity string `yaml:"severity"`
+}
+
+// TeamSettings sets how a team a PR waits on is notified, so a review request to a
+// large team doesn't DM everyone on it.
+type TeamSettings struct {
+	// Fanout is FanoutAll, FanoutUsergroup, FanoutRandom, or FanoutLead.
+	Fanout string `yaml:"fanout"`
+	// Usergroup is the ID of the Slack usergroup mentioned for FanoutUsergroup, such as S0123ABCD.
+	Usergroup string `yaml:"usergroup"`
+	// Count is how many members FanoutRandom notifies, defaulting to DefaultFanoutCount.
+	Count int `yaml:"count"`
+	// Lead is the GitHub login FanoutLead notifies.
+	Lead string `yaml:"lead"`
+}
+
+// Fanout policies for a team a PR waits on.
+const (
+	// FanoutAll notifies every member, for teams no larger than the expansion limit.
+	FanoutAll = "all"
+	// FanoutUsergroup mentions the team's Slack usergroup in the PR's thread instead of notifying members.
+	FanoutUsergroup = "usergroup"
+	// FanoutRandom notifies Count members, picked per PR so each PR keeps the same ones.
+	FanoutRandom = "random"
+	// FanoutLead notifies only the team's lead.
+	FanoutLead = "lead"
+)
+
+// DefaultFanoutCount is how many members FanoutRandom notifies unless a team sets count.
+const DefaultFanoutCount = 2
+
+// Severity tiers for how aggressively a repo's PRs are chased.
+const (
+	// SeverityCritical halves nudge delays, staleness windows, and review claims.
+	SeverityCritical = "critical"
+	// SeverityStandard uses the configured and default timings as they are.
+	SeverityStandard = "standard"
+	// SeverityLow doubles them, and blocked users are only DMed, never mentioned in threads.
+	SeverityLow = "low"
+)
+
+// severityScales stretches timings for each tier other than standard.
+var severityScales = map[string]float64{
+	SeverityCritical: 0.5,
+	SeverityLow:      2,
+}
+
+// DigestConfig schedules the weekly open PR digest posted to each configured channel.
+type DigestConfig struct {
+	Day      string `yaml:"day"`      // Weekday name, e.g. "monday".
+	Time     string `yaml:"time"`     // Local time in 24-hour HH:MM format, e.g. "09:00".
+	Timezone string `yaml:"timezone"` // IANA timezone name, defaults to UTC.
+	Enabled  bool   `yaml:"enabled"`
+}
+
+// defaultRepoConfig returns the config used when an org has no usable slack.yaml.
+func defaultRepoConfig() *RepoConfig {
+	return &RepoConfig{
+		Global: GlobalConfig{Prefix: ":postal_horn:"},
+		Repos:  make(map[string]RepoSettings),
+	}
 }
 
 // Manager manages repository configurations.
 type Manager struct {
-	configs map[string]*RepoConfig
-	client  *github.Client
-	mu      sync.RWMutex
+	configs    map[string]*RepoConfig
+	client     *github.Client
+	orgClients map[string]*github.Client // Orgs that fetch configs with their own GitHub App.
+	renamed    map[string]string         // Previous names of renamed repos, by lowercased org/repo.
+	bus        *events.Bus
+	mu         sync.RWMutex
 }
 
 // New creates a new config manager.
 func New(ctx context.Context) *Manager {
 	return &Manager{
 		configs: make(map[string]*RepoConfig),
+		renamed: make(map[string]string),
 	}
 }
 
@@ -56,89 +292,48 @@ func (m *Manager) SetGitHubClient(client *github.Client) {
 	m.client = client
 }
 
-// LoadConfig loads the configuration for a GitHub org with retry logic.
-func (m *Manager) LoadConfig(ctx context.Context, org string) error {
-	slog.Info("loading config", "org", org)
-
+// SetBus sets the bus that reloaded org configs are published to.
+func (m *Manager) SetBus(b *events.Bus) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
+	m.bus = b
+}
 
-	if m.client == nil {
-		return errors.New("github client not initialized")
+// SetOrgGitHubClient sets the GitHub client for fetching an org's configs, in place
+// of the shared client, for orgs with a GitHub App of their own.
+func (m *Manager) SetOrgGitHubClient(org string, client *github.Client) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.orgClients == nil {
+		m.orgClients = make(map[string]*github.Client)
 	}
+	m.orgClients[org] = client
+}
 
-	var content *github.RepositoryContent
-	var configContent string
-
-	// Fetch the config file with retry
-	err := retry.Do(
-		func() error {
-			var err error
-			content, _, _, err = m.client.Repositories.GetContents(
-				ctx,
-				org,
-				".github",
-				"codeGROOVE/slack.yaml",
-				nil,
-			)
-			if err != nil {
-				// Check if it's a 404 - config might not exist yet
-				var ghErr *github.ErrorResponse
-				if errors.As(err, &ghErr) && ghErr.Response.StatusCode == http.StatusNotFound {
-					slog.Debug("config file not found, using defaults", "org", org)
-					return retry.Unrecoverable(err)
-				}
-				slog.Warn("failed to fetch config, retrying", "org", org, "error", err)
-				return err
-			}
+// clientFor returns the GitHub client for fetching an org's configs (must hold lock).
+func (m *Manager) clientFor(org string) *github.Client {
+	if client, exists := m.orgClients[org]; exists {
+		return client
+	}
+	return m.client
+}
 
-			if content == nil || content.Content == nil {
-				slog.Debug("config file empty", "org", org)
-				return retry.Unrecoverable(errors.New("config file empty"))
-			}
+// LoadConfig loads the configuration for a GitHub org with retry logic.
+func (m *Manager) LoadConfig(ctx context.Context, org string) error {
+	slog.Info("loading config", "org", org)
 
-			// Decode the content
-			configContent, err = content.GetContent()
-			if err != nil {
-				slog.Warn("failed to decode config content", "error", err)
-				return err
-			}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-			return nil
-		},
-		retry.Attempts(3),
-		retry.Delay(time.Second),
-		retry.MaxDelay(30*time.Second),
-		retry.DelayType(retry.BackOffDelay),
-		retry.LastErrorOnly(true),
-		retry.Context(ctx),
-	)
-	if err != nil {
-		// Use default empty config if not found
-		slog.Warn("failed to load config, using empty config", "org", org, "error", err)
-		m.configs[org] = &RepoConfig{
-			Global: struct {
-				Prefix string `yaml:"prefix"`
-			}{Prefix: ":postal_horn:"},
-			Repos: make(map[string]struct {
-				Channels []string `yaml:"channels"`
-			}),
-		}
-		return nil // Graceful degradation
+	if m.clientFor(org) == nil {
+		return errors.New("github client not initialized")
 	}
 
-	// Parse the YAML
 	var config RepoConfig
-	if err := yaml.Unmarshal([]byte(configContent), &config); err != nil {
-		slog.Warn("failed to parse config YAML, using empty config", "org", org, "error", err)
-		m.configs[org] = &RepoConfig{
-			Global: struct {
-				Prefix string `yaml:"prefix"`
-			}{Prefix: ":postal_horn:"},
-			Repos: make(map[string]struct {
-				Channels []string `yaml:"channels"`
-			}),
-		}
+	if err := m.decodeConfig(ctx, org, configPath, nil, &config); err != nil {
+		// Use default empty config if not found or unreadable.
+		slog.Warn("failed to load config, using empty config", "org", org, "error", err)
+		m.configs[org] = defaultRepoConfig()
 		return nil // Graceful degradation
 	}
 
@@ -159,6 +354,63 @@ func (m *Manager) GetConfig(org string) (*RepoConfig, bool) {
 	return config, exists
 }
 
+// Orgs returns the GitHub orgs with a loaded configuration.
+func (m *Manager) Orgs() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	orgs := make([]string, 0, len(m.configs))
+	for org := range m.configs {
+		orgs = append(orgs, org)
+	}
+	return orgs
+}
+
+// GetChannels returns every channel configured for an org, without duplicates.
+func (m *Manager) GetChannels(org string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var channels []string
+	for _, repoConfig := range config.Repos {
+		for _, ch := range repoConfig.Channels {
+			if !seen[ch] {
+				seen[ch] = true
+				channels = append(channels, ch)
+			}
+		}
+	}
+	return channels
+}
+
+// GetReposForChannel returns the repos in an org that post to the given channel.
+func (m *Manager) GetReposForChannel(org, channel string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return nil
+	}
+
+	var repos []string
+	for repo, repoConfig := range config.Repos {
+		for _, ch := range repoConfig.Channels {
+			if ch == channel {
+				repos = append(repos, repo)
+				break
+			}
+		}
+	}
+	return repos
+}
+
 // GetChannelsForRepo returns the Slack channels configured for a specific repo.
 func (m *Manager) GetChannelsForRepo(org, repo string) []string {
 	m.mu.RLock()
@@ -168,11 +420,277 @@ func (m *Manager) GetChannelsForRepo(org, repo string) []string {
 	if !exists {
 		return nil
 	}
+	return m.repoSettingsLocked(config, org, repo).Channels
+}
+
+// RecordRename has a repo renamed within an org keep the settings slack.yaml has
+// under its old name, until slack.yaml configures it under the new one.
+func (m *Manager) RecordRename(org, oldRepo, newRepo string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.renamed[strings.ToLower(org+"/"+newRepo)] = oldRepo
+}
 
-	if repoConfig, ok := config.Repos[repo]; ok {
-		return repoConfig.Channels
+// repoSettingsLocked returns the settings of a repo in an org's config, matching its
+// name case-insensitively and following renames back to a name the config still
+// uses (must hold lock).
+func (m *Manager) repoSettingsLocked(config *RepoConfig, org, repo string) RepoSettings {
+	// Following no more renames than are recorded, renaming a repo back and forth can't loop.
+	for range len(m.renamed) + 1 {
+		if settings, ok := config.Repos[repo]; ok {
+			return settings
+		}
+		for name, settings := range config.Repos {
+			if strings.EqualFold(name, repo) {
+				return settings
+			}
+		}
+		previous, renamed := m.renamed[strings.ToLower(org+"/"+repo)]
+		if !renamed {
+			break
+		}
+		repo = previous
 	}
-	return nil
+	return RepoSettings{}
+}
+
+// GetRequiredReviewers returns the reviewers who must approve PRs in a repo.
+func (m *Manager) GetRequiredReviewers(org, repo string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return nil
+	}
+	return m.repoSettingsLocked(config, org, repo).RequiredReviewers
+}
+
+// GetTeamSettings returns how an org's config notifies a team, by its slug.
+func (m *Manager) GetTeamSettings(org, slug string) TeamSettings {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return TeamSettings{}
+	}
+	for s, settings := range config.Teams {
+		if strings.EqualFold(s, slug) {
+			return settings
+		}
+	}
+	return TeamSettings{}
+}
+
+// MilestoneSummariesEnabled reports whether a repo posts pinned milestone progress summaries.
+func (m *Manager) MilestoneSummariesEnabled(org, repo string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return false
+	}
+	return m.repoSettingsLocked(config, org, repo).MilestoneSummaries
+}
+
+// TopicCountsEnabled reports whether an org's channels show PR counts in their topics.
+func (m *Manager) TopicCountsEnabled(org string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return false
+	}
+	return config.Global.TopicCounts
+}
+
+// ThreadLinksEnabled reports whether an org's PRs get a GitHub comment linking to their Slack thread.
+func (m *Manager) ThreadLinksEnabled(org string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return false
+	}
+	return config.Global.ThreadLinks
+}
+
+// SlackUserFor returns the Slack user ID or email address an org's config maps a
+// GitHub login to, or "" if it doesn't.
+func (m *Manager) SlackUserFor(org, login string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return ""
+	}
+	for l, user := range config.Users {
+		if strings.EqualFold(l, login) {
+			return user
+		}
+	}
+	return ""
+}
+
+// GitHubLoginFor returns the GitHub login an org's config maps a Slack user ID to, or
+// "" if it doesn't. If several logins map to the user, the first alphabetically wins.
+func (m *Manager) GitHubLoginFor(org, userID string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return ""
+	}
+	var found string
+	for login, user := range config.Users {
+		if user == userID && (found == "" || login < found) {
+			found = login
+		}
+	}
+	return found
+}
+
+// FailureIssuesEnabled reports whether repeated event failures in an org's repos are
+// filed as issues in its .github repo.
+func (m *Manager) FailureIssuesEnabled(org string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return false
+	}
+	return config.Global.FailureIssues
+}
+
+// SlackCheckEnabled reports whether an org's PRs get a check run linking to their Slack thread.
+func (m *Manager) SlackCheckEnabled(org string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return false
+	}
+	return config.Global.SlackCheck
+}
+
+// HoldsForConversations reports whether approved PRs in an org wait for their review
+// conversations to be resolved.
+func (m *Manager) HoldsForConversations(org string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return false
+	}
+	return config.Global.HoldForConversations
+}
+
+// GetUrgentLabels returns the labels that mark a PR in an org as urgent.
+func (m *Manager) GetUrgentLabels(org string) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return nil
+	}
+	return config.Global.UrgentLabels
+}
+
+// GetLargePR returns the file and line counts past which a PR in an org is large.
+func (m *Manager) GetLargePR(org string) (files, lines int) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return 0, 0
+	}
+	return config.Global.LargePR.Files, config.Global.LargePR.Lines
+}
+
+// GetStaleApprovals returns whether and when review is re-requested from a PR's approvers after new commits.
+func (m *Manager) GetStaleApprovals(org string) StaleApprovalsConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return StaleApprovalsConfig{}
+	}
+	return config.Global.StaleApprovals
+}
+
+// GetDeferral returns the reaction that defers an org's PRs and how much it relaxes
+// their nudges, with the emoji's colons trimmed and the default scale filled in. It is
+// empty, with a zero scale, if the org hasn't turned deferral on.
+func (m *Manager) GetDeferral(org string) DeferralConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return DeferralConfig{}
+	}
+	d := config.Global.Deferral
+	d.Emoji = strings.Trim(d.Emoji, ":")
+	if d.Emoji == "" {
+		return DeferralConfig{}
+	}
+	if d.Scale <= 0 {
+		d.Scale = DefaultDeferralScale
+	}
+	return d
+}
+
+// GetLeaderboard returns an org's leaderboard settings.
+func (m *Manager) GetLeaderboard(org string) LeaderboardConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return LeaderboardConfig{}
+	}
+	return config.Global.Leaderboard
+}
+
+// ActiveFreeze returns the org's freeze window in effect at now, if any.
+func (m *Manager) ActiveFreeze(org string, now time.Time) (FreezeWindow, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return FreezeWindow{}, false
+	}
+	for _, freeze := range config.Global.Freezes {
+		if !now.Before(freeze.Start) && now.Before(freeze.End) {
+			return freeze, true
+		}
+	}
+	return FreezeWindow{}, false
+}
+
+// GetBackportLabel returns the prefix of labels requesting a backport in an org.
+func (m *Manager) GetBackportLabel(org string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists || config.Global.BackportLabel == "" {
+		return "backport"
+	}
+	return config.Global.BackportLabel
 }
 
 // GetPrefix returns the prefix for messages in an org.
@@ -187,8 +705,192 @@ func (m *Manager) GetPrefix(org string) string {
 	return config.Global.Prefix
 }
 
+// GetFetchErrorPolicy returns how an org's PR states are determined when GitHub data is missing.
+func (m *Manager) GetFetchErrorPolicy(org string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return FetchErrorsDegrade
+	}
+	switch policy := strings.ToLower(config.Global.OnFetchError); policy {
+	case FetchErrorsHold, FetchErrorsUnknown:
+		return policy
+	case "", FetchErrorsDegrade:
+		return FetchErrorsDegrade
+	default:
+		slog.Warn("unknown fetch error policy, using degrade", "org", org, "policy", config.Global.OnFetchError)
+		return FetchErrorsDegrade
+	}
+}
+
+// GetReactionMode returns how PR state is shown on threads in an org, defaulting to ReactionsReplace.
+func (m *Manager) GetReactionMode(org string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return ReactionsReplace
+	}
+	switch mode := strings.ToLower(config.Global.Reactions); mode {
+	case ReactionsAccumulate, ReactionsNone:
+		return mode
+	case "", ReactionsReplace:
+		return ReactionsReplace
+	default:
+		slog.Warn("unknown reaction mode, using replace", "org", org, "mode", config.Global.Reactions)
+		return ReactionsReplace
+	}
+}
+
+// GetTone returns the tone an org's nudges and digests are phrased in.
+func (m *Manager) GetTone(org string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return ToneNeutral
+	}
+	switch tone := strings.ToLower(config.Global.Tone); tone {
+	case ToneFriendly, ToneTerse:
+		return tone
+	case "", ToneNeutral:
+		return ToneNeutral
+	default:
+		slog.Warn("unknown tone, using neutral", "org", org, "tone", config.Global.Tone)
+		return ToneNeutral
+	}
+}
+
+// GetStaleness returns the open and idle thresholds past which a PR is considered stale.
+func (m *Manager) GetStaleness(org string) (open, idle time.Duration) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	openDays, idleDays := 7, 3
+	if config, exists := m.configs[org]; exists {
+		if config.Global.Staleness.OpenDays > 0 {
+			openDays = config.Global.Staleness.OpenDays
+		}
+		if config.Global.Staleness.IdleDays > 0 {
+			idleDays = config.Global.Staleness.IdleDays
+		}
+	}
+	return time.Duration(openDays) * 24 * time.Hour, time.Duration(idleDays) * 24 * time.Hour
+}
+
+// GetSeverity returns a repo's severity tier, SeverityStandard unless configured otherwise.
+func (m *Manager) GetSeverity(org, repo string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	config, exists := m.configs[org]
+	if !exists {
+		return SeverityStandard
+	}
+	configured := m.repoSettingsLocked(config, org, repo).Severity
+	switch severity := strings.ToLower(configured); severity {
+	case SeverityCritical, SeverityLow:
+		return severity
+	case "", SeverityStandard:
+		return SeverityStandard
+	default:
+		slog.Warn("unknown severity, using standard", "org", org, "repo", repo, "severity", configured)
+		return SeverityStandard
+	}
+}
+
+// SeverityScale returns how much a repo's severity tier stretches nudge delays,
+// staleness windows, and review claims: below 1 for critical repos, above for low.
+func (m *Manager) SeverityScale(org, repo string) float64 {
+	if f, ok := severityScales[m.GetSeverity(org, repo)]; ok {
+		return f
+	}
+	return 1
+}
+
+// SeverityScales returns the scale of each of an org's repos whose tier isn't standard.
+func (m *Manager) SeverityScales(org string) map[string]float64 {
+	m.mu.RLock()
+	config, exists := m.configs[org]
+	m.mu.RUnlock()
+	if !exists {
+		return nil
+	}
+
+	scales := make(map[string]float64)
+	for repo := range config.Repos {
+		if f := m.SeverityScale(org, repo); f != 1 {
+			scales[repo] = f
+		}
+	}
+	return scales
+}
+
+// FeatureCounts returns how many loaded org configs use each optional setting, by its
+// slack.yaml name, for anonymized telemetry.
+func (m *Manager) FeatureCounts() map[string]int {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	counts := make(map[string]int)
+	for _, config := range m.configs {
+		g := config.Global
+		for name, used := range map[string]bool{
+			"extends":                len(config.Extends) > 0,
+			"digest":                 g.Digest.Enabled,
+			"staleness":              g.Staleness.OpenDays > 0 || g.Staleness.IdleDays > 0,
+			"topic_counts":           g.TopicCounts,
+			"thread_links":           g.ThreadLinks,
+			"slack_check":            g.SlackCheck,
+			"hold_for_conversations": g.HoldForConversations,
+			"urgent_labels":          len(g.UrgentLabels) > 0,
+			"large_pr":               g.LargePR.Files > 0 || g.LargePR.Lines > 0,
+			"leaderboard":            g.Leaderboard.Enabled,
+			"freeze_windows":         len(g.Freezes) > 0,
+			"backport_label":         g.BackportLabel != "",
+			"stale_approvals":        g.StaleApprovals.Rerequest,
+			"failure_issues":         g.FailureIssues,
+			"tone":                   g.Tone != "",
+			"defer":                  g.Deferral.Emoji != "",
+		} {
+			if used {
+				counts[name]++
+			}
+		}
+		if len(config.Users) > 0 {
+			counts["users"]++
+		}
+		if len(config.Teams) > 0 {
+			counts["teams"]++
+		}
+		repoFeatures := make(map[string]bool)
+		for _, repo := range config.Repos {
+			repoFeatures["required_reviewers"] = repoFeatures["required_reviewers"] || len(repo.RequiredReviewers) > 0
+			repoFeatures["milestone_summaries"] = repoFeatures["milestone_summaries"] || repo.MilestoneSummaries
+			repoFeatures["severity"] = repoFeatures["severity"] || repo.Severity != ""
+		}
+		for name, used := range repoFeatures {
+			if used {
+				counts[name]++
+			}
+		}
+	}
+	return counts
+}
+
 // ReloadConfig reloads the configuration for an org (e.g., when .github repo is updated).
 func (m *Manager) ReloadConfig(ctx context.Context, org string) error {
 	slog.Info("reloading config", "org", org)
-	return m.LoadConfig(ctx, org)
+	if err := m.LoadConfig(ctx, org); err != nil {
+		return err
+	}
+	m.mu.RLock()
+	bus := m.bus
+	m.mu.RUnlock()
+	bus.Publish(ctx, events.ConfigReloaded{Time: time.Now(), Org: org})
+	return nil
 }
@@ -0,0 +1,122 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/google/go-github/v50/github"
+	"gopkg.in/yaml.v3"
+)
+
+// configPath is the org config's location in the org's .github repo.
+const configPath = "codeGROOVE/slack.yaml"
+
+// maxExtendsDepth bounds how many files a chain of extends may pass through.
+const maxExtendsDepth = 8
+
+// fileList is a list of file paths that may be written in YAML as a single string.
+type fileList []string
+
+// UnmarshalYAML accepts either a scalar path or a sequence of paths.
+func (f *fileList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*f = fileList{node.Value}
+		return nil
+	}
+	var paths []string
+	if err := node.Decode(&paths); err != nil {
+		return err
+	}
+	*f = paths
+	return nil
+}
+
+// decodeConfig decodes the config at path into config, first decoding every file it
+// extends so the file's own settings take precedence. Maps such as repos are merged
+// key by key; a repo listed in both files takes the later file's settings. chain
+// holds the files already being decoded and is used to detect cycles.
+func (m *Manager) decodeConfig(ctx context.Context, org, path string, chain []string, config *RepoConfig) error {
+	path = strings.TrimPrefix(path, "/")
+	if slices.Contains(chain, path) {
+		return fmt.Errorf("config extends cycle: %s", strings.Join(append(chain, path), " -> "))
+	}
+	if len(chain) >= maxExtendsDepth {
+		return fmt.Errorf("config extends more than %d files deep at %s", maxExtendsDepth, path)
+	}
+	chain = append(chain, path)
+
+	content, err := m.fetchFile(ctx, org, path)
+	if err != nil {
+		return err
+	}
+	if err := ValidateConfig([]byte(content)); err != nil {
+		return fmt.Errorf("%s does not match the config schema: %w", path, err)
+	}
+
+	var header struct {
+		Extends fileList `yaml:"extends"`
+	}
+	if err := yaml.Unmarshal([]byte(content), &header); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for _, base := range header.Extends {
+		if err := m.decodeConfig(ctx, org, base, chain, config); err != nil {
+			return err
+		}
+	}
+
+	if err := yaml.Unmarshal([]byte(content), config); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return nil
+}
+
+// fetchFile fetches a file from the org's .github repo with retry logic.
+func (m *Manager) fetchFile(ctx context.Context, org, path string) (string, error) {
+	var fileContent string
+	err := retry.Do(
+		func() error {
+			content, _, _, err := m.clientFor(org).Repositories.GetContents(ctx, org, ".github", path, nil)
+			if err != nil {
+				// Check if it's a 404 - config might not exist yet
+				var ghErr *github.ErrorResponse
+				if errors.As(err, &ghErr) && ghErr.Response.StatusCode == http.StatusNotFound {
+					slog.Debug("config file not found", "org", org, "path", path)
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to fetch config, retrying", "org", org, "path", path, "error", err)
+				return err
+			}
+
+			if content == nil || content.Content == nil {
+				slog.Debug("config file empty", "org", org, "path", path)
+				return retry.Unrecoverable(errors.New("config file empty"))
+			}
+
+			// Decode the content
+			fileContent, err = content.GetContent()
+			if err != nil {
+				slog.Warn("failed to decode config content", "path", path, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", path, err)
+	}
+	return fileContent, nil
+}
@@ -0,0 +1,140 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultWorkspace is the workspace used when no routing file is configured.
+const DefaultWorkspace = "default"
+
+// Routing maps GitHub orgs to the Slack workspaces their PRs are posted to.
+// It is loaded from the server-level file named by ROUTING_CONFIG.
+type Routing struct {
+	Workspaces map[string]WorkspaceConfig `yaml:"workspaces"`
+	Orgs       map[string]string          `yaml:"orgs"` // GitHub org to workspace name.
+	// DefaultForUnlisted routes orgs missing from Orgs to the default workspace, as
+	// when tenants are added to a server whose default workspace serves every org.
+	DefaultForUnlisted bool `yaml:"-"`
+}
+
+// WorkspaceConfig holds the Slack app credentials for one workspace.
+// Secrets may be given inline or, preferably, as the name of an environment variable.
+type WorkspaceConfig struct {
+	Token            string `yaml:"token"`
+	TokenEnv         string `yaml:"token_env"`
+	SigningSecret    string `yaml:"signing_secret"`
+	SigningSecretEnv string `yaml:"signing_secret_env"`
+	// Commands lists the slash commands the workspace's Slack app is configured with.
+	// Each is an alias for the same subcommands; empty uses the server default.
+	Commands []string `yaml:"commands"`
+	// Admins lists Slack user IDs allowed admin-only subcommands besides the workspace's admins.
+	Admins []string `yaml:"admins"`
+}
+
+// BotToken returns the workspace's Slack bot token.
+func (w WorkspaceConfig) BotToken() string {
+	if w.TokenEnv != "" {
+		return os.Getenv(w.TokenEnv)
+	}
+	return w.Token
+}
+
+// Secret returns the workspace's Slack signing secret.
+func (w WorkspaceConfig) Secret() string {
+	if w.SigningSecretEnv != "" {
+		return os.Getenv(w.SigningSecretEnv)
+	}
+	return w.SigningSecret
+}
+
+// LoadRouting reads and validates a routing file.
+func LoadRouting(path string) (*Routing, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read routing config: %w", err)
+	}
+
+	var routing Routing
+	if err := yaml.Unmarshal(data, &routing); err != nil {
+		return nil, fmt.Errorf("failed to parse routing config: %w", err)
+	}
+	if err := routing.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid routing config: %w", err)
+	}
+	return &routing, nil
+}
+
+// DefaultRouting routes every org to a single workspace using the given credentials.
+func DefaultRouting(token, signingSecret string) *Routing {
+	return &Routing{
+		Workspaces: map[string]WorkspaceConfig{
+			DefaultWorkspace: {Token: token, SigningSecret: signingSecret},
+		},
+	}
+}
+
+// Validate checks that every workspace has credentials and every org routes to a known workspace.
+func (r *Routing) Validate() error {
+	if len(r.Workspaces) == 0 {
+		return errors.New("no workspaces configured")
+	}
+	for name, ws := range r.Workspaces {
+		if ws.BotToken() == "" {
+			return fmt.Errorf("workspace %q has no bot token", name)
+		}
+		if ws.Secret() == "" {
+			return fmt.Errorf("workspace %q has no signing secret", name)
+		}
+		for _, command := range ws.Commands {
+			if err := ValidateCommand(command); err != nil {
+				return fmt.Errorf("workspace %q: %w", name, err)
+			}
+		}
+	}
+	if _, exists := r.Workspaces[DefaultWorkspace]; !exists && len(r.Orgs) == 0 {
+		return fmt.Errorf("orgs must be routed unless a %q workspace is configured", DefaultWorkspace)
+	}
+	for org, name := range r.Orgs {
+		if _, exists := r.Workspaces[name]; !exists {
+			return fmt.Errorf("org %q routes to unknown workspace %q", org, name)
+		}
+	}
+	return nil
+}
+
+// ValidateCommand checks that a slash command name is one Slack accepts, such as "/r2r".
+func ValidateCommand(command string) error {
+	if !strings.HasPrefix(command, "/") || len(command) < 2 || len(command) > 32 || strings.ContainsAny(command, " \t\n") {
+		return fmt.Errorf("invalid slash command %q", command)
+	}
+	return nil
+}
+
+// WorkspaceFor returns the workspace an org's PRs are posted to. Without an
+// explicit org list, every org uses the single default workspace.
+func (r *Routing) WorkspaceFor(org string) (string, bool) {
+	if name, exists := r.Orgs[org]; exists {
+		return name, true
+	}
+	if len(r.Orgs) == 0 || r.DefaultForUnlisted {
+		_, exists := r.Workspaces[DefaultWorkspace]
+		return DefaultWorkspace, exists
+	}
+	return "", false
+}
+
+// OrgNames returns the explicitly routed orgs in sorted order.
+func (r *Routing) OrgNames() []string {
+	orgs := make([]string, 0, len(r.Orgs))
+	for org := range r.Orgs {
+		orgs = append(orgs, org)
+	}
+	sort.Strings(orgs)
+	return orgs
+}
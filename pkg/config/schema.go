@@ -0,0 +1,234 @@
+package config
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Schema is the JSON Schema for slack.yaml.
+//
+//go:embed schema.json
+var Schema []byte
+
+// schemaNode is the subset of JSON Schema used by Schema.
+type schemaNode struct {
+	Properties           map[string]*schemaNode `json:"properties"`
+	Items                *schemaNode            `json:"items"`
+	AdditionalProperties json.RawMessage        `json:"additionalProperties"`
+	Minimum              *float64               `json:"minimum"`
+	Type                 schemaTypes            `json:"type"`
+	Pattern              string                 `json:"pattern"`
+	Enum                 []any                  `json:"enum"`
+}
+
+// schemaTypes is a schema's type keyword, which may be a single type or a list.
+type schemaTypes []string
+
+// UnmarshalJSON accepts either a type name or a list of type names.
+func (t *schemaTypes) UnmarshalJSON(data []byte) error {
+	var single string
+	if err := json.Unmarshal(data, &single); err == nil {
+		*t = schemaTypes{single}
+		return nil
+	}
+	var list []string
+	if err := json.Unmarshal(data, &list); err != nil {
+		return err
+	}
+	*t = list
+	return nil
+}
+
+// rootSchema is Schema, parsed once.
+var rootSchema = func() *schemaNode {
+	var root schemaNode
+	if err := json.Unmarshal(Schema, &root); err != nil {
+		panic(fmt.Sprintf("invalid embedded schema: %v", err))
+	}
+	return &root
+}()
+
+// ValidateConfig checks a slack.yaml document against Schema, returning every problem found.
+func ValidateConfig(data []byte) error {
+	var doc any
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return fmt.Errorf("invalid YAML: %w", err)
+	}
+	if doc == nil {
+		// An empty file is a valid, empty config.
+		return nil
+	}
+
+	var problems []error
+	rootSchema.validate("", doc, &problems)
+	return errors.Join(problems...)
+}
+
+// LintConfig validates every file an org's config is built from, without loading it.
+func (m *Manager) LintConfig(ctx context.Context, org string) error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.clientFor(org) == nil {
+		return errors.New("github client not initialized")
+	}
+
+	var problems []error
+	queue := []string{configPath}
+	seen := make(map[string]bool)
+	for len(queue) > 0 {
+		path := strings.TrimPrefix(queue[0], "/")
+		queue = queue[1:]
+		if seen[path] {
+			continue
+		}
+		seen[path] = true
+
+		content, err := m.fetchFile(ctx, org, path)
+		if err != nil {
+			problems = append(problems, err)
+			continue
+		}
+		if err := ValidateConfig([]byte(content)); err != nil {
+			problems = append(problems, fmt.Errorf("%s: %w", path, err))
+			continue
+		}
+		var header struct {
+			Extends fileList `yaml:"extends"`
+		}
+		if err := yaml.Unmarshal([]byte(content), &header); err == nil {
+			queue = append(queue, header.Extends...)
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// CheckConfig fetches and decodes an org's config as LoadConfig does, without loading
+// it, returning how many repos it configures. Unlike LoadConfig, a config that can't
+// be fetched or decoded is an error rather than falling back to the defaults.
+func (m *Manager) CheckConfig(ctx context.Context, org string) (repos int, err error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.clientFor(org) == nil {
+		return 0, errors.New("github client not initialized")
+	}
+	var config RepoConfig
+	if err := m.decodeConfig(ctx, org, configPath, nil, &config); err != nil {
+		return 0, err
+	}
+	return len(config.Repos), nil
+}
+
+// validate appends a problem for each way value violates the schema node. path locates value in the document.
+func (s *schemaNode) validate(path string, value any, problems *[]error) {
+	where := path
+	if where == "" {
+		where = "(root)"
+	}
+
+	if len(s.Type) > 0 && !slices.Contains(s.Type, jsonType(value)) &&
+		!(jsonType(value) == "integer" && slices.Contains(s.Type, "number")) {
+		*problems = append(*problems, fmt.Errorf("%s: must be %s, got %s", where, strings.Join(s.Type, " or "), jsonType(value)))
+		return
+	}
+
+	if len(s.Enum) > 0 && !slices.ContainsFunc(s.Enum, func(e any) bool { return fmt.Sprint(e) == fmt.Sprint(value) }) {
+		*problems = append(*problems, fmt.Errorf("%s: must be one of %v", where, s.Enum))
+	}
+
+	switch v := value.(type) {
+	case string:
+		if s.Pattern != "" && !regexp.MustCompile(s.Pattern).MatchString(v) {
+			*problems = append(*problems, fmt.Errorf("%s: %q does not match %s", where, v, s.Pattern))
+		}
+	case int:
+		if s.Minimum != nil && float64(v) < *s.Minimum {
+			*problems = append(*problems, fmt.Errorf("%s: must be at least %v", where, *s.Minimum))
+		}
+	case float64:
+		if s.Minimum != nil && v < *s.Minimum {
+			*problems = append(*problems, fmt.Errorf("%s: must be at least %v", where, *s.Minimum))
+		}
+	case []any:
+		if s.Items != nil {
+			for i, item := range v {
+				s.Items.validate(fmt.Sprintf("%s[%d]", path, i), item, problems)
+			}
+		}
+	case map[string]any:
+		s.validateObject(path, v, problems)
+	}
+}
+
+// validateObject checks an object's properties, in key order so problems are reported stably.
+func (s *schemaNode) validateObject(path string, obj map[string]any, problems *[]error) {
+	var additional *schemaNode
+	allowAdditional := true
+	if len(s.AdditionalProperties) > 0 {
+		if err := json.Unmarshal(s.AdditionalProperties, &allowAdditional); err != nil {
+			allowAdditional = true
+			additional = &schemaNode{}
+			if err := json.Unmarshal(s.AdditionalProperties, additional); err != nil {
+				additional = nil
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		child := key
+		if path != "" {
+			child = path + "." + key
+		}
+		if prop, exists := s.Properties[key]; exists {
+			prop.validate(child, obj[key], problems)
+			continue
+		}
+		if !allowAdditional {
+			*problems = append(*problems, fmt.Errorf("%s: unknown setting", child))
+			continue
+		}
+		if additional != nil {
+			additional.validate(child, obj[key], problems)
+		}
+	}
+}
+
+// jsonType returns the JSON Schema type name of a decoded YAML value.
+func jsonType(value any) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case int, int64, uint64:
+		return "integer"
+	case float64:
+		return "number"
+	case string, time.Time:
+		// YAML timestamps decode as time.Time.
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
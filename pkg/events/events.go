@@ -0,0 +1,118 @@
+// Package events is an in-process bus for what happens to PRs, users, and configs.
+// Modules publish to it and subscribe to it instead of calling each other, so features
+// such as analytics and audit logging can observe the bot without the coordinator
+// knowing about them.
+package events
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// Event is something published on the bus. Each event type has its own name.
+type Event interface {
+	Name() string
+}
+
+// PRStateChanged is published when a tracked PR moves to a new state.
+type PRStateChanged struct {
+	Time      time.Time
+	PR        *state.PRState // A copy, safe to keep.
+	Workspace string
+	Previous  string // The state the PR left, empty for a PR seen for the first time.
+}
+
+// Name returns "pr_state_changed".
+func (PRStateChanged) Name() string { return "pr_state_changed" }
+
+// UserBlocked is published when a PR starts waiting on a user.
+type UserBlocked struct {
+	Time      time.Time
+	Workspace string
+	Owner     string
+	Repo      string
+	User      string // GitHub login.
+	Number    int
+}
+
+// Name returns "user_blocked".
+func (UserBlocked) Name() string { return "user_blocked" }
+
+// NotificationSent is published when a user is sent a DM about a PR.
+type NotificationSent struct {
+	Time      time.Time
+	Workspace string
+	UserID    string // Slack user ID.
+	Owner     string
+	Repo      string
+	State     string
+	Number    int
+}
+
+// Name returns "notification_sent".
+func (NotificationSent) Name() string { return "notification_sent" }
+
+// ConfigReloaded is published when an org's slack.yaml has been reloaded.
+type ConfigReloaded struct {
+	Time time.Time
+	Org  string
+}
+
+// Name returns "config_reloaded".
+func (ConfigReloaded) Name() string { return "config_reloaded" }
+
+// Bus delivers published events to their subscribers. A nil *Bus discards events,
+// so publishers need not check whether one is configured.
+type Bus struct {
+	handlers map[string][]func(context.Context, Event)
+	mu       sync.RWMutex
+}
+
+// New creates a bus without subscribers.
+func New() *Bus {
+	return &Bus{handlers: make(map[string][]func(context.Context, Event))}
+}
+
+// Subscribe registers fn to receive every event of type E. Subscribers run in
+// registration order on the publisher's goroutine, so slow work belongs on a
+// subscriber's own queue.
+func Subscribe[E Event](b *Bus, fn func(ctx context.Context, ev E)) {
+	var zero E
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.handlers[zero.Name()] = append(b.handlers[zero.Name()], func(ctx context.Context, ev Event) {
+		fn(ctx, ev.(E)) // Handlers are keyed by the name of E, so the assertion holds.
+	})
+}
+
+// Publish delivers ev to its subscribers. A subscriber that panics is logged and
+// skipped, so one module can't break the others or the publisher.
+func (b *Bus) Publish(ctx context.Context, ev Event) {
+	if b == nil {
+		return
+	}
+	b.mu.RLock()
+	handlers := b.handlers[ev.Name()]
+	b.mu.RUnlock()
+
+	metrics.IncCounter("slacker_bus_events_total", "event", ev.Name())
+	for _, h := range handlers {
+		deliver(ctx, ev, h)
+	}
+}
+
+// deliver runs one subscriber, recovering a panic.
+func deliver(ctx context.Context, ev Event, h func(context.Context, Event)) {
+	defer func() {
+		if r := recover(); r != nil {
+			slog.Error("panic recovered in event subscriber", "event", ev.Name(), "panic", r)
+			metrics.IncCounter("slacker_bus_panics_total", "event", ev.Name())
+		}
+	}()
+	h(ctx, ev)
+}
@@ -0,0 +1,114 @@
+package github
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/google/go-github/v50/github"
+	"golang.org/x/oauth2"
+)
+
+const (
+	// jwtLifetime is how long an app JWT is valid. GitHub allows at most 10 minutes.
+	jwtLifetime = 9 * time.Minute
+	// jwtClockSkew backdates a JWT's issue time, in case GitHub's clock is behind ours.
+	jwtClockSkew = time.Minute
+	// tokenRefreshMargin is how long before an installation token expires that it's
+	// replaced, so a request never goes out with a token about to expire.
+	tokenRefreshMargin = 5 * time.Minute
+	// tokenTimeout bounds fetching a replacement installation token.
+	tokenTimeout = 30 * time.Second
+)
+
+// signJWT creates a JWT, signed with RS256, that authenticates as the GitHub App
+// until the returned expiry.
+func signJWT(key *rsa.PrivateKey, appID string, now time.Time) (string, time.Time, error) {
+	expiry := now.Add(jwtLifetime)
+	header, err := json.Marshal(map[string]string{"alg": "RS256", "typ": "JWT"})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iat": now.Add(-jwtClockSkew).Unix(),
+		"exp": expiry.Unix(),
+		"iss": appID,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), expiry, nil
+}
+
+// jwtSource mints app JWTs as they expire.
+type jwtSource struct {
+	c *Client
+}
+
+// Token implements oauth2.TokenSource.
+func (s jwtSource) Token() (*oauth2.Token, error) {
+	jwt, expiry, err := s.c.createJWT()
+	if err != nil {
+		return nil, err
+	}
+	return &oauth2.Token{AccessToken: jwt, Expiry: expiry}, nil
+}
+
+// installationSource fetches installation tokens as they expire.
+type installationSource struct {
+	c *Client
+}
+
+// Token implements oauth2.TokenSource.
+func (s installationSource) Token() (*oauth2.Token, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tokenTimeout)
+	defer cancel()
+	slog.Info("refreshing GitHub installation token", "app_id", s.c.appID)
+	return s.c.installationToken(ctx)
+}
+
+// installationToken fetches a new installation token with retry logic.
+func (c *Client) installationToken(ctx context.Context) (*oauth2.Token, error) {
+	var token *github.InstallationToken
+	err := retry.Do(
+		func() error {
+			var err error
+			token, _, err = c.appClient.Apps.CreateInstallationToken(
+				ctx,
+				c.installationID,
+				&github.InstallationTokenOptions{},
+			)
+			if err != nil {
+				slog.Warn("failed to create installation token, retrying", "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(5),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create installation token after retries: %w", err)
+	}
+	return &oauth2.Token{AccessToken: token.GetToken(), Expiry: token.GetExpiresAt().Time}, nil
+}
@@ -0,0 +1,128 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/google/go-github/v50/github"
+)
+
+// reviewThreadsQuery pages through a PR's review threads, which only the GraphQL API
+// reports as resolved or not.
+const reviewThreadsQuery = `query($owner: String!, $repo: String!, $number: Int!, $cursor: String) {
+  repository(owner: $owner, name: $repo) {
+    pullRequest(number: $number) {
+      reviewThreads(first: 100, after: $cursor) {
+        nodes { isResolved }
+        pageInfo { hasNextPage endCursor }
+      }
+    }
+  }
+}`
+
+// maxReviewThreadPages bounds the review threads counted on one PR, at 100 a page.
+const maxReviewThreadPages = 10
+
+// reviewThreadsPage is one page of the review threads query's response.
+type reviewThreadsPage struct {
+	Data struct {
+		Repository struct {
+			PullRequest struct {
+				ReviewThreads struct {
+					Nodes []struct {
+						IsResolved bool `json:"isResolved"`
+					} `json:"nodes"`
+					PageInfo struct {
+						EndCursor   string `json:"endCursor"`
+						HasNextPage bool   `json:"hasNextPage"`
+					} `json:"pageInfo"`
+				} `json:"reviewThreads"`
+			} `json:"pullRequest"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// UnresolvedConversations counts a PR's review conversations that haven't been resolved.
+func (c *Client) UnresolvedConversations(ctx context.Context, owner, repo string, number int) (int, error) {
+	unresolved := 0
+	var cursor *string
+	for range maxReviewThreadPages {
+		page, err := c.reviewThreads(ctx, owner, repo, number, cursor)
+		if err != nil {
+			return 0, err
+		}
+		threads := page.Data.Repository.PullRequest.ReviewThreads
+		for _, thread := range threads.Nodes {
+			if !thread.IsResolved {
+				unresolved++
+			}
+		}
+		if !threads.PageInfo.HasNextPage {
+			return unresolved, nil
+		}
+		cursor = github.String(threads.PageInfo.EndCursor)
+	}
+	slog.Debug("PR has more review threads than counted", "owner", owner, "repo", repo, "number", number)
+	return unresolved, nil
+}
+
+// reviewThreads fetches one page of a PR's review threads with retry logic.
+func (c *Client) reviewThreads(ctx context.Context, owner, repo string, number int, cursor *string) (*reviewThreadsPage, error) {
+	body := map[string]any{
+		"query": reviewThreadsQuery,
+		"variables": map[string]any{
+			"owner":  owner,
+			"repo":   repo,
+			"number": number,
+			"cursor": cursor,
+		},
+	}
+
+	var page reviewThreadsPage
+	err := retry.Do(
+		func() error {
+			req, err := c.client.NewRequest(http.MethodPost, "graphql", body)
+			if err != nil {
+				return retry.Unrecoverable(err)
+			}
+			page = reviewThreadsPage{}
+			resp, err := c.client.Do(ctx, req, &page)
+			if err != nil {
+				if resp != nil && resp.StatusCode < http.StatusInternalServerError && resp.StatusCode != http.StatusTooManyRequests {
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to get review threads, retrying",
+					"owner", owner, "repo", repo, "number", number, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review threads: %w", err)
+	}
+	if len(page.Errors) > 0 {
+		messages := make([]string, 0, len(page.Errors))
+		for _, e := range page.Errors {
+			messages = append(messages, e.Message)
+		}
+		return nil, errors.New("failed to get review threads: " + strings.Join(messages, "; "))
+	}
+	return &page, nil
+}
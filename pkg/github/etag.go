@@ -0,0 +1,119 @@
+package github
+
+import (
+	"bytes"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync"
+)
+
+const (
+	// maxETagEntries bounds how many responses are cached for conditional requests.
+	maxETagEntries = 1000
+	// maxETagBodySize is the largest response body cached for conditional requests.
+	maxETagBodySize = 1 << 20
+)
+
+// etagEntry is a cached response body and the ETag it was served with.
+type etagEntry struct {
+	header http.Header
+	etag   string
+	body   []byte
+}
+
+// etagTransport makes GET requests conditional on previously seen ETags.
+// When GitHub answers 304 Not Modified the cached body is replayed as a 200,
+// so polling unchanged resources costs no rate limit and no bandwidth.
+type etagTransport struct {
+	base    http.RoundTripper
+	entries map[string]etagEntry
+	mu      sync.Mutex
+}
+
+// newETagTransport wraps base, or http.DefaultTransport if nil, with ETag-based conditional requests.
+func newETagTransport(base http.RoundTripper) *etagTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &etagTransport{
+		base:    base,
+		entries: make(map[string]etagEntry),
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *etagTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet || req.Header.Get("If-None-Match") != "" {
+		return t.base.RoundTrip(req)
+	}
+
+	key := req.URL.String() + "|" + req.Header.Get("Accept")
+	t.mu.Lock()
+	entry, cached := t.entries[key]
+	t.mu.Unlock()
+
+	if cached {
+		req = req.Clone(req.Context())
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached && resp.StatusCode == http.StatusNotModified {
+		if err := resp.Body.Close(); err != nil {
+			slog.Debug("failed to close response body", "error", err)
+		}
+		slog.Debug("github resource not modified, using cached response", "url", req.URL.String())
+
+		// Keep fresh headers (rate limit counters) but serve the cached representation.
+		header := entry.header.Clone()
+		for k, v := range resp.Header {
+			header[k] = v
+		}
+		return &http.Response{
+			Status:        "200 OK",
+			StatusCode:    http.StatusOK,
+			Proto:         resp.Proto,
+			ProtoMajor:    resp.ProtoMajor,
+			ProtoMinor:    resp.ProtoMinor,
+			Header:        header,
+			Body:          io.NopCloser(bytes.NewReader(entry.body)),
+			ContentLength: int64(len(entry.body)),
+			Request:       req,
+		}, nil
+	}
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != http.StatusOK || etag == "" || resp.ContentLength > maxETagBodySize {
+		return resp, nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxETagBodySize+1))
+	if closeErr := resp.Body.Close(); closeErr != nil {
+		slog.Debug("failed to close response body", "error", closeErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	if len(body) > maxETagBodySize {
+		return resp, nil
+	}
+
+	t.mu.Lock()
+	if _, exists := t.entries[key]; !exists && len(t.entries) >= maxETagEntries {
+		// Evict an arbitrary entry; the cache is only an optimization.
+		for k := range t.entries {
+			delete(t.entries, k)
+			break
+		}
+	}
+	t.entries[key] = etagEntry{header: resp.Header.Clone(), etag: etag, body: body}
+	t.mu.Unlock()
+
+	return resp, nil
+}
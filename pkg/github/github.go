@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/codeGROOVE-dev/retry"
+	"github.com/codeGROOVE-dev/slacker/pkg/httpclient"
 	"github.com/google/go-github/v50/github"
 	"golang.org/x/oauth2"
 )
@@ -22,12 +23,16 @@ import (
 type Client struct {
 	privateKey     *rsa.PrivateKey
 	client         *github.Client
+	appClient      *github.Client
+	httpClient     *http.Client
+	breaker        *httpclient.Breaker // Holds GitHub's retry budget.
 	appID          string
 	installationID int64
 }
 
 // New creates a new GitHub client configured as a GitHub App.
-func New(ctx context.Context, appID, privateKeyPEM, installationID string) (*Client, error) {
+// API calls use the transport and timeout of httpClient, or http.DefaultClient if nil.
+func New(ctx context.Context, appID, privateKeyPEM, installationID string, httpClient *http.Client) (*Client, error) {
 	// Parse the private key.
 	block, _ := pem.Decode([]byte(privateKeyPEM))
 	if block == nil {
@@ -54,10 +59,16 @@ func New(ctx context.Context, appID, privateKeyPEM, installationID string) (*Cli
 		return nil, fmt.Errorf("invalid installation ID: %w", err)
 	}
 
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
 	gc := &Client{
 		appID:          appID,
 		privateKey:     key,
 		installationID: instID,
+		httpClient:     httpClient,
+		breaker:        httpclient.BreakerFor("github"),
 	}
 
 	// Create authenticated client.
@@ -68,64 +79,70 @@ func New(ctx context.Context, appID, privateKeyPEM, installationID string) (*Cli
 	return gc, nil
 }
 
-// authenticate creates an authenticated GitHub client with retry logic.
+// authenticate creates the app and installation clients. The app client signs a
+// fresh JWT as each expires, and the installation client replaces its token shortly
+// before it expires, so the clients keep working for the life of the process.
 func (c *Client) authenticate(ctx context.Context) error {
 	slog.Info("authenticating GitHub App", "app_id", c.appID)
 
-	// Create JWT for app authentication.
-	jwt, err := c.createJWT()
+	// Create app client.
+	jwt, err := jwtSource{c: c}.Token()
 	if err != nil {
 		return fmt.Errorf("failed to create JWT: %w", err)
 	}
-
-	// Create app client.
-	ts := oauth2.StaticTokenSource(&oauth2.Token{AccessToken: jwt})
-	tc := oauth2.NewClient(ctx, ts)
-	appClient := github.NewClient(tc)
-
-	// Get installation token with retry.
-	var token *github.InstallationToken
-	err = retry.Do(
-		func() error {
-			var err error
-			token, _, err = appClient.Apps.CreateInstallationToken(
-				ctx,
-				c.installationID,
-				&github.InstallationTokenOptions{},
-			)
-			if err != nil {
-				slog.Warn("failed to create installation token, retrying", "error", err)
-				return err
-			}
-			return nil
+	c.appClient = github.NewClient(&http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.ReuseTokenSource(jwt, jwtSource{c: c}),
+			Base:   c.httpClient.Transport,
 		},
-		retry.Attempts(5),
-		retry.Delay(time.Second),
-		retry.MaxDelay(30*time.Second),
-		retry.DelayType(retry.BackOffDelay),
-		retry.LastErrorOnly(true),
-		retry.Context(ctx),
-	)
+		Timeout: c.httpClient.Timeout,
+	})
+
+	// Fetch the first installation token now, so a misconfigured app fails at startup.
+	token, err := c.installationToken(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to create installation token after retries: %w", err)
+		return err
 	}
 
-	// Create installation client.
-	ts = oauth2.StaticTokenSource(&oauth2.Token{AccessToken: token.GetToken()})
-	tc = oauth2.NewClient(ctx, ts)
-	c.client = github.NewClient(tc)
+	// Create installation client with conditional requests for polled resources,
+	// and writes that follow renamed repos' redirects.
+	c.client = github.NewClient(&http.Client{
+		Transport: &oauth2.Transport{
+			Source: oauth2.ReuseTokenSourceWithExpiry(token, installationSource{c: c}, tokenRefreshMargin),
+			Base:   newRedirectTransport(newETagTransport(c.httpClient.Transport)),
+		},
+		Timeout: c.httpClient.Timeout,
+	})
 
-	slog.Info("successfully authenticated GitHub App", "app_id", c.appID)
+	slog.Info("successfully authenticated GitHub App", "app_id", c.appID, "token_expires", token.Expiry)
 	return nil
 }
 
-// createJWT creates a JWT for GitHub App authentication.
-func (c *Client) createJWT() (string, error) {
-	// This is a simplified version. In production, use a proper JWT library.
-	// For now, return a placeholder that would be replaced with actual JWT generation.
-	return "jwt-placeholder", nil
+// CheckInstallation verifies that the GitHub App is installed on an org, and that
+// the installation grants the permissions the bot's features need.
+func (c *Client) CheckInstallation(ctx context.Context, org string) error {
+	installation, _, err := c.appClient.Apps.FindOrganizationInstallation(ctx, org)
+	if err != nil {
+		return fmt.Errorf("no GitHub App installation found for org %s: %w", org, err)
+	}
+	if !installation.GetSuspendedAt().Time.IsZero() {
+		return fmt.Errorf("GitHub App installation for org %s is suspended", org)
+	}
+	return checkPermissions(org, installation.GetPermissions())
+}
+
+// createJWT creates a JWT for GitHub App authentication, returning when it expires.
+func (c *Client) createJWT() (string, time.Time, error) {
+	return signJWT(c.privateKey, c.appID, time.Now())
 }
 
+// PR state comes from each PR, its reviews, and its line comments.
+var _ = requires(Feature{
+	Name:        "pull-requests",
+	Description: "Read PRs, reviews, and review comments",
+	Permissions: []string{"pull_requests:read"},
+})
+
 // GetPR gets pull request details with retry logic.
 func (c *Client) GetPR(ctx context.Context, owner, repo string, number int) (*github.PullRequest, error) {
 	slog.Info("fetching PR", "owner", owner, "repo", repo, "number", number)
@@ -153,6 +170,7 @@ func (c *Client) GetPR(ctx context.Context, owner, repo string, number int) (*gi
 		retry.MaxDelay(2*time.Minute),
 		retry.DelayType(retry.BackOffDelay),
 		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
 		retry.Context(ctx),
 	)
 	if err != nil {
@@ -183,6 +201,7 @@ func (c *Client) GetPRReviews(ctx context.Context, owner, repo string, number in
 		retry.MaxDelay(2*time.Minute),
 		retry.DelayType(retry.BackOffDelay),
 		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
 		retry.Context(ctx),
 	)
 	if err != nil {
@@ -193,6 +212,13 @@ func (c *Client) GetPRReviews(ctx context.Context, owner, repo string, number in
 	return reviews, nil
 }
 
+// Check runs decide whether a PR's tests pass.
+var _ = requires(Feature{
+	Name:        "checks",
+	Description: "Read PR check runs",
+	Permissions: []string{"checks:read"},
+})
+
 // GetPRChecks gets check runs for a pull request with retry logic.
 func (c *Client) GetPRChecks(ctx context.Context, owner, repo string, number int) (*github.ListCheckRunsResults, error) {
 	slog.Info("fetching PR checks", "owner", owner, "repo", repo, "number", number)
@@ -226,6 +252,7 @@ func (c *Client) GetPRChecks(ctx context.Context, owner, repo string, number int
 		retry.MaxDelay(2*time.Minute),
 		retry.DelayType(retry.BackOffDelay),
 		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
 		retry.Context(ctx),
 	)
 	if err != nil {
@@ -240,32 +267,100 @@ func (c *Client) GetPRChecks(ctx context.Context, owner, repo string, number int
 	return checkRuns, nil
 }
 
+// Commit comparisons detect force pushes.
+var _ = requires(Feature{
+	Name:        "force-push-notes",
+	Description: "Note force pushes in PR threads",
+	Permissions: []string{"contents:read"},
+})
+
+// IsForcePush reports whether moving a branch from before to after rewrote history,
+// meaning before is no longer an ancestor of after.
+func (c *Client) IsForcePush(ctx context.Context, owner, repo, before, after string) (bool, error) {
+	var comparison *github.CommitsComparison
+	err := retry.Do(
+		func() error {
+			var resp *github.Response
+			var err error
+			comparison, resp, err = c.client.Repositories.CompareCommits(ctx, owner, repo, before, after, nil)
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusNotFound {
+					// The old head may have been garbage collected after the rewrite.
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to compare commits, retrying",
+					"owner", owner, "repo", repo, "before", before, "after", after, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to compare commits: %w", err)
+	}
+
+	switch comparison.GetStatus() {
+	case "diverged", "behind":
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// PRStatus is the derived review state of a PR.
+type PRStatus struct {
+	State              string
+	Author             string
+	BlockedOn          []string
+	ChangesRequestedBy []string // Reviewers whose latest review requests changes.
+	Approvers          []string // Reviewers whose latest review approves.
+	// Incomplete names the data, "checks", "reviews", or "conversations", that couldn't
+	// be fetched. The state was derived without it.
+	Incomplete []string
+	// UnresolvedConversations counts review conversations no one has resolved yet.
+	UnresolvedConversations int
+}
+
 // GetPRState determines the current state of a PR.
-func (c *Client) GetPRState(ctx context.Context, owner, repo string, number int) (string, []string, error) {
+func (c *Client) GetPRState(ctx context.Context, owner, repo string, number int) (*PRStatus, error) {
 	pr, err := c.GetPR(ctx, owner, repo, number)
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
 	// Check if merged or closed.
 	if pr.GetMerged() {
-		return "pray", nil, nil // Merged
+		return &PRStatus{State: "pray"}, nil // Merged
 	}
 	if pr.GetState() == "closed" {
-		return "face_palm", nil, nil // Closed but not merged
+		return &PRStatus{State: "face_palm"}, nil // Closed but not merged
 	}
 
+	var incomplete []string
+
 	// Get check runs.
 	checks, err := c.GetPRChecks(ctx, owner, repo, number)
 	if err != nil {
 		slog.Warn("failed to get checks for PR state",
 			"owner", owner, "repo", repo, "number", number, "error", err)
+		incomplete = append(incomplete, "checks")
 	}
 
 	// Analyze check status.
 	var checksRunning, checksFailed bool
 	if checks != nil {
 		for _, check := range checks.CheckRuns {
+			if check.GetName() == SlackCheckName {
+				// Our own link to the Slack thread says nothing about the code.
+				continue
+			}
 			switch check.GetStatus() {
 			case "in_progress", "queued", "pending":
 				checksRunning = true
@@ -285,26 +380,51 @@ func (c *Client) GetPRState(ctx context.Context, owner, repo string, number int)
 	if err != nil {
 		slog.Warn("failed to get reviews for PR state",
 			"owner", owner, "repo", repo, "number", number, "error", err)
+		incomplete = append(incomplete, "reviews")
 	}
 
-	// Check review status.
-	hasApproval := false
-	needsChanges := false
-	reviewers := make(map[string]bool)
+	// Track each reviewer's latest decisive review; reviews are returned oldest first.
+	latest := make(map[string]string)
+	var order []string
 	for _, review := range reviews {
-		if review.GetUser() != nil {
-			reviewers[review.GetUser().GetLogin()] = true
+		login := review.GetUser().GetLogin()
+		if login == "" {
+			continue
 		}
 		switch review.GetState() {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			if _, seen := latest[login]; !seen {
+				order = append(order, login)
+			}
+			latest[login] = review.GetState()
+		default:
+			// Comments and pending reviews don't change a reviewer's decision.
+			slog.Debug("other review state", "state", review.GetState())
+		}
+	}
+
+	// Check review status.
+	var approvers, changesRequestedBy []string
+	for _, login := range order {
+		switch latest[login] {
 		case "APPROVED":
-			hasApproval = true
+			approvers = append(approvers, login)
 		case "CHANGES_REQUESTED":
-			needsChanges = true
+			changesRequestedBy = append(changesRequestedBy, login)
 		default:
-			// Other review states (COMMENTED, PENDING, DISMISSED, etc.)
-			slog.Debug("other review state", "state", review.GetState())
+			// Dismissed reviews no longer count.
 		}
 	}
+	hasApproval := len(approvers) > 0
+	needsChanges := len(changesRequestedBy) > 0
+
+	// Count open review conversations.
+	unresolved, err := c.UnresolvedConversations(ctx, owner, repo, number)
+	if err != nil {
+		slog.Warn("failed to get review conversations for PR state",
+			"owner", owner, "repo", repo, "number", number, "error", err)
+		incomplete = append(incomplete, "conversations")
+	}
 
 	// Determine state and who it's blocked on.
 	var state string
@@ -332,7 +452,15 @@ func (c *Client) GetPRState(ctx context.Context, owner, repo string, number int)
 		}
 	}
 
-	return state, blockedOn, nil
+	return &PRStatus{
+		State:                   state,
+		Author:                  pr.GetUser().GetLogin(),
+		BlockedOn:               blockedOn,
+		ChangesRequestedBy:      changesRequestedBy,
+		Approvers:               approvers,
+		Incomplete:              incomplete,
+		UnresolvedConversations: unresolved,
+	}, nil
 }
 
 // WebhookHandler handles GitHub webhooks.
@@ -380,6 +508,13 @@ type PRInfo struct {
 	Number    int
 }
 
+// Org configs are fetched through the underlying client from each org's .github repo.
+var _ = requires(Feature{
+	Name:        "org-config",
+	Description: "Fetch slack.yaml from each org's .github repo",
+	Permissions: []string{"contents:read"},
+})
+
 // GetClient returns the underlying GitHub client.
 func (c *Client) GetClient() *github.Client {
 	return c.client
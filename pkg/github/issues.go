@@ -0,0 +1,81 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/google/go-github/v50/github"
+)
+
+// IssueLabel marks the issues the bot files, so it can find its own open ones.
+const IssueLabel = "slacker"
+
+// Failure issues tell org admins when a repo's events keep failing.
+var _ = requires(Feature{
+	Name:        "failure-issues",
+	Description: "File issues in the org's .github repo when a repo's events keep failing",
+	Permissions: []string{"issues:write"},
+	Optional:    true,
+})
+
+// FileIssue opens an issue labeled IssueLabel in a repo, unless an open one with the
+// same title already exists. It returns the issue's URL and whether it was created.
+func (c *Client) FileIssue(ctx context.Context, owner, repo, title, body string) (string, bool, error) {
+	var url string
+	var created bool
+	err := retry.Do(
+		func() error {
+			opts := &github.IssueListByRepoOptions{
+				State:       "open",
+				Labels:      []string{IssueLabel},
+				ListOptions: github.ListOptions{PerPage: 100},
+			}
+			open, resp, err := c.client.Issues.ListByRepo(ctx, owner, repo, opts)
+			if err != nil {
+				return issueError(resp, err, owner, repo)
+			}
+			for _, issue := range open {
+				if issue.GetTitle() == title {
+					url = issue.GetHTMLURL()
+					return nil
+				}
+			}
+
+			issue, resp, err := c.client.Issues.Create(ctx, owner, repo, &github.IssueRequest{
+				Title:  github.String(title),
+				Body:   github.String(body),
+				Labels: &[]string{IssueLabel},
+			})
+			if err != nil {
+				return issueError(resp, err, owner, repo)
+			}
+			url, created = issue.GetHTMLURL(), true
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to file issue: %w", err)
+	}
+	return url, created, nil
+}
+
+// issueError marks errors retrying won't fix, such as a missing repo or permission, as unrecoverable.
+func issueError(resp *github.Response, err error, owner, repo string) error {
+	if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound ||
+		resp.StatusCode == http.StatusGone || resp.StatusCode == http.StatusUnprocessableEntity) {
+		return retry.Unrecoverable(err)
+	}
+	slog.Warn("failed to file issue, retrying", "owner", owner, "repo", repo, "error", err)
+	return err
+}
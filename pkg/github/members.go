@@ -0,0 +1,141 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/google/go-github/v50/github"
+)
+
+// Org membership lets approve and assign check who is asking before acting.
+var _ = requires(Feature{
+	Name:        "org-members",
+	Description: "Check that Slack users acting on PRs are org members with write access",
+	Permissions: []string{"members:read"},
+	Optional:    true,
+})
+
+// OrgMembers returns the logins of an org's members.
+func (c *Client) OrgMembers(ctx context.Context, org string) ([]string, error) {
+	var logins []string
+	opts := &github.ListMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	err := retry.Do(
+		func() error {
+			logins = nil
+			opts.Page = 0
+			for {
+				page, resp, err := c.client.Organizations.ListMembers(ctx, org, opts)
+				if err != nil {
+					if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) {
+						// The installation lacks members:read; retrying won't help.
+						return retry.Unrecoverable(err)
+					}
+					slog.Warn("failed to list org members, retrying", "org", org, "error", err)
+					return err
+				}
+				for _, user := range page {
+					logins = append(logins, user.GetLogin())
+				}
+				if resp.NextPage == 0 {
+					return nil
+				}
+				opts.Page = resp.NextPage
+			}
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list org members: %w", err)
+	}
+	return logins, nil
+}
+
+// RepoPermission returns a user's permission on a repo: "admin", "write", "read",
+// or "none" if they aren't a collaborator.
+func (c *Client) RepoPermission(ctx context.Context, owner, repo, login string) (string, error) {
+	permission := "none"
+	err := retry.Do(
+		func() error {
+			level, resp, err := c.client.Repositories.GetPermissionLevel(ctx, owner, repo, login)
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusNotFound {
+					permission = "none"
+					return nil
+				}
+				slog.Warn("failed to get repo permission, retrying", "owner", owner, "repo", repo, "user", login, "error", err)
+				return err
+			}
+			permission = level.GetPermission()
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get repo permission: %w", err)
+	}
+	return permission, nil
+}
+
+// Team expansion puts the members of teams asked to review a PR on their own dashboards.
+var _ = requires(Feature{
+	Name:        "team-expansion",
+	Description: "Notify the members of teams asked to review a PR",
+	Permissions: []string{"members:read"},
+	Optional:    true,
+})
+
+// TeamMembers returns the logins of an org team's members, including those of its child teams.
+func (c *Client) TeamMembers(ctx context.Context, org, slug string) ([]string, error) {
+	var logins []string
+	opts := &github.TeamListTeamMembersOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	err := retry.Do(
+		func() error {
+			logins = nil
+			opts.Page = 0
+			for {
+				page, resp, err := c.client.Teams.ListTeamMembersBySlug(ctx, org, slug, opts)
+				if err != nil {
+					if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) {
+						return retry.Unrecoverable(err)
+					}
+					slog.Warn("failed to list team members, retrying", "org", org, "team", slug, "error", err)
+					return err
+				}
+				for _, user := range page {
+					logins = append(logins, user.GetLogin())
+				}
+				if resp.NextPage == 0 {
+					return nil
+				}
+				opts.Page = resp.NextPage
+			}
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list team members: %w", err)
+	}
+	return logins, nil
+}
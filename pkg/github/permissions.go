@@ -0,0 +1,83 @@
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/google/go-github/v50/github"
+)
+
+// Feature is a capability of the bot and the GitHub App permissions it needs, each
+// written as permission:level, such as checks:read. Features are declared next to
+// the code that implements them.
+type Feature struct {
+	Name        string
+	Description string
+	Permissions []string
+	Optional    bool // Whether the bot runs without it, such as features orgs opt in to.
+}
+
+// features are the bot's GitHub features, in declaration order.
+var features []Feature
+
+// requires declares a feature's GitHub App permissions.
+func requires(f Feature) Feature {
+	features = append(features, f)
+	return f
+}
+
+// permissionLevels ranks permission levels; a higher level includes the lower ones.
+var permissionLevels = map[string]int{"read": 1, "write": 2, "admin": 3}
+
+// checkPermissions verifies an installation grants the permissions of every
+// feature. Permissions missing for required features are an error listing each
+// one with the features that need it; those missing only for optional features
+// are logged, since just those features will fail.
+func checkPermissions(org string, granted *github.InstallationPermissions) error {
+	// The permissions' JSON names are the names GitHub uses for them.
+	raw, err := json.Marshal(granted)
+	if err != nil {
+		return fmt.Errorf("failed to read installation permissions: %w", err)
+	}
+	levels := make(map[string]string)
+	if err := json.Unmarshal(raw, &levels); err != nil {
+		return fmt.Errorf("failed to read installation permissions: %w", err)
+	}
+
+	neededBy := make(map[string][]string)
+	for _, f := range features {
+		var missing []string
+		for _, perm := range f.Permissions {
+			name, level, _ := strings.Cut(perm, ":")
+			if permissionLevels[levels[name]] < permissionLevels[level] {
+				missing = append(missing, perm)
+			}
+		}
+		if len(missing) == 0 {
+			continue
+		}
+		if f.Optional {
+			slog.Warn("GitHub App lacks permissions for an optional feature, which will fail until they are granted",
+				"org", org, "feature", f.Name, "missing", missing)
+			continue
+		}
+		for _, perm := range missing {
+			neededBy[perm] = append(neededBy[perm], f.Name)
+		}
+	}
+	if len(neededBy) == 0 {
+		return nil
+	}
+
+	required := slices.Sorted(maps.Keys(neededBy))
+	details := make([]string, 0, len(required))
+	for _, perm := range required {
+		details = append(details, fmt.Sprintf("%s (%s)", perm, strings.Join(neededBy[perm], ", ")))
+	}
+	return fmt.Errorf("GitHub App installation for org %s is missing permissions: %s; grant them in the app's settings and accept them for the org",
+		org, strings.Join(details, ", "))
+}
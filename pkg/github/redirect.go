@@ -0,0 +1,75 @@
+package github
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+)
+
+// maxRedirects bounds how many redirects one request follows.
+const maxRedirects = 5
+
+// redirectTransport follows GitHub's redirects for requests that change something.
+// GitHub answers requests for a renamed or transferred repo with a redirect to its
+// new URL. net/http follows a 301 for a POST or PATCH by sending a GET instead,
+// which would silently read the new resource rather than write to it. So such
+// redirects are followed here, keeping the method and body. GET and HEAD redirects
+// are left to net/http.
+type redirectTransport struct {
+	base http.RoundTripper
+}
+
+// newRedirectTransport wraps base, or http.DefaultTransport if nil, so writes follow redirects.
+func newRedirectTransport(base http.RoundTripper) *redirectTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &redirectTransport{base: base}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *redirectTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method == http.MethodGet || req.Method == http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	for range maxRedirects {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		switch resp.StatusCode {
+		case http.StatusMovedPermanently, http.StatusFound, http.StatusSeeOther,
+			http.StatusTemporaryRedirect, http.StatusPermanentRedirect:
+		default:
+			return resp, nil
+		}
+		loc, err := resp.Location()
+		// Only follow to the same host, so credentials aren't sent elsewhere, and only
+		// if the body can be sent again.
+		if err != nil || loc.Host != req.URL.Host || (req.Body != nil && req.GetBody == nil) {
+			return resp, nil
+		}
+
+		next := req.Clone(req.Context())
+		next.URL = loc
+		next.Host = ""
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			next.Body = body
+		}
+		if _, err := io.Copy(io.Discard, resp.Body); err != nil {
+			slog.Debug("failed to drain redirect body", "error", err)
+		}
+		if err := resp.Body.Close(); err != nil {
+			slog.Debug("failed to close response body", "error", err)
+		}
+		slog.Info("following GitHub redirect, the repo may have been renamed",
+			"method", req.Method, "from", req.URL.String(), "to", loc.String())
+		req = next
+	}
+	return t.base.RoundTrip(req)
+}
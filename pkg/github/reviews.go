@@ -0,0 +1,303 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/google/go-github/v50/github"
+)
+
+// Assigning asks reviewers on GitHub from a PR's thread or review claims.
+var _ = requires(Feature{
+	Name:        "assign",
+	Description: "Request reviews from PR threads",
+	Permissions: []string{"pull_requests:write"},
+	Optional:    true,
+})
+
+// RequestReviewers asks the given users to review a pull request.
+func (c *Client) RequestReviewers(ctx context.Context, owner, repo string, number int, logins []string) error {
+	err := retry.Do(
+		func() error {
+			_, resp, err := c.client.PullRequests.RequestReviewers(ctx, owner, repo, number, github.ReviewersRequest{Reviewers: logins})
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity {
+					// Not a collaborator, or the author; retrying won't help.
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to request reviewers, retrying",
+					"owner", owner, "repo", repo, "number", number, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to request reviewers: %w", err)
+	}
+	return nil
+}
+
+// Approving submits a review from a PR's thread.
+var _ = requires(Feature{
+	Name:        "approve",
+	Description: "Approve PRs from their threads",
+	Permissions: []string{"pull_requests:write"},
+	Optional:    true,
+})
+
+// Approve submits an approving review as the app, with body explaining who asked for it.
+func (c *Client) Approve(ctx context.Context, owner, repo string, number int, body string) error {
+	review := &github.PullRequestReviewRequest{
+		Event: github.String("APPROVE"),
+		Body:  github.String(body),
+	}
+	err := retry.Do(
+		func() error {
+			_, resp, err := c.client.PullRequests.CreateReview(ctx, owner, repo, number, review)
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusUnprocessableEntity {
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to approve PR, retrying",
+					"owner", owner, "repo", repo, "number", number, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to approve PR: %w", err)
+	}
+	return nil
+}
+
+// Thread links comment on each PR, for orgs that turn on thread_links.
+var _ = requires(Feature{
+	Name:        "thread-links",
+	Description: "Comment on PRs with a link to their Slack thread",
+	Permissions: []string{"pull_requests:write"},
+	Optional:    true,
+})
+
+// Comment posts a comment on a pull request's conversation.
+func (c *Client) Comment(ctx context.Context, owner, repo string, number int, body string) error {
+	comment := &github.IssueComment{Body: github.String(body)}
+	err := retry.Do(
+		func() error {
+			_, resp, err := c.client.Issues.CreateComment(ctx, owner, repo, number, comment)
+			if err != nil {
+				if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound) {
+					// The app lacks access to the repo; retrying won't help.
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to comment on PR, retrying",
+					"owner", owner, "repo", repo, "number", number, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to comment on PR: %w", err)
+	}
+	return nil
+}
+
+// Team membership resolves required reviewers given as team:slug.
+var _ = requires(Feature{
+	Name:        "required-reviewer-teams",
+	Description: "Resolve required reviewer teams to their members",
+	Permissions: []string{"members:read"},
+	Optional:    true,
+})
+
+// IsTeamMember reports whether a user is an active member of an org's team.
+func (c *Client) IsTeamMember(ctx context.Context, org, slug, login string) (bool, error) {
+	var member bool
+	err := retry.Do(
+		func() error {
+			membership, resp, err := c.client.Teams.GetTeamMembershipBySlug(ctx, org, slug, login)
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusNotFound {
+					member = false
+					return nil
+				}
+				slog.Warn("failed to get team membership, retrying", "org", org, "team", slug, "user", login, "error", err)
+				return err
+			}
+			member = membership.GetState() == "active"
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to get team membership: %w", err)
+	}
+	return member, nil
+}
+
+// GetPRReviewComments gets a pull request's line comments with retry logic.
+func (c *Client) GetPRReviewComments(ctx context.Context, owner, repo string, number int) ([]*github.PullRequestComment, error) {
+	var comments []*github.PullRequestComment
+	opts := &github.PullRequestListCommentsOptions{ListOptions: github.ListOptions{PerPage: 100}}
+	err := retry.Do(
+		func() error {
+			comments = nil
+			opts.Page = 0
+			for {
+				page, resp, err := c.client.PullRequests.ListComments(ctx, owner, repo, number, opts)
+				if err != nil {
+					slog.Warn("failed to get review comments, retrying",
+						"owner", owner, "repo", repo, "number", number, "error", err)
+					return err
+				}
+				comments = append(comments, page...)
+				if resp.NextPage == 0 {
+					return nil
+				}
+				opts.Page = resp.NextPage
+			}
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review comments: %w", err)
+	}
+	return comments, nil
+}
+
+// SlackCheckName is the name of the check run linking a PR to its Slack thread.
+const SlackCheckName = "ready-to-review/slack"
+
+// Slack checks add a check run linking to the thread, for orgs that turn on slack_check.
+var _ = requires(Feature{
+	Name:        "slack-check",
+	Description: "Add a check run linking each PR to its Slack thread",
+	Permissions: []string{"checks:write"},
+	Optional:    true,
+})
+
+// PublishSlackCheck adds a completed, neutral check run to a commit whose details link to url.
+func (c *Client) PublishSlackCheck(ctx context.Context, owner, repo, sha, url string) error {
+	opts := github.CreateCheckRunOptions{
+		Name:       SlackCheckName,
+		HeadSHA:    sha,
+		DetailsURL: github.String(url),
+		Status:     github.String("completed"),
+		Conclusion: github.String("neutral"),
+		Output: &github.CheckRunOutput{
+			Title:   github.String("Discussed in Slack"),
+			Summary: github.String("Review discussion for this PR happens in [its Slack thread](" + url + ")."),
+		},
+	}
+	err := retry.Do(
+		func() error {
+			_, resp, err := c.client.Checks.CreateCheckRun(ctx, owner, repo, opts)
+			if err != nil {
+				if resp != nil && (resp.StatusCode == http.StatusForbidden || resp.StatusCode == http.StatusNotFound ||
+					resp.StatusCode == http.StatusUnprocessableEntity) {
+					// Missing checks permission or an unknown commit; retrying won't help.
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to create check run, retrying",
+					"owner", owner, "repo", repo, "sha", sha, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create check run: %w", err)
+	}
+	return nil
+}
+
+// Re-requesting review measures pushes against approvals, for orgs that turn on stale_approvals.
+var _ = requires(Feature{
+	Name:        "rerequest-review",
+	Description: "Re-request review from approvers after significant new commits",
+	Permissions: []string{"contents:read", "pull_requests:write"},
+	Optional:    true,
+})
+
+// ChangedLines counts the lines added and deleted between two commits.
+func (c *Client) ChangedLines(ctx context.Context, owner, repo, base, head string) (int, error) {
+	var comparison *github.CommitsComparison
+	err := retry.Do(
+		func() error {
+			var resp *github.Response
+			var err error
+			comparison, resp, err = c.client.Repositories.CompareCommits(ctx, owner, repo, base, head, nil)
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusNotFound {
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to compare commits, retrying",
+					"owner", owner, "repo", repo, "base", base, "head", head, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to compare commits: %w", err)
+	}
+
+	lines := 0
+	for _, file := range comparison.Files {
+		lines += file.GetAdditions() + file.GetDeletions()
+	}
+	return lines, nil
+}
@@ -0,0 +1,42 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+)
+
+// UserEmail returns the public email address on a GitHub user's profile, or "" if
+// they don't show one.
+func (c *Client) UserEmail(ctx context.Context, login string) (string, error) {
+	var email string
+	err := retry.Do(
+		func() error {
+			user, resp, err := c.client.Users.Get(ctx, login)
+			if err != nil {
+				if resp != nil && resp.StatusCode == http.StatusNotFound {
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to get GitHub user, retrying", "login", login, "error", err)
+				return err
+			}
+			email = user.GetEmail()
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to get GitHub user: %w", err)
+	}
+	return email, nil
+}
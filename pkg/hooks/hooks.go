@@ -0,0 +1,101 @@
+// Package hooks lets deployments customize bot behavior without forking it,
+// either with Go functions when embedding or with an external webhook plugin.
+package hooks
+
+import (
+	"context"
+	"sync"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// PROpenedHook is called when a PR is opened, before its thread is posted.
+// It returns the channels to post the PR to, which may differ from those configured.
+type PROpenedHook func(ctx context.Context, pr *state.PRState, channels []string) []string
+
+// StateChangeHook is called after a PR moves from previous to its current state.
+type StateChangeHook func(ctx context.Context, pr *state.PRState, previous string)
+
+// BeforeNotifyHook is called before a user is sent a DM about a PR. Returning false suppresses it.
+type BeforeNotifyHook func(ctx context.Context, workspaceID, userID string, pr *state.PRState) bool
+
+// Registry holds registered hooks. A nil *Registry runs no hooks.
+type Registry struct {
+	opened  []PROpenedHook
+	changed []StateChangeHook
+	notify  []BeforeNotifyHook
+	mu      sync.RWMutex
+}
+
+// New creates an empty hook registry.
+func New() *Registry {
+	return &Registry{}
+}
+
+// OnPROpened registers a hook run when a PR is opened. Hooks run in registration
+// order, each receiving the channels returned by the one before.
+func (r *Registry) OnPROpened(h PROpenedHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.opened = append(r.opened, h)
+}
+
+// OnStateChange registers a hook run when a PR changes state.
+func (r *Registry) OnStateChange(h StateChangeHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.changed = append(r.changed, h)
+}
+
+// BeforeNotify registers a hook that may suppress a user notification.
+func (r *Registry) BeforeNotify(h BeforeNotifyHook) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.notify = append(r.notify, h)
+}
+
+// RunPROpened runs the PR opened hooks, returning the channels to post the PR to.
+func (r *Registry) RunPROpened(ctx context.Context, pr *state.PRState, channels []string) []string {
+	if r == nil {
+		return channels
+	}
+	r.mu.RLock()
+	hooks := r.opened
+	r.mu.RUnlock()
+
+	for _, h := range hooks {
+		channels = h(ctx, pr, channels)
+	}
+	return channels
+}
+
+// RunStateChange runs the state change hooks.
+func (r *Registry) RunStateChange(ctx context.Context, pr *state.PRState, previous string) {
+	if r == nil {
+		return
+	}
+	r.mu.RLock()
+	hooks := r.changed
+	r.mu.RUnlock()
+
+	for _, h := range hooks {
+		h(ctx, pr, previous)
+	}
+}
+
+// AllowNotify runs the before notify hooks, reporting false if any suppresses the notification.
+func (r *Registry) AllowNotify(ctx context.Context, workspaceID, userID string, pr *state.PRState) bool {
+	if r == nil {
+		return true
+	}
+	r.mu.RLock()
+	hooks := r.notify
+	r.mu.RUnlock()
+
+	for _, h := range hooks {
+		if !h(ctx, workspaceID, userID, pr) {
+			return false
+		}
+	}
+	return true
+}
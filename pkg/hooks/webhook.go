@@ -0,0 +1,107 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// webhookTimeout bounds each call to a webhook plugin, since hooks run inline with event processing.
+const webhookTimeout = 5 * time.Second
+
+// webhookRequest is the JSON body posted to a webhook plugin.
+type webhookRequest struct {
+	PR            *state.PRState `json:"pr"`
+	Hook          string         `json:"hook"` // "pr_opened", "state_change", or "before_notify".
+	Workspace     string         `json:"workspace,omitempty"`
+	UserID        string         `json:"user_id,omitempty"`
+	PreviousState string         `json:"previous_state,omitempty"`
+	Channels      []string       `json:"channels,omitempty"`
+}
+
+// webhookResponse is a webhook plugin's reply. Omitted fields leave behavior unchanged.
+type webhookResponse struct {
+	Channels *[]string `json:"channels"`
+	Allow    *bool     `json:"allow"`
+}
+
+// RegisterWebhook registers hooks that call an external plugin over HTTP. The plugin
+// may reply to pr_opened with {"channels": [...]} to reroute the PR and to
+// before_notify with {"allow": false} to suppress the notification. If the plugin
+// fails or times out, the bot carries on as if it had not been called.
+func (r *Registry) RegisterWebhook(url string, client *http.Client) {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	call := func(ctx context.Context, req webhookRequest) (webhookResponse, error) {
+		return callWebhook(ctx, client, url, req)
+	}
+
+	r.OnPROpened(func(ctx context.Context, pr *state.PRState, channels []string) []string {
+		resp, err := call(ctx, webhookRequest{Hook: "pr_opened", PR: pr, Channels: channels})
+		if err != nil {
+			slog.Warn("webhook plugin failed", "hook", "pr_opened", "error", err)
+			return channels
+		}
+		if resp.Channels != nil {
+			return *resp.Channels
+		}
+		return channels
+	})
+	r.OnStateChange(func(ctx context.Context, pr *state.PRState, previous string) {
+		if _, err := call(ctx, webhookRequest{Hook: "state_change", PR: pr, PreviousState: previous}); err != nil {
+			slog.Warn("webhook plugin failed", "hook", "state_change", "error", err)
+		}
+	})
+	r.BeforeNotify(func(ctx context.Context, workspaceID, userID string, pr *state.PRState) bool {
+		resp, err := call(ctx, webhookRequest{Hook: "before_notify", PR: pr, Workspace: workspaceID, UserID: userID})
+		if err != nil {
+			slog.Warn("webhook plugin failed", "hook", "before_notify", "error", err)
+			return true
+		}
+		return resp.Allow == nil || *resp.Allow
+	})
+}
+
+// callWebhook posts one hook call to a webhook plugin.
+func callWebhook(ctx context.Context, client *http.Client, url string, hookReq webhookRequest) (webhookResponse, error) {
+	var result webhookResponse
+	body, err := json.Marshal(hookReq)
+	if err != nil {
+		return result, fmt.Errorf("failed to encode hook request: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, webhookTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return result, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return result, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Debug("failed to close response body", "error", err)
+		}
+	}()
+	if resp.StatusCode == http.StatusNoContent {
+		return result, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("plugin returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("failed to decode plugin response: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,189 @@
+package httpclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+// Circuit breaker states.
+const (
+	// BreakerClosed lets calls through.
+	BreakerClosed = "closed"
+	// BreakerOpen fails calls immediately until the cooldown has passed.
+	BreakerOpen = "open"
+	// BreakerHalfOpen lets a single probe call through to test whether the upstream has recovered.
+	BreakerHalfOpen = "half-open"
+)
+
+const (
+	// breakerThreshold is the number of consecutive failures that opens a breaker.
+	breakerThreshold = 5
+	// breakerCooldown is how long an open breaker fails calls before probing.
+	breakerCooldown = 30 * time.Second
+	// retryBudget is the most retries an upstream may have banked.
+	retryBudget = 10
+	// retryRefill is the retry budget earned by each successful call, so retries stay
+	// a small fraction of traffic while an upstream is struggling.
+	retryRefill = 0.2
+)
+
+// ErrBreakerOpen is returned for calls made while an upstream's breaker is open.
+var ErrBreakerOpen = errors.New("circuit breaker open")
+
+// Breaker is a circuit breaker for one upstream. It opens after repeated failures,
+// fails calls fast while open, and closes again once a probe call succeeds. It also
+// holds the upstream's retry budget, shared by every caller's retry loop.
+type Breaker struct {
+	openedAt time.Time
+	name     string
+	state    string
+	failures int
+	budget   float64
+	probing  bool
+	mu       sync.Mutex
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*Breaker)
+)
+
+// BreakerFor returns the shared breaker for an upstream, creating it on first use.
+func BreakerFor(name string) *Breaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+
+	b, exists := breakers[name]
+	if !exists {
+		b = &Breaker{name: name, state: BreakerClosed, budget: retryBudget}
+		breakers[name] = b
+	}
+	return b
+}
+
+// BreakerStates returns the state of every upstream's breaker, by upstream name.
+func BreakerStates() map[string]string {
+	breakersMu.Lock()
+	names := make([]string, 0, len(breakers))
+	for name := range breakers {
+		names = append(names, name)
+	}
+	breakersMu.Unlock()
+	sort.Strings(names)
+
+	states := make(map[string]string, len(names))
+	for _, name := range names {
+		states[name] = BreakerFor(name).State()
+	}
+	return states
+}
+
+// State returns the breaker's state.
+func (b *Breaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == BreakerOpen && time.Since(b.openedAt) >= breakerCooldown {
+		// The next call will probe.
+		return BreakerHalfOpen
+	}
+	return b.state
+}
+
+// Allow reports whether a call may be made, returning ErrBreakerOpen if not.
+// A call allowed while half-open is the probe, and must be followed by Record.
+func (b *Breaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if time.Since(b.openedAt) < breakerCooldown {
+			return fmt.Errorf("%s: %w", b.name, ErrBreakerOpen)
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			return fmt.Errorf("%s: %w", b.name, ErrBreakerOpen)
+		}
+		b.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Record records the outcome of an allowed call.
+func (b *Breaker) Record(failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+	if !failed {
+		b.failures = 0
+		b.budget = min(retryBudget, b.budget+retryRefill)
+		b.setState(BreakerClosed)
+		return
+	}
+	b.failures++
+	if b.state == BreakerHalfOpen || b.failures >= breakerThreshold {
+		b.openedAt = time.Now()
+		b.setState(BreakerOpen)
+	}
+}
+
+// Retryable reports whether a failed call may be retried, spending from the upstream's
+// retry budget. It is meant for retry.RetryIf; calls rejected by an open breaker are
+// never retried.
+func (b *Breaker) Retryable(err error) bool {
+	if errors.Is(err, ErrBreakerOpen) {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.budget < 1 {
+		metrics.IncCounter("slacker_retry_budget_exhausted_total", "upstream", b.name)
+		return false
+	}
+	b.budget--
+	return true
+}
+
+// setState moves the breaker to state, recording the transition. b.mu must be held.
+func (b *Breaker) setState(state string) {
+	if b.state == state {
+		return
+	}
+	metrics.IncCounter("slacker_circuit_breaker_transitions_total", "upstream", b.name, "state", state)
+	b.state = state
+}
+
+// upstreamFor names the upstream a host belongs to, so every host of an API shares one breaker.
+func upstreamFor(host string) string {
+	switch {
+	case host == "slack.com" || strings.HasSuffix(host, ".slack.com"):
+		return "slack"
+	case host == "github.com" || strings.HasSuffix(host, ".github.com"):
+		return "github"
+	default:
+		return host
+	}
+}
+
+// countsAsFailure reports whether a request outcome indicates an unhealthy upstream.
+// Client errors and rate limits are answers from a healthy upstream; cancellations are the caller's.
+func countsAsFailure(resp *http.Response, err error) bool {
+	if err != nil {
+		return !errors.Is(err, context.Canceled)
+	}
+	return resp.StatusCode >= http.StatusInternalServerError
+}
@@ -0,0 +1,136 @@
+// Package httpclient builds the shared outbound HTTP transport used for Slack, GitHub, and sprinkler,
+// with a circuit breaker and retry budget per upstream.
+package httpclient
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/gorilla/websocket"
+)
+
+// Options configures outbound connections.
+type Options struct {
+	ProxyURL        string        // Outbound proxy; empty honors HTTPS_PROXY/HTTP_PROXY/NO_PROXY.
+	Timeout         time.Duration // Overall request timeout.
+	DialTimeout     time.Duration
+	KeepAlive       time.Duration
+	IdleConnTimeout time.Duration
+	MaxIdleConns    int
+}
+
+// DefaultOptions returns the settings used when nothing is configured.
+func DefaultOptions() Options {
+	return Options{
+		Timeout:         30 * time.Second,
+		DialTimeout:     10 * time.Second,
+		KeepAlive:       30 * time.Second,
+		IdleConnTimeout: 90 * time.Second,
+		MaxIdleConns:    100,
+	}
+}
+
+// proxy returns the proxy function for the options.
+func (o Options) proxy() (func(*http.Request) (*url.URL, error), error) {
+	if o.ProxyURL == "" {
+		return http.ProxyFromEnvironment, nil
+	}
+	u, err := url.Parse(o.ProxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid proxy URL: %w", err)
+	}
+	return http.ProxyURL(u), nil
+}
+
+// NewTransport returns an instrumented transport configured by opts.
+func NewTransport(opts Options) (http.RoundTripper, error) {
+	proxy, err := opts.proxy()
+	if err != nil {
+		return nil, err
+	}
+
+	dialer := &net.Dialer{
+		Timeout:   opts.DialTimeout,
+		KeepAlive: opts.KeepAlive,
+	}
+	base := &http.Transport{
+		Proxy:                 proxy,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          opts.MaxIdleConns,
+		MaxIdleConnsPerHost:   opts.MaxIdleConns,
+		IdleConnTimeout:       opts.IdleConnTimeout,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+	return &instrumentedTransport{base: base}, nil
+}
+
+// New returns an HTTP client using an instrumented transport configured by opts.
+func New(opts Options) (*http.Client, error) {
+	transport, err := NewTransport(opts)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Client{Transport: transport, Timeout: opts.Timeout}, nil
+}
+
+// NewWebsocketDialer returns a websocket dialer honoring the same proxy and dial settings.
+// It offers permessage-deflate compression, which the server may accept or ignore.
+func NewWebsocketDialer(opts Options) (*websocket.Dialer, error) {
+	proxy, err := opts.proxy()
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{
+		Timeout:   opts.DialTimeout,
+		KeepAlive: opts.KeepAlive,
+	}
+	return &websocket.Dialer{
+		HandshakeTimeout:  10 * time.Second,
+		Proxy:             proxy,
+		NetDialContext:    dialer.DialContext,
+		EnableCompression: true,
+	}, nil
+}
+
+// instrumentedTransport records request counts and latencies per upstream host,
+// waits on any rate limiter attached to the request context, and fails requests
+// fast while the upstream's circuit breaker is open.
+type instrumentedTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *instrumentedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if l := limiterFrom(req.Context()); l != nil {
+		waitStart := time.Now()
+		if err := l.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		metrics.Observe("slacker_http_client_limiter_wait_seconds", time.Since(waitStart).Seconds(), "host", req.URL.Hostname())
+	}
+
+	breaker := BreakerFor(upstreamFor(req.URL.Hostname()))
+	if err := breaker.Allow(); err != nil {
+		metrics.IncCounter("slacker_http_client_requests_total", "host", req.URL.Hostname(), "code", "breaker_open")
+		return nil, err
+	}
+
+	start := time.Now()
+	resp, err := t.base.RoundTrip(req)
+	host := req.URL.Hostname()
+	breaker.Record(countsAsFailure(resp, err))
+	metrics.Observe("slacker_http_client_request_duration_seconds", time.Since(start).Seconds(), "host", host)
+	if err != nil {
+		metrics.IncCounter("slacker_http_client_requests_total", "host", host, "code", "error")
+		return nil, err
+	}
+	metrics.IncCounter("slacker_http_client_requests_total", "host", host, "code", strconv.Itoa(resp.StatusCode))
+	return resp, nil
+}
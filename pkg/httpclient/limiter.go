@@ -0,0 +1,67 @@
+package httpclient
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a token bucket limiting how often outbound requests may be made.
+type Limiter struct {
+	last   time.Time
+	rate   float64 // Tokens added per second.
+	burst  float64
+	tokens float64
+	mu     sync.Mutex
+}
+
+// NewLimiter returns a limiter allowing rate requests per second with bursts of up to burst.
+func NewLimiter(rate float64, burst int) *Limiter {
+	return &Limiter{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// Wait blocks until a request may be made or the context ends.
+func (l *Limiter) Wait(ctx context.Context) error {
+	for {
+		l.mu.Lock()
+		now := time.Now()
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+type limiterKey struct{}
+
+// WithLimiter returns a context whose outbound requests through the shared transport wait on l.
+func WithLimiter(ctx context.Context, l *Limiter) context.Context {
+	return context.WithValue(ctx, limiterKey{}, l)
+}
+
+// limiterFrom returns the limiter attached to ctx, if any.
+func limiterFrom(ctx context.Context) *Limiter {
+	l, ok := ctx.Value(limiterKey{}).(*Limiter)
+	if !ok {
+		return nil
+	}
+	return l
+}
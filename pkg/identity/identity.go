@@ -0,0 +1,155 @@
+// Package identity resolves the GitHub logins PRs wait on to the Slack users to notify.
+package identity
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// How a login was resolved, recorded with each cached identity.
+const (
+	SourceLinked = "linked" // The Slack user linked the login themselves.
+	SourceConfig = "config" // The org's slack.yaml maps the login.
+	SourceEmail  = "email"  // The email on the GitHub profile belongs to a Slack user.
+)
+
+const (
+	// cacheTTL is how long a login resolved by email stays resolved before it's looked up again.
+	cacheTTL = 7 * 24 * time.Hour
+	// missTTL is how long a login that matched no Slack user waits before it's looked up again.
+	missTTL = 24 * time.Hour
+	// lookupTimeout bounds the GitHub and Slack lookups for one login.
+	lookupTimeout = 10 * time.Second
+)
+
+// GitHubUsers looks up GitHub profiles.
+type GitHubUsers interface {
+	// UserEmail returns the public email on a user's profile, or "" if none.
+	UserEmail(ctx context.Context, login string) (string, error)
+}
+
+// SlackUsers looks up Slack users.
+type SlackUsers interface {
+	// LookupUserByEmail returns the ID of the user with an email address, or "" if none.
+	LookupUserByEmail(ctx context.Context, email string) (string, error)
+}
+
+// Resolver maps GitHub logins to Slack users. In order, it uses: the Slack user who
+// linked the login themselves, the org's slack.yaml users map, and the Slack user
+// whose email is the public email on the GitHub profile. Lookups are cached in the
+// state manager, so they persist across restarts.
+type Resolver struct {
+	state   *state.Manager
+	configs *config.Manager
+	github  func(org string) GitHubUsers
+	slack   func(workspaceID string) SlackUsers
+}
+
+// New creates a resolver that only uses linked logins and the org configs until
+// SetLookups is called.
+func New(stateManager *state.Manager, configManager *config.Manager) *Resolver {
+	return &Resolver{state: stateManager, configs: configManager}
+}
+
+// SetLookups sets how GitHub profiles are looked up for an org, and Slack users by
+// email for a workspace.
+func (r *Resolver) SetLookups(github func(org string) GitHubUsers, slack func(workspaceID string) SlackUsers) {
+	r.github = github
+	r.slack = slack
+}
+
+// Resolve returns the Slack user in a workspace that a GitHub login in an org belongs to.
+func (r *Resolver) Resolve(ctx context.Context, workspaceID, org, login string) (string, bool) {
+	if login == "" {
+		return "", false
+	}
+	if userID, linked := r.state.LinkedUser(workspaceID, login); linked {
+		return userID, true
+	}
+	return r.ResolveVerified(ctx, workspaceID, org, login)
+}
+
+// ResolveVerified resolves a login as Resolve does, but only through the org's
+// slack.yaml and email, skipping logins that Slack users linked themselves. Anyone can
+// link any login, so only this answer may decide what a user is allowed to do.
+func (r *Resolver) ResolveVerified(ctx context.Context, workspaceID, org, login string) (string, bool) {
+	if login == "" {
+		return "", false
+	}
+
+	now := time.Now()
+	cached, exists := r.state.Identity(workspaceID, login)
+	if mapped := r.configs.SlackUserFor(org, login); mapped != "" {
+		if !strings.Contains(mapped, "@") {
+			if !exists || cached.SlackID != mapped || cached.Source != SourceConfig {
+				r.remember(workspaceID, login, mapped, SourceConfig, now)
+			}
+			return mapped, true
+		}
+		if exists && cached.Source == SourceConfig && fresh(cached, now) {
+			return cached.SlackID, cached.SlackID != ""
+		}
+		return r.byEmail(ctx, workspaceID, login, mapped, SourceConfig, now)
+	}
+	if exists && cached.Source == SourceEmail && fresh(cached, now) {
+		return cached.SlackID, cached.SlackID != ""
+	}
+
+	if r.github == nil || r.slack == nil {
+		return "", false
+	}
+	ctx, cancel := context.WithTimeout(ctx, lookupTimeout)
+	defer cancel()
+	email, err := r.github(org).UserEmail(ctx, login)
+	if err != nil {
+		slog.Warn("failed to look up GitHub user's email", "org", org, "login", login, "error", err)
+		metrics.IncCounter("slacker_identity_lookups_total", "result", "error", "source", SourceEmail)
+		return "", false
+	}
+	if email == "" {
+		r.remember(workspaceID, login, "", SourceEmail, now)
+		return "", false
+	}
+	return r.byEmail(ctx, workspaceID, login, email, SourceEmail, now)
+}
+
+// fresh reports whether a cached lookup is recent enough to use.
+func fresh(cached state.Identity, now time.Time) bool {
+	ttl := cacheTTL
+	if cached.SlackID == "" {
+		ttl = missTTL
+	}
+	return now.Sub(cached.ResolvedAt) < ttl
+}
+
+// byEmail resolves a login to the Slack user with an email address, caching the answer.
+func (r *Resolver) byEmail(ctx context.Context, workspaceID, login, email, source string, now time.Time) (string, bool) {
+	if r.slack == nil {
+		return "", false
+	}
+	userID, err := r.slack(workspaceID).LookupUserByEmail(ctx, email)
+	if err != nil {
+		slog.Warn("failed to look up Slack user by email", "workspace", workspaceID, "login", login, "error", err)
+		metrics.IncCounter("slacker_identity_lookups_total", "result", "error", "source", source)
+		return "", false
+	}
+	r.remember(workspaceID, login, userID, source, now)
+	return userID, userID != ""
+}
+
+// remember caches how a login resolved.
+func (r *Resolver) remember(workspaceID, login, userID, source string, now time.Time) {
+	result := "found"
+	if userID == "" {
+		result = "not_found"
+	}
+	metrics.IncCounter("slacker_identity_lookups_total", "result", result, "source", source)
+	slog.Debug("resolved GitHub login", "workspace", workspaceID, "login", login, "user", userID, "source", source)
+	r.state.SetIdentity(workspaceID, login, state.Identity{ResolvedAt: now, SlackID: userID, Source: source})
+}
@@ -0,0 +1,239 @@
+// Package metrics provides lightweight in-process metrics exposed in Prometheus text format.
+package metrics
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// defaultBuckets are histogram upper bounds suited to latencies in seconds.
+var defaultBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+type kind int
+
+const (
+	counterKind kind = iota
+	gaugeKind
+	histogramKind
+)
+
+// series is a single metric with a fixed label set.
+type series struct {
+	labels  string
+	pairs   []string // Labels as alternating name, value pairs.
+	buckets []uint64
+	value   float64
+	sum     float64
+	count   uint64
+}
+
+// family groups series sharing a metric name.
+type family struct {
+	series  map[string]*series
+	name    string
+	buckets []float64 // Histogram upper bounds.
+	kind    kind
+}
+
+var (
+	mu       sync.Mutex
+	families = make(map[string]*family)
+)
+
+// IncCounter increments a counter by one. Labels are given as alternating name, value pairs.
+func IncCounter(name string, labels ...string) {
+	AddCounter(name, 1, labels...)
+}
+
+// AddCounter adds delta to a counter.
+func AddCounter(name string, delta float64, labels ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	lookup(name, counterKind, labels).value += delta
+}
+
+// SetGauge sets a gauge to value.
+func SetGauge(name string, value float64, labels ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	lookup(name, gaugeKind, labels).value = value
+}
+
+// AddGauge adds delta to a gauge.
+func AddGauge(name string, delta float64, labels ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	lookup(name, gaugeKind, labels).value += delta
+}
+
+// Observe records a value in a histogram.
+func Observe(name string, value float64, labels ...string) {
+	mu.Lock()
+	defer mu.Unlock()
+	s := lookup(name, histogramKind, labels)
+	bounds := families[name].buckets
+	if s.buckets == nil {
+		s.buckets = make([]uint64, len(bounds))
+	}
+	for i, bound := range bounds {
+		if value <= bound {
+			s.buckets[i]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+// SetBuckets sets the upper bounds of a histogram, for values outside the range of
+// defaultBuckets. It must be called before the histogram is first observed.
+func SetBuckets(name string, buckets []float64) {
+	mu.Lock()
+	defer mu.Unlock()
+	f := familyFor(name, histogramKind)
+	if len(f.series) > 0 {
+		slog.Warn("histogram buckets set after first observation, ignoring", "metric", name)
+		return
+	}
+	f.buckets = buckets
+}
+
+// familyFor returns the family for name, creating it if needed (must hold mu).
+func familyFor(name string, k kind) *family {
+	f, exists := families[name]
+	if !exists {
+		f = &family{name: name, kind: k, series: make(map[string]*series), buckets: defaultBuckets}
+		families[name] = f
+	}
+	return f
+}
+
+// lookup returns the series for name and labels, creating it if needed (must hold mu).
+func lookup(name string, k kind, labels []string) *series {
+	f := familyFor(name, k)
+	key := formatLabels(labels)
+	s, exists := f.series[key]
+	if !exists {
+		s = &series{labels: key, pairs: append([]string(nil), labels...)}
+		f.series[key] = s
+	}
+	return s
+}
+
+// formatLabels renders alternating name, value pairs as a Prometheus label set.
+func formatLabels(labels []string) string {
+	if len(labels) < 2 {
+		return ""
+	}
+	parts := make([]string, 0, len(labels)/2)
+	for i := 0; i+1 < len(labels); i += 2 {
+		value := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(labels[i+1])
+		parts = append(parts, fmt.Sprintf("%s=%q", labels[i], value))
+	}
+	sort.Strings(parts)
+	return strings.Join(parts, ",")
+}
+
+// Totals sums a counter or gauge's series by the value of one label, such as the
+// processed events by "result". Series without the label are summed under "".
+func Totals(name, label string) map[string]float64 {
+	mu.Lock()
+	defer mu.Unlock()
+
+	totals := make(map[string]float64)
+	f, exists := families[name]
+	if !exists || f.kind == histogramKind {
+		return totals
+	}
+	for _, s := range f.series {
+		value := ""
+		for i := 0; i+1 < len(s.pairs); i += 2 {
+			if s.pairs[i] == label {
+				value = s.pairs[i+1]
+			}
+		}
+		totals[value] += s.value
+	}
+	return totals
+}
+
+// Handler serves all metrics in Prometheus text exposition format.
+func Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if _, err := w.Write([]byte(render())); err != nil {
+			slog.Error("failed to write metrics response", "error", err)
+		}
+	})
+}
+
+// render formats every metric family.
+func render() string {
+	mu.Lock()
+	defer mu.Unlock()
+
+	names := make([]string, 0, len(families))
+	for name := range families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		f := families[name]
+		keys := make([]string, 0, len(f.series))
+		for key := range f.series {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		switch f.kind {
+		case counterKind:
+			fmt.Fprintf(&b, "# TYPE %s counter\n", name)
+		case gaugeKind:
+			fmt.Fprintf(&b, "# TYPE %s gauge\n", name)
+		case histogramKind:
+			fmt.Fprintf(&b, "# TYPE %s histogram\n", name)
+		}
+
+		for _, key := range keys {
+			s := f.series[key]
+			if f.kind != histogramKind {
+				fmt.Fprintf(&b, "%s%s %s\n", name, braces(s.labels), formatFloat(s.value))
+				continue
+			}
+			for i, bound := range f.buckets {
+				fmt.Fprintf(&b, "%s_bucket%s %d\n", name, braces(join(s.labels, fmt.Sprintf("le=%q", formatFloat(bound)))), s.buckets[i])
+			}
+			fmt.Fprintf(&b, "%s_bucket%s %d\n", name, braces(join(s.labels, `le="+Inf"`)), s.count)
+			fmt.Fprintf(&b, "%s_sum%s %s\n", name, braces(s.labels), formatFloat(s.sum))
+			fmt.Fprintf(&b, "%s_count%s %d\n", name, braces(s.labels), s.count)
+		}
+	}
+	return b.String()
+}
+
+func braces(labels string) string {
+	if labels == "" {
+		return ""
+	}
+	return "{" + labels + "}"
+}
+
+func join(labels, extra string) string {
+	if labels == "" {
+		return extra
+	}
+	return labels + "," + extra
+}
+
+func formatFloat(v float64) string {
+	if v == math.Trunc(v) && math.Abs(v) < 1e15 {
+		return fmt.Sprintf("%d", int64(v))
+	}
+	return fmt.Sprintf("%g", v)
+}
@@ -0,0 +1,360 @@
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultPushInterval is how often metrics are pushed when no interval is configured.
+const DefaultPushInterval = 15 * time.Second
+
+// maxDatagram bounds the size of a StatsD packet, to stay under common network MTUs.
+const maxDatagram = 1400
+
+// start is when the process began counting, the start of every cumulative OTLP series.
+var start = time.Now()
+
+// Pusher periodically pushes every metric to a collector, for deployments such as
+// Cloud Run whose short-lived instances are scraped poorly. It speaks OTLP over HTTP
+// with JSON encoding to http and https URLs, and DogStatsD-tagged StatsD over UDP to
+// statsd:// URLs.
+type Pusher struct {
+	client   *http.Client
+	last     map[string]float64 // StatsD counter values as last pushed, by series.
+	target   string             // The OTLP endpoint or the StatsD host:port.
+	instance string
+	interval time.Duration
+	statsd   bool
+}
+
+// NewPusher creates a pusher for target, such as "https://collector:4318/v1/metrics"
+// or "statsd://localhost:8125". A zero interval uses DefaultPushInterval.
+func NewPusher(target string, interval time.Duration, client *http.Client) (*Pusher, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid metrics push URL: %w", err)
+	}
+	if interval <= 0 {
+		interval = DefaultPushInterval
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+	p := &Pusher{client: client, interval: interval, instance: fmt.Sprintf("%s-%d", instance, os.Getpid())}
+
+	switch u.Scheme {
+	case "http", "https":
+		p.target = target
+	case "statsd":
+		if u.Host == "" {
+			return nil, fmt.Errorf("metrics push URL %q has no host", target)
+		}
+		p.target = u.Host
+		p.statsd = true
+		p.last = make(map[string]float64)
+	default:
+		return nil, fmt.Errorf("metrics push URL %q must be http, https, or statsd", target)
+	}
+	return p, nil
+}
+
+// Run pushes metrics every interval until the context is cancelled, then pushes once
+// more so an instance being shut down reports what it counted since the last push.
+func (p *Pusher) Run(ctx context.Context) error {
+	slog.Info("pushing metrics", "target", p.target, "interval", p.interval)
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			p.pushLogged(flushCtx)
+			cancel()
+			return ctx.Err()
+		case <-ticker.C:
+			p.pushLogged(ctx)
+		}
+	}
+}
+
+// pushLogged pushes metrics, logging rather than returning a failure since the next push retries.
+func (p *Pusher) pushLogged(ctx context.Context) {
+	if err := p.push(ctx); err != nil {
+		slog.Warn("failed to push metrics", "target", p.target, "error", err)
+		IncCounter("slacker_metrics_pushes_total", "result", "error")
+		return
+	}
+	IncCounter("slacker_metrics_pushes_total", "result", "ok")
+}
+
+// push sends the current value of every metric.
+func (p *Pusher) push(ctx context.Context) error {
+	points := snapshot()
+	if p.statsd {
+		return p.pushStatsD(ctx, points)
+	}
+	return p.pushOTLP(ctx, points)
+}
+
+// point is a copy of one series, taken so it can be pushed without holding mu.
+type point struct {
+	name    string
+	pairs   []string
+	key     string
+	bounds  []float64
+	buckets []uint64
+	value   float64
+	sum     float64
+	count   uint64
+	kind    kind
+}
+
+// snapshot copies every series.
+func snapshot() []point {
+	mu.Lock()
+	defer mu.Unlock()
+
+	var points []point
+	for name, f := range families {
+		for key, s := range f.series {
+			points = append(points, point{
+				name:    name,
+				pairs:   s.pairs,
+				key:     name + "{" + key + "}",
+				bounds:  f.buckets,
+				buckets: append([]uint64(nil), s.buckets...),
+				value:   s.value,
+				sum:     s.sum,
+				count:   s.count,
+				kind:    f.kind,
+			})
+		}
+	}
+	return points
+}
+
+// pushStatsD sends gauges as they are, and counters and histogram sums and counts as
+// their change since the last push, as StatsD expects.
+func (p *Pusher) pushStatsD(ctx context.Context, points []point) error {
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, "udp", p.target)
+	if err != nil {
+		return fmt.Errorf("failed to reach StatsD: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Debug("failed to close StatsD connection", "error", err)
+		}
+	}()
+
+	var lines []string
+	counter := func(name, key string, total float64, tags string) {
+		delta := total - p.last[key]
+		p.last[key] = total
+		if delta != 0 {
+			lines = append(lines, fmt.Sprintf("%s:%s|c%s", name, formatFloat(delta), tags))
+		}
+	}
+	for _, pt := range points {
+		tags := statsdTags(pt.pairs)
+		switch pt.kind {
+		case counterKind:
+			counter(pt.name, pt.key, pt.value, tags)
+		case gaugeKind:
+			lines = append(lines, fmt.Sprintf("%s:%s|g%s", pt.name, formatFloat(pt.value), tags))
+		case histogramKind:
+			counter(pt.name+"_sum", pt.key+"_sum", pt.sum, tags)
+			counter(pt.name+"_count", pt.key+"_count", float64(pt.count), tags)
+		}
+	}
+
+	var packet bytes.Buffer
+	send := func() error {
+		if packet.Len() == 0 {
+			return nil
+		}
+		_, err := conn.Write(packet.Bytes())
+		packet.Reset()
+		return err
+	}
+	for _, line := range lines {
+		if packet.Len() > 0 && packet.Len()+1+len(line) > maxDatagram {
+			if err := send(); err != nil {
+				return fmt.Errorf("failed to send to StatsD: %w", err)
+			}
+		}
+		if packet.Len() > 0 {
+			packet.WriteByte('\n')
+		}
+		packet.WriteString(line)
+	}
+	if err := send(); err != nil {
+		return fmt.Errorf("failed to send to StatsD: %w", err)
+	}
+	return nil
+}
+
+// statsdTags renders label pairs as DogStatsD tags, such as "|#result:ok,type:dm".
+func statsdTags(pairs []string) string {
+	if len(pairs) < 2 {
+		return ""
+	}
+	tags := make([]string, 0, len(pairs)/2)
+	clean := strings.NewReplacer("|", "_", ",", "_", "#", "_", "\n", " ")
+	for i := 0; i+1 < len(pairs); i += 2 {
+		tags = append(tags, pairs[i]+":"+clean.Replace(pairs[i+1]))
+	}
+	return "|#" + strings.Join(tags, ",")
+}
+
+// OTLP JSON documents, as described by opentelemetry-proto's metrics service.
+type (
+	otlpRequest struct {
+		ResourceMetrics []otlpResourceMetrics `json:"resourceMetrics"`
+	}
+	otlpResourceMetrics struct {
+		Resource     otlpResource       `json:"resource"`
+		ScopeMetrics []otlpScopeMetrics `json:"scopeMetrics"`
+	}
+	otlpResource struct {
+		Attributes []otlpAttribute `json:"attributes"`
+	}
+	otlpScopeMetrics struct {
+		Scope   otlpScope    `json:"scope"`
+		Metrics []otlpMetric `json:"metrics"`
+	}
+	otlpScope struct {
+		Name string `json:"name"`
+	}
+	otlpAttribute struct {
+		Key   string `json:"key"`
+		Value struct {
+			StringValue string `json:"stringValue"`
+		} `json:"value"`
+	}
+	otlpMetric struct {
+		Sum       *otlpData `json:"sum,omitempty"`
+		Gauge     *otlpData `json:"gauge,omitempty"`
+		Histogram *otlpData `json:"histogram,omitempty"`
+		Name      string    `json:"name"`
+	}
+	otlpData struct {
+		DataPoints  []otlpPoint `json:"dataPoints"`
+		Temporality int         `json:"aggregationTemporality,omitempty"`
+		Monotonic   bool        `json:"isMonotonic,omitempty"`
+	}
+	otlpPoint struct {
+		AsDouble     *float64        `json:"asDouble,omitempty"`
+		Sum          *float64        `json:"sum,omitempty"`
+		Start        string          `json:"startTimeUnixNano,omitempty"`
+		Time         string          `json:"timeUnixNano"`
+		Count        string          `json:"count,omitempty"`
+		Attributes   []otlpAttribute `json:"attributes,omitempty"`
+		BucketCounts []string        `json:"bucketCounts,omitempty"`
+		Bounds       []float64       `json:"explicitBounds,omitempty"`
+	}
+)
+
+// otlpCumulative is AGGREGATION_TEMPORALITY_CUMULATIVE.
+const otlpCumulative = 2
+
+// attributes converts label pairs to OTLP attributes.
+func attributes(pairs ...string) []otlpAttribute {
+	attrs := make([]otlpAttribute, 0, len(pairs)/2)
+	for i := 0; i+1 < len(pairs); i += 2 {
+		a := otlpAttribute{Key: pairs[i]}
+		a.Value.StringValue = pairs[i+1]
+		attrs = append(attrs, a)
+	}
+	return attrs
+}
+
+// pushOTLP posts every series as a cumulative OTLP metric.
+func (p *Pusher) pushOTLP(ctx context.Context, points []point) error {
+	now := strconv.FormatInt(time.Now().UnixNano(), 10)
+	began := strconv.FormatInt(start.UnixNano(), 10)
+
+	byName := make(map[string]*otlpMetric)
+	var metrics []*otlpMetric
+	for _, pt := range points {
+		m, exists := byName[pt.name]
+		if !exists {
+			m = &otlpMetric{Name: pt.name}
+			switch pt.kind {
+			case counterKind:
+				m.Sum = &otlpData{Temporality: otlpCumulative, Monotonic: true}
+			case gaugeKind:
+				m.Gauge = &otlpData{}
+			case histogramKind:
+				m.Histogram = &otlpData{Temporality: otlpCumulative}
+			}
+			byName[pt.name] = m
+			metrics = append(metrics, m)
+		}
+
+		dp := otlpPoint{Attributes: attributes(pt.pairs...), Time: now}
+		switch pt.kind {
+		case counterKind:
+			dp.Start, dp.AsDouble = began, &pt.value
+			m.Sum.DataPoints = append(m.Sum.DataPoints, dp)
+		case gaugeKind:
+			dp.AsDouble = &pt.value
+			m.Gauge.DataPoints = append(m.Gauge.DataPoints, dp)
+		case histogramKind:
+			dp.Start, dp.Sum, dp.Bounds = began, &pt.sum, pt.bounds
+			dp.Count = strconv.FormatUint(pt.count, 10)
+			// Buckets are kept cumulative, but OTLP counts each bucket on its own.
+			var below uint64
+			for _, n := range pt.buckets {
+				dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(n-below, 10))
+				below = n
+			}
+			dp.BucketCounts = append(dp.BucketCounts, strconv.FormatUint(pt.count-below, 10))
+			m.Histogram.DataPoints = append(m.Histogram.DataPoints, dp)
+		}
+	}
+
+	scope := otlpScopeMetrics{Scope: otlpScope{Name: "github.com/codeGROOVE-dev/slacker"}}
+	for _, m := range metrics {
+		scope.Metrics = append(scope.Metrics, *m)
+	}
+	body, err := json.Marshal(otlpRequest{ResourceMetrics: []otlpResourceMetrics{{
+		Resource:     otlpResource{Attributes: attributes("service.name", "slacker", "service.instance.id", p.instance)},
+		ScopeMetrics: []otlpScopeMetrics{scope},
+	}}})
+	if err != nil {
+		return fmt.Errorf("failed to encode metrics: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post metrics: %w", err)
+	}
+	if err := resp.Body.Close(); err != nil {
+		slog.Debug("failed to close response body", "error", err)
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+	return nil
+}
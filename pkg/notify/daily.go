@@ -0,0 +1,171 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+const (
+	// dailyReminderHour is the local hour during which daily reminders are sent.
+	dailyReminderHour = 8
+	// maxDailyReminderPRs bounds the PRs listed in one daily reminder.
+	maxDailyReminderPRs = 20
+	// dailyReminderGap is how long since a user's last notification before they get a
+	// daily reminder; one notified more recently already knows what's waiting.
+	dailyReminderGap = 8 * time.Hour
+	// timezoneRetry is how long a failed timezone lookup is remembered before the
+	// user's Slack profile is looked up again.
+	timezoneRetry = 6 * time.Hour
+)
+
+// sendDailyReminders runs the daily reminder check for every workspace.
+func (m *Manager) sendDailyReminders(ctx context.Context) {
+	for _, workspaceID := range m.stateManager.Workspaces() {
+		if err := m.CheckDailyReminders(ctx, workspaceID); err != nil {
+			slog.Warn("failed to check daily reminders", "workspace", workspaceID, "error", err)
+		}
+	}
+}
+
+// CheckDailyReminders DMs each user with daily reminders enabled, once a day between
+// 8 and 9am in their timezone, a list of the PRs blocked on them, unless they were
+// notified within the last 8 hours.
+func (m *Manager) CheckDailyReminders(ctx context.Context, workspaceID string) error {
+	now := m.clock.Now()
+	snap := m.stateManager.Snapshot(workspaceID)
+	sent := 0
+	for userID := range snap.UserPRs {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		prefs := m.stateManager.GetUserPreferences(workspaceID, userID)
+		if !prefs.DailyReminders || prefs.Away(now) || now.Sub(prefs.LastNotified) <= dailyReminderGap {
+			continue
+		}
+		// Dashboards are kept under GitHub logins too. Only Slack users map to a login,
+		// so this skips those before Slack is asked anything.
+		prs := m.blockedPRs(workspaceID, userID, snap)
+		if len(prs) == 0 {
+			continue
+		}
+		loc, ok := m.userTimezone(ctx, workspaceID, userID, prefs)
+		if !ok {
+			continue
+		}
+		local := now.In(loc)
+		if local.Hour() != dailyReminderHour {
+			continue
+		}
+		key := state.DailyReminderKey(userID, local.Format(time.DateOnly))
+		if !m.stateManager.ClaimDelivery(workspaceID, key, state.DeliveryDM, now) {
+			continue
+		}
+
+		text := m.formatDailyReminder(prs)
+		if err := m.slackFor(workspaceID).SendDirectMessage(ctx, userID, text); err != nil {
+			m.queueDelivery(workspaceID, state.OutboxItem{Kind: "dm", UserID: userID, Text: text}, err)
+			continue
+		}
+		m.stateManager.UpdateLastNotified(workspaceID, userID, now)
+		sent++
+		slog.Info("sent daily reminder", "workspace", workspaceID, "user", userID, "prs", len(prs))
+	}
+	if sent > 0 {
+		slog.Debug("checked daily reminders", "workspace", workspaceID, "sent", sent)
+	}
+	return nil
+}
+
+// userTimezone returns a user's timezone: the one stored with their preferences, or
+// else the one on their Slack profile, which is then stored so it's looked up once.
+// A failed lookup isn't retried for timezoneRetry.
+func (m *Manager) userTimezone(ctx context.Context, workspaceID, userID string, prefs state.UserPreferences) (*time.Location, bool) {
+	tz := prefs.Timezone
+	if tz == "" {
+		key := workspaceID + "/" + userID
+		m.timezonesMu.Lock()
+		failedAt, failed := m.timezoneFailures[key]
+		m.timezonesMu.Unlock()
+		if failed && m.clock.Now().Sub(failedAt) < timezoneRetry {
+			return nil, false
+		}
+		user, err := m.slackFor(workspaceID).GetUserInfo(ctx, userID)
+		m.timezonesMu.Lock()
+		if err != nil {
+			m.timezoneFailures[key] = m.clock.Now()
+		} else {
+			delete(m.timezoneFailures, key)
+		}
+		m.timezonesMu.Unlock()
+		if err != nil {
+			slog.Debug("failed to look up timezone", "user", userID, "error", err)
+			return nil, false
+		}
+		tz = user.TZ
+		if tz == "" {
+			tz = "UTC"
+		}
+		prefs.Timezone = tz
+		m.stateManager.SetUserPreferences(workspaceID, userID, prefs)
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		slog.Warn("invalid timezone for daily reminder", "user", userID, "timezone", tz, "error", err)
+		return time.UTC, true
+	}
+	return loc, true
+}
+
+// blockedPRs returns the PRs on a user's dashboard that are blocked on their GitHub
+// login, leaving out those of paused orgs.
+func (m *Manager) blockedPRs(workspaceID, userID string, snap *state.Snapshot) []*state.PRState {
+	login, found := m.stateManager.GitHubLoginFor(workspaceID, userID)
+	if !found {
+		return nil
+	}
+	var prs []*state.PRState
+	for _, pr := range snap.UserPRsFor(userID) {
+		if !slices.ContainsFunc(pr.BlockedOn, func(l string) bool { return strings.EqualFold(l, login) }) {
+			continue
+		}
+		if m.stateManager.Paused(workspaceID, pr.Owner) {
+			continue
+		}
+		prs = append(prs, pr)
+	}
+	// Longest waiting first.
+	slices.SortFunc(prs, func(a, b *state.PRState) int { return a.UpdatedAt.Compare(b.UpdatedAt) })
+	return prs
+}
+
+// formatDailyReminder formats the daily reminder DM listing prs. It's phrased in
+// the tone of the PRs' org, or neutrally if they span orgs with different tones.
+func (m *Manager) formatDailyReminder(prs []*state.PRState) string {
+	catalog := m.catalog(prs[0].Owner)
+	for _, pr := range prs[1:] {
+		if m.catalog(pr.Owner) != catalog {
+			catalog = slack.CatalogFor(config.ToneNeutral)
+			break
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(catalog.DailyReminder(len(prs)))
+	for i, pr := range prs {
+		if i == maxDailyReminderPRs {
+			fmt.Fprintf(&b, "\n…and %d more. See the Home tab for all of them.", len(prs)-i)
+			break
+		}
+		fmt.Fprintf(&b, "\n• <https://github.com/%s/%s/pull/%d|%s/%s#%d> %s - %s",
+			pr.Owner, pr.Repo, pr.Number, pr.Owner, pr.Repo, pr.Number, pr.Title, m.action(pr))
+	}
+	return b.String()
+}
@@ -0,0 +1,177 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/events"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// Subscribe notifies users that PRs newly wait on, and PRs' subscribers of their state
+// changes, as the coordinator publishes them on bus.
+func (m *Manager) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(ctx context.Context, ev events.UserBlocked) {
+		if slug, isTeam := strings.CutPrefix(ev.User, "team:"); isTeam {
+			if err := m.mentionTeam(ctx, ev.Workspace, ev.Owner, ev.Repo, ev.Number, slug); err != nil {
+				slog.Warn("failed to mention blocked team", "team", slug, "owner", ev.Owner, "repo", ev.Repo, "number", ev.Number, "error", err)
+			}
+			return
+		}
+		userID, found := m.slackUser(ctx, ev.Workspace, ev.Owner, ev.User)
+		if !found {
+			return
+		}
+		stored, exists := m.stateManager.GetPRState(ev.Workspace, ev.Owner, ev.Repo, ev.Number)
+		if !exists {
+			return
+		}
+		if err := m.Deliver(ctx, ev.Workspace, userID, stored); err != nil {
+			slog.Warn("failed to notify blocked user", "user", userID, "owner", ev.Owner, "repo", ev.Repo, "number", ev.Number, "error", err)
+		}
+	})
+	// Blocked users are published first, so a subscriber the PR waits on hears about it once.
+	events.Subscribe(bus, func(ctx context.Context, ev events.PRStateChanged) {
+		for _, userID := range ev.PR.Subscribers {
+			if err := m.notifySubscriber(ctx, ev.Workspace, userID, ev.PR); err != nil {
+				slog.Warn("failed to notify subscriber", "user", userID, "owner", ev.PR.Owner, "repo", ev.PR.Repo, "number", ev.PR.Number, "error", err)
+			}
+		}
+	})
+}
+
+// notifySubscriber DMs a subscriber that a PR changed state, if the notification
+// gates pass and they haven't already been told about this state.
+func (m *Manager) notifySubscriber(ctx context.Context, workspaceID, userID string, pr *state.PRState) error {
+	for _, r := range m.checkGates(ctx, workspaceID, userID, pr, false) {
+		if !r.Passed {
+			slog.Debug("skipping subscriber notification", "user", userID, "gate", r.Name, "reason", r.Detail)
+			return nil
+		}
+	}
+	if !m.stateManager.ClaimDelivery(workspaceID, state.DeliveryKey(userID, pr), state.DeliveryDM, m.clock.Now()) {
+		metrics.IncCounter("slacker_notifications_deduplicated_total", "via", state.DeliveryDM)
+		return nil
+	}
+	return m.sendDM(ctx, workspaceID, userID, pr, m.FormatUpdate(pr))
+}
+
+// Deliver tells a user a PR is waiting on them, once per state the PR enters. By
+// default the user gets a DM if the notification gates pass and is mentioned in the
+// PR's thread otherwise, except in low severity repos; users who prefer thread mentions
+// never get the DM, and users who turned on smart timing may get it later in their
+// usual review hours. Users on vacation aren't told; the thread is told they're away instead. Nothing is sent while
+// posting is paused for the PR's org.
+func (m *Manager) Deliver(ctx context.Context, workspaceID, userID string, pr *state.PRState) error {
+	if m.stateManager.Paused(workspaceID, pr.Owner) {
+		return nil
+	}
+	if prefs := m.stateManager.GetUserPreferences(workspaceID, userID); prefs.Away(m.clock.Now()) {
+		return m.noteAway(ctx, workspaceID, userID, prefs, pr)
+	}
+	via := m.deliveryChannel(ctx, workspaceID, userID, pr)
+	if via == "" {
+		slog.Debug("no way to notify user", "user", userID, "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number)
+		return nil
+	}
+	if via == state.DeliveryDM && m.deferDM(ctx, workspaceID, userID, pr) {
+		return nil
+	}
+	return m.deliverOnce(ctx, workspaceID, userID, pr, via)
+}
+
+// deliveryChannel picks how to tell a user about a PR, or returns "" if there's no way to.
+func (m *Manager) deliveryChannel(ctx context.Context, workspaceID, userID string, pr *state.PRState) string {
+	if m.stateManager.GetUserPreferences(workspaceID, userID).NotifyVia != state.DeliveryThread {
+		dm := true
+		for _, r := range m.checkGates(ctx, workspaceID, userID, pr, false) {
+			if !r.Passed {
+				slog.Debug("not sending DM", "user", userID, "gate", r.Name, "reason", r.Detail)
+				dm = false
+			}
+		}
+		if dm {
+			return state.DeliveryDM
+		}
+		// Low severity repos don't escalate to a mention in the PR's thread.
+		if m.lowSeverity(pr) {
+			return ""
+		}
+	}
+	if pr.ThreadTS == "" || pr.State == state.Unknown {
+		return ""
+	}
+	return state.DeliveryThread
+}
+
+// deliverOnce sends a notification via a channel unless the user was already told
+// about the PR's current state. A send that fails is queued for retry, so it still
+// counts as delivered.
+func (m *Manager) deliverOnce(ctx context.Context, workspaceID, userID string, pr *state.PRState, via string) error {
+	if !m.stateManager.ClaimDelivery(workspaceID, state.DeliveryKey(userID, pr), via, m.clock.Now()) {
+		slog.Debug("skipping notification already delivered", "user", userID, "via", via,
+			"owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "state", pr.State)
+		metrics.IncCounter("slacker_notifications_deduplicated_total", "via", via)
+		return nil
+	}
+
+	if via == state.DeliveryDM {
+		return m.sendDM(ctx, workspaceID, userID, pr, m.FormatNotification(pr))
+	}
+	if err := m.SendThreadUpdate(ctx, workspaceID, pr, fmt.Sprintf("<@%s>: %s", userID, m.action(pr))); err != nil {
+		return fmt.Errorf("failed to mention user in thread: %w", err)
+	}
+	slog.Info("mentioned user in thread", "user", userID, "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number)
+	return nil
+}
+
+// mentionTeam mentions the Slack usergroup of a team a PR waits on in the PR's thread,
+// once per state the PR enters, if the team's fanout is to its usergroup.
+func (m *Manager) mentionTeam(ctx context.Context, workspaceID, owner, repo string, number int, slug string) error {
+	if m.config == nil || m.stateManager.Paused(workspaceID, owner) {
+		return nil
+	}
+	settings := m.config.GetTeamSettings(owner, slug)
+	if settings.Fanout != config.FanoutUsergroup || settings.Usergroup == "" {
+		return nil
+	}
+	pr, exists := m.stateManager.GetPRState(workspaceID, owner, repo, number)
+	if !exists || pr.ThreadTS == "" || pr.State == state.Unknown {
+		return nil
+	}
+	if !m.stateManager.ClaimDelivery(workspaceID, state.DeliveryKey("team:"+slug, pr), state.DeliveryThread, m.clock.Now()) {
+		return nil
+	}
+	message := fmt.Sprintf("<!subteam^%s>: %s", settings.Usergroup, m.action(pr))
+	if err := m.SendThreadUpdate(ctx, workspaceID, pr, message); err != nil {
+		return fmt.Errorf("failed to mention usergroup in thread: %w", err)
+	}
+	slog.Info("mentioned team usergroup in thread", "team", slug, "owner", owner, "repo", repo, "number", number)
+	return nil
+}
+
+// noteAway tells a PR's thread, once per state the PR enters, that a user it waits
+// on is away, so the author can find someone else rather than wait out the vacation.
+func (m *Manager) noteAway(ctx context.Context, workspaceID, userID string, prefs state.UserPreferences, pr *state.PRState) error {
+	if pr.ThreadTS == "" {
+		return nil
+	}
+	if !m.stateManager.ClaimDelivery(workspaceID, state.DeliveryKey(userID, pr), state.DeliveryAway, m.clock.Now()) {
+		return nil
+	}
+	who := prefs.GitHubLogin
+	if who == "" {
+		who = "A reviewer"
+	}
+	message := fmt.Sprintf("🌴 %s is away until %s, so this may need someone else.", who, slack.FormatTime(prefs.AwayUntil, slack.DateTime))
+	if err := m.SendThreadUpdate(ctx, workspaceID, pr, message); err != nil {
+		return fmt.Errorf("failed to note away user in thread: %w", err)
+	}
+	slog.Info("noted away user in thread", "user", userID, "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number)
+	return nil
+}
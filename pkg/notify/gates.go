@@ -0,0 +1,161 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// GateResult reports whether a notification passed one of the checks before delivery.
+type GateResult struct {
+	Name   string
+	Detail string
+	Passed bool
+}
+
+// gate is a check a notification must pass before it is sent.
+type gate struct {
+	check func(ctx context.Context, m *Manager, workspaceID, userID string, pr *state.PRState) (bool, string)
+	name  string
+}
+
+// gates are checked in order; the cheap local checks come before the Slack API call.
+var gates = []gate{
+	{
+		name: "posting",
+		check: func(_ context.Context, m *Manager, workspaceID, _ string, pr *state.PRState) (bool, string) {
+			if m.stateManager.Paused(workspaceID, pr.Owner) {
+				return false, "posting is paused by an admin"
+			}
+			return true, "not paused"
+		},
+	},
+	{
+		name: "known state",
+		check: func(_ context.Context, _ *Manager, _, _ string, pr *state.PRState) (bool, string) {
+			if pr.State == state.Unknown {
+				return false, "the PR's state is unknown until its data can be fetched from GitHub"
+			}
+			return true, "known"
+		},
+	},
+	{
+		name: "away",
+		check: func(_ context.Context, m *Manager, workspaceID, userID string, _ *state.PRState) (bool, string) {
+			prefs := m.stateManager.GetUserPreferences(workspaceID, userID)
+			if prefs.Away(m.clock.Now()) {
+				return false, "away until " + slack.FormatTime(prefs.AwayUntil, slack.DateTime)
+			}
+			return true, "not away"
+		},
+	},
+	{
+		name: "real-time notifications",
+		check: func(_ context.Context, m *Manager, workspaceID, userID string, _ *state.PRState) (bool, string) {
+			if !m.stateManager.GetUserPreferences(workspaceID, userID).RealTimeNotifications {
+				return false, "turned off in your preferences"
+			}
+			return true, "enabled"
+		},
+	},
+	{
+		name: "notify delay",
+		check: func(_ context.Context, m *Manager, workspaceID, userID string, pr *state.PRState) (bool, string) {
+			prefs := m.stateManager.GetUserPreferences(workspaceID, userID)
+			if wait := m.relaxed(pr, prefs.ChannelNotifyDelay) - m.clock.Now().Sub(prefs.LastNotified); wait > 0 {
+				if m.deferred(pr) {
+					return false, fmt.Sprintf("last notified too recently, and the PR is deferred; next in %s", wait.Round(time.Minute))
+				}
+				return false, fmt.Sprintf("last notified too recently; next in %s", wait.Round(time.Minute))
+			}
+			return true, "not notified recently"
+		},
+	},
+	{
+		name: "review claim",
+		check: func(_ context.Context, m *Manager, workspaceID, userID string, pr *state.PRState) (bool, string) {
+			now := m.clock.Now()
+			// A claim by someone who has since gone away no longer holds others back.
+			if pr.State == "hourglass" && pr.ClaimActive(now, m.scaled(pr, state.ClaimTimeout)) && pr.ClaimedBy != userID &&
+				!m.stateManager.GetUserPreferences(workspaceID, pr.ClaimedBy).Away(now) {
+				return false, fmt.Sprintf("<@%s> is already reviewing", pr.ClaimedBy)
+			}
+			return true, "no one else is reviewing"
+		},
+	},
+	{
+		name: "plugins",
+		check: func(ctx context.Context, m *Manager, workspaceID, userID string, pr *state.PRState) (bool, string) {
+			if !m.hooks.AllowNotify(ctx, workspaceID, userID, pr) {
+				return false, "suppressed by a notify hook"
+			}
+			return true, "allowed"
+		},
+	},
+	{
+		name: "presence",
+		check: func(ctx context.Context, m *Manager, workspaceID, userID string, _ *state.PRState) (bool, string) {
+			if !m.slackFor(workspaceID).IsUserActive(ctx, userID) {
+				return false, "not active in Slack, so notifications are deferred"
+			}
+			return true, "active"
+		},
+	},
+}
+
+// checkGates runs the gates in order, stopping at the first failure unless all is set.
+func (m *Manager) checkGates(ctx context.Context, workspaceID, userID string, pr *state.PRState, all bool) []GateResult {
+	results := make([]GateResult, 0, len(gates))
+	for _, g := range gates {
+		passed, detail := g.check(ctx, m, workspaceID, userID, pr)
+		results = append(results, GateResult{Name: g.name, Detail: detail, Passed: passed})
+		if !passed && !all {
+			break
+		}
+	}
+	return results
+}
+
+// samplePR is the PR described by test notifications.
+var samplePR = state.PRState{
+	Owner:  "codeGROOVE-dev",
+	Repo:   "slacker",
+	Number: 1,
+	Title:  "Sample notification",
+	Author: "ready-to-review",
+	State:  "hourglass",
+}
+
+// TestNotification runs a sample notification for a user through every gate and,
+// if they all pass, sends it. The test does not count as the user's last
+// notification, so it never delays a real one.
+func (m *Manager) TestNotification(ctx context.Context, workspaceID, userID string) string {
+	pr := samplePR
+	results := m.checkGates(ctx, workspaceID, userID, &pr, true)
+
+	lines := []string{"Notification checks:"}
+	passed := true
+	for _, r := range results {
+		mark := "✅"
+		if !r.Passed {
+			mark = "❌"
+			passed = false
+		}
+		lines = append(lines, fmt.Sprintf("%s %s: %s", mark, r.Name, r.Detail))
+	}
+	if !passed {
+		return strings.Join(append(lines, "", "No test DM was sent; a real notification would be held back too."), "\n")
+	}
+
+	message := m.FormatNotification(&pr)
+	if err := m.slackFor(workspaceID).SendDirectMessage(ctx, userID, message, slack.StateAttachments(pr.State)...); err != nil {
+		slog.Warn("failed to send test notification", "user", userID, "error", err)
+		return strings.Join(append(lines, "", fmt.Sprintf("❌ delivery: %v", err)), "\n")
+	}
+	return strings.Join(append(lines, "✅ delivery: sent, check your DMs"), "\n")
+}
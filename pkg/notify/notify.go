@@ -3,10 +3,18 @@ package notify
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
+	"sync"
 	"time"
 
+	"github.com/codeGROOVE-dev/slacker/pkg/clock"
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/events"
+	"github.com/codeGROOVE-dev/slacker/pkg/hooks"
+	"github.com/codeGROOVE-dev/slacker/pkg/identity"
 	"github.com/codeGROOVE-dev/slacker/pkg/slack"
 	"github.com/codeGROOVE-dev/slacker/pkg/state"
 )
@@ -14,28 +22,128 @@ import (
 // Manager handles user notifications.
 type Manager struct {
 	slack        *slack.Client
+	workspaces   map[string]*slack.Client
 	stateManager *state.Manager
+	config       *config.Manager
+	bus          *events.Bus
+	hooks        *hooks.Registry
+	identities   *identity.Resolver
+	clock        clock.Clock
+	// timezoneFailures holds when looking up a user's timezone last failed, by workspace/user.
+	timezoneFailures map[string]time.Time
+	timezonesMu      sync.Mutex
 }
 
-// New creates a new notification manager.
+// New creates a new notification manager. slackClient is used for workspaces
+// without a client of their own.
 func New(slackClient *slack.Client, stateManager *state.Manager) *Manager {
 	return &Manager{
-		slack:        slackClient,
-		stateManager: stateManager,
+		slack:            slackClient,
+		workspaces:       make(map[string]*slack.Client),
+		stateManager:     stateManager,
+		clock:            clock.Real,
+		timezoneFailures: make(map[string]time.Time),
 	}
 }
 
+// SetClock sets the clock that schedules notifications and reminders, and times delays and claims.
+func (m *Manager) SetClock(c clock.Clock) {
+	m.clock = c
+}
+
+// SetWorkspaceClient sets the Slack client used to notify a workspace.
+func (m *Manager) SetWorkspaceClient(workspaceID string, client *slack.Client) {
+	m.workspaces[workspaceID] = client
+}
+
+// SetIdentities sets the resolver that finds the Slack users of the GitHub logins PRs wait on.
+func (m *Manager) SetIdentities(r *identity.Resolver) {
+	m.identities = r
+}
+
+// slackUser returns the Slack user a GitHub login in an org belongs to.
+func (m *Manager) slackUser(ctx context.Context, workspaceID, org, login string) (string, bool) {
+	if m.identities == nil {
+		return m.stateManager.FindUserByGitHubLogin(workspaceID, login)
+	}
+	return m.identities.Resolve(ctx, workspaceID, org, login)
+}
+
+// SetConfig sets the org configs consulted when formatting notifications.
+func (m *Manager) SetConfig(c *config.Manager) {
+	m.config = c
+}
+
+// frozen reports whether a freeze window is in effect for an org.
+func (m *Manager) frozen(org string) bool {
+	if m.config == nil {
+		return false
+	}
+	_, active := m.config.ActiveFreeze(org, m.clock.Now())
+	return active
+}
+
+// scaled stretches a duration by the severity tier of a PR's repo.
+func (m *Manager) scaled(pr *state.PRState, d time.Duration) time.Duration {
+	if m.config == nil {
+		return d
+	}
+	return time.Duration(float64(d) * m.config.SeverityScale(pr.Owner, pr.Repo))
+}
+
+// deferred reports whether a PR is deferred by a thread reaction in an org that
+// still has deferral turned on.
+func (m *Manager) deferred(pr *state.PRState) bool {
+	return m.config != nil && m.config.GetDeferral(pr.Owner).Scale > 0 && pr.DeferralActive(m.clock.Now())
+}
+
+// relaxed stretches a nudge timing for a PR by its repo's severity tier and, while
+// the PR is deferred, by its org's deferral scale.
+func (m *Manager) relaxed(pr *state.PRState, d time.Duration) time.Duration {
+	d = m.scaled(pr, d)
+	if m.deferred(pr) {
+		d = time.Duration(float64(d) * m.config.GetDeferral(pr.Owner).Scale)
+	}
+	return d
+}
+
+// lowSeverity reports whether a PR's repo is in the low severity tier.
+func (m *Manager) lowSeverity(pr *state.PRState) bool {
+	return m.config != nil && m.config.GetSeverity(pr.Owner, pr.Repo) == config.SeverityLow
+}
+
+// SetBus sets the bus that sent notifications are published to.
+func (m *Manager) SetBus(b *events.Bus) {
+	m.bus = b
+}
+
+// SetHooks sets the hooks that may suppress notifications.
+func (m *Manager) SetHooks(r *hooks.Registry) {
+	m.hooks = r
+}
+
+// slackFor returns the Slack client for a workspace.
+func (m *Manager) slackFor(workspaceID string) *slack.Client {
+	if client, exists := m.workspaces[workspaceID]; exists {
+		return client
+	}
+	return m.slack
+}
+
 // Run starts the notification scheduler.
 func (m *Manager) Run(ctx context.Context) error {
-	ticker := time.NewTicker(1 * time.Minute)
+	ticker := m.clock.NewTicker(1 * time.Minute)
 	defer ticker.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return ctx.Err()
-		case <-ticker.C:
+		case <-ticker.C():
 			m.checkNotifications(ctx)
+			m.sendReminders(ctx)
+			m.sendDailyReminders(ctx)
+			m.retryOutbox(ctx)
 		}
 	}
 }
@@ -53,59 +161,45 @@ func (m *Manager) checkNotifications(ctx context.Context) {
 	// 4. Send notifications as needed
 }
 
-// NotifyUser sends a notification to a user about a PR.
+// NotifyUser sends a DM to a user about a PR, unless the user was already told about
+// the PR's current state.
 func (m *Manager) NotifyUser(ctx context.Context, workspaceID, userID string, pr *state.PRState) error {
-	// Get user preferences.
-	prefs := m.stateManager.GetUserPreferences(workspaceID, userID)
-
-	// Check if real-time notifications are enabled.
-	if !prefs.RealTimeNotifications {
-		return nil
-	}
-
-	// Check if enough time has passed since last notification.
-	if time.Since(prefs.LastNotified) < prefs.ChannelNotifyDelay {
-		slog.Debug("skipping notification - too soon", "user", userID)
-		return nil
-	}
-
-	// Check if user is active.
-	if !m.slack.IsUserActive(ctx, userID) {
-		slog.Debug("user not active, deferring notification", "user", userID)
-		return nil
+	// Check preferences, plugins, and presence.
+	for _, r := range m.checkGates(ctx, workspaceID, userID, pr, false) {
+		if !r.Passed {
+			slog.Debug("skipping notification", "user", userID, "gate", r.Name, "reason", r.Detail)
+			return nil
+		}
 	}
+	return m.deliverOnce(ctx, workspaceID, userID, pr, state.DeliveryDM)
+}
 
-	// Format notification message.
-	message := m.formatNotificationMessage(pr)
-
+// sendDM sends a notification DM about a PR, queueing it for retry if Slack is unavailable.
+func (m *Manager) sendDM(ctx context.Context, workspaceID, userID string, pr *state.PRState, message string) error {
 	// Send DM to user.
-	if err := m.slack.SendDirectMessage(ctx, userID, message); err != nil {
+	if err := m.slackFor(workspaceID).SendDirectMessage(ctx, userID, message, slack.StateAttachments(pr.State)...); err != nil {
+		m.queueDelivery(workspaceID, state.OutboxItem{Kind: "dm", UserID: userID, Text: message}, err)
 		return fmt.Errorf("failed to send notification: %w", err)
 	}
 
 	// Update last notified time.
-	m.stateManager.UpdateLastNotified(workspaceID, userID)
+	m.stateManager.UpdateLastNotified(workspaceID, userID, m.clock.Now())
+	m.bus.Publish(ctx, events.NotificationSent{
+		Time:      m.clock.Now(),
+		Workspace: workspaceID,
+		Owner:     pr.Owner,
+		Repo:      pr.Repo,
+		Number:    pr.Number,
+		State:     pr.State,
+		UserID:    userID,
+	})
 
 	slog.Info("sent notification", "user", userID, "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number)
 	return nil
 }
 
-// formatNotificationMessage formats a notification message for a PR.
-func (m *Manager) formatNotificationMessage(pr *state.PRState) string {
-	var action string
-	switch pr.State {
-	case "broken_heart":
-		action = "waiting for you to fix tests"
-	case "hourglass":
-		action = "waiting for your review"
-	case "carpentry_saw":
-		action = "waiting for you to address review feedback"
-	case "check":
-		action = "approved and ready to merge"
-	default:
-		action = "needs your attention"
-	}
-
+// FormatNotification formats the DM sent to a user about a PR.
+func (m *Manager) FormatNotification(pr *state.PRState) string {
 	return fmt.Sprintf(
 		":postal_horn: %s • %s/%s#%d by @%s - %s",
 		pr.Title,
@@ -113,32 +207,141 @@ func (m *Manager) formatNotificationMessage(pr *state.PRState) string {
 		pr.Repo,
 		pr.Number,
 		pr.Author,
-		action,
+		m.action(pr),
+	)
+}
+
+// FormatUpdate formats the DM sent to a PR's subscribers when it changes state.
+func (m *Manager) FormatUpdate(pr *state.PRState) string {
+	label := slack.StateLabel(pr.State)
+	if pr.State == "check" && m.frozen(pr.Owner) {
+		label += ", but 🧊 freeze in effect"
+	}
+	return fmt.Sprintf(
+		":bell: %s • %s/%s#%d by @%s - now %s %s",
+		pr.Title,
+		pr.Owner,
+		pr.Repo,
+		pr.Number,
+		pr.Author,
+		slack.StateEmoji(pr.State),
+		label,
 	)
 }
 
-// CheckDailyReminders checks and sends daily reminders.
-func (m *Manager) CheckDailyReminders(ctx context.Context, workspaceID string) error {
-	// This would be called periodically to send daily reminders.
-	// It would check each user's timezone and preferences.
-	slog.Debug("checking daily reminders", "workspace", workspaceID)
+// catalog returns the phrasing of an org's nudges, in the tone its config sets.
+func (m *Manager) catalog(org string) slack.Catalog {
+	if m.config == nil {
+		return slack.CatalogFor(config.ToneNeutral)
+	}
+	return slack.CatalogFor(m.config.GetTone(org))
+}
 
-	// In production:
-	// 1. Get all users in workspace
-	// 2. For each user, check if it's 8-9am in their timezone
-	// 3. Check if daily reminders are enabled
-	// 4. Check if >8 hours since last notification
-	// 5. Send summary of blocked PRs
+// action describes what a PR is waiting on its blocked users for, in its org's tone.
+func (m *Manager) action(pr *state.PRState) string {
+	catalog := m.catalog(pr.Owner)
+	switch pr.State {
+	case "broken_heart":
+		return catalog.FixTests
+	case "hourglass":
+		return catalog.Review
+	case "carpentry_saw":
+		return catalog.AddressFeedback
+	case "check":
+		if m.frozen(pr.Owner) {
+			return catalog.MergeFrozen
+		}
+		return catalog.Merge
+	default:
+		return catalog.Attention
+	}
+}
 
+// SendThreadUpdate sends an update to a PR thread, queueing it for retry if Slack is unavailable.
+// A reply made while handling a GitHub delivery is sent once per delivery and content, so
+// reprocessing or redelivering the event doesn't post it twice, even if it was queued.
+func (m *Manager) SendThreadUpdate(ctx context.Context, workspaceID string, pr *state.PRState, message string) error {
+	if m.stateManager.Paused(workspaceID, pr.Owner) {
+		return nil
+	}
+	if deliveryID := deliveryFrom(ctx); deliveryID != "" {
+		if !m.stateManager.ClaimThreadReply(workspaceID, pr, deliveryID+":"+contentHash(message)) {
+			slog.Debug("skipping duplicate thread reply", "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number, "delivery_id", deliveryID)
+			return nil
+		}
+	}
+	if err := m.slackFor(workspaceID).PostThreadReply(ctx, pr.ChannelID, pr.ThreadTS, message); err != nil {
+		m.queueDelivery(workspaceID, state.OutboxItem{
+			Kind:      "thread_reply",
+			ChannelID: pr.ChannelID,
+			ThreadTS:  pr.ThreadTS,
+			Text:      message,
+		}, err)
+		return err
+	}
 	return nil
 }
 
-// SendThreadUpdate sends an update to a PR thread.
-func (m *Manager) SendThreadUpdate(ctx context.Context, channelID, threadTS, message string) error {
-	return m.slack.PostThreadReply(ctx, channelID, threadTS, message)
+type deliveryKey struct{}
+
+// WithDelivery returns a context for handling the GitHub delivery with the given ID,
+// whose thread replies are sent once each.
+func WithDelivery(ctx context.Context, deliveryID string) context.Context {
+	return context.WithValue(ctx, deliveryKey{}, deliveryID)
+}
+
+// deliveryFrom returns the ID of the delivery being handled with ctx, if any.
+func deliveryFrom(ctx context.Context) string {
+	id, ok := ctx.Value(deliveryKey{}).(string)
+	if !ok {
+		return ""
+	}
+	return id
+}
+
+// UpdateThreadReaction updates the reaction on a PR thread based on PR state, skipping unchanged states.
+func (m *Manager) UpdateThreadReaction(ctx context.Context, workspaceID string, pr *state.PRState, newState string) error {
+	return m.applyOnce(workspaceID, pr, "reaction", newState, func() error {
+		return m.slackFor(workspaceID).UpdateReactions(ctx, pr.ChannelID, pr.ThreadTS, newState)
+	})
+}
+
+// AddThreadReaction adds a reaction for the PR state to its thread, keeping earlier state reactions.
+func (m *Manager) AddThreadReaction(ctx context.Context, workspaceID string, pr *state.PRState, newState string) error {
+	return m.applyOnce(workspaceID, pr, "reaction", newState, func() error {
+		return m.slackFor(workspaceID).AddStateReaction(ctx, pr.ChannelID, pr.ThreadTS, newState)
+	})
+}
+
+// EditThreadMessage replaces the text of a PR thread's parent message and colors its
+// attachment bar for prState, skipping unchanged messages.
+func (m *Manager) EditThreadMessage(ctx context.Context, workspaceID string, pr *state.PRState, text, prState string) error {
+	return m.applyOnce(workspaceID, pr, "message", text+"\x00"+prState, func() error {
+		return m.slackFor(workspaceID).UpdateMessage(ctx, pr.ChannelID, pr.ThreadTS, text, slack.ThreadAttachments(prState, m.frozen(pr.Owner))...)
+	})
+}
+
+// applyOnce calls send unless content matches the last update of this kind applied to the PR's
+// thread. While posting is paused nothing is sent or recorded, so the update applies on resuming.
+func (m *Manager) applyOnce(workspaceID string, pr *state.PRState, kind, content string, send func() error) error {
+	if m.stateManager.Paused(workspaceID, pr.Owner) {
+		return nil
+	}
+	hash := contentHash(content)
+	if pr.ThreadHashes[kind] == hash {
+		slog.Debug("skipping duplicate thread update", "kind", kind, "owner", pr.Owner, "repo", pr.Repo, "number", pr.Number)
+		return nil
+	}
+
+	if err := send(); err != nil {
+		return err
+	}
+	m.stateManager.RecordThreadHash(workspaceID, pr, kind, hash)
+	return nil
 }
 
-// UpdateThreadReaction updates the reaction on a thread based on PR state.
-func (m *Manager) UpdateThreadReaction(ctx context.Context, channelID, timestamp, newState string) error {
-	return m.slack.UpdateReactions(ctx, channelID, timestamp, newState)
+// contentHash returns a short hash of a thread update's content.
+func contentHash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:8])
 }
@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/admin"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+const (
+	// maxOutboxAttempts is how many retries a failed delivery gets before it is dead-lettered.
+	maxOutboxAttempts = 10
+	// maxOutboxBackoff caps the delay between retries of a failed delivery.
+	maxOutboxBackoff = time.Hour
+)
+
+// queueDelivery persists a failed delivery so it is retried after the Slack retry window.
+func (m *Manager) queueDelivery(workspaceID string, item state.OutboxItem, cause error) {
+	item.Attempts = 1
+	item.LastError = cause.Error()
+	item.NextAttempt = m.clock.Now().Add(outboxBackoff(item.Attempts))
+	m.stateManager.EnqueueOutbox(workspaceID, item)
+	slog.Warn("queued failed delivery for retry", "workspace", workspaceID, "kind", item.Kind, "error", cause)
+}
+
+// retryOutbox retries failed deliveries that are due, dead-lettering those that keep failing.
+func (m *Manager) retryOutbox(ctx context.Context) {
+	now := m.clock.Now()
+	for _, workspaceID := range m.stateManager.Workspaces() {
+		for _, item := range m.stateManager.ListOutbox(workspaceID) {
+			if item.Dead || now.Before(item.NextAttempt) {
+				continue
+			}
+
+			err := m.deliver(ctx, workspaceID, item)
+			if err == nil {
+				m.stateManager.RemoveOutboxItem(workspaceID, item.ID)
+				slog.Info("delivered queued message", "workspace", workspaceID, "kind", item.Kind, "attempts", item.Attempts+1)
+				continue
+			}
+
+			item.Attempts++
+			item.LastError = err.Error()
+			if item.Attempts >= maxOutboxAttempts {
+				item.Dead = true
+				slog.Error("dead-lettered delivery after repeated failures",
+					"workspace", workspaceID, "kind", item.Kind, "id", item.ID, "error", err)
+			} else {
+				item.NextAttempt = now.Add(outboxBackoff(item.Attempts))
+			}
+			m.stateManager.UpdateOutboxItem(workspaceID, item)
+		}
+	}
+}
+
+// deliver sends a queued item to the workspace's Slack.
+func (m *Manager) deliver(ctx context.Context, workspaceID string, item state.OutboxItem) error {
+	client := m.slackFor(workspaceID)
+	switch item.Kind {
+	case "dm":
+		return client.SendDirectMessage(ctx, item.UserID, item.Text)
+	case "thread_reply":
+		return client.PostThreadReply(ctx, item.ChannelID, item.ThreadTS, item.Text)
+	default:
+		return fmt.Errorf("unknown outbox item kind: %q", item.Kind)
+	}
+}
+
+// outboxBackoff returns the delay before the next retry, doubling from one minute.
+func outboxBackoff(attempts int) time.Duration {
+	delay := time.Minute << min(attempts, 10)
+	return min(delay, maxOutboxBackoff)
+}
+
+// OutboxHandler serves the queued and dead-lettered deliveries of every workspace as JSON.
+func (m *Manager) OutboxHandler(w http.ResponseWriter, _ *http.Request) {
+	outbox := make(map[string][]state.OutboxItem)
+	for _, workspaceID := range m.stateManager.Workspaces() {
+		if items := m.stateManager.ListOutbox(workspaceID); len(items) > 0 {
+			outbox[workspaceID] = items
+		}
+	}
+
+	admin.WriteJSON(w, http.StatusOK, outbox)
+}
@@ -0,0 +1,22 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// sendReminders DMs users whose scheduled PR reminders are due.
+func (m *Manager) sendReminders(ctx context.Context) {
+	now := m.clock.Now()
+	for _, workspaceID := range m.stateManager.Workspaces() {
+		for _, r := range m.stateManager.TakeDueReminders(workspaceID, now) {
+			if err := m.slackFor(workspaceID).SendDirectMessage(ctx, r.UserID, r.Text); err != nil {
+				m.queueDelivery(workspaceID, state.OutboxItem{Kind: "dm", UserID: r.UserID, Text: r.Text}, err)
+				continue
+			}
+			slog.Info("sent reminder", "workspace", workspaceID, "user", r.UserID, "pr", r.PRKey)
+		}
+	}
+}
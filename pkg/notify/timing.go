@@ -0,0 +1,98 @@
+package notify
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// minTimingReviews is how many recorded reviews a user needs before smart timing
+// trusts the hours they usually review in. Until then DMs aren't held.
+const minTimingReviews = 5
+
+// responsiveHours returns the local hours of the day a user usually reviews in: those
+// holding at least their share of the user's reviews. It reports false if there are
+// too few reviews to tell.
+func responsiveHours(reviews []time.Time, loc *time.Location) ([24]bool, bool) {
+	var hours [24]bool
+	if len(reviews) < minTimingReviews {
+		return hours, false
+	}
+	var counts [24]int
+	for _, at := range reviews {
+		counts[at.In(loc).Hour()]++
+	}
+	for h, n := range counts {
+		hours[h] = n*len(counts) >= len(reviews)
+	}
+	return hours, true
+}
+
+// nextResponsive returns now if it falls in one of the responsive hours, or else the
+// start of the next one. local must be in the user's timezone.
+func nextResponsive(hours [24]bool, local time.Time) time.Time {
+	if hours[local.Hour()] {
+		return local
+	}
+	for i := 1; i < 24; i++ {
+		start := time.Date(local.Year(), local.Month(), local.Day(), local.Hour()+i, 0, 0, 0, local.Location())
+		if hours[start.Hour()] {
+			return start
+		}
+	}
+	return local
+}
+
+// urgent reports whether a PR is too pressing for smart timing to hold its DMs: its
+// thread is pinned for an urgent label, or its repo is critical.
+func (m *Manager) urgent(pr *state.PRState) bool {
+	return pr.Pinned || (m.config != nil && m.config.GetSeverity(pr.Owner, pr.Repo) == config.SeverityCritical)
+}
+
+// deferDM holds a DM about a PR until the user's next usual review hour, if they
+// turned on smart timing, the PR isn't urgent, and it's outside those hours now. The
+// DM is scheduled as a reminder that's dropped if the PR changes state first. It
+// reports whether the DM was held.
+func (m *Manager) deferDM(ctx context.Context, workspaceID, userID string, pr *state.PRState) bool {
+	prefs := m.stateManager.GetUserPreferences(workspaceID, userID)
+	if !prefs.SmartTiming || m.urgent(pr) {
+		return false
+	}
+	login, found := m.stateManager.GitHubLoginFor(workspaceID, userID)
+	if !found {
+		return false
+	}
+	loc, ok := m.userTimezone(ctx, workspaceID, userID, prefs)
+	if !ok {
+		return false
+	}
+	hours, ok := responsiveHours(m.stateManager.ReviewTimes(workspaceID, login), loc)
+	if !ok {
+		return false
+	}
+	now := m.clock.Now()
+	due := nextResponsive(hours, now.In(loc))
+	if !due.After(now) {
+		return false
+	}
+
+	if !m.stateManager.ClaimDelivery(workspaceID, state.DeliveryKey(userID, pr), state.DeliveryDeferred, now) {
+		metrics.IncCounter("slacker_notifications_deduplicated_total", "via", state.DeliveryDeferred)
+		return true
+	}
+	m.stateManager.AddReminder(workspaceID, state.Reminder{
+		Due:    due,
+		UserID: userID,
+		PRKey:  state.PRKey(pr.Owner, pr.Repo, pr.Number),
+		Text:   m.FormatNotification(pr),
+		State:  pr.State,
+	})
+	metrics.IncCounter("slacker_notifications_deferred_total")
+	slog.Info("held notification for usual review hours", "user", userID, "owner", pr.Owner, "repo", pr.Repo,
+		"number", pr.Number, "until", due)
+	return true
+}
@@ -0,0 +1,175 @@
+// Package sink forwards the bot's derived events to an external HTTP endpoint.
+package sink
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/codeGROOVE-dev/slacker/pkg/events"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+// Event types emitted to the sink.
+const (
+	// PRStateChanged is emitted when a tracked PR moves to a new state.
+	PRStateChanged = "pr_state_changed"
+	// NotificationSent is emitted when a user is sent a DM about a PR.
+	NotificationSent = "notification_sent"
+)
+
+// bufferSize is how many events may wait to be posted before new ones are dropped.
+const bufferSize = 1000
+
+// Event is the normalized JSON document posted to the sink.
+type Event struct {
+	Time          time.Time `json:"time"`
+	Type          string    `json:"type"`
+	Workspace     string    `json:"workspace,omitempty"`
+	Owner         string    `json:"owner"`
+	Repo          string    `json:"repo"`
+	State         string    `json:"state,omitempty"`
+	PreviousState string    `json:"previous_state,omitempty"`
+	UserID        string    `json:"user_id,omitempty"` // Slack user notified.
+	URL           string    `json:"url"`
+	Number        int       `json:"number"`
+}
+
+// Sink posts events to an HTTP endpoint in the background.
+// A nil *Sink discards events, so callers need not check whether one is configured.
+type Sink struct {
+	client *http.Client
+	events chan Event
+	url    string
+	secret string
+}
+
+// New creates a sink posting to url. When secret is set, each request carries an
+// X-Slacker-Signature header: "sha256=" and the hex HMAC-SHA256 of the body.
+func New(url, secret string, client *http.Client) *Sink {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Sink{
+		client: client,
+		events: make(chan Event, bufferSize),
+		url:    url,
+		secret: secret,
+	}
+}
+
+// Emit queues an event for delivery without blocking. Events are dropped if the buffer is full.
+func (s *Sink) Emit(ev Event) {
+	if s == nil {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.URL == "" {
+		ev.URL = fmt.Sprintf("https://github.com/%s/%s/pull/%d", ev.Owner, ev.Repo, ev.Number)
+	}
+	select {
+	case s.events <- ev:
+	default:
+		metrics.IncCounter("slacker_sink_events_dropped_total", "type", ev.Type)
+		slog.Warn("event sink buffer full, dropping event", "type", ev.Type)
+	}
+}
+
+// Subscribe forwards the PR state changes and sent notifications published on bus.
+func (s *Sink) Subscribe(bus *events.Bus) {
+	events.Subscribe(bus, func(_ context.Context, ev events.PRStateChanged) {
+		s.Emit(Event{
+			Time:          ev.Time,
+			Type:          PRStateChanged,
+			Workspace:     ev.Workspace,
+			Owner:         ev.PR.Owner,
+			Repo:          ev.PR.Repo,
+			Number:        ev.PR.Number,
+			State:         ev.PR.State,
+			PreviousState: ev.Previous,
+		})
+	})
+	events.Subscribe(bus, func(_ context.Context, ev events.NotificationSent) {
+		s.Emit(Event{
+			Time:      ev.Time,
+			Type:      NotificationSent,
+			Workspace: ev.Workspace,
+			Owner:     ev.Owner,
+			Repo:      ev.Repo,
+			Number:    ev.Number,
+			State:     ev.State,
+			UserID:    ev.UserID,
+		})
+	})
+}
+
+// Run posts queued events until the context is cancelled.
+func (s *Sink) Run(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case ev := <-s.events:
+			if err := s.post(ctx, ev); err != nil {
+				metrics.IncCounter("slacker_sink_events_total", "type", ev.Type, "result", "error")
+				slog.Warn("failed to post event to sink", "type", ev.Type, "error", err)
+				continue
+			}
+			metrics.IncCounter("slacker_sink_events_total", "type", ev.Type, "result", "ok")
+		}
+	}
+}
+
+// post sends one event with retry logic.
+func (s *Sink) post(ctx context.Context, ev Event) error {
+	body, err := json.Marshal(ev)
+	if err != nil {
+		return fmt.Errorf("failed to encode event: %w", err)
+	}
+
+	return retry.Do(
+		func() error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+			if err != nil {
+				return retry.Unrecoverable(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if s.secret != "" {
+				mac := hmac.New(sha256.New, []byte(s.secret))
+				mac.Write(body)
+				req.Header.Set("X-Slacker-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+			}
+
+			resp, err := s.client.Do(req)
+			if err != nil {
+				return err
+			}
+			if err := resp.Body.Close(); err != nil {
+				slog.Debug("failed to close response body", "error", err)
+			}
+			if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+				return fmt.Errorf("sink returned status %d", resp.StatusCode)
+			}
+			if resp.StatusCode >= 300 {
+				return retry.Unrecoverable(fmt.Errorf("sink returned status %d", resp.StatusCode))
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	)
+}
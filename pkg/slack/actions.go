@@ -0,0 +1,83 @@
+package slack
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/slack-go/slack"
+)
+
+// Action IDs of the buttons on PR threads.
+const (
+	ClaimReviewAction = "claim_review" // "I'll review this".
+	SubscribeAction   = "subscribe"    // "Notify me about this PR".
+	UnsubscribeAction = "unsubscribe"  // "Stop notifying me".
+)
+
+// Action is a button press on a message posted by the bot, or the submission
+// of a form opened from one.
+type Action struct {
+	Values    map[string]string // Submitted form values, by input ID.
+	Workspace string            // Workspace the message was posted in.
+	ChannelID string
+	UserID    string
+	MessageTS string // Message the button belongs to.
+	ActionID  string
+	Value     string // Option picked from an overflow menu.
+	TriggerID string // Lets the handler open a modal in response.
+}
+
+// ActionHandler responds to button presses.
+type ActionHandler interface {
+	// HandleAction returns the reply to post in the message's thread, or "" for no reply.
+	HandleAction(ctx context.Context, a Action) string
+}
+
+// SetActionHandler sets the handler for button presses.
+func (c *Client) SetActionHandler(h ActionHandler) {
+	c.actions = h
+}
+
+// runActions passes each block action in an interaction to the action handler
+// and posts its replies in the message's thread. Buttons on the help message and
+// the Home tab's settings are handled here instead.
+func (c *Client) runActions(ctx context.Context, interaction slack.InteractionCallback) {
+	for _, action := range interaction.ActionCallback.BlockActions {
+		a := Action{
+			Workspace: c.workspace,
+			ChannelID: interaction.Channel.ID,
+			UserID:    interaction.User.ID,
+			MessageTS: interaction.Message.Timestamp,
+			ActionID:  action.ActionID,
+			Value:     action.SelectedOption.Value,
+			TriggerID: interaction.TriggerID,
+		}
+		c.recordUse("slacker_slack_actions_total", a.UserID, "action", a.ActionID)
+		if c.runHelpAction(ctx, a) || c.runSettingsAction(ctx, a) || c.actions == nil {
+			continue
+		}
+		c.runAction(ctx, a)
+	}
+}
+
+// runSubmission passes a modal submission to the action handler.
+func (c *Client) runSubmission(ctx context.Context, interaction slack.InteractionCallback) {
+	if c.actions == nil || interaction.View.CallbackID != ScheduleReviewSubmission {
+		return
+	}
+	if a, ok := c.scheduleAction(interaction); ok {
+		c.recordUse("slacker_slack_actions_total", a.UserID, "action", a.ActionID)
+		c.runAction(ctx, a)
+	}
+}
+
+// runAction runs an action and posts the handler's reply in the message's thread.
+func (c *Client) runAction(ctx context.Context, a Action) {
+	reply := c.actions.HandleAction(ctx, a)
+	if reply == "" {
+		return
+	}
+	if err := c.PostThreadReply(ctx, a.ChannelID, a.MessageTS, reply); err != nil {
+		slog.Warn("failed to reply to action", "channel", a.ChannelID, "action", a.ActionID, "error", err)
+	}
+}
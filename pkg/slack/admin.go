@@ -0,0 +1,58 @@
+package slack
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// adminCheckTimeout bounds the Slack lookup of whether a user is a workspace admin.
+const adminCheckTimeout = 2 * time.Second
+
+// Maintainer performs the admin-only /r2r subcommands.
+type Maintainer interface {
+	// SyncAll re-resolves every open PR in a workspace from GitHub.
+	SyncAll(ctx context.Context, workspaceID string) string
+	// ReloadConfigs re-reads slack.yaml for the orgs routed to a workspace.
+	ReloadConfigs(ctx context.Context, workspaceID string) string
+	// ForgetUser deletes what is stored about a Slack user in a workspace.
+	ForgetUser(ctx context.Context, workspaceID, userID string) string
+	// Simulate replays an org's recent history against a candidate notification config;
+	// args are the org and the candidate's settings.
+	Simulate(ctx context.Context, workspaceID string, args []string) string
+	// PausePosting stops posting for an org, or all orgs, or lists the pauses; args name the org.
+	PausePosting(ctx context.Context, workspaceID string, args []string) string
+	// ResumePosting lifts a pause and posts a catch-up; args name the org.
+	ResumePosting(ctx context.Context, workspaceID string, args []string) string
+}
+
+// SetMaintainer sets the handler for the admin-only /r2r subcommands.
+func (c *Client) SetMaintainer(m Maintainer) {
+	c.maintainer = m
+}
+
+// SetAdmins allows the given Slack users to run admin-only subcommands, in addition
+// to the workspace's admins and owners.
+func (c *Client) SetAdmins(userIDs []string) {
+	c.admins = make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		c.admins[id] = true
+	}
+}
+
+// isAdmin reports whether a user may run admin-only subcommands: users on the
+// configured list, and the workspace's admins and owners. Lookup failures deny.
+func (c *Client) isAdmin(ctx context.Context, userID string) bool {
+	if c.admins[userID] {
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, adminCheckTimeout)
+	defer cancel()
+	user, err := c.GetUserInfo(ctx, userID)
+	if err != nil {
+		slog.Warn("failed to check whether user is an admin", "user", userID, "error", err)
+		return false
+	}
+	return user.IsAdmin || user.IsOwner || user.IsPrimaryOwner
+}
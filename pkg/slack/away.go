@@ -0,0 +1,22 @@
+package slack
+
+import "context"
+
+// AwayKeeper records when users are on vacation.
+type AwayKeeper interface {
+	// SetAway sets, clears, or describes a user's vacation from the arguments of /r2r away.
+	SetAway(ctx context.Context, workspaceID, userID string, args []string) string
+}
+
+// SetAwayKeeper sets the keeper used by /r2r away.
+func (c *Client) SetAwayKeeper(k AwayKeeper) {
+	c.away = k
+}
+
+// awayCommand handles /r2r away.
+func (c *Client) awayCommand(ctx context.Context, userID string, args []string) string {
+	if c.away == nil {
+		return "Vacations are not available."
+	}
+	return c.away.SetAway(ctx, c.workspace, userID, args)
+}
@@ -2,12 +2,57 @@ package slack
 
 import (
 	"fmt"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/codeGROOVE-dev/slacker/pkg/state"
 	"github.com/slack-go/slack"
 )
 
+const (
+	// maxSectionText is the most characters Slack accepts in a section's text.
+	maxSectionText = 3000
+	// maxMessageBlocks is the most blocks Slack accepts in a message.
+	maxMessageBlocks = 50
+)
+
+// AgeThresholds sets when a PR's age or inactivity is emphasized.
+type AgeThresholds struct {
+	Open time.Duration // Emphasize PRs open longer than this.
+	Idle time.Duration // Emphasize PRs without activity for longer than this.
+	// Scales stretches both for repos, by name, whose severity tier isn't standard.
+	Scales map[string]float64
+	// Deferral stretches both for PRs deferred by a thread reaction; zero leaves them be.
+	Deferral float64
+}
+
+// DefaultAgeThresholds are used when no org-specific thresholds apply.
+var DefaultAgeThresholds = AgeThresholds{Open: 7 * 24 * time.Hour, Idle: 3 * 24 * time.Hour}
+
+// DashboardSections are a user's PRs grouped as the dashboard shows them.
+type DashboardSections struct {
+	BlockedOnYou    []*state.PRState
+	WaitingOnOthers []*state.PRState
+	Other           []*state.PRState
+}
+
+// GroupDashboard groups PRs into dashboard sections by state.
+func GroupDashboard(prs []*state.PRState) DashboardSections {
+	var sections DashboardSections
+	for _, pr := range prs {
+		switch pr.State {
+		case "broken_heart", "carpentry_saw", "check":
+			sections.BlockedOnYou = append(sections.BlockedOnYou, pr)
+		case "hourglass":
+			sections.WaitingOnOthers = append(sections.WaitingOnOthers, pr)
+		default:
+			sections.Other = append(sections.Other, pr)
+		}
+	}
+	return sections
+}
+
 // BuildDashboardBlocks creates Slack blocks for the PR dashboard.
 func BuildDashboardBlocks(userID string, prs []*state.PRState) []slack.Block {
 	blocks := []slack.Block{
@@ -24,18 +69,9 @@ func BuildDashboardBlocks(userID string, prs []*state.PRState) []slack.Block {
 		return blocks
 	}
 
-	// Group PRs by status.
-	var blockedOnYou, waitingOnOthers, other []*state.PRState
-	for _, pr := range prs {
-		switch pr.State {
-		case "broken_heart", "carpentry_saw", "check":
-			blockedOnYou = append(blockedOnYou, pr)
-		case "hourglass":
-			waitingOnOthers = append(waitingOnOthers, pr)
-		default:
-			other = append(other, pr)
-		}
-	}
+	now := time.Now()
+	sections := GroupDashboard(prs)
+	blockedOnYou, waitingOnOthers, other := sections.BlockedOnYou, sections.WaitingOnOthers, sections.Other
 
 	// Add blocked on you section.
 	if len(blockedOnYou) > 0 {
@@ -45,7 +81,7 @@ func BuildDashboardBlocks(userID string, prs []*state.PRState) []slack.Block {
 			nil, nil,
 		))
 		for _, pr := range blockedOnYou {
-			blocks = append(blocks, createPRBlock(pr))
+			blocks = append(blocks, createPRBlock(pr, now))
 		}
 	}
 
@@ -57,7 +93,7 @@ func BuildDashboardBlocks(userID string, prs []*state.PRState) []slack.Block {
 			nil, nil,
 		))
 		for _, pr := range waitingOnOthers {
-			blocks = append(blocks, createPRBlock(pr))
+			blocks = append(blocks, createPRBlock(pr, now))
 		}
 	}
 
@@ -69,7 +105,7 @@ func BuildDashboardBlocks(userID string, prs []*state.PRState) []slack.Block {
 			nil, nil,
 		))
 		for _, pr := range other {
-			blocks = append(blocks, createPRBlock(pr))
+			blocks = append(blocks, createPRBlock(pr, now))
 		}
 	}
 
@@ -79,7 +115,7 @@ func BuildDashboardBlocks(userID string, prs []*state.PRState) []slack.Block {
 		"",
 		slack.NewTextBlockObject("mrkdwn",
 			fmt.Sprintf("Last updated: %s | <https://dash.ready-to-review.dev/?user=%s|View web dashboard>",
-				time.Now().Format("3:04 PM"), userID),
+				FormatTime(now, TimeOnly), userID),
 			false, false,
 		),
 	))
@@ -87,33 +123,12 @@ func BuildDashboardBlocks(userID string, prs []*state.PRState) []slack.Block {
 	return blocks
 }
 
-func createPRBlock(pr *state.PRState) slack.Block {
-	// Map state to emoji
-	var stateEmoji string
-	switch pr.State {
-	case "test_tube":
-		stateEmoji = "🧪"
-	case "broken_heart":
-		stateEmoji = "💔"
-	case "hourglass":
-		stateEmoji = "⏳"
-	case "carpentry_saw":
-		stateEmoji = "🪚"
-	case "check":
-		stateEmoji = "✅"
-	case "pray":
-		stateEmoji = "🙏"
-	case "face_palm":
-		stateEmoji = "🤦"
-	default:
-		stateEmoji = "❓"
-	}
-
-	prURL := fmt.Sprintf("https://github.com/%s/%s/pull/%d", pr.Owner, pr.Repo, pr.Number)
+func createPRBlock(pr *state.PRState, now time.Time) slack.Block {
+	stateEmoji := StateEmoji(pr.State)
 
 	text := fmt.Sprintf("%s <%s|%s/%s#%d>\n%s\nby @%s",
 		stateEmoji,
-		prURL,
+		prURL(pr),
 		pr.Owner,
 		pr.Repo,
 		pr.Number,
@@ -121,16 +136,246 @@ func createPRBlock(pr *state.PRState) slack.Block {
 		pr.Author,
 	)
 
+	if activity := FormatActivity(pr, now, DefaultAgeThresholds); activity != "" {
+		text += "\n" + activity
+	}
+
 	if len(pr.BlockedOn) > 0 {
 		text += fmt.Sprintf("\n_Blocked on: %v_", pr.BlockedOn)
 	}
 
+	if len(pr.ChangesRequestedBy) > 0 {
+		text += fmt.Sprintf("\n🪚 _Changes requested by %s_", formatLogins(pr.ChangesRequestedBy))
+	}
+
+	if conversations := FormatConversations(pr.UnresolvedConversations); conversations != "" {
+		text += "\n_" + conversations + "_"
+	}
+
 	return slack.NewSectionBlock(
 		slack.NewTextBlockObject("mrkdwn", text, false, false),
 		nil, nil,
 	)
 }
 
+// BuildDigestBlocks creates Slack blocks for a channel's open PR digest, dated now
+// and phrased from catalog. PRs are grouped by state and sorted oldest first, with
+// the oldest PR called out.
+func BuildDigestBlocks(channel string, prs []*state.PRState, now time.Time, thresholds AgeThresholds, catalog Catalog) []slack.Block {
+	blocks := buildOpenPRBlocks(prs, now, thresholds, catalog, maxMessageBlocks-1) // Leave room for the footer.
+	if len(prs) == 0 {
+		return blocks
+	}
+	return append(blocks, slack.NewContextBlock(
+		"",
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("Weekly digest for %s • %s", channel, FormatTime(now, LongDateTime)), false, false),
+	))
+}
+
+// BuildOpenPRBlocks lists open PRs grouped by state and sorted oldest first, with
+// the oldest PR called out, in at most maxBlocks blocks.
+func BuildOpenPRBlocks(prs []*state.PRState, now time.Time, thresholds AgeThresholds, maxBlocks int) []slack.Block {
+	return buildOpenPRBlocks(prs, now, thresholds, CatalogFor(""), maxBlocks)
+}
+
+// buildOpenPRBlocks lists open PRs as BuildOpenPRBlocks does, titled from catalog, in
+// at most maxBlocks blocks. Each state's PRs are split across as many sections as
+// their lines need; PRs that don't fit are counted in a final "…and N more".
+func buildOpenPRBlocks(prs []*state.PRState, now time.Time, thresholds AgeThresholds, catalog Catalog, maxBlocks int) []slack.Block {
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(
+			slack.NewTextBlockObject("plain_text", catalog.DigestTitle, false, false),
+		),
+	}
+
+	if len(prs) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", catalog.DigestEmpty, false, false),
+			nil, nil,
+		))
+		return blocks
+	}
+
+	sorted := make([]*state.PRState, len(prs))
+	copy(sorted, prs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+
+	oldest := sorted[0]
+	blocks = append(blocks, slack.NewSectionBlock(
+		slack.NewTextBlockObject("mrkdwn",
+			fmt.Sprintf("*%d open* • oldest: <%s|%s/%s#%d> %s, open for *%s*",
+				len(sorted), prURL(oldest), oldest.Owner, oldest.Repo, oldest.Number,
+				oldest.Title, formatAge(now.Sub(oldest.CreatedAt))),
+			false, false,
+		),
+		nil, nil,
+	))
+
+	// Group by state, keeping the order in which states first appear among the oldest PRs.
+	var order []string
+	groups := make(map[string][]*state.PRState)
+	for _, pr := range sorted {
+		if _, ok := groups[pr.State]; !ok {
+			order = append(order, pr.State)
+		}
+		groups[pr.State] = append(groups[pr.State], pr)
+	}
+
+	// Keep the last block free to count the PRs left out.
+	hidden := len(sorted)
+	room := func(n int) bool { return len(blocks)+n <= maxBlocks-1 }
+states:
+	for _, prState := range order {
+		var lines []string
+		for _, pr := range groups[prState] {
+			line := fmt.Sprintf("• <%s|%s/%s#%d> %s", prURL(pr), pr.Owner, pr.Repo, pr.Number, pr.Title)
+			if activity := FormatActivity(pr, now, thresholds); activity != "" {
+				line += " • " + activity
+			}
+			lines = append(lines, line)
+		}
+		if !room(2) {
+			break
+		}
+		blocks = append(blocks, slack.NewDividerBlock())
+		texts, counts := sectionTexts(fmt.Sprintf("%s *%d*", StateEmoji(prState), len(lines)), lines)
+		for i, text := range texts {
+			if !room(1) {
+				break states
+			}
+			blocks = append(blocks, slack.NewSectionBlock(slack.NewTextBlockObject("mrkdwn", text, false, false), nil, nil))
+			hidden -= counts[i]
+		}
+	}
+	if hidden > 0 {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("…and %d more.", hidden), false, false),
+			nil, nil,
+		))
+	}
+	return blocks
+}
+
+// sectionTexts splits lines following a heading into texts short enough for a
+// section each, returning how many of the lines each text holds.
+func sectionTexts(heading string, lines []string) ([]string, []int) {
+	var texts []string
+	var counts []int
+	text, count := heading, 0
+	for _, line := range lines {
+		if count > 0 && len(text)+len("\n")+len(line) > maxSectionText {
+			texts = append(texts, text)
+			counts = append(counts, count)
+			text, count = line, 1
+			continue
+		}
+		text += "\n" + line
+		count++
+	}
+	return append(texts, text), append(counts, count)
+}
+
+// formatLogins formats GitHub logins as a comma-separated list of @mentions.
+func formatLogins(logins []string) string {
+	mentions := make([]string, len(logins))
+	for i, login := range logins {
+		mentions[i] = "@" + login
+	}
+	return strings.Join(mentions, ", ")
+}
+
+// prURL returns the GitHub URL for a PR.
+func prURL(pr *state.PRState) string {
+	return fmt.Sprintf("https://github.com/%s/%s/pull/%d", pr.Owner, pr.Repo, pr.Number)
+}
+
+// FormatConversations describes a PR's unresolved review conversations, such as
+// "💬 3 unresolved conversations", or returns "" when there are none.
+func FormatConversations(n int) string {
+	switch n {
+	case 0:
+		return ""
+	case 1:
+		return "💬 1 unresolved conversation"
+	default:
+		return fmt.Sprintf("💬 %d unresolved conversations", n)
+	}
+}
+
+// largePRFactor stretches the thresholds for large PRs, which take longer to review.
+const largePRFactor = 2
+
+// FormatActivity describes how long a PR has been open, in its current state, and
+// idle, such as "open for 6d • in review for 2d 4h • last activity 3d ago", bolding
+// any part past its threshold.
+func FormatActivity(pr *state.PRState, now time.Time, thresholds AgeThresholds) string {
+	if pr.Large {
+		thresholds.Open *= largePRFactor
+		thresholds.Idle *= largePRFactor
+	}
+	if f, ok := thresholds.Scales[pr.Repo]; ok {
+		thresholds.Open = time.Duration(float64(thresholds.Open) * f)
+		thresholds.Idle = time.Duration(float64(thresholds.Idle) * f)
+	}
+	deferred := thresholds.Deferral > 0 && pr.DeferralActive(now)
+	if deferred {
+		thresholds.Open = time.Duration(float64(thresholds.Open) * thresholds.Deferral)
+		thresholds.Idle = time.Duration(float64(thresholds.Idle) * thresholds.Deferral)
+	}
+	var parts []string
+	if !pr.CreatedAt.IsZero() {
+		age := now.Sub(pr.CreatedAt)
+		part := "open for " + formatAge(age)
+		if thresholds.Open > 0 && age > thresholds.Open {
+			part = "*" + part + "*"
+		}
+		parts = append(parts, part)
+	}
+	if since := styleFor(pr.State).since; since != "" {
+		if d := pr.TimeInState(now); d > 0 {
+			parts = append(parts, since+" for "+formatDuration(d))
+		}
+	}
+	if !pr.UpdatedAt.IsZero() {
+		idle := now.Sub(pr.UpdatedAt)
+		part := "last activity " + formatAge(idle) + " ago"
+		if thresholds.Idle > 0 && idle > thresholds.Idle {
+			part = "*" + part + "* 🐢"
+		}
+		parts = append(parts, part)
+	}
+	if deferred {
+		parts = append(parts, "📅 deferred")
+	}
+	return strings.Join(parts, " • ")
+}
+
+// formatAge formats a duration as a compact age such as "3d" or "5h".
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
+// formatDuration formats a duration to two units, such as "2d 4h" or "3h 10m".
+func formatDuration(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd %dh", int(d.Hours()/24), int(d.Hours())%24)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
+
 // BuildSettingsBlocks creates Slack blocks for user settings.
 func BuildSettingsBlocks(prefs state.UserPreferences) []slack.Block {
 	blocks := []slack.Block{
@@ -149,8 +394,8 @@ func BuildSettingsBlocks(prefs state.UserPreferences) []slack.Block {
 		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Real-time notifications:* %s", realtimeText), false, false),
 		nil,
 		slack.NewAccessory(slack.NewButtonBlockElement(
-			"toggle_realtime",
-			"toggle_realtime",
+			ToggleRealtimeAction,
+			ToggleRealtimeAction,
 			slack.NewTextBlockObject("plain_text", "Toggle", false, false),
 		)),
 	))
@@ -161,7 +406,7 @@ func BuildSettingsBlocks(prefs state.UserPreferences) []slack.Block {
 		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Notification delay after channel post:* %s", delayText), false, false),
 		nil,
 		slack.NewAccessory(slack.NewOverflowBlockElement(
-			"change_delay",
+			ChangeDelayAction,
 			slack.NewOptionBlockObject("15", slack.NewTextBlockObject("plain_text", "15 minutes", false, false), nil),
 			slack.NewOptionBlockObject("30", slack.NewTextBlockObject("plain_text", "30 minutes", false, false), nil),
 			slack.NewOptionBlockObject("60", slack.NewTextBlockObject("plain_text", "1 hour", false, false), nil),
@@ -169,6 +414,21 @@ func BuildSettingsBlocks(prefs state.UserPreferences) []slack.Block {
 		)),
 	))
 
+	// Smart timing toggle.
+	smartText := "🔕 Disabled"
+	if prefs.SmartTiming {
+		smartText = "🕘 Enabled (non-urgent DMs wait for the hours you usually review in)"
+	}
+	blocks = append(blocks, slack.NewSectionBlock(
+		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Smart timing:* %s", smartText), false, false),
+		nil,
+		slack.NewAccessory(slack.NewButtonBlockElement(
+			ToggleSmartTimingAction,
+			ToggleSmartTimingAction,
+			slack.NewTextBlockObject("plain_text", "Toggle", false, false),
+		)),
+	))
+
 	// Daily reminders toggle.
 	dailyText := "🔕 Disabled"
 	if prefs.DailyReminders {
@@ -178,8 +438,8 @@ func BuildSettingsBlocks(prefs state.UserPreferences) []slack.Block {
 		slack.NewTextBlockObject("mrkdwn", fmt.Sprintf("*Daily reminders:* %s", dailyText), false, false),
 		nil,
 		slack.NewAccessory(slack.NewButtonBlockElement(
-			"toggle_daily",
-			"toggle_daily",
+			ToggleDailyAction,
+			ToggleDailyAction,
 			slack.NewTextBlockObject("plain_text", "Toggle", false, false),
 		)),
 	))
@@ -0,0 +1,76 @@
+package slack
+
+import "fmt"
+
+// Catalog holds the phrasing of the bot's nudges and digests in one tone.
+type Catalog struct {
+	// What a PR is waiting on a user for, following "owner/repo#1 by @author - ".
+	Review          string
+	FixTests        string
+	AddressFeedback string
+	Merge           string
+	MergeFrozen     string // Approved while a freeze is in effect.
+	Attention       string // Any other state.
+
+	dailyOne  string
+	dailyMany string // Formatted with the number of PRs.
+
+	DigestTitle string
+	DigestEmpty string
+}
+
+// DailyReminder introduces the list of the n PRs in a daily reminder.
+func (c Catalog) DailyReminder(n int) string {
+	if n == 1 {
+		return c.dailyOne
+	}
+	return fmt.Sprintf(c.dailyMany, n)
+}
+
+// catalogs holds each tone's phrasing, by the tone's name in slack.yaml.
+var catalogs = map[string]Catalog{
+	"neutral": {
+		Review:          "waiting for your review",
+		FixTests:        "waiting for you to fix tests",
+		AddressFeedback: "waiting for you to address review feedback",
+		Merge:           "approved and ready to merge",
+		MergeFrozen:     "approved, but 🧊 freeze in effect; hold the merge until it lifts",
+		Attention:       "needs your attention",
+		dailyOne:        ":sunrise: Good morning! 1 PR is waiting on you:",
+		dailyMany:       ":sunrise: Good morning! %d PRs are waiting on you:",
+		DigestTitle:     "Open pull requests",
+		DigestEmpty:     "_Nothing open. Enjoy the quiet._",
+	},
+	"friendly": {
+		Review:          "could use your review when you have a moment",
+		FixTests:        "has a few failing tests that could use a look",
+		AddressFeedback: "has review feedback ready for you",
+		Merge:           "is approved and ready to merge 🎉",
+		MergeFrozen:     "is approved 🎉 but a 🧊 freeze is in effect, so hold the merge until it lifts",
+		Attention:       "could use a look from you",
+		dailyOne:        ":sunrise: Morning! Here's a PR that could use you today:",
+		dailyMany:       ":sunrise: Morning! Here are %d PRs that could use you today:",
+		DigestTitle:     "This week's open pull requests",
+		DigestEmpty:     "_Nothing open. Enjoy the quiet!_ 🌿",
+	},
+	"terse": {
+		Review:          "review",
+		FixTests:        "fix tests",
+		AddressFeedback: "address feedback",
+		Merge:           "merge",
+		MergeFrozen:     "approved; 🧊 freeze, don't merge",
+		Attention:       "needs attention",
+		dailyOne:        "1 PR waiting on you:",
+		dailyMany:       "%d PRs waiting on you:",
+		DigestTitle:     "Open PRs",
+		DigestEmpty:     "_None open._",
+	},
+}
+
+// CatalogFor returns the phrasing for a tone from slack.yaml, falling back to neutral.
+func CatalogFor(tone string) Catalog {
+	if c, exists := catalogs[tone]; exists {
+		return c
+	}
+	return catalogs["neutral"]
+}
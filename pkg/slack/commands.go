@@ -0,0 +1,191 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/command"
+	"github.com/slack-go/slack"
+)
+
+// slashCommand is a /r2r subcommand.
+type slashCommand = command.Command[slack.SlashCommand, commandResponse]
+
+// slashCommands builds the /r2r subcommands, in the order help lists them.
+func (c *Client) slashCommands() *command.Registry[slack.SlashCommand, commandResponse] {
+	text := func(run func(ctx context.Context, cmd slack.SlashCommand, in *command.Input) string) func(context.Context, slack.SlashCommand, *command.Input) commandResponse {
+		return func(ctx context.Context, cmd slack.SlashCommand, in *command.Input) commandResponse {
+			return textResponse(run(ctx, cmd, in))
+		}
+	}
+
+	r := command.NewRegistry[slack.SlashCommand](textResponse)
+	r.SetRepos(func(ctx context.Context, cmd slack.SlashCommand) []string {
+		if c.help == nil {
+			return nil
+		}
+		ctx, cancel := context.WithTimeout(ctx, helpTimeout)
+		defer cancel()
+		return c.help.ChannelRepos(ctx, c.workspace, cmd.ChannelID)
+	})
+	r.Register(
+		slashCommand{
+			Name:    "dashboard",
+			Summary: "View your PR dashboard",
+			Run: text(func(_ context.Context, cmd slack.SlashCommand, _ *command.Input) string {
+				// Note: In a full implementation, we'd send blocks here instead of plain text.
+				// For now, return a link to the web dashboard.
+				return fmt.Sprintf("View your dashboard at: https://dash.ready-to-review.dev/?user=%s\n"+
+					"Or use the Home tab in this app for the native Slack experience.", cmd.UserID)
+			}),
+		},
+		slashCommand{
+			Name:    "list",
+			Summary: "List the open PRs tracked for this channel, by state",
+			Run: func(ctx context.Context, cmd slack.SlashCommand, _ *command.Input) commandResponse {
+				return c.listCommand(ctx, cmd.ChannelID)
+			},
+		},
+		slashCommand{
+			Name:    "settings",
+			Summary: "Configure notification preferences",
+			Run: text(func(context.Context, slack.SlashCommand, *command.Input) string {
+				return "Open the Home tab in this app to configure your notification preferences."
+			}),
+		},
+		slashCommand{
+			Name:    "away",
+			Summary: "Pause your notifications while you're away, or return early with off",
+			Usage:   "[2024-07-01..2024-07-14|off]",
+			Args:    []command.Arg{{Name: "dates", Optional: true}},
+			Run: text(func(ctx context.Context, cmd slack.SlashCommand, in *command.Input) string {
+				return c.awayCommand(ctx, cmd.UserID, in.Words)
+			}),
+		},
+		slashCommand{
+			Name:    "config lint",
+			Summary: "Check an org's slack.yaml against the config schema",
+			Args:    []command.Arg{{Name: "github-org"}},
+			Run: text(func(ctx context.Context, _ slack.SlashCommand, in *command.Input) string {
+				return c.configCommand(ctx, in.Arg("github-org"))
+			}),
+		},
+		slashCommand{
+			Name:    "preview",
+			Summary: "Show the thread message and DM a PR would get, without sending them",
+			Args:    []command.Arg{{Name: "pr", Kind: command.PR}},
+			Run: text(func(ctx context.Context, _ slack.SlashCommand, in *command.Input) string {
+				ref, _ := in.PR("pr")
+				return c.previewCommand(ctx, ref.String())
+			}),
+		},
+		slashCommand{
+			Name:    "test-dm",
+			Summary: "Send yourself a sample notification and see which checks it passes",
+			Run: text(func(ctx context.Context, cmd slack.SlashCommand, _ *command.Input) string {
+				return c.testDMCommand(ctx, cmd.UserID)
+			}),
+		},
+		slashCommand{
+			Name:    "leaderboard",
+			Summary: "Show the org's reviewers over the last 30 days",
+			Args:    []command.Arg{{Name: "github-org"}},
+			Run: func(ctx context.Context, _ slack.SlashCommand, in *command.Input) commandResponse {
+				return c.leaderboardCommand(ctx, in.Arg("github-org"))
+			},
+		},
+		slashCommand{
+			Name:    "help",
+			Summary: "Show what the bot can do, or how to use one subcommand",
+			Args:    []command.Arg{{Name: "subcommand", Kind: command.Rest, Optional: true}},
+			Run: func(ctx context.Context, cmd slack.SlashCommand, in *command.Input) commandResponse {
+				if len(in.Words) == 0 {
+					return c.helpCommand(ctx, cmd.Command, cmd.ChannelID)
+				}
+				if help, ok := c.slash.CommandHelp(cmd.Command+" ", in.Words); ok {
+					return textResponse(help)
+				}
+				return textResponse(fmt.Sprintf("There's no `%s %s` subcommand. Try: %s help", cmd.Command, in.Arg("subcommand"), cmd.Command))
+			},
+		},
+		slashCommand{
+			Name:    "sync all",
+			Summary: "Re-fetch every open PR's state from GitHub (admins only)",
+			Run: c.admin(func(ctx context.Context, _ slack.SlashCommand, _ *command.Input) string {
+				return c.maintainer.SyncAll(ctx, c.workspace)
+			}),
+		},
+		slashCommand{
+			Name:    "config reload",
+			Summary: "Re-read slack.yaml for this workspace's orgs (admins only)",
+			Run: c.admin(func(ctx context.Context, _ slack.SlashCommand, _ *command.Input) string {
+				return c.maintainer.ReloadConfigs(ctx, c.workspace)
+			}),
+		},
+		slashCommand{
+			Name:    "forget-user",
+			Summary: "Delete everything stored about a user (admins only)",
+			Args:    []command.Arg{{Name: "user", Kind: command.User}},
+			Run: c.admin(func(ctx context.Context, _ slack.SlashCommand, in *command.Input) string {
+				return c.maintainer.ForgetUser(ctx, c.workspace, in.User("user"))
+			}),
+		},
+		slashCommand{
+			Name:    "pause",
+			Summary: "Stop posting for an org during an incident or migration, or list the pauses (admins only)",
+			Args:    []command.Arg{{Name: "org|all", Optional: true}},
+			Run: c.admin(func(ctx context.Context, _ slack.SlashCommand, in *command.Input) string {
+				return c.maintainer.PausePosting(ctx, c.workspace, in.Words)
+			}),
+		},
+		slashCommand{
+			Name:    "resume",
+			Summary: "Resume posting for an org and catch up on what happened meanwhile (admins only)",
+			Args:    []command.Arg{{Name: "org|all"}},
+			Run: c.admin(func(ctx context.Context, _ slack.SlashCommand, in *command.Input) string {
+				return c.maintainer.ResumePosting(ctx, c.workspace, in.Words)
+			}),
+		},
+		slashCommand{
+			Name:    "simulate",
+			Summary: "Count the DMs and mentions a candidate config would have sent (admins only)",
+			Args:    []command.Arg{{Name: "org"}, {Name: "days", Optional: true}},
+			Flags:   []command.Flag{{Name: "delay", Example: "1h"}, {Name: "digest", Example: "monday@09:00|off"}},
+			Run: c.admin(func(ctx context.Context, _ slack.SlashCommand, in *command.Input) string {
+				return c.maintainer.Simulate(ctx, c.workspace, in.Words)
+			}),
+		},
+	)
+	return r
+}
+
+// handleR2RCommand handles the /r2r slash command, under whichever name it was invoked.
+func (c *Client) handleR2RCommand(ctx context.Context, cmd slack.SlashCommand) commandResponse {
+	name := cmd.Command
+	words := command.Fields(cmd.Text)
+	c.recordUse("slacker_slack_commands_total", cmd.UserID, "subcommand", c.countedSubcommand(words))
+	if len(words) == 0 {
+		return textResponse("Usage: " + name + " [" + strings.Join(c.slash.Names(), "|") + "]")
+	}
+	if response, ok := c.slash.Run(ctx, cmd, name+" ", cmd.Text); ok {
+		return response
+	}
+	return textResponse("Unknown subcommand. Try: " + name + " help")
+}
+
+// admin limits a subcommand to workspace admins and the bot's configured admins.
+func (c *Client) admin(run func(ctx context.Context, cmd slack.SlashCommand, in *command.Input) string) func(context.Context, slack.SlashCommand, *command.Input) commandResponse {
+	return func(ctx context.Context, cmd slack.SlashCommand, in *command.Input) commandResponse {
+		if c.maintainer == nil {
+			return textResponse("Admin commands are not available.")
+		}
+		if !c.isAdmin(ctx, cmd.UserID) {
+			slog.Warn("denied admin slash command", "workspace", c.workspace, "user", cmd.UserID, "subcommand", in.Name)
+			return textResponse("⛔ `" + cmd.Command + " " + in.Name + "` is limited to workspace admins and the bot's configured admins.")
+		}
+		slog.Info("running admin slash command", "workspace", c.workspace, "user", cmd.UserID, "subcommand", in.Name)
+		return textResponse(run(ctx, cmd, in))
+	}
+}
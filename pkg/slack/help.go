@@ -0,0 +1,154 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// HelpDashboardAction is the action ID of the help message's "Open dashboard" button.
+	HelpDashboardAction = "help_dashboard"
+	// HelpTestDMAction is the action ID of the help message's "Send test DM" button.
+	HelpTestDMAction = "help_test_dm"
+)
+
+// helpTimeout keeps the help lookups within Slack's deadline for answering a slash command.
+const helpTimeout = 2 * time.Second
+
+// maxModalBlocks is the most blocks Slack accepts in a modal.
+const maxModalBlocks = 100
+
+// HelpSource provides the per-channel and per-user details behind /r2r help.
+type HelpSource interface {
+	// ChannelRepos returns the repos, as owner/repo, whose PRs are posted to a channel.
+	ChannelRepos(ctx context.Context, workspaceID, channelID string) []string
+	// UserPRs returns the PRs on a user's dashboard.
+	UserPRs(ctx context.Context, workspaceID, userID string) []*state.PRState
+}
+
+// SetHelpSource sets the source of the channel hints and dashboard shown from /r2r help.
+func (c *Client) SetHelpSource(s HelpSource) {
+	c.help = s
+}
+
+// helpCommand handles /r2r help, with plain text as the notification fallback.
+func (c *Client) helpCommand(ctx context.Context, name, channelID string) commandResponse {
+	var repos []string
+	if c.help != nil {
+		ctx, cancel := context.WithTimeout(ctx, helpTimeout)
+		defer cancel()
+		repos = c.help.ChannelRepos(ctx, c.workspace, channelID)
+	}
+	return commandResponse{
+		Text:   "Ready to Review helps you stay on top of pull requests. Try: " + name + " dashboard",
+		Blocks: BuildHelpBlocks(name, repos),
+	}
+}
+
+// BuildHelpBlocks creates the /r2r help message: a section per capability, buttons
+// to try the dashboard and a test DM, and which repos post to the current channel.
+func BuildHelpBlocks(name string, channelRepos []string) []slack.Block {
+	mrkdwn := func(s string) *slack.TextBlockObject {
+		return slack.NewTextBlockObject(slack.MarkdownType, s, false, false)
+	}
+	section := func(title string, lines ...string) slack.Block {
+		for i, line := range lines {
+			lines[i] = "• " + strings.ReplaceAll(line, "/r2r", name)
+		}
+		return slack.NewSectionBlock(mrkdwn("*"+title+"*\n"+strings.Join(lines, "\n")), nil, nil)
+	}
+
+	hint := "This channel doesn't receive PRs. Add it to a repo's `channels` in slack.yaml to post them here."
+	if len(channelRepos) > 0 {
+		hint = "📌 This channel gets PRs from " + strings.Join(channelRepos, ", ") + "."
+	}
+
+	dashboard := slack.NewButtonBlockElement(HelpDashboardAction, "dashboard",
+		slack.NewTextBlockObject(slack.PlainTextType, "📋 Open dashboard", true, false))
+	testDM := slack.NewButtonBlockElement(HelpTestDMAction, "test_dm",
+		slack.NewTextBlockObject(slack.PlainTextType, "📨 Send test DM", true, false))
+
+	return []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject(slack.PlainTextType, "Ready to Review", false, false)),
+		slack.NewSectionBlock(mrkdwn("Ready to Review helps you stay on top of pull requests."), nil, nil),
+		slack.NewContextBlock("", mrkdwn(hint)),
+		slack.NewDividerBlock(),
+		section("Your PRs",
+			"`/r2r dashboard` - View your PR dashboard",
+			"`/r2r list` - List the open PRs tracked for this channel, by state",
+			"`/r2r settings` - Configure notification preferences",
+			"`/r2r away 2024-07-01..2024-07-14` - Pause your notifications while you're away; `/r2r away off` to return early",
+			"`/r2r test-dm` - Send yourself a sample notification and see which checks it passes"),
+		slack.NewActionBlock("", dashboard, testDM),
+		section("Org config",
+			"`/r2r config lint <org>` - Check an org's slack.yaml against the config schema",
+			"`/r2r preview <owner/repo#123>` - Show the thread message and DM a PR would get, without sending them"),
+		section("Reviewer stats",
+			"`/r2r leaderboard <org>` - Show the org's reviewers over the last 30 days"),
+		section("Admins only",
+			"`/r2r sync all` - Re-fetch every open PR's state from GitHub",
+			"`/r2r config reload` - Re-read slack.yaml for this workspace's orgs",
+			"`/r2r forget-user @user` - Delete everything stored about a user",
+			"`/r2r pause <org|all>` / `/r2r resume <org|all>` - Stop posting during an incident or migration, then catch up",
+			"`/r2r simulate <org> [days] [delay=1h] [digest=monday@09:00|off]` - Count the DMs and mentions a candidate config would have sent"),
+		slack.NewContextBlock("", mrkdwn("You can also visit the Home tab in this app for a full dashboard.")),
+	}
+}
+
+// runHelpAction handles a button on the help message, reporting whether the action was one.
+func (c *Client) runHelpAction(ctx context.Context, a Action) bool {
+	switch a.ActionID {
+	case HelpDashboardAction:
+		if err := c.openDashboardModal(ctx, a.TriggerID, a.UserID); err != nil {
+			slog.Warn("failed to open dashboard from help", "user", a.UserID, "error", err)
+		}
+	case HelpTestDMAction:
+		text := "Test notifications are not available."
+		if c.tester != nil {
+			text = c.tester.TestNotification(ctx, c.workspace, a.UserID)
+		}
+		if err := c.PostEphemeral(ctx, a.ChannelID, a.UserID, text); err != nil {
+			slog.Warn("failed to report test DM from help", "user", a.UserID, "error", err)
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+// openDashboardModal shows a user's PR dashboard in a modal.
+func (c *Client) openDashboardModal(ctx context.Context, triggerID, userID string) error {
+	var prs []*state.PRState
+	if c.help != nil {
+		prs = c.help.UserPRs(ctx, c.workspace, userID)
+	}
+	blocks := BuildDashboardBlocks(userID, prs)
+	if len(blocks) > maxModalBlocks {
+		blocks = blocks[:maxModalBlocks]
+	}
+
+	view := slack.ModalViewRequest{
+		Type:   slack.VTModal,
+		Title:  slack.NewTextBlockObject(slack.PlainTextType, "Your dashboard", false, false),
+		Close:  slack.NewTextBlockObject(slack.PlainTextType, "Close", false, false),
+		Blocks: slack.Blocks{BlockSet: blocks},
+	}
+	if _, err := c.api.OpenViewContext(ctx, triggerID, view); err != nil {
+		return fmt.Errorf("failed to open dashboard modal: %w", err)
+	}
+	return nil
+}
+
+// PostEphemeral posts a message in a channel that only one user can see.
+func (c *Client) PostEphemeral(ctx context.Context, channelID, userID, text string) error {
+	if _, err := c.api.PostEphemeralContext(ctx, channelID, userID, slack.MsgOptionText(text, false)); err != nil {
+		return fmt.Errorf("failed to post ephemeral message: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/slack-go/slack"
+)
+
+const (
+	// maxHomeBlocks is the most blocks Slack accepts in a Home tab.
+	maxHomeBlocks = 100
+	// homeRefreshDelay is how long a refresh of a user's Home tab waits, so a burst of
+	// changes to their PRs publishes the view once.
+	homeRefreshDelay = 5 * time.Second
+	// homeTimeout bounds building and publishing one Home tab.
+	homeTimeout = 10 * time.Second
+)
+
+// The app home shows each user their PR dashboard.
+var _ = requires(Feature{
+	Name:        "app-home",
+	Description: "Show a PR dashboard in the Home tab",
+	Events:      []string{"app_home_opened"},
+})
+
+// homeViews tracks which users have opened the Home tab, so only their views are
+// refreshed as PRs change, and which refreshes are waiting to run.
+type homeViews struct {
+	viewers map[string]bool
+	pending map[string]bool
+	mu      sync.Mutex
+}
+
+// opened records that a user opened the Home tab.
+func (h *homeViews) opened(userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.viewers == nil {
+		h.viewers = make(map[string]bool)
+	}
+	h.viewers[userID] = true
+}
+
+// schedule reports whether a refresh should be scheduled for a user: they have
+// opened the Home tab and no refresh is already waiting.
+func (h *homeViews) schedule(userID string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if !h.viewers[userID] || h.pending[userID] {
+		return false
+	}
+	if h.pending == nil {
+		h.pending = make(map[string]bool)
+	}
+	h.pending[userID] = true
+	return true
+}
+
+// done clears a user's waiting refresh.
+func (h *homeViews) done(userID string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.pending, userID)
+}
+
+// RefreshHome republishes a user's Home tab shortly, if they have opened it since
+// the bot started. The coordinator calls it when one of the user's PRs changes.
+func (c *Client) RefreshHome(userID string) {
+	if !c.home.schedule(userID) {
+		return
+	}
+	time.AfterFunc(homeRefreshDelay, func() {
+		c.home.done(userID)
+		ctx, cancel := context.WithTimeout(context.Background(), homeTimeout)
+		defer cancel()
+		if err := c.updateAppHome(ctx, userID); err != nil {
+			slog.Warn("failed to refresh app home", "workspace", c.workspace, "user", userID, "error", err)
+			return
+		}
+		metrics.IncCounter("slacker_home_refreshes_total")
+	})
+}
+
+// openedAppHome publishes a user's Home tab as they open it.
+func (c *Client) openedAppHome(ctx context.Context, userID string) {
+	c.home.opened(userID)
+	ctx, cancel := context.WithTimeout(ctx, homeTimeout)
+	defer cancel()
+	if err := c.updateAppHome(ctx, userID); err != nil {
+		slog.Warn("failed to update app home", "workspace", c.workspace, "user", userID, "error", err)
+	}
+}
+
+// updateAppHome publishes a user's PR dashboard to their Home tab, followed by
+// their notification settings.
+func (c *Client) updateAppHome(ctx context.Context, userID string) error {
+	var prs []*state.PRState
+	if c.help != nil {
+		prs = c.help.UserPRs(ctx, c.workspace, userID)
+	}
+	var settings []slack.Block
+	if c.settings != nil {
+		settings = BuildSettingsBlocks(c.settings.GetUserPreferences(c.workspace, userID))
+	}
+	// A long dashboard is cut short rather than the settings after it.
+	blocks := BuildDashboardBlocks(userID, prs)
+	if len(blocks) > maxHomeBlocks-len(settings) {
+		blocks = blocks[:maxHomeBlocks-len(settings)]
+	}
+	blocks = append(blocks, settings...)
+	if err := c.PublishHomeView(ctx, userID, blocks); err != nil {
+		return err
+	}
+	slog.Debug("updated app home", "workspace", c.workspace, "user", userID, "prs", len(prs))
+	return nil
+}
+
+// PublishHomeView publishes a view to a user's app home.
+func (c *Client) PublishHomeView(ctx context.Context, userID string, blocks []slack.Block) error {
+	view := slack.HomeTabViewRequest{
+		Type:   "home",
+		Blocks: slack.Blocks{BlockSet: blocks},
+	}
+
+	if _, err := c.api.PublishViewContext(ctx, userID, view, ""); err != nil {
+		return fmt.Errorf("failed to publish home view: %w", err)
+	}
+	return nil
+}
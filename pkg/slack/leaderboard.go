@@ -0,0 +1,96 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// LeaderboardWindow is the period reviewer stats cover.
+const LeaderboardWindow = 30 * 24 * time.Hour
+
+// ReviewerStat summarizes one reviewer's activity over LeaderboardWindow.
+type ReviewerStat struct {
+	Login          string
+	Reviews        int
+	MedianResponse time.Duration // Zero if no response times are known.
+}
+
+// LeaderboardSource provides reviewer stats for /r2r leaderboard.
+type LeaderboardSource interface {
+	// Leaderboard returns an org's reviewer stats in display order, and whether they
+	// are ranked. enabled is false if the org has not opted in.
+	Leaderboard(ctx context.Context, workspaceID, org string) (stats []ReviewerStat, ranked, enabled bool)
+}
+
+// SetLeaderboardSource sets the source of reviewer stats for /r2r leaderboard.
+func (c *Client) SetLeaderboardSource(s LeaderboardSource) {
+	c.leaderboard = s
+}
+
+// leaderboardCommand handles /r2r leaderboard.
+func (c *Client) leaderboardCommand(ctx context.Context, org string) commandResponse {
+	if c.leaderboard == nil {
+		return textResponse("The leaderboard is not available.")
+	}
+
+	stats, ranked, enabled := c.leaderboard.Leaderboard(ctx, c.workspace, org)
+	if !enabled {
+		return textResponse(fmt.Sprintf("The leaderboard isn't enabled for %s. Set `leaderboard.enabled` in its slack.yaml to turn it on.", org))
+	}
+	return commandResponse{
+		Text:   fmt.Sprintf("Reviewers in %s over the last 30 days", org),
+		Blocks: BuildLeaderboardBlocks(org, stats, ranked),
+	}
+}
+
+// leaderboardSize is the number of reviewers shown on the leaderboard.
+const leaderboardSize = 10
+
+// medals decorate the top three ranked reviewers.
+var medals = []string{"🥇", "🥈", "🥉"}
+
+// BuildLeaderboardBlocks creates Slack blocks listing an org's reviewers. Ranked
+// boards number the top reviewers; plain boards just list them.
+func BuildLeaderboardBlocks(org string, stats []ReviewerStat, ranked bool) []slack.Block {
+	title := "Review activity in " + org
+	if ranked {
+		title = "🏆 Review leaderboard for " + org
+	}
+	blocks := []slack.Block{
+		slack.NewHeaderBlock(slack.NewTextBlockObject("plain_text", title, false, false)),
+		slack.NewContextBlock("", slack.NewTextBlockObject("mrkdwn", "Reviews submitted in the last 30 days", false, false)),
+	}
+
+	if len(stats) == 0 {
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", "_No reviews yet._", false, false),
+			nil, nil,
+		))
+		return blocks
+	}
+
+	if len(stats) > leaderboardSize {
+		stats = stats[:leaderboardSize]
+	}
+	for i, stat := range stats {
+		line := fmt.Sprintf("*%s* • %d reviews", stat.Login, stat.Reviews)
+		if stat.MedianResponse > 0 {
+			line += " • median response " + formatDuration(stat.MedianResponse)
+		}
+		if ranked {
+			rank := fmt.Sprintf("%d.", i+1)
+			if i < len(medals) {
+				rank = medals[i]
+			}
+			line = rank + " " + line
+		}
+		blocks = append(blocks, slack.NewSectionBlock(
+			slack.NewTextBlockObject("mrkdwn", line, false, false),
+			nil, nil,
+		))
+	}
+	return blocks
+}
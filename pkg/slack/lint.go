@@ -0,0 +1,51 @@
+package slack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// lintTimeout keeps config lint within Slack's deadline for answering a slash command.
+const lintTimeout = 2500 * time.Millisecond
+
+// ErrNotRouted is returned by a ConfigLinter for an org whose PRs aren't posted to
+// the workspace asking.
+var ErrNotRouted = errors.New("org isn't routed to this workspace")
+
+// ConfigLinter checks an org's slack.yaml.
+type ConfigLinter interface {
+	// LintConfig returns every problem found in the org's config, or nil if it is
+	// valid, or ErrNotRouted if the org isn't routed to the workspace.
+	LintConfig(ctx context.Context, workspaceID, org string) error
+}
+
+// SetConfigLinter sets the linter used by /r2r config lint.
+func (c *Client) SetConfigLinter(l ConfigLinter) {
+	c.linter = l
+}
+
+// configCommand handles /r2r config lint.
+func (c *Client) configCommand(ctx context.Context, org string) string {
+	if c.linter == nil {
+		return "Config linting is not available."
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, lintTimeout)
+	defer cancel()
+
+	err := c.linter.LintConfig(ctx, c.workspace, org)
+	if errors.Is(err, ErrNotRouted) {
+		return fmt.Sprintf("%s isn't routed to this workspace.", org)
+	}
+	if err != nil {
+		lines := strings.Split(err.Error(), "\n")
+		for i, line := range lines {
+			lines[i] = "• " + line
+		}
+		return fmt.Sprintf("❌ Problems found in %s's slack.yaml:\n%s", org, strings.Join(lines, "\n"))
+	}
+	return fmt.Sprintf("✅ %s's slack.yaml is valid.", org)
+}
@@ -0,0 +1,42 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/slack-go/slack"
+)
+
+// listTimeout keeps a channel listing within Slack's deadline for answering a slash command.
+const listTimeout = 2500 * time.Millisecond
+
+// ChannelLister finds the PRs tracked for a channel.
+type ChannelLister interface {
+	// ChannelPRs returns the open PRs tracked for a channel in a workspace.
+	ChannelPRs(ctx context.Context, workspaceID, channelID string) []*state.PRState
+}
+
+// SetChannelLister sets the lister used by /r2r list.
+func (c *Client) SetChannelLister(l ChannelLister) {
+	c.lister = l
+}
+
+// listCommand handles /r2r list, showing the channel's open PRs grouped by state.
+func (c *Client) listCommand(ctx context.Context, channelID string) commandResponse {
+	if c.lister == nil {
+		return textResponse("Listing PRs is not available.")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, listTimeout)
+	defer cancel()
+	prs := c.lister.ChannelPRs(ctx, c.workspace, channelID)
+	blocks := BuildOpenPRBlocks(prs, time.Now(), DefaultAgeThresholds, maxMessageBlocks-1) // Leave room for the footer.
+	blocks = append(blocks, slack.NewContextBlock("",
+		slack.NewTextBlockObject(slack.MarkdownType, fmt.Sprintf("PRs tracked for <#%s>, visible only to you", channelID), false, false)))
+	return commandResponse{
+		Text:   fmt.Sprintf("%d open pull requests", len(prs)),
+		Blocks: blocks,
+	}
+}
@@ -0,0 +1,177 @@
+package slack
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Feature is a capability of the bot and the Slack scopes and event subscriptions
+// it needs. Features are declared next to the code that implements them, and the
+// app manifest asks for the union of what the enabled ones need.
+type Feature struct {
+	Name        string
+	Description string
+	Scopes      []string // Bot token scopes.
+	Events      []string // Bot event subscriptions.
+	Optional    bool     // Whether the feature can be left out of the manifest.
+}
+
+// features are the bot's features, in declaration order.
+var features []Feature
+
+// requires declares a feature for the app manifest.
+func requires(f Feature) Feature {
+	features = append(features, f)
+	return f
+}
+
+// Features returns the bot's features sorted by name.
+func Features() []Feature {
+	out := slices.Clone(features)
+	sort.Slice(out, func(i, j int) bool { return out[i].Name < out[j].Name })
+	return out
+}
+
+// ManifestOptions describes the deployment a Slack app manifest is generated for.
+type ManifestOptions struct {
+	Name      string   // App and bot display name.
+	BaseURL   string   // Public URL of the server, such as https://slacker.example.com.
+	Workspace string   // Routed workspace the app is for; empty for the default workspace.
+	Commands  []string // Slash command names, defaulting to DefaultCommand.
+	Without   []string // Optional features to leave out.
+}
+
+// Manifest is a Slack app manifest, ready to import at api.slack.com/apps.
+type Manifest struct {
+	DisplayInformation manifestDisplay  `json:"display_information"`
+	Features           manifestFeatures `json:"features"`
+	OAuthConfig        manifestOAuth    `json:"oauth_config"`
+	Settings           manifestSettings `json:"settings"`
+}
+
+type manifestDisplay struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+type manifestFeatures struct {
+	AppHome       manifestAppHome        `json:"app_home"`
+	BotUser       manifestBotUser        `json:"bot_user"`
+	SlashCommands []manifestSlashCommand `json:"slash_commands"`
+}
+
+type manifestAppHome struct {
+	HomeTabEnabled             bool `json:"home_tab_enabled"`
+	MessagesTabEnabled         bool `json:"messages_tab_enabled"`
+	MessagesTabReadOnlyEnabled bool `json:"messages_tab_read_only_enabled"`
+}
+
+type manifestBotUser struct {
+	DisplayName  string `json:"display_name"`
+	AlwaysOnline bool   `json:"always_online"`
+}
+
+type manifestSlashCommand struct {
+	Command      string `json:"command"`
+	URL          string `json:"url"`
+	Description  string `json:"description"`
+	UsageHint    string `json:"usage_hint"`
+	ShouldEscape bool   `json:"should_escape"`
+}
+
+type manifestOAuth struct {
+	Scopes struct {
+		Bot []string `json:"bot"`
+	} `json:"scopes"`
+}
+
+type manifestSettings struct {
+	EventSubscriptions struct {
+		RequestURL string   `json:"request_url"`
+		BotEvents  []string `json:"bot_events"`
+	} `json:"event_subscriptions"`
+	Interactivity struct {
+		IsEnabled  bool   `json:"is_enabled"`
+		RequestURL string `json:"request_url"`
+	} `json:"interactivity"`
+	OrgDeployEnabled     bool `json:"org_deploy_enabled"`
+	SocketModeEnabled    bool `json:"socket_mode_enabled"`
+	TokenRotationEnabled bool `json:"token_rotation_enabled"`
+}
+
+// BuildManifest creates the Slack app manifest for a deployment: the scopes and
+// events of every feature not left out, and the server's Slack endpoints.
+func BuildManifest(opts ManifestOptions) (*Manifest, error) {
+	base, err := url.Parse(strings.TrimSuffix(opts.BaseURL, "/"))
+	if err != nil || base.Scheme != "https" || base.Host == "" {
+		return nil, fmt.Errorf("base URL %q must be an absolute https URL", opts.BaseURL)
+	}
+	prefix := base.String() + "/slack"
+	if opts.Workspace != "" && opts.Workspace != "default" {
+		prefix += "/" + opts.Workspace
+	}
+
+	for _, name := range opts.Without {
+		i := slices.IndexFunc(features, func(f Feature) bool { return f.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("unknown feature %q", name)
+		}
+		if !features[i].Optional {
+			return nil, fmt.Errorf("feature %q is required", name)
+		}
+	}
+
+	var scopes, events []string
+	for _, f := range features {
+		if slices.Contains(opts.Without, f.Name) {
+			continue
+		}
+		scopes = append(scopes, f.Scopes...)
+		events = append(events, f.Events...)
+	}
+	slices.Sort(scopes)
+	slices.Sort(events)
+
+	name := opts.Name
+	if name == "" {
+		name = "Ready to Review"
+	}
+	commands := opts.Commands
+	if len(commands) == 0 {
+		commands = []string{DefaultCommand}
+	}
+	if len(commands) > 50 {
+		return nil, errors.New("a Slack app can have at most 50 slash commands")
+	}
+
+	m := &Manifest{
+		DisplayInformation: manifestDisplay{
+			Name:        name,
+			Description: "Posts GitHub pull requests to Slack and tells you when they're waiting on you.",
+		},
+		Features: manifestFeatures{
+			AppHome: manifestAppHome{HomeTabEnabled: true, MessagesTabEnabled: true, MessagesTabReadOnlyEnabled: true},
+			BotUser: manifestBotUser{DisplayName: strings.ToLower(strings.ReplaceAll(name, " ", "-")), AlwaysOnline: true},
+		},
+	}
+	for _, command := range commands {
+		m.Features.SlashCommands = append(m.Features.SlashCommands, manifestSlashCommand{
+			Command:     command,
+			URL:         prefix + "/slash",
+			Description: "Pull request dashboard, settings, and help",
+			UsageHint:   "[dashboard|list|settings|config|preview|test-dm|leaderboard|help]",
+			// Escaping delivers mentions as user IDs, which forget-user relies on.
+			ShouldEscape: true,
+		})
+	}
+	m.OAuthConfig.Scopes.Bot = slices.Compact(scopes)
+	m.Settings.EventSubscriptions.RequestURL = prefix + "/events"
+	m.Settings.EventSubscriptions.BotEvents = slices.Compact(events)
+	m.Settings.Interactivity.IsEnabled = true
+	m.Settings.Interactivity.RequestURL = prefix + "/interactions"
+	return m, nil
+}
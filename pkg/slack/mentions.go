@@ -0,0 +1,134 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/slack-go/slack"
+	"github.com/slack-go/slack/slackevents"
+)
+
+// mentionPattern matches the user mention Slack puts at the start of an app_mention.
+var mentionPattern = regexp.MustCompile(`^\s*<@([A-Z0-9]+)(?:\|[^>]*)?>\s*`)
+
+// Mention is a message addressed to the bot.
+type Mention struct {
+	Workspace string // Workspace the message was posted in.
+	ChannelID string
+	UserID    string
+	Text      string // Message text with the leading bot mention removed.
+	ThreadTS  string // Thread the message belongs to, if any.
+}
+
+// MentionHandler answers commands addressed to the bot by mention.
+type MentionHandler interface {
+	// HandleMention returns the reply to post in-thread, or "" for no reply.
+	HandleMention(ctx context.Context, m Mention) string
+}
+
+// SetMentionHandler sets the handler for commands addressed to the bot in channels.
+func (c *Client) SetMentionHandler(h MentionHandler) {
+	c.mentions = h
+}
+
+// Mentions answer commands addressed to the bot in channels.
+var _ = requires(Feature{
+	Name:        "mentions",
+	Description: "Answer commands addressed to the bot by @mention",
+	Scopes:      []string{"app_mentions:read", "channels:read"},
+	Events:      []string{"app_mention"},
+})
+
+// Thread commands answer commands in PR threads, which arrive as messages.
+var _ = requires(Feature{
+	Name:        "thread-commands",
+	Description: "Answer remind, assign, and approve commands in PR threads",
+	Scopes:      []string{"channels:history", "groups:history"},
+	Events:      []string{"message.channels", "message.groups"},
+	Optional:    true,
+})
+
+// replyToMention runs a mention command and replies in the message's thread.
+func (c *Client) replyToMention(ctx context.Context, evt *slackevents.AppMentionEvent) {
+	if c.mentions == nil || evt.BotID != "" {
+		return
+	}
+	// Thread messages also arrive as message events, which handle them once subscribed.
+	if evt.ThreadTimeStamp != "" && c.seenMessageEvents.Load() {
+		return
+	}
+	c.runMention(ctx, evt.Channel, evt.User, evt.Text, evt.ThreadTimeStamp, evt.TimeStamp)
+}
+
+// replyToThreadMessage runs a command typed in a thread and addressed to the bot.
+func (c *Client) replyToThreadMessage(ctx context.Context, evt *slackevents.MessageEvent) {
+	c.seenMessageEvents.Store(true)
+	if c.mentions == nil || evt.BotID != "" || evt.SubType != "" {
+		return
+	}
+	if evt.ThreadTimeStamp == "" || evt.ThreadTimeStamp == evt.TimeStamp {
+		return
+	}
+
+	botID, err := c.botUserID(ctx)
+	if err != nil {
+		slog.Warn("failed to identify bot user", "error", err)
+		return
+	}
+	match := mentionPattern.FindStringSubmatch(evt.Text)
+	if match == nil || match[1] != botID {
+		return
+	}
+	c.runMention(ctx, evt.Channel, evt.User, evt.Text, evt.ThreadTimeStamp, evt.TimeStamp)
+}
+
+// runMention passes a command to the mention handler and posts its reply in-thread.
+func (c *Client) runMention(ctx context.Context, channelID, userID, text, threadTS, ts string) {
+	m := Mention{
+		Workspace: c.workspace,
+		ChannelID: channelID,
+		UserID:    userID,
+		Text:      strings.TrimSpace(mentionPattern.ReplaceAllString(text, "")),
+		ThreadTS:  threadTS,
+	}
+	c.recordUse("slacker_slack_mentions_total", userID)
+	reply := c.mentions.HandleMention(ctx, m)
+	if reply == "" {
+		return
+	}
+
+	if threadTS == "" {
+		threadTS = ts
+	}
+	if err := c.PostThreadReply(ctx, channelID, threadTS, reply); err != nil {
+		slog.Warn("failed to reply to mention", "channel", channelID, "error", err)
+	}
+}
+
+// botUserID returns the bot's own Slack user ID, looked up once.
+func (c *Client) botUserID(ctx context.Context) (string, error) {
+	c.botMu.Lock()
+	defer c.botMu.Unlock()
+
+	if c.botID != "" {
+		return c.botID, nil
+	}
+	resp, err := c.api.AuthTestContext(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to call auth.test: %w", err)
+	}
+	c.botID = resp.UserID
+	return c.botID, nil
+}
+
+// ChannelName returns the name of a channel, without the leading '#'.
+func (c *Client) ChannelName(ctx context.Context, channelID string) (string, error) {
+	channel, err := c.api.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{ChannelID: channelID})
+	if err != nil {
+		return "", fmt.Errorf("failed to get channel info: %w", err)
+	}
+	return channel.Name, nil
+}
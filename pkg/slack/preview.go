@@ -0,0 +1,32 @@
+package slack
+
+import (
+	"context"
+	"time"
+)
+
+// previewTimeout keeps a preview within Slack's deadline for answering a slash command.
+const previewTimeout = 2500 * time.Millisecond
+
+// Previewer renders the messages the bot would send for a PR.
+type Previewer interface {
+	// PreviewPR describes the thread message and notification DM for the PR named
+	// by ref in a workspace, without sending either.
+	PreviewPR(ctx context.Context, workspaceID, ref string) string
+}
+
+// SetPreviewer sets the previewer used by /r2r preview.
+func (c *Client) SetPreviewer(p Previewer) {
+	c.previewer = p
+}
+
+// previewCommand handles /r2r preview.
+func (c *Client) previewCommand(ctx context.Context, ref string) string {
+	if c.previewer == nil {
+		return "Previews are not available."
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, previewTimeout)
+	defer cancel()
+	return c.previewer.PreviewPR(ctx, c.workspace, ref)
+}
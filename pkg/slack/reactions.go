@@ -0,0 +1,61 @@
+package slack
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/slack-go/slack/slackevents"
+)
+
+// Reaction is an emoji reaction added to or removed from a message.
+type Reaction struct {
+	Workspace string // Workspace the message was posted in.
+	ChannelID string
+	MessageTS string // Timestamp of the message reacted to.
+	UserID    string
+	Emoji     string // Reaction name, without colons.
+	Added     bool   // False when the reaction was removed.
+}
+
+// ReactionHandler acts on reactions people add to and remove from the bot's messages.
+type ReactionHandler interface {
+	HandleReaction(ctx context.Context, r Reaction)
+}
+
+// SetReactionHandler sets the handler for reactions to messages.
+func (c *Client) SetReactionHandler(h ReactionHandler) {
+	c.reactions = h
+}
+
+// Thread reactions let people defer a PR by reacting to its thread.
+var _ = requires(Feature{
+	Name:        "defer-reactions",
+	Description: "Relax nudges for PRs deferred by a reaction on their thread",
+	Scopes:      []string{"reactions:read"},
+	Events:      []string{"reaction_added", "reaction_removed"},
+	Optional:    true,
+})
+
+// handleReaction passes a reaction to a message on to the reaction handler, ignoring
+// the bot's own state reactions.
+func (c *Client) handleReaction(ctx context.Context, user, emoji string, item slackevents.Item, added bool) {
+	if c.reactions == nil || item.Type != "message" {
+		return
+	}
+	botID, err := c.botUserID(ctx)
+	if err != nil {
+		slog.Warn("failed to identify bot user", "error", err)
+		return
+	}
+	if user == botID {
+		return
+	}
+	c.reactions.HandleReaction(ctx, Reaction{
+		Workspace: c.workspace,
+		ChannelID: item.Channel,
+		MessageTS: item.Timestamp,
+		UserID:    user,
+		Emoji:     emoji,
+		Added:     added,
+	})
+}
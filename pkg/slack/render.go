@@ -0,0 +1,110 @@
+package slack
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+// stateStyle is how a PR state is rendered in Slack.
+type stateStyle struct {
+	emoji string
+	color string // Attachment bar color.
+	label string
+	since string // Describes time spent in the state, as in "in review for 2d 4h".
+}
+
+// stateStyles maps each PR state to its rendering. Colors follow Slack's palette
+// so the bar reads the same as the emoji for users who hide reactions.
+var stateStyles = map[string]stateStyle{
+	"test_tube":     {emoji: "🧪", color: "#1D9BD1", label: "Tests running", since: "testing"},
+	"broken_heart":  {emoji: "💔", color: "#E01E5A", label: "Tests failing", since: "failing tests"},
+	"hourglass":     {emoji: "⏳", color: "#ECB22E", label: "Waiting for review", since: "in review"},
+	"carpentry_saw": {emoji: "🪚", color: "#E8912D", label: "Changes requested", since: "awaiting changes"},
+	"check":         {emoji: "✅", color: "#2EB67D", label: "Approved", since: "approved"},
+	"pray":          {emoji: "🙏", color: "#8250DF", label: "Merged"},
+	"face_palm":     {emoji: "🤦", color: "#616061", label: "Closed"},
+	"question":      {emoji: "❓", color: "#DDDDDD", label: "State unknown", since: "unknown"},
+}
+
+// unknownStyle renders states missing from stateStyles.
+var unknownStyle = stateStyle{emoji: "❓", color: "#DDDDDD", label: "Unknown"}
+
+// styleFor returns the rendering for a PR state.
+func styleFor(prState string) stateStyle {
+	if style, exists := stateStyles[prState]; exists {
+		return style
+	}
+	return unknownStyle
+}
+
+// StateEmoji maps a PR state to the emoji used to render it.
+func StateEmoji(prState string) string {
+	return styleFor(prState).emoji
+}
+
+// StateColor maps a PR state to the color of its attachment bar.
+func StateColor(prState string) string {
+	return styleFor(prState).color
+}
+
+// StateLabel describes a PR state in words.
+func StateLabel(prState string) string {
+	return styleFor(prState).label
+}
+
+// StateAttachments returns a colored attachment describing a PR state, or nil
+// if the state is not known yet.
+func StateAttachments(prState string) []slack.Attachment {
+	if prState == "" {
+		return nil
+	}
+	style := styleFor(prState)
+	return []slack.Attachment{{
+		Color:    style.color,
+		Text:     style.emoji + " " + style.label,
+		Fallback: style.label,
+	}}
+}
+
+// ThreadAttachments returns the attachments for a PR thread's parent message: the
+// state attachment and, while the PR is open, buttons to claim or schedule its review
+// and to subscribe to or unsubscribe from its state changes.
+// Approved PRs are marked as held while frozen, rather than ready to merge.
+func ThreadAttachments(prState string, frozen bool) []slack.Attachment {
+	attachments := StateAttachments(prState)
+	if frozen && prState == "check" {
+		attachments[0].Text += " • 🧊 freeze in effect"
+	}
+	if prState == "pray" || prState == "face_palm" {
+		return attachments
+	}
+	claim := slack.NewButtonBlockElement(ClaimReviewAction, "claim",
+		slack.NewTextBlockObject(slack.PlainTextType, "👀 I'll review this", true, false))
+	schedule := slack.NewButtonBlockElement(ScheduleReviewAction, "schedule",
+		slack.NewTextBlockObject(slack.PlainTextType, "📅 Schedule review", true, false))
+	subscribe := slack.NewButtonBlockElement(SubscribeAction, "subscribe",
+		slack.NewTextBlockObject(slack.PlainTextType, "🔔 Notify me about this PR", true, false))
+	unsubscribe := slack.NewButtonBlockElement(UnsubscribeAction, "unsubscribe",
+		slack.NewTextBlockObject(slack.PlainTextType, "🔕 Stop notifying me", true, false))
+	return append(attachments, slack.Attachment{
+		Fallback: "I'll review this",
+		Blocks:   slack.Blocks{BlockSet: []slack.Block{slack.NewActionBlock("", claim, schedule, subscribe, unsubscribe)}},
+	})
+}
+
+// Slack date token formats for FormatTime. Slack fills them in from each viewer's
+// own timezone, language, and 12- or 24-hour clock setting.
+const (
+	TimeOnly     = "{time}"
+	DateTime     = "{date_short_pretty} at {time}"
+	LongDateTime = "{date_long_pretty} at {time}"
+)
+
+// FormatTime renders t for Slack in format, so that each person sees it in their
+// own timezone and locale rather than the server's. Clients that can't render date
+// tokens, such as notification previews, show t in UTC instead.
+func FormatTime(t time.Time, format string) string {
+	return fmt.Sprintf("<!date^%d^%s|%s>", t.Unix(), format, t.UTC().Format("Mon Jan 2 15:04 UTC"))
+}
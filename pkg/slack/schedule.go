@@ -0,0 +1,91 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	// ScheduleReviewAction is the action ID of the "Schedule review" button on PR threads.
+	ScheduleReviewAction = "schedule_review"
+	// ScheduleReviewSubmission is the callback ID of the schedule review modal.
+	ScheduleReviewSubmission = "schedule_review_submission"
+
+	// Block and action IDs of the schedule review modal's inputs, which are also
+	// the keys of the submitted Action's Values.
+	SchedulePartner = "partner"
+	ScheduleDate    = "date"
+	ScheduleTime    = "time"
+)
+
+// OpenScheduleModal opens the form for proposing a pair review time for the PR
+// whose thread starts at threadTS. The date starts at today's date in today's location,
+// which should be the user's timezone.
+func (c *Client) OpenScheduleModal(ctx context.Context, triggerID, channelID, threadTS string, today time.Time) error {
+	text := func(s string) *slack.TextBlockObject {
+		return slack.NewTextBlockObject(slack.PlainTextType, s, false, false)
+	}
+
+	date := slack.NewDatePickerBlockElement(ScheduleDate)
+	date.InitialDate = today.Format("2006-01-02")
+
+	view := slack.ModalViewRequest{
+		Type:            slack.VTModal,
+		CallbackID:      ScheduleReviewSubmission,
+		PrivateMetadata: channelID + "/" + threadTS,
+		Title:           text("Schedule review"),
+		Submit:          text("Schedule"),
+		Close:           text("Cancel"),
+		Blocks: slack.Blocks{BlockSet: []slack.Block{
+			slack.NewInputBlock(SchedulePartner, text("Review with"), nil,
+				slack.NewOptionsSelectBlockElement(slack.OptTypeUser, text("Choose a person"), SchedulePartner)),
+			slack.NewInputBlock(ScheduleDate, text("Date"), nil, date),
+			slack.NewInputBlock(ScheduleTime, text("Time"), nil, slack.NewTimePickerBlockElement(ScheduleTime)),
+		}},
+	}
+	if _, err := c.api.OpenViewContext(ctx, triggerID, view); err != nil {
+		return fmt.Errorf("failed to open schedule modal: %w", err)
+	}
+	return nil
+}
+
+// scheduleAction converts a schedule review modal submission into an Action on the PR's thread.
+func (c *Client) scheduleAction(interaction slack.InteractionCallback) (Action, bool) {
+	channelID, threadTS, found := strings.Cut(interaction.View.PrivateMetadata, "/")
+	if !found {
+		return Action{}, false
+	}
+	values := interaction.View.State.Values
+	return Action{
+		Workspace: c.workspace,
+		ChannelID: channelID,
+		UserID:    interaction.User.ID,
+		MessageTS: threadTS,
+		ActionID:  ScheduleReviewSubmission,
+		Values: map[string]string{
+			SchedulePartner: values[SchedulePartner][SchedulePartner].SelectedUser,
+			ScheduleDate:    values[ScheduleDate][ScheduleDate].SelectedDate,
+			ScheduleTime:    values[ScheduleTime][ScheduleTime].SelectedTime,
+		},
+	}, true
+}
+
+// ScheduleDirectMessage schedules a DM to a user, delivered by Slack at the given time.
+func (c *Client) ScheduleDirectMessage(ctx context.Context, userID string, at time.Time, text string) error {
+	channelID, err := c.openDM(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if _, _, err := c.api.ScheduleMessageContext(ctx, channelID, strconv.FormatInt(at.Unix(), 10),
+		slack.MsgOptionText(text, false)); err != nil {
+		return fmt.Errorf("failed to schedule DM: %w", err)
+	}
+	slog.Info("scheduled DM", "user", userID, "at", at)
+	return nil
+}
@@ -0,0 +1,107 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+// errScopesUnreported is returned when Slack doesn't report a token's scopes,
+// as with legacy tokens.
+var errScopesUnreported = errors.New("Slack did not report the token's scopes")
+
+// GrantedScopes calls auth.test, which slack-go wraps without its response headers,
+// and returns the scopes Slack reports for the bot token in X-OAuth-Scopes.
+func (c *Client) GrantedScopes(ctx context.Context) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, slack.APIURL+"auth.test", http.NoBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call auth.test: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Debug("failed to close response body", "error", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("auth.test returned status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		Error string `json:"error"`
+		OK    bool   `json:"ok"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode auth.test response: %w", err)
+	}
+	if !result.OK {
+		return nil, fmt.Errorf("bot token rejected by auth.test: %s", result.Error)
+	}
+
+	header := resp.Header.Get("X-OAuth-Scopes")
+	if header == "" {
+		return nil, errScopesUnreported
+	}
+	var scopes []string
+	for _, scope := range strings.Split(header, ",") {
+		if scope = strings.TrimSpace(scope); scope != "" {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes, nil
+}
+
+// CheckScopes verifies the bot token is valid and grants every scope the bot's
+// features need. Scopes missing for required features are an error listing each
+// one with the features that need it; those missing only for optional features are
+// logged, since just those features will fail. A token whose scopes Slack doesn't
+// report is accepted with a warning.
+func (c *Client) CheckScopes(ctx context.Context) error {
+	granted, err := c.GrantedScopes(ctx)
+	if errors.Is(err, errScopesUnreported) {
+		slog.Warn("unable to verify Slack scopes", "workspace", c.workspace, "error", err)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	neededBy := make(map[string][]string)
+	for _, f := range Features() {
+		scopes := slices.DeleteFunc(slices.Clone(f.Scopes), func(scope string) bool { return slices.Contains(granted, scope) })
+		if len(scopes) == 0 {
+			continue
+		}
+		if f.Optional {
+			slog.Warn("Slack token lacks scopes for an optional feature, which will fail until they are granted",
+				"workspace", c.workspace, "feature", f.Name, "missing", scopes)
+			continue
+		}
+		for _, scope := range scopes {
+			neededBy[scope] = append(neededBy[scope], f.Name)
+		}
+	}
+	if len(neededBy) == 0 {
+		return nil
+	}
+
+	required := slices.Sorted(maps.Keys(neededBy))
+	details := make([]string, 0, len(required))
+	for _, scope := range required {
+		details = append(details, fmt.Sprintf("%s (%s)", scope, strings.Join(neededBy[scope], ", ")))
+	}
+	return fmt.Errorf("bot token is missing Slack scopes: %s; add them to the Slack app and reinstall it", strings.Join(details, ", "))
+}
@@ -0,0 +1,76 @@
+package slack
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// Action IDs of the controls in the Home tab's notification settings.
+const (
+	ToggleRealtimeAction    = "toggle_realtime"     // Turn real-time DMs on or off.
+	ToggleDailyAction       = "toggle_daily"        // Turn daily reminders on or off.
+	ChangeDelayAction       = "change_delay"        // Pick the delay after a channel post, in minutes.
+	ToggleSmartTimingAction = "toggle_smart_timing" // Hold DMs until the hours the user usually reviews in.
+)
+
+// maxNotifyDelay bounds the delay a settings change may set, in case a stale or
+// forged view submits an option the menu doesn't offer.
+const maxNotifyDelay = 24 * time.Hour
+
+// SettingsStore holds each user's notification preferences.
+type SettingsStore interface {
+	GetUserPreferences(workspaceID, userID string) state.UserPreferences
+	SetUserPreferences(workspaceID, userID string, prefs state.UserPreferences)
+}
+
+// SetSettingsStore sets the store the Home tab's settings read and change.
+func (c *Client) SetSettingsStore(s SettingsStore) {
+	c.settings = s
+}
+
+// runSettingsAction handles a control in the Home tab's settings, reporting whether
+// the action was one. The Home tab is republished so it shows the change.
+func (c *Client) runSettingsAction(ctx context.Context, a Action) bool {
+	switch a.ActionID {
+	case ToggleRealtimeAction, ToggleDailyAction, ChangeDelayAction, ToggleSmartTimingAction:
+	default:
+		return false
+	}
+	if c.settings == nil {
+		return true
+	}
+
+	prefs := c.settings.GetUserPreferences(c.workspace, a.UserID)
+	switch a.ActionID {
+	case ToggleRealtimeAction:
+		prefs.RealTimeNotifications = !prefs.RealTimeNotifications
+	case ToggleDailyAction:
+		prefs.DailyReminders = !prefs.DailyReminders
+	case ToggleSmartTimingAction:
+		prefs.SmartTiming = !prefs.SmartTiming
+	case ChangeDelayAction:
+		minutes, err := strconv.Atoi(a.Value)
+		delay := time.Duration(minutes) * time.Minute
+		if err != nil || delay <= 0 || delay > maxNotifyDelay {
+			slog.Warn("ignoring invalid notification delay", "user", a.UserID, "value", a.Value)
+			return true
+		}
+		prefs.ChannelNotifyDelay = delay
+	default:
+	}
+	c.settings.SetUserPreferences(c.workspace, a.UserID, prefs)
+	slog.Info("updated notification settings", "workspace", c.workspace, "user", a.UserID, "action", a.ActionID,
+		"realtime", prefs.RealTimeNotifications, "daily", prefs.DailyReminders, "delay", prefs.ChannelNotifyDelay,
+		"smart_timing", prefs.SmartTiming)
+
+	ctx, cancel := context.WithTimeout(ctx, homeTimeout)
+	defer cancel()
+	if err := c.updateAppHome(ctx, a.UserID); err != nil {
+		slog.Warn("failed to republish app home after settings change", "user", a.UserID, "error", err)
+	}
+	return true
+}
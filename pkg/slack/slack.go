@@ -2,6 +2,7 @@
 package slack
 
 import (
+	"bytes"
 	"context"
 	"crypto/hmac"
 	"crypto/sha256"
@@ -13,29 +14,86 @@ import (
 	"net/http"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/codeGROOVE-dev/retry"
+	"github.com/codeGROOVE-dev/slacker/pkg/command"
+	"github.com/codeGROOVE-dev/slacker/pkg/httpclient"
 	"github.com/slack-go/slack"
 	"github.com/slack-go/slack/slackevents"
 )
 
 // Client wraps the Slack API client.
 type Client struct {
-	api           *slack.Client
-	signingSecret string
+	api               *slack.Client
+	httpClient        *http.Client
+	breaker           *httpclient.Breaker // Holds Slack's retry budget.
+	userEvents        UserEventHandler
+	mentions          MentionHandler
+	reactions         ReactionHandler
+	actions           ActionHandler
+	linter            ConfigLinter
+	previewer         Previewer
+	tester            NotificationTester
+	away              AwayKeeper
+	leaderboard       LeaderboardSource
+	maintainer        Maintainer
+	help              HelpSource
+	lister            ChannelLister
+	settings          SettingsStore
+	admins            map[string]bool           // Users allowed admin-only subcommands besides workspace admins.
+	commands          map[string]commandHandler // Slash command name to handler.
+	slash             *command.Registry[slack.SlashCommand, commandResponse]
+	token             string
+	signingSecret     string
+	workspace         string
+	botID             string
+	botMu             sync.Mutex
+	usage             usageTracker
+	home              homeViews
+	seenMessageEvents atomic.Bool
 }
 
-// New creates a new Slack client.
-func New(token, signingSecret string) *Client {
-	return &Client{
-		api:           slack.New(token),
+// New creates a new Slack client that makes API calls with httpClient.
+func New(token, signingSecret string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	c := &Client{
+		api:           slack.New(token, slack.OptionHTTPClient(httpClient)),
+		httpClient:    httpClient,
+		breaker:       httpclient.BreakerFor("slack"),
+		token:         token,
 		signingSecret: signingSecret,
 	}
+	c.slash = c.slashCommands()
+	c.SetCommands([]string{DefaultCommand})
+	return c
+}
+
+// SetWorkspace names the workspace this client posts to, reported with inbound events.
+func (c *Client) SetWorkspace(workspaceID string) {
+	c.workspace = workspaceID
+}
+
+// Workspace returns the name of the workspace this client posts to.
+func (c *Client) Workspace() string {
+	return c.workspace
 }
 
+// Threads post each PR to its repo's channels, posting to public channels without
+// joining them first, and show its state with reactions or message edits.
+var _ = requires(Feature{
+	Name:        "threads",
+	Description: "Post a thread per PR and show its state",
+	Scopes:      []string{"chat:write", "chat:write.public", "reactions:write", "channels:read"},
+})
+
 // PostThread creates a new thread in a channel for a PR with retry logic.
-func (c *Client) PostThread(ctx context.Context, channelID, text string, attachments []slack.Attachment) (string, error) {
+// It returns the ID of the channel posted to, which may have been given by name, and the thread timestamp.
+func (c *Client) PostThread(ctx context.Context, channelID, text string, attachments []slack.Attachment) (postedChannelID, threadTS string, err error) {
 	slog.Info("posting thread to channel", "channel", channelID)
 
 	// Disable unfurling for GitHub links.
@@ -45,11 +103,10 @@ func (c *Client) PostThread(ctx context.Context, channelID, text string, attachm
 		slack.MsgOptionDisableLinkUnfurl(),
 	}
 
-	var timestamp string
-	err := retry.Do(
+	err = retry.Do(
 		func() error {
 			var err error
-			_, timestamp, err = c.api.PostMessageContext(ctx, channelID, options...)
+			postedChannelID, threadTS, err = c.api.PostMessageContext(ctx, channelID, options...)
 			if err != nil {
 				if isRateLimitError(err) {
 					slog.Warn("rate limited posting, backing off", "channel", channelID)
@@ -71,14 +128,15 @@ func (c *Client) PostThread(ctx context.Context, channelID, text string, attachm
 		retry.MaxDelay(2*time.Minute),
 		retry.DelayType(retry.BackOffDelay),
 		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
 		retry.Context(ctx),
 	)
 	if err != nil {
-		return "", fmt.Errorf("failed to post message after retries: %w", err)
+		return "", "", fmt.Errorf("failed to post message after retries: %w", err)
 	}
 
-	slog.Info("successfully posted thread", "thread", timestamp, "channel", channelID)
-	return timestamp, nil
+	slog.Info("successfully posted thread", "thread", threadTS, "channel", postedChannelID)
+	return postedChannelID, threadTS, nil
 }
 
 // PostThreadReply posts a reply to an existing thread.
@@ -96,6 +154,39 @@ func (c *Client) PostThreadReply(ctx context.Context, channelID, threadTS, text
 	return nil
 }
 
+// PostBlocks posts a Block Kit message to a channel, using text as the notification fallback.
+func (c *Client) PostBlocks(ctx context.Context, channelID, text string, blocks []slack.Block) error {
+	err := retry.Do(
+		func() error {
+			_, _, err := c.api.PostMessageContext(ctx, channelID,
+				slack.MsgOptionText(text, false),
+				slack.MsgOptionBlocks(blocks...),
+				slack.MsgOptionDisableLinkUnfurl(),
+			)
+			if err != nil {
+				if strings.Contains(err.Error(), "channel_not_found") ||
+					strings.Contains(err.Error(), "not_in_channel") {
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to post blocks, retrying", "channel", channelID, "error", err)
+				return err
+			}
+			return nil
+		},
+		retry.Attempts(5),
+		retry.Delay(2*time.Second),
+		retry.MaxDelay(2*time.Minute),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to post blocks after retries: %w", err)
+	}
+	return nil
+}
+
 // AddReaction adds a reaction emoji to a message.
 func (c *Client) AddReaction(ctx context.Context, channelID, timestamp, emoji string) error {
 	err := c.api.AddReactionContext(ctx, emoji, slack.ItemRef{
@@ -126,21 +217,22 @@ func (c *Client) RemoveReaction(ctx context.Context, channelID, timestamp, emoji
 	return nil
 }
 
+// stateReactions maps PR states to the reaction emojis that show them.
+var stateReactions = map[string]string{
+	"test_tube":     "test_tube",
+	"broken_heart":  "broken_heart",
+	"hourglass":     "hourglass",
+	"carpentry_saw": "carpentry_saw",
+	"check":         "white_check_mark",
+	"pray":          "pray",
+	"face_palm":     "face_palm",
+	"question":      "question",
+}
+
 // UpdateReactions updates the reaction on a message based on PR state.
 func (c *Client) UpdateReactions(ctx context.Context, channelID, timestamp, newState string) error {
-	// Map states to emojis.
-	stateEmojis := map[string]string{
-		"test_tube":     "test_tube",
-		"broken_heart":  "broken_heart",
-		"hourglass":     "hourglass",
-		"carpentry_saw": "carpentry_saw",
-		"check":         "white_check_mark",
-		"pray":          "pray",
-		"face_palm":     "face_palm",
-	}
-
 	// Remove all existing reactions.
-	for _, emoji := range stateEmojis {
+	for _, emoji := range stateReactions {
 		if err := c.RemoveReaction(ctx, channelID, timestamp, emoji); err != nil {
 			// Log but don't fail - reaction might not exist.
 			slog.Warn("failed to remove reaction", "emoji", emoji, "error", err)
@@ -148,47 +240,117 @@ func (c *Client) UpdateReactions(ctx context.Context, channelID, timestamp, newS
 	}
 
 	// Add new reaction.
-	if emoji, ok := stateEmojis[newState]; ok {
+	if emoji, ok := stateReactions[newState]; ok {
 		return c.AddReaction(ctx, channelID, timestamp, emoji)
 	}
 
 	return nil
 }
 
-// SendDirectMessage sends a direct message to a user with retry logic.
-func (c *Client) SendDirectMessage(ctx context.Context, userID, text string) error {
-	slog.Info("sending DM to user", "user", userID)
+// AddStateReaction adds the reaction for a PR state, leaving earlier state reactions in place.
+func (c *Client) AddStateReaction(ctx context.Context, channelID, timestamp, newState string) error {
+	if emoji, ok := stateReactions[newState]; ok {
+		return c.AddReaction(ctx, channelID, timestamp, emoji)
+	}
+	return nil
+}
 
-	var channelID string
+// Pins keep urgent PRs' threads and milestone summaries at the top of their channels.
+var _ = requires(Feature{
+	Name:        "pins",
+	Description: "Pin urgent PR threads and milestone summaries",
+	Scopes:      []string{"pins:write"},
+	Optional:    true,
+})
+
+// PinMessage pins a message to its channel.
+func (c *Client) PinMessage(ctx context.Context, channelID, timestamp string) error {
+	err := c.api.AddPinContext(ctx, channelID, slack.ItemRef{
+		Channel:   channelID,
+		Timestamp: timestamp,
+	})
+	if err != nil && !strings.Contains(err.Error(), "already_pinned") {
+		return fmt.Errorf("failed to pin message: %w", err)
+	}
+	return nil
+}
+
+// UnpinMessage unpins a message from its channel.
+func (c *Client) UnpinMessage(ctx context.Context, channelID, timestamp string) error {
+	err := c.api.RemovePinContext(ctx, channelID, slack.ItemRef{
+		Channel:   channelID,
+		Timestamp: timestamp,
+	})
+	if err != nil && !strings.Contains(err.Error(), "no_pin") {
+		return fmt.Errorf("failed to unpin message: %w", err)
+	}
+	return nil
+}
+
+// Permalink returns a link to a message that opens it in Slack.
+func (c *Client) Permalink(ctx context.Context, channelID, timestamp string) (string, error) {
+	link, err := c.api.GetPermalinkContext(ctx, &slack.PermalinkParameters{Channel: channelID, Ts: timestamp})
+	if err != nil {
+		return "", fmt.Errorf("failed to get permalink: %w", err)
+	}
+	return link, nil
+}
 
-	// First, open conversation with retry
+// UpdateMessage replaces the text of a message with retry logic. Attachments, if
+// given, replace the message's attachments; otherwise they are left as they were.
+func (c *Client) UpdateMessage(ctx context.Context, channelID, timestamp, text string, attachments ...slack.Attachment) error {
+	options := []slack.MsgOption{slack.MsgOptionText(text, false), slack.MsgOptionDisableLinkUnfurl()}
+	if len(attachments) > 0 {
+		options = append(options, slack.MsgOptionAttachments(attachments...))
+	}
 	err := retry.Do(
 		func() error {
-			channel, _, _, err := c.api.OpenConversationContext(ctx, &slack.OpenConversationParameters{
-				Users: []string{userID},
-			})
+			_, _, _, err := c.api.UpdateMessageContext(ctx, channelID, timestamp, options...)
 			if err != nil {
-				slog.Warn("failed to open conversation, retrying", "user", userID, "error", err)
+				if strings.Contains(err.Error(), "message_not_found") || strings.Contains(err.Error(), "cant_update_message") {
+					return retry.Unrecoverable(err)
+				}
+				slog.Warn("failed to update message, retrying", "channel", channelID, "error", err)
 				return err
 			}
-			channelID = channel.ID
 			return nil
 		},
-		retry.Attempts(3),
-		retry.Delay(time.Second),
-		retry.MaxDelay(30*time.Second),
+		retry.Attempts(5),
+		retry.Delay(2*time.Second),
+		retry.MaxDelay(2*time.Minute),
 		retry.DelayType(retry.BackOffDelay),
 		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
 		retry.Context(ctx),
 	)
 	if err != nil {
-		return fmt.Errorf("failed to open conversation after retries: %w", err)
+		return fmt.Errorf("failed to update message after retries: %w", err)
+	}
+	return nil
+}
+
+// Direct messages carry notifications, reminders, and scheduled reviews.
+var _ = requires(Feature{
+	Name:        "dms",
+	Description: "DM users about PRs waiting on them",
+	Scopes:      []string{"chat:write", "im:write"},
+})
+
+// SendDirectMessage sends a direct message to a user with retry logic.
+func (c *Client) SendDirectMessage(ctx context.Context, userID, text string, attachments ...slack.Attachment) error {
+	slog.Info("sending DM to user", "user", userID)
+
+	// First, open conversation.
+	channelID, err := c.openDM(ctx, userID)
+	if err != nil {
+		return err
 	}
 
 	// Then send message with retry
 	err = retry.Do(
 		func() error {
-			_, _, err := c.api.PostMessageContext(ctx, channelID, slack.MsgOptionText(text, false))
+			_, _, err := c.api.PostMessageContext(ctx, channelID,
+				slack.MsgOptionText(text, false), slack.MsgOptionAttachments(attachments...))
 			if err != nil {
 				if isRateLimitError(err) {
 					slog.Warn("rate limited sending DM, backing off", "user", userID)
@@ -204,6 +366,7 @@ func (c *Client) SendDirectMessage(ctx context.Context, userID, text string) err
 		retry.MaxDelay(2*time.Minute),
 		retry.DelayType(retry.BackOffDelay),
 		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
 		retry.Context(ctx),
 	)
 	if err != nil {
@@ -214,6 +377,42 @@ func (c *Client) SendDirectMessage(ctx context.Context, userID, text string) err
 	return nil
 }
 
+// openDM opens a direct message conversation with a user, with retry logic, returning its channel ID.
+func (c *Client) openDM(ctx context.Context, userID string) (string, error) {
+	var channelID string
+	err := retry.Do(
+		func() error {
+			channel, _, _, err := c.api.OpenConversationContext(ctx, &slack.OpenConversationParameters{
+				Users: []string{userID},
+			})
+			if err != nil {
+				slog.Warn("failed to open conversation, retrying", "user", userID, "error", err)
+				return err
+			}
+			channelID = channel.ID
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.RetryIf(c.breaker.Retryable),
+		retry.Context(ctx),
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to open conversation after retries: %w", err)
+	}
+	return channelID, nil
+}
+
+// User lookups find timezones, workspace admins, and whether a user is active.
+var _ = requires(Feature{
+	Name:        "users",
+	Description: "Look up user timezones, admin status, and presence",
+	Scopes:      []string{"users:read"},
+})
+
 // GetUserInfo gets user information including timezone.
 func (c *Client) GetUserInfo(ctx context.Context, userID string) (*slack.User, error) {
 	user, err := c.api.GetUserInfoContext(ctx, userID)
@@ -223,6 +422,26 @@ func (c *Client) GetUserInfo(ctx context.Context, userID string) (*slack.User, e
 	return user, nil
 }
 
+// Email lookups map GitHub users to Slack users by the email on their GitHub profile.
+var _ = requires(Feature{
+	Name:        "identity-email",
+	Description: "Find the Slack users of GitHub logins by email address",
+	Scopes:      []string{"users:read.email"},
+	Optional:    true,
+})
+
+// LookupUserByEmail returns the ID of the Slack user with an email address, or "" if there is none.
+func (c *Client) LookupUserByEmail(ctx context.Context, email string) (string, error) {
+	user, err := c.api.GetUserByEmailContext(ctx, email)
+	if err != nil {
+		if err.Error() == "users_not_found" {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to look up user by email: %w", err)
+	}
+	return user.ID, nil
+}
+
 // GetUserPresence gets user presence (active/away).
 func (c *Client) GetUserPresence(ctx context.Context, userID string) (string, error) {
 	presence, err := c.api.GetUserPresenceContext(ctx, userID)
@@ -261,6 +480,12 @@ func (c *Client) EventsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// User and workspace changes are not modeled by slackevents, so they are handled first.
+	if c.handleUserEvent(r.Context(), body) {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	eventsAPIEvent, err := slackevents.ParseEvent(body, slackevents.OptionNoVerifyToken())
 	if err != nil {
 		slog.Warn("failed to parse Slack event", "error", err)
@@ -288,14 +513,23 @@ func (c *Client) EventsHandler(w http.ResponseWriter, r *http.Request) {
 	if eventsAPIEvent.Type == slackevents.CallbackEvent {
 		switch evt := eventsAPIEvent.InnerEvent.Data.(type) {
 		case *slackevents.MessageEvent:
-			// Handle message events if needed.
-			slog.Debug("received message event", "event", evt)
+			slog.Debug("received message event", "channel", evt.Channel, "user", evt.User)
+			go c.replyToThreadMessage(context.WithoutCancel(r.Context()), evt)
 		case *slackevents.AppMentionEvent:
-			// Handle app mentions if needed.
-			slog.Debug("received app mention", "event", evt)
+			slog.Debug("received app mention", "channel", evt.Channel, "user", evt.User)
+			go c.replyToMention(context.WithoutCancel(r.Context()), evt)
+		case *slackevents.ReactionAddedEvent:
+			go c.handleReaction(context.WithoutCancel(r.Context()), evt.User, evt.Reaction, evt.Item, true)
+		case *slackevents.ReactionRemovedEvent:
+			go c.handleReaction(context.WithoutCancel(r.Context()), evt.User, evt.Reaction, evt.Item, false)
 		case *slackevents.AppHomeOpenedEvent:
 			// Update app home when user opens it.
-			go c.updateAppHome(evt.User)
+			c.recordUse("slacker_slack_home_opens_total", evt.User)
+			go c.openedAppHome(context.WithoutCancel(r.Context()), evt.User)
+		case *slackevents.GridMigrationFinishedEvent:
+			if c.userEvents != nil {
+				go c.userEvents.GridMigrated(context.WithoutCancel(r.Context()), c.workspace, eventsAPIEvent.TeamID)
+			}
 		}
 	}
 
@@ -304,6 +538,12 @@ func (c *Client) EventsHandler(w http.ResponseWriter, r *http.Request) {
 
 // InteractionsHandler handles Slack interactive components.
 func (c *Client) InteractionsHandler(w http.ResponseWriter, r *http.Request) {
+	// Verify the request signature before the body is consumed by form parsing.
+	if !c.verifyRequest(r) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
 	// Parse the payload.
 	payload := r.FormValue("payload")
 	if payload == "" {
@@ -318,20 +558,17 @@ func (c *Client) InteractionsHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Verify the request signature.
-	if !c.verifyRequest(r) {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
-
 	// Handle different interaction types.
 	switch interaction.Type {
 	case slack.InteractionTypeBlockActions:
-		// Handle block actions (buttons, selects, etc.).
+		// Handle block actions (buttons, selects, etc.). Slack expects an answer
+		// within three seconds, so the handler runs after responding.
 		slog.Debug("received block action", "interaction", interaction)
+		go c.runActions(context.WithoutCancel(r.Context()), interaction)
 	case slack.InteractionTypeViewSubmission:
-		// Handle modal submissions.
+		// Handle modal submissions. An empty response closes the modal.
 		slog.Debug("received view submission", "interaction", interaction)
+		go c.runSubmission(context.WithoutCancel(r.Context()), interaction)
 	default:
 		// Other interaction types
 		slog.Debug("unhandled interaction type", "type", interaction.Type)
@@ -356,52 +593,53 @@ func (c *Client) SlashCommandHandler(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Handle different commands.
-	var response string
-	switch cmd.Command {
-	case "/r2r":
-		response = c.handleR2RCommand(cmd)
-	default:
-		response = "Unknown command"
+	// Route the command to its handler.
+	response := textResponse("Unknown command")
+	if handler, exists := c.commands[cmd.Command]; exists {
+		response = handler(r.Context(), cmd)
 	}
 
 	// Send response.
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	if err := json.NewEncoder(w).Encode(map[string]string{
-		"text": response,
-	}); err != nil {
+	if err := json.NewEncoder(w).Encode(response); err != nil {
 		slog.Error("failed to encode slash command response", "error", err)
 	}
 }
 
-// handleR2RCommand handles the /r2r slash command.
-func (c *Client) handleR2RCommand(cmd slack.SlashCommand) string {
-	args := strings.Fields(cmd.Text)
-	if len(args) == 0 {
-		return "Usage: /r2r [dashboard|settings|help]"
-	}
-
-	switch args[0] {
-	case "dashboard":
-		// Note: In a full implementation, we'd send blocks here instead of plain text.
-		// For now, return a link to the web dashboard.
-		return fmt.Sprintf("View your dashboard at: https://dash.ready-to-review.dev/?user=%s\n"+
-			"Or use the Home tab in this app for the native Slack experience.", cmd.UserID)
-	case "settings":
-		return "Open the Home tab in this app to configure your notification preferences."
-	case "help":
-		return "Ready to Review helps you stay on top of pull requests.\n" +
-			"Commands:\n" +
-			"• /r2r dashboard - View your PR dashboard\n" +
-			"• /r2r settings - Configure notification preferences\n" +
-			"• /r2r help - Show this help message\n\n" +
-			"You can also visit the Home tab in this app for a full dashboard."
-	default:
-		return "Unknown subcommand. Try: /r2r help"
+// commandResponse is the reply to a slash command, shown only to the user who ran it.
+type commandResponse struct {
+	Text   string        `json:"text"`
+	Blocks []slack.Block `json:"blocks,omitempty"`
+}
+
+// Slash commands are served for every configured command name.
+var _ = requires(Feature{
+	Name:        "slash-commands",
+	Description: "Answer the /r2r slash command",
+	Scopes:      []string{"commands"},
+})
+
+// DefaultCommand is the slash command handled when no names are configured.
+const DefaultCommand = "/r2r"
+
+// commandHandler answers a slash command.
+type commandHandler func(ctx context.Context, cmd slack.SlashCommand) commandResponse
+
+// SetCommands sets the slash command names the Slack app is configured with,
+// replacing the default. Every name is an alias for the same subcommands.
+func (c *Client) SetCommands(names []string) {
+	c.commands = make(map[string]commandHandler, len(names))
+	for _, name := range names {
+		c.commands[name] = c.handleR2RCommand
 	}
 }
 
+// textResponse is a plain text reply to a slash command.
+func textResponse(text string) commandResponse {
+	return commandResponse{Text: text}
+}
+
 // verifySignature verifies a Slack request signature.
 func (c *Client) verifySignature(signature, timestamp string, body []byte) bool {
 	// Check timestamp to prevent replay attacks.
@@ -430,11 +668,12 @@ func (c *Client) verifyRequest(r *http.Request) bool {
 	signature := r.Header.Get("X-Slack-Signature")
 	timestamp := r.Header.Get("X-Slack-Request-Timestamp")
 
-	// Read body.
+	// Read body, leaving it in place for form parsing.
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
 		return false
 	}
+	r.Body = io.NopCloser(bytes.NewReader(body))
 
 	return c.verifySignature(signature, timestamp, body)
 }
@@ -447,28 +686,3 @@ func isRateLimitError(err error) bool {
 	return strings.Contains(err.Error(), "rate_limited") ||
 		strings.Contains(err.Error(), "429")
 }
-
-// updateAppHome updates the app home view for a user.
-func (c *Client) updateAppHome(userID string) {
-	// In a full implementation, this would:
-	// 1. Get user's PRs from state manager
-	// 2. Build blocks using BuildDashboardBlocks
-	// 3. Call views.publish to update the home tab
-
-	// For now, just log.
-	slog.Debug("would update app home for user", "user", userID)
-}
-
-// PublishHomeView publishes a view to a user's app home.
-func (c *Client) PublishHomeView(userID string, blocks []slack.Block) error {
-	view := slack.HomeTabViewRequest{
-		Type:   "home",
-		Blocks: slack.Blocks{BlockSet: blocks},
-	}
-
-	_, err := c.api.PublishView(userID, view, "")
-	if err != nil {
-		return fmt.Errorf("failed to publish home view: %w", err)
-	}
-	return nil
-}
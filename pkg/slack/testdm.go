@@ -0,0 +1,32 @@
+package slack
+
+import (
+	"context"
+	"time"
+)
+
+// testDMTimeout keeps a test notification within Slack's deadline for answering a slash command.
+const testDMTimeout = 2500 * time.Millisecond
+
+// NotificationTester sends sample notifications.
+type NotificationTester interface {
+	// TestNotification sends a user a sample notification through the normal
+	// checks and describes which of them passed.
+	TestNotification(ctx context.Context, workspaceID, userID string) string
+}
+
+// SetNotificationTester sets the tester used by /r2r test-dm.
+func (c *Client) SetNotificationTester(t NotificationTester) {
+	c.tester = t
+}
+
+// testDMCommand handles /r2r test-dm.
+func (c *Client) testDMCommand(ctx context.Context, userID string) string {
+	if c.tester == nil {
+		return "Test notifications are not available."
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, testDMTimeout)
+	defer cancel()
+	return c.tester.TestNotification(ctx, c.workspace, userID)
+}
@@ -0,0 +1,58 @@
+package slack
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/slack-go/slack"
+)
+
+const (
+	// topicMarker separates the bot's suffix from the rest of a channel topic.
+	topicMarker = " | 📬 "
+	// maxTopicLength is Slack's limit on channel topic length, in characters.
+	maxTopicLength = 250
+)
+
+// Topics show open and blocked PR counts in the topics of channels with PR threads.
+var _ = requires(Feature{
+	Name:        "topics",
+	Description: "Show PR counts in channel topics",
+	Scopes:      []string{"channels:read", "groups:read", "channels:write.topic", "groups:write.topic"},
+	Optional:    true,
+})
+
+// SetTopicSuffix replaces the bot's suffix on a channel's topic, keeping the rest
+// of the topic as people wrote it. The topic is left alone if it is unchanged.
+func (c *Client) SetTopicSuffix(ctx context.Context, channelID, suffix string) error {
+	channel, err := c.api.GetConversationInfoContext(ctx, &slack.GetConversationInfoInput{ChannelID: channelID})
+	if err != nil {
+		return fmt.Errorf("failed to get channel info: %w", err)
+	}
+
+	current := channel.Topic.Value
+	base := current
+	if i := strings.LastIndex(base, topicMarker); i >= 0 {
+		base = base[:i]
+	} else if strings.HasPrefix(base, strings.TrimLeft(topicMarker, " ")) {
+		base = ""
+	}
+
+	tail := topicMarker + suffix
+	if base == "" {
+		tail = strings.TrimLeft(tail, " ")
+	}
+	if room := maxTopicLength - len([]rune(tail)); len([]rune(base)) > room {
+		base = string([]rune(base)[:max(room, 0)])
+	}
+
+	topic := base + tail
+	if topic == current {
+		return nil
+	}
+	if _, err := c.api.SetTopicOfConversationContext(ctx, channelID, topic); err != nil {
+		return fmt.Errorf("failed to set channel topic: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package slack
+
+import (
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+// activeWindow is how long a user counts as active after they last used the bot.
+const activeWindow = 7 * 24 * time.Hour
+
+// usageTracker remembers when each user last used the bot, for the active users gauge.
+type usageTracker struct {
+	lastUsed map[string]time.Time
+	mu       sync.Mutex
+}
+
+// touch records that a user used the bot at now, returning how many users did so within activeWindow.
+func (t *usageTracker) touch(userID string, now time.Time) int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.lastUsed == nil {
+		t.lastUsed = make(map[string]time.Time)
+	}
+	for id, at := range t.lastUsed {
+		if now.Sub(at) > activeWindow {
+			delete(t.lastUsed, id)
+		}
+	}
+	if userID != "" {
+		t.lastUsed[userID] = now
+	}
+	return len(t.lastUsed)
+}
+
+// recordUse counts a use of the bot for adoption metrics: metric is the counter to
+// increment, labels any besides the workspace's. Users active in the last week, since
+// the process started, are reported per workspace as slacker_slack_active_users.
+func (c *Client) recordUse(metric, userID string, labels ...string) {
+	metrics.IncCounter(metric, append([]string{"workspace", c.workspace}, labels...)...)
+	metrics.SetGauge("slacker_slack_active_users", float64(c.usage.touch(userID, time.Now())), "workspace", c.workspace)
+}
+
+// countedSubcommand names a slash subcommand for metrics; anything that isn't one
+// counts as "unknown", so typos can't grow the metric without bound.
+func (c *Client) countedSubcommand(words []string) string {
+	if len(words) == 0 {
+		return ""
+	}
+	if cmd, _ := c.slash.Find(words); cmd != nil {
+		return cmd.Name
+	}
+	return "unknown"
+}
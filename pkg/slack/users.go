@@ -0,0 +1,156 @@
+package slack
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/slack-go/slack"
+)
+
+// maxExchangeUsers is the most user IDs migration.exchange accepts per call.
+const maxExchangeUsers = 400
+
+// UserEventHandler is notified of Slack user and workspace changes that affect stored state.
+type UserEventHandler interface {
+	// UserChanged is called when a user's profile changes.
+	UserChanged(ctx context.Context, workspaceID, teamID, userID, timezone string)
+	// TeamDomainChanged is called when the workspace subdomain changes.
+	TeamDomainChanged(ctx context.Context, workspaceID, teamID, domain string)
+	// GridMigrated is called when the workspace finishes migrating into an Enterprise Grid.
+	GridMigrated(ctx context.Context, workspaceID, teamID string)
+}
+
+// Profile sync keeps stored timezones, the workspace domain, and user IDs current.
+var _ = requires(Feature{
+	Name:        "profile-sync",
+	Description: "Follow user timezone, workspace domain, and Enterprise Grid changes",
+	Scopes:      []string{"users:read", "team:read"},
+	Events:      []string{"user_change", "team_domain_change", "grid_migration_finished"},
+	Optional:    true,
+})
+
+// SetUserEventHandler sets the handler for user and workspace change events.
+func (c *Client) SetUserEventHandler(h UserEventHandler) {
+	c.userEvents = h
+}
+
+// handleUserEvent dispatches user_change and team_domain_change callbacks,
+// reporting whether the body was one of them.
+func (c *Client) handleUserEvent(ctx context.Context, body []byte) bool {
+	var envelope struct {
+		Event struct {
+			User   json.RawMessage `json:"user"`
+			Type   string          `json:"type"`
+			Domain string          `json:"domain"`
+		} `json:"event"`
+		Type   string `json:"type"`
+		TeamID string `json:"team_id"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Type != "event_callback" {
+		return false
+	}
+
+	switch envelope.Event.Type {
+	case "user_change":
+		var user slack.User
+		if err := json.Unmarshal(envelope.Event.User, &user); err != nil {
+			slog.Warn("failed to parse user_change event", "error", err)
+			return true
+		}
+		slog.Debug("received user change", "team", envelope.TeamID, "user", user.ID)
+		if c.userEvents != nil {
+			go c.userEvents.UserChanged(context.WithoutCancel(ctx), c.workspace, envelope.TeamID, user.ID, user.TZ)
+		}
+		return true
+	case "team_domain_change":
+		slog.Info("workspace domain changed", "team", envelope.TeamID, "domain", envelope.Event.Domain)
+		if c.userEvents != nil {
+			go c.userEvents.TeamDomainChanged(context.WithoutCancel(ctx), c.workspace, envelope.TeamID, envelope.Event.Domain)
+		}
+		return true
+	default:
+		return false
+	}
+}
+
+// ExchangeUserIDs maps workspace-local user IDs to their current IDs after an
+// Enterprise Grid migration. IDs that did not change are omitted from the result.
+func (c *Client) ExchangeUserIDs(ctx context.Context, userIDs []string) (map[string]string, error) {
+	mapping := make(map[string]string)
+	for start := 0; start < len(userIDs); start += maxExchangeUsers {
+		batch := userIDs[start:min(start+maxExchangeUsers, len(userIDs))]
+
+		var result map[string]string
+		err := retry.Do(
+			func() error {
+				var err error
+				result, err = c.exchangeBatch(ctx, batch)
+				if err != nil {
+					slog.Warn("failed to exchange user IDs, retrying", "users", len(batch), "error", err)
+				}
+				return err
+			},
+			retry.Attempts(3),
+			retry.Delay(time.Second),
+			retry.MaxDelay(30*time.Second),
+			retry.DelayType(retry.BackOffDelay),
+			retry.LastErrorOnly(true),
+			retry.RetryIf(c.breaker.Retryable),
+			retry.Context(ctx),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to exchange user IDs after retries: %w", err)
+		}
+		for oldID, newID := range result {
+			if oldID != newID {
+				mapping[oldID] = newID
+			}
+		}
+	}
+	return mapping, nil
+}
+
+// exchangeBatch calls migration.exchange, which slack-go does not wrap.
+func (c *Client) exchangeBatch(ctx context.Context, userIDs []string) (map[string]string, error) {
+	form := url.Values{"users": {strings.Join(userIDs, ",")}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, slack.APIURL+"migration.exchange", strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Debug("failed to close response body", "error", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var result struct {
+		UserIDMap map[string]string `json:"user_id_map"`
+		Error     string            `json:"error"`
+		OK        bool              `json:"ok"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode migration.exchange response: %w", err)
+	}
+	if !result.OK {
+		return nil, errors.New(result.Error)
+	}
+	return result.UserIDMap, nil
+}
@@ -0,0 +1,30 @@
+package state
+
+import (
+	"strings"
+	"time"
+)
+
+// Away reports whether the user is on vacation at now.
+func (p UserPreferences) Away(now time.Time) bool {
+	return !p.AwayUntil.IsZero() && !now.Before(p.AwayFrom) && now.Before(p.AwayUntil)
+}
+
+// AwayLogins returns when each linked GitHub user on vacation at now is back, keyed
+// by lowercased login.
+func (m *Manager) AwayLogins(workspaceID string, now time.Time) map[string]time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	away := make(map[string]time.Time)
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return away
+	}
+	for _, prefs := range workspace.Users {
+		if prefs.GitHubLogin != "" && prefs.Away(now) {
+			away[strings.ToLower(prefs.GitHubLogin)] = prefs.AwayUntil
+		}
+	}
+	return away
+}
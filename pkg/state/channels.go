@@ -0,0 +1,31 @@
+package state
+
+// SetChannelMuted mutes or unmutes new PR posts to a channel.
+func (m *Manager) SetChannelMuted(workspaceID, channel string, muted bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	if workspace.Muted == nil {
+		workspace.Muted = make(map[string]bool)
+	}
+	if muted {
+		workspace.Muted[channel] = true
+	} else {
+		delete(workspace.Muted, channel)
+	}
+
+	m.queueSave(workspaceID)
+}
+
+// ChannelMuted reports whether new PR posts to a channel are muted.
+func (m *Manager) ChannelMuted(workspaceID, channel string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return false
+	}
+	return workspace.Muted[channel]
+}
@@ -0,0 +1,68 @@
+package state
+
+import (
+	"slices"
+	"time"
+)
+
+// ClaimTimeout is how long a review claim holds back nudges to other reviewers, before
+// scaling by the repo's severity tier.
+const ClaimTimeout = 24 * time.Hour
+
+// ClaimActive reports whether someone has claimed the PR's review within timeout of now.
+func (p *PRState) ClaimActive(now time.Time, timeout time.Duration) bool {
+	return p.ClaimedBy != "" && now.Sub(p.ClaimedAt) < timeout
+}
+
+// DeferralTimeout is how long a thread reaction defers a PR, covering the rest of the week.
+const DeferralTimeout = 7 * 24 * time.Hour
+
+// DeferralActive reports whether someone deferred the PR within DeferralTimeout of now.
+func (p *PRState) DeferralActive(now time.Time) bool {
+	return len(p.DeferredBy) > 0 && now.Sub(p.DeferredAt) < DeferralTimeout
+}
+
+// Defer adds a user to those deferring the PR, reporting false if they already were
+// one. A deferral that has run out starts over.
+func (p *PRState) Defer(userID string, now time.Time) bool {
+	if !p.DeferralActive(now) {
+		p.DeferredBy = nil
+		p.DeferredAt = now
+	}
+	if slices.Contains(p.DeferredBy, userID) {
+		return false
+	}
+	p.DeferredBy = append(slices.Clone(p.DeferredBy), userID)
+	return true
+}
+
+// Undefer removes a user from those deferring the PR, reporting false if they weren't
+// one. The deferral ends with the last of them.
+func (p *PRState) Undefer(userID string) bool {
+	if !slices.Contains(p.DeferredBy, userID) {
+		return false
+	}
+	p.DeferredBy = slices.DeleteFunc(slices.Clone(p.DeferredBy), func(id string) bool { return id == userID })
+	if len(p.DeferredBy) == 0 {
+		p.DeferredAt = time.Time{}
+	}
+	return true
+}
+
+// Subscribe adds a user to the PR's subscribers, reporting false if they already were one.
+func (p *PRState) Subscribe(userID string) bool {
+	if slices.Contains(p.Subscribers, userID) {
+		return false
+	}
+	p.Subscribers = append(slices.Clone(p.Subscribers), userID)
+	return true
+}
+
+// Unsubscribe removes a user from the PR's subscribers, reporting false if they weren't one.
+func (p *PRState) Unsubscribe(userID string) bool {
+	if !slices.Contains(p.Subscribers, userID) {
+		return false
+	}
+	p.Subscribers = slices.DeleteFunc(slices.Clone(p.Subscribers), func(id string) bool { return id == userID })
+	return true
+}
@@ -0,0 +1,71 @@
+package state
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DeliveryRetention is how long a delivery is remembered, well past the life of most transitions.
+const DeliveryRetention = 30 * 24 * time.Hour
+
+// Ways a user can be told a PR is waiting on them.
+const (
+	DeliveryDM     = "dm"
+	DeliveryThread = "thread"
+	DeliveryAway   = "away" // The user was away, so the thread was told instead.
+	// DeliveryDeferred means the DM was scheduled for the user's usual review hours.
+	DeliveryDeferred = "deferred"
+)
+
+// Delivery records how a user was told about one of a PR's transitions.
+type Delivery struct {
+	At  time.Time `json:"at"`
+	Via string    `json:"via"` // DeliveryDM, DeliveryThread, DeliveryAway, or DeliveryDeferred.
+}
+
+// DeliveryKey names a user's notification about the PR's current state: the user,
+// the PR, and the transition into that state, so each transition is delivered once.
+func DeliveryKey(userID string, pr *PRState) string {
+	entered := pr.LastUpdated
+	if n := len(pr.StateChanges); n > 0 && pr.StateChanges[n-1].State == pr.State {
+		entered = pr.StateChanges[n-1].At
+	}
+	return fmt.Sprintf("%s|%s|%s@%d", userID, PRKey(pr.Owner, pr.Repo, pr.Number), pr.State, entered.Unix())
+}
+
+// DailyReminderKey names a user's daily reminder for a day, given as YYYY-MM-DD in
+// their timezone, so each day's reminder is delivered once.
+func DailyReminderKey(userID, day string) string {
+	return fmt.Sprintf("%s|daily|%s", userID, day)
+}
+
+// ClaimDelivery records that key is being delivered via a channel, reporting false
+// if it was already delivered. Deliveries older than DeliveryRetention are dropped.
+func (m *Manager) ClaimDelivery(workspaceID, key, via string, at time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	if _, exists := workspace.Deliveries[key]; exists {
+		return false
+	}
+	if workspace.Deliveries == nil {
+		workspace.Deliveries = make(map[string]Delivery)
+	}
+	for k, d := range workspace.Deliveries {
+		if at.Sub(d.At) > DeliveryRetention {
+			delete(workspace.Deliveries, k)
+		}
+	}
+	workspace.Deliveries[key] = Delivery{At: at, Via: via}
+
+	m.queueSave(workspaceID)
+	return true
+}
+
+// deliveryUser returns the Slack user a delivery key belongs to.
+func deliveryUser(key string) string {
+	user, _, _ := strings.Cut(key, "|")
+	return user
+}
@@ -0,0 +1,251 @@
+package state
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+// DefaultBackups is how many previous copies of each state file are kept.
+const DefaultBackups = 3
+
+// checksumMagic starts the footer that follows the gzip stream in a state file.
+// The rest of the footer is the SHA-256 of everything before it. Files saved
+// before the footer existed are still read, relying on gzip's own CRC.
+var checksumMagic = []byte("SLKRSUM1")
+
+// footerSize is the length of a state file's checksum footer.
+const footerSize = 8 + sha256.Size
+
+// errChecksum is returned for a state file whose contents don't match its footer.
+var errChecksum = errors.New("checksum mismatch")
+
+// SetBackups sets how many previous copies of each state file are kept; zero keeps none.
+func (m *Manager) SetBackups(n int) {
+	m.backups.Store(int32(n))
+}
+
+// fileStore saves each workspace as a gzipped JSON file in a directory, keeping
+// backups of previous versions. It is the default Store.
+type fileStore struct {
+	backups *atomic.Int32 // The manager's, so SetBackups applies.
+	dir     string
+}
+
+// stateFile returns the path of a workspace's state file.
+func (s *fileStore) stateFile(workspaceID string) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s.json.gz", workspaceID))
+}
+
+// Load implements Store.
+func (s *fileStore) Load(workspaceID string) (*WorkspaceData, int64, bool) {
+	return s.loadStateFile(workspaceID)
+}
+
+// Save implements Store. The new file is synced before it replaces the old one,
+// which becomes a backup.
+func (s *fileStore) Save(workspaceID string, data *WorkspaceData) (int64, error) {
+	filename := s.stateFile(workspaceID)
+	tempFile := filename + ".tmp"
+
+	size, err := writeStateFile(tempFile, data)
+	if err != nil {
+		return 0, errors.Join(err, removeTemp(tempFile))
+	}
+
+	// Atomic rename.
+	rotateBackups(filename, int(s.backups.Load()))
+	if err := os.Rename(tempFile, filename); err != nil {
+		return 0, errors.Join(fmt.Errorf("failed to rename temp file: %w", err), removeTemp(tempFile))
+	}
+	syncDir(s.dir)
+	return size, nil
+}
+
+// Workspaces implements Store.
+func (s *fileStore) Workspaces() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list state files: %w", err)
+	}
+	var ids []string
+	for _, entry := range entries {
+		if id, ok := strings.CutSuffix(entry.Name(), ".json.gz"); ok && !entry.IsDir() {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+// removeTemp removes a temp file left by a failed save.
+func removeTemp(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove temp file: %w", err)
+	}
+	return nil
+}
+
+// backupFile returns the path of the nth most recent backup of a state file.
+func backupFile(filename string, n int) string {
+	return fmt.Sprintf("%s.%d", filename, n)
+}
+
+// writeStateFile writes workspace data as gzipped JSON with a checksum footer and
+// syncs it to disk. It returns the uncompressed size.
+func writeStateFile(path string, data *WorkspaceData) (int64, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, FileMode)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create file: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil && !errors.Is(err, os.ErrClosed) {
+			slog.Error("failed to close file", "error", err)
+		}
+	}()
+
+	hash := sha256.New()
+	gz := gzip.NewWriter(io.MultiWriter(file, hash))
+	counter := &countingWriter{w: gz}
+	if err := json.NewEncoder(counter).Encode(data); err != nil {
+		return 0, fmt.Errorf("failed to encode state data: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close gzip writer: %w", err)
+	}
+	if _, err := file.Write(append(bytes.Clone(checksumMagic), hash.Sum(nil)...)); err != nil {
+		return 0, fmt.Errorf("failed to write checksum: %w", err)
+	}
+	if err := file.Sync(); err != nil {
+		return 0, fmt.Errorf("failed to sync file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return 0, fmt.Errorf("failed to close file: %w", err)
+	}
+	return counter.n, nil
+}
+
+// readStateFile reads and verifies a state file, returning the data and its
+// uncompressed size. Truncated or corrupt files are errors.
+func readStateFile(path string) (*WorkspaceData, int64, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	body := raw
+	if n := len(raw) - footerSize; n >= 0 && bytes.Equal(raw[n:n+len(checksumMagic)], checksumMagic) {
+		body = raw[:n]
+		sum := sha256.Sum256(body)
+		if !bytes.Equal(sum[:], raw[n+len(checksumMagic):]) {
+			return nil, 0, errChecksum
+		}
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create gzip reader: %w", err)
+	}
+	var data WorkspaceData
+	counter := &countingReader{r: gz}
+	if err := json.NewDecoder(counter).Decode(&data); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode state data: %w", err)
+	}
+	// Read to the end so gzip verifies its CRC and length.
+	if _, err := io.Copy(io.Discard, counter); err != nil {
+		return nil, 0, fmt.Errorf("failed to read state data: %w", err)
+	}
+	return &data, counter.n, nil
+}
+
+// rotateBackups shifts a state file's backups down by one, making the current
+// file the most recent backup and dropping the oldest.
+func rotateBackups(filename string, backups int) {
+	if backups <= 0 {
+		return
+	}
+	for n := backups - 1; n >= 1; n-- {
+		if err := os.Rename(backupFile(filename, n), backupFile(filename, n+1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+			slog.Warn("failed to rotate state backup", "file", filename, "error", err)
+		}
+	}
+	if err := os.Rename(filename, backupFile(filename, 1)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		slog.Warn("failed to back up state file", "file", filename, "error", err)
+	}
+}
+
+// loadStateFile loads a workspace's state file, falling back to its most recent
+// good backup if the file is corrupt. A corrupt file is moved aside to a .corrupt
+// file, so it is kept for inspection and never rotated over a good backup.
+func (s *fileStore) loadStateFile(workspaceID string) (*WorkspaceData, int64, bool) {
+	filename := s.stateFile(workspaceID)
+	paths := []string{filename}
+	for n := 1; n <= int(s.backups.Load()); n++ {
+		paths = append(paths, backupFile(filename, n))
+	}
+
+	corrupt := false
+	for i, path := range paths {
+		data, size, err := readStateFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			slog.Error("state file is corrupt", "file", path, "error", err)
+			if i == 0 {
+				corrupt = true
+			}
+			continue
+		}
+		if i > 0 {
+			metrics.IncCounter("slacker_state_recovered_total", "source", "backup")
+			slog.Warn("recovered state from backup", "workspace", workspaceID, "backup", path)
+		}
+		if corrupt {
+			quarantine(filename)
+		}
+		return data, size, true
+	}
+
+	if corrupt {
+		metrics.IncCounter("slacker_state_recovered_total", "source", "none")
+		slog.Error("no usable state file or backup, starting empty", "workspace", workspaceID, "file", filename)
+		quarantine(filename)
+	}
+	return nil, 0, false
+}
+
+// quarantine moves a corrupt state file aside.
+func quarantine(filename string) {
+	if err := os.Rename(filename, filename+".corrupt"); err != nil {
+		slog.Error("failed to move corrupt state file aside", "file", filename, "error", err)
+	}
+}
+
+// syncDir syncs a directory, making renames within it durable.
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		slog.Warn("failed to open data directory for sync", "error", err)
+		return
+	}
+	defer func() {
+		if err := d.Close(); err != nil {
+			slog.Warn("failed to close data directory", "error", err)
+		}
+	}()
+	if err := d.Sync(); err != nil {
+		slog.Warn("failed to sync data directory", "error", err)
+	}
+}
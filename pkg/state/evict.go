@@ -0,0 +1,256 @@
+package state
+
+import (
+	"io"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+)
+
+// SetEviction sets when workspaces are dropped from memory. Workspaces unused for longer
+// than idle are evicted, and while the workspaces in memory exceed budget bytes, the least
+// recently used are evicted too. Zero disables either limit. Workspaces are saved before
+// eviction and reloaded from disk on next use; those with queued deliveries or reminders
+// stay in memory.
+func (m *Manager) SetEviction(idle time.Duration, budget int64) {
+	m.usage.mu.Lock()
+	defer m.usage.mu.Unlock()
+	m.usage.idle = idle
+	m.usage.budget = budget
+}
+
+// workspaceUsage tracks when each workspace was last used and roughly how much memory it holds.
+type workspaceUsage struct {
+	accessed map[string]time.Time
+	sizes    map[string]int64       // Encoded JSON size, a proxy for memory use.
+	changed  map[string]bool        // Workspaces changed since they were last saved.
+	saved    map[string]fingerprint // Each workspace as last loaded or saved, for merging after a save conflict.
+	idle     time.Duration
+	budget   int64
+	mu       sync.Mutex
+}
+
+// newWorkspaceUsage returns usage tracking with eviction disabled.
+func newWorkspaceUsage() workspaceUsage {
+	return workspaceUsage{
+		accessed: make(map[string]time.Time),
+		sizes:    make(map[string]int64),
+		changed:  make(map[string]bool),
+		saved:    make(map[string]fingerprint),
+	}
+}
+
+// touch records that a workspace was used.
+func (u *workspaceUsage) touch(workspaceID string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.accessed[workspaceID] = time.Now()
+}
+
+// seen reports whether a workspace has been in memory since startup.
+func (u *workspaceUsage) seen(workspaceID string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	_, exists := u.accessed[workspaceID]
+	return exists
+}
+
+// setSize records a workspace's encoded size.
+func (u *workspaceUsage) setSize(workspaceID string, size int64) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.sizes[workspaceID] = size
+}
+
+// workspaceLoad is a workspace being loaded from the store or saved for eviction,
+// which other callers wanting it wait for rather than reading the store themselves.
+type workspaceLoad struct {
+	done    chan struct{}
+	waiters int
+}
+
+// setChanged records whether a workspace has changed since it was last saved.
+func (u *workspaceUsage) setChanged(workspaceID string, changed bool) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if changed {
+		u.changed[workspaceID] = true
+	} else {
+		delete(u.changed, workspaceID)
+	}
+}
+
+// setSaved records a workspace's fingerprint as last loaded or saved, or forgets it
+// if print is nil.
+func (u *workspaceUsage) setSaved(workspaceID string, print fingerprint) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if print == nil {
+		delete(u.saved, workspaceID)
+	} else {
+		u.saved[workspaceID] = print
+	}
+}
+
+// lastSaved returns a workspace's fingerprint as last loaded or saved.
+func (u *workspaceUsage) lastSaved(workspaceID string) fingerprint {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.saved[workspaceID]
+}
+
+// hasChanged reports whether a workspace has changed since it was last saved.
+func (u *workspaceUsage) hasChanged(workspaceID string) bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.changed[workspaceID]
+}
+
+// residentLocked returns a workspace, loading it from the store if it isn't in memory.
+// m.mu must be held for writing; it is released while the store is read, so a slow
+// store doesn't hold up other workspaces.
+func (m *Manager) residentLocked(workspaceID string) (*WorkspaceData, bool) {
+	for {
+		if workspace, exists := m.data[workspaceID]; exists {
+			m.usage.touch(workspaceID)
+			return workspace, true
+		}
+		if load, loading := m.loading[workspaceID]; loading {
+			// Wait, then look again: it may have been created, or evicted, since.
+			load.waiters++
+			m.mu.Unlock()
+			<-load.done
+			m.mu.Lock()
+			continue
+		}
+
+		load := &workspaceLoad{done: make(chan struct{})}
+		m.loading[workspaceID] = load
+		m.mu.Unlock()
+		workspace := m.loadWorkspaceData(workspaceID)
+		m.mu.Lock()
+		delete(m.loading, workspaceID)
+		close(load.done)
+		if workspace == nil {
+			return nil, false
+		}
+		// Nobody else can have added it while it loaded; they'd have waited.
+		m.data[workspaceID] = workspace
+		m.usage.touch(workspaceID)
+		return workspace, true
+	}
+}
+
+// residentRLocked returns a workspace, loading it from the store if it isn't in memory.
+// m.mu must be held for reading; it is briefly released to load the workspace.
+func (m *Manager) residentRLocked(workspaceID string) (*WorkspaceData, bool) {
+	if workspace, exists := m.data[workspaceID]; exists {
+		m.usage.touch(workspaceID)
+		return workspace, true
+	}
+	m.mu.RUnlock()
+	m.mu.Lock()
+	workspace, exists := m.residentLocked(workspaceID)
+	m.mu.Unlock()
+	m.mu.RLock()
+	return workspace, exists
+}
+
+// evictWorkspaces saves and drops idle workspaces, then the least recently used ones
+// while memory use is over budget.
+func (m *Manager) evictWorkspaces() {
+	m.usage.mu.Lock()
+	idle, budget := m.usage.idle, m.usage.budget
+	m.usage.mu.Unlock()
+	if idle <= 0 && budget <= 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Order workspaces from least to most recently used, and total their sizes.
+	m.usage.mu.Lock()
+	ids := make([]string, 0, len(m.data))
+	var total int64
+	for id := range m.data {
+		ids = append(ids, id)
+		total += m.usage.sizes[id]
+	}
+	accessed := make(map[string]time.Time, len(ids))
+	for _, id := range ids {
+		accessed[id] = m.usage.accessed[id]
+	}
+	m.usage.mu.Unlock()
+	sort.Slice(ids, func(i, j int) bool {
+		return accessed[ids[i]].Before(accessed[ids[j]])
+	})
+
+	now := time.Now()
+	for _, id := range ids {
+		isIdle := idle > 0 && now.Sub(accessed[id]) > idle
+		overBudget := budget > 0 && total > budget
+		if !isIdle && !overBudget {
+			continue
+		}
+		workspace, exists := m.data[id]
+		if !exists || len(workspace.Outbox) > 0 || len(workspace.Reminders) > 0 {
+			continue
+		}
+		m.usage.mu.Lock()
+		used := m.usage.accessed[id].After(accessed[id])
+		m.usage.mu.Unlock()
+		if used {
+			continue // Used while an earlier workspace was saved.
+		}
+
+		// Take it out of memory while it's saved, so callers wanting it wait for the
+		// save instead of changing it unsaved, without holding the lock for the save.
+		delete(m.data, id)
+		evicting := &workspaceLoad{done: make(chan struct{})}
+		m.loading[id] = evicting
+		m.mu.Unlock()
+		err := m.writeWorkspace(id, workspace)
+		m.mu.Lock()
+		delete(m.loading, id)
+		close(evicting.done)
+		if err != nil || evicting.waiters > 0 {
+			m.data[id] = workspace
+			continue
+		}
+		m.usage.setSaved(id, nil)
+
+		m.usage.mu.Lock()
+		size := m.usage.sizes[id]
+		m.usage.mu.Unlock()
+		total -= size
+		slog.Info("evicted workspace from memory", "workspace", id, "idle", now.Sub(accessed[id]).Round(time.Second), "bytes", size)
+	}
+}
+
+// countingReader counts the bytes read through it.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+// Read implements io.Reader.
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// countingWriter counts the bytes written through it.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+// Write implements io.Writer.
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
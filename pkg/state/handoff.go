@@ -0,0 +1,78 @@
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// handoffFile is the file in the data directory a draining instance leaves for its successor.
+const handoffFile = "handoff.json"
+
+// Handoff is the event stream position a draining instance passes to the instance
+// replacing it, so a rollout neither drops nor reprocesses events.
+type Handoff struct {
+	At time.Time `json:"at"`
+	// Seen holds the delivery IDs recently processed, and when, so redeliveries are skipped.
+	Seen map[string]time.Time `json:"seen"`
+	// Pending holds the events received but not finished, in the order they arrived.
+	Pending []JournalEntry `json:"pending"`
+}
+
+// WriteHandoff durably leaves a handoff in dataDir, replacing any left before.
+func WriteHandoff(dataDir string, h *Handoff) error {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("failed to encode handoff: %w", err)
+	}
+	path := filepath.Join(dataDir, handoffFile)
+	tempFile := path + ".tmp"
+	file, err := os.OpenFile(tempFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, FileMode)
+	if err != nil {
+		return fmt.Errorf("failed to create handoff: %w", err)
+	}
+	if _, err := file.Write(data); err != nil {
+		return errors.Join(fmt.Errorf("failed to write handoff: %w", err), file.Close(), os.Remove(tempFile))
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync handoff: %w", err), file.Close(), os.Remove(tempFile))
+	}
+	if err := file.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to close handoff: %w", err), os.Remove(tempFile))
+	}
+	if err := os.Rename(tempFile, path); err != nil {
+		return errors.Join(fmt.Errorf("failed to replace handoff: %w", err), os.Remove(tempFile))
+	}
+	syncDir(dataDir)
+	return nil
+}
+
+// TakeHandoff claims the handoff left in dataDir, if any, removing it so no other
+// instance takes it too.
+func TakeHandoff(dataDir string) (*Handoff, bool, error) {
+	path := filepath.Join(dataDir, handoffFile)
+	claimed := fmt.Sprintf("%s.%d", path, os.Getpid())
+	// Renaming is atomic, so of instances racing for the handoff only one gets it.
+	if err := os.Rename(path, claimed); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("failed to claim handoff: %w", err)
+	}
+	data, err := os.ReadFile(claimed)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read handoff: %w", err)
+	}
+	if err := os.Remove(claimed); err != nil {
+		return nil, false, fmt.Errorf("failed to remove claimed handoff: %w", err)
+	}
+
+	var h Handoff
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, false, fmt.Errorf("failed to decode handoff: %w", err)
+	}
+	return &h, true, nil
+}
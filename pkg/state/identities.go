@@ -0,0 +1,133 @@
+package state
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Identity is the Slack user a GitHub login was resolved to, cached so the lookup
+// isn't repeated for every event.
+type Identity struct {
+	ResolvedAt time.Time `json:"resolved_at"`
+	SlackID    string    `json:"slack_id,omitempty"` // Empty if no Slack user was found.
+	Source     string    `json:"source,omitempty"`   // How it was resolved, such as "email".
+}
+
+// Identity returns the cached resolution of a GitHub login, if any.
+func (m *Manager) Identity(workspaceID, login string) (Identity, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return Identity{}, false
+	}
+	id, exists := workspace.Identities[strings.ToLower(login)]
+	return id, exists
+}
+
+// Identities returns every cached resolution in a workspace, by lowercased GitHub login.
+func (m *Manager) Identities(workspaceID string) map[string]Identity {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return nil
+	}
+	return maps.Clone(workspace.Identities)
+}
+
+// SetIdentity caches the resolution of a GitHub login. A login resolved to a Slack
+// user also gets the PRs blocked on it added to that user's dashboard.
+func (m *Manager) SetIdentity(workspaceID, login string, id Identity) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	if workspace.Identities == nil {
+		workspace.Identities = make(map[string]Identity)
+	}
+	workspace.Identities[strings.ToLower(login)] = id
+	if id.SlackID != "" {
+		for key, pr := range workspace.PRs {
+			if slices.ContainsFunc(pr.BlockedOn, func(l string) bool { return strings.EqualFold(l, login) }) {
+				addUserPRLocked(workspace, id.SlackID, key)
+			}
+		}
+	}
+	workspace.LastUpdated = time.Now()
+
+	m.queueSave(workspaceID)
+}
+
+// slackUserLocked returns the Slack user a GitHub login belongs to: the user who
+// linked it, or else its cached resolution (must hold lock).
+func slackUserLocked(workspace *WorkspaceData, login string) (string, bool) {
+	for id, prefs := range workspace.Users {
+		if prefs.GitHubLogin != "" && strings.EqualFold(prefs.GitHubLogin, login) {
+			return id, true
+		}
+	}
+	if id, exists := workspace.Identities[strings.ToLower(login)]; exists && id.SlackID != "" {
+		return id.SlackID, true
+	}
+	return "", false
+}
+
+// addUserPRLocked adds a PR to a user's dashboard, once (must hold lock).
+func addUserPRLocked(workspace *WorkspaceData, userID, key string) {
+	if workspace.UserPRs == nil {
+		workspace.UserPRs = make(map[string][]string)
+	}
+	if !slices.Contains(workspace.UserPRs[userID], key) {
+		workspace.UserPRs[userID] = append(workspace.UserPRs[userID], key)
+	}
+}
+
+// GitHubLoginFor returns the GitHub login a Slack user linked, or else one that was
+// resolved to them.
+func (m *Manager) GitHubLoginFor(workspaceID, userID string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return "", false
+	}
+	if login := workspace.Users[userID].GitHubLogin; login != "" {
+		return login, true
+	}
+	return resolvedLoginLocked(workspace, userID, nil)
+}
+
+// ResolvedGitHubLoginFor returns a GitHub login that was resolved to a Slack user by
+// one of sources, never the one they linked themselves.
+func (m *Manager) ResolvedGitHubLoginFor(workspaceID, userID string, sources ...string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return "", false
+	}
+	return resolvedLoginLocked(workspace, userID, sources)
+}
+
+// resolvedLoginLocked returns the first alphabetically of the logins resolved to a
+// Slack user, by one of sources if any are given (must hold lock).
+func resolvedLoginLocked(workspace *WorkspaceData, userID string, sources []string) (string, bool) {
+	var logins []string
+	for login, id := range workspace.Identities {
+		if id.SlackID == userID && (sources == nil || slices.Contains(sources, id.Source)) {
+			logins = append(logins, login)
+		}
+	}
+	if len(logins) == 0 {
+		return "", false
+	}
+	slices.Sort(logins)
+	return logins[0], true
+}
@@ -0,0 +1,223 @@
+package state
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+)
+
+// journalCompactThreshold is how many completed records accumulate before the journal is rewritten.
+const journalCompactThreshold = 1000
+
+// journalRecord is one line of the journal file.
+type journalRecord struct {
+	Op     string          `json:"op"` // "begin" or "done".
+	ID     string          `json:"id"`
+	Record json.RawMessage `json:"record,omitempty"`
+}
+
+// JournalEntry is an event that was started but never marked complete.
+type JournalEntry struct {
+	ID     string          `json:"id"`
+	Record json.RawMessage `json:"record"`
+}
+
+// Journal is a small write-ahead log of in-flight events. Events are recorded before
+// processing and marked done afterward, so incomplete events can be replayed after a crash.
+type Journal struct {
+	file      *os.File
+	pending   map[string]json.RawMessage
+	path      string
+	order     []string
+	completed int
+	mu        sync.Mutex
+}
+
+// OpenJournal opens or creates the journal at path, loading any incomplete entries.
+func OpenJournal(path string) (*Journal, error) {
+	j := &Journal{
+		path:    path,
+		pending: make(map[string]json.RawMessage),
+	}
+
+	if err := j.load(); err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, FileMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open journal: %w", err)
+	}
+	j.file = file
+
+	if len(j.pending) > 0 {
+		slog.Info("journal has incomplete events", "count", len(j.pending))
+	}
+	return j, nil
+}
+
+// load reads the journal file, tolerating a torn final line from a crash mid-write.
+func (j *Journal) load() error {
+	file, err := os.Open(j.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+	defer func() {
+		if err := file.Close(); err != nil {
+			slog.Error("failed to close journal", "error", err)
+		}
+	}()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var rec journalRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			slog.Warn("skipping unreadable journal line", "error", err)
+			continue
+		}
+		switch rec.Op {
+		case "begin":
+			if _, exists := j.pending[rec.ID]; !exists {
+				j.order = append(j.order, rec.ID)
+			}
+			j.pending[rec.ID] = rec.Record
+		case "done":
+			delete(j.pending, rec.ID)
+		default:
+			slog.Warn("unknown journal operation", "op", rec.Op)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to scan journal: %w", err)
+	}
+	return nil
+}
+
+// Begin durably records that processing of an event has started.
+func (j *Journal) Begin(id string, record json.RawMessage) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if _, exists := j.pending[id]; !exists {
+		j.order = append(j.order, id)
+	}
+	j.pending[id] = record
+	return j.appendLocked(journalRecord{Op: "begin", ID: id, Record: record}, true)
+}
+
+// Complete records that processing of an event has finished.
+func (j *Journal) Complete(id string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	delete(j.pending, id)
+	if err := j.appendLocked(journalRecord{Op: "done", ID: id}, false); err != nil {
+		return err
+	}
+
+	j.completed++
+	if j.completed >= journalCompactThreshold {
+		if err := j.compactLocked(); err != nil {
+			slog.Warn("failed to compact journal", "error", err)
+		}
+	}
+	return nil
+}
+
+// Pending returns incomplete entries in the order they were started.
+func (j *Journal) Pending() []JournalEntry {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	entries := make([]JournalEntry, 0, len(j.pending))
+	for _, id := range j.order {
+		if record, exists := j.pending[id]; exists {
+			entries = append(entries, JournalEntry{ID: id, Record: record})
+		}
+	}
+	return entries
+}
+
+// Close closes the journal file.
+func (j *Journal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.file.Close()
+}
+
+// appendLocked writes a record, syncing to disk if requested (must hold lock).
+func (j *Journal) appendLocked(rec journalRecord, sync bool) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("failed to encode journal record: %w", err)
+	}
+	if _, err := j.file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write journal: %w", err)
+	}
+	if sync {
+		if err := j.file.Sync(); err != nil {
+			return fmt.Errorf("failed to sync journal: %w", err)
+		}
+	}
+	return nil
+}
+
+// compactLocked rewrites the journal with only the pending entries (must hold lock).
+func (j *Journal) compactLocked() error {
+	tempFile := j.path + ".tmp"
+	file, err := os.OpenFile(tempFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, FileMode)
+	if err != nil {
+		return fmt.Errorf("failed to create journal temp file: %w", err)
+	}
+
+	var order []string
+	w := bufio.NewWriter(file)
+	for _, id := range j.order {
+		record, exists := j.pending[id]
+		if !exists {
+			continue
+		}
+		order = append(order, id)
+		line, err := json.Marshal(journalRecord{Op: "begin", ID: id, Record: record})
+		if err != nil {
+			return errors.Join(fmt.Errorf("failed to encode journal record: %w", err), file.Close(), os.Remove(tempFile))
+		}
+		if _, err := w.Write(append(line, '\n')); err != nil {
+			return errors.Join(fmt.Errorf("failed to write journal: %w", err), file.Close(), os.Remove(tempFile))
+		}
+	}
+	if err := w.Flush(); err != nil {
+		return errors.Join(fmt.Errorf("failed to flush journal: %w", err), file.Close(), os.Remove(tempFile))
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync journal: %w", err), file.Close(), os.Remove(tempFile))
+	}
+	if err := file.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to close journal: %w", err), os.Remove(tempFile))
+	}
+	if err := os.Rename(tempFile, j.path); err != nil {
+		return errors.Join(fmt.Errorf("failed to replace journal: %w", err), os.Remove(tempFile))
+	}
+
+	// Reopen the compacted file for appending.
+	if err := j.file.Close(); err != nil {
+		slog.Debug("failed to close old journal file", "error", err)
+	}
+	appendFile, err := os.OpenFile(j.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, FileMode)
+	if err != nil {
+		return fmt.Errorf("failed to reopen journal: %w", err)
+	}
+	j.file = appendFile
+	j.order = order
+	j.completed = 0
+	slog.Debug("compacted journal", "pending", len(order))
+	return nil
+}
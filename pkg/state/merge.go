@@ -0,0 +1,210 @@
+package state
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// fingerprint hashes each entry of a workspace as it was last loaded or saved, by
+// section and key, so that after a save conflict the entries changed here since can
+// be told apart from those another replica changed.
+type fingerprint map[string]map[string]uint64
+
+// Sections of a workspace that are merged entry by entry after a save conflict.
+const (
+	sectionPRs        = "prs"
+	sectionUsers      = "users"
+	sectionDigests    = "digests"
+	sectionMuted      = "muted"
+	sectionMilestones = "milestones"
+	sectionDeliveries = "deliveries"
+	sectionPaused     = "paused"
+	sectionIdentities = "identities"
+	sectionOutbox     = "outbox"
+	sectionReminders  = "reminders"
+	sectionReviews    = "reviews"
+)
+
+// hashEntry hashes an entry's JSON encoding.
+func hashEntry(v any) uint64 {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return 0 // Unencodable entries can't be saved either.
+	}
+	h := fnv.New64a()
+	h.Write(raw)
+	return h.Sum64()
+}
+
+// hashSection hashes each entry of one section.
+func hashSection[V any](entries map[string]V) map[string]uint64 {
+	hashes := make(map[string]uint64, len(entries))
+	for key, v := range entries {
+		hashes[key] = hashEntry(v)
+	}
+	return hashes
+}
+
+// fingerprintOf fingerprints a workspace that nothing else is changing.
+func fingerprintOf(w *WorkspaceData) fingerprint {
+	return fingerprint{
+		sectionPRs:        hashSection(w.PRs),
+		sectionUsers:      hashSection(w.Users),
+		sectionDigests:    hashSection(w.Digests),
+		sectionMuted:      hashSection(w.Muted),
+		sectionMilestones: hashSection(w.Milestones),
+		sectionDeliveries: hashSection(w.Deliveries),
+		sectionPaused:     hashSection(w.Paused),
+		sectionIdentities: hashSection(w.Identities),
+		sectionOutbox:     hashSection(outboxByID(w.Outbox)),
+		sectionReminders:  hashSection(byContent(w.Reminders)),
+		sectionReviews:    hashSection(byContent(w.Reviews)),
+	}
+}
+
+// mergeSection re-applies to theirs the entries of ours that were added, changed, or
+// removed since base. Where theirs changed the same entry differently, ours is kept
+// only if keepOurs says so. It returns the merged entries and the keys of the changes
+// made here that were dropped.
+func mergeSection[V any](theirs, ours map[string]V, base map[string]uint64, keepOurs func(ours, theirs V) bool) (map[string]V, []string) {
+	if theirs == nil {
+		theirs = make(map[string]V)
+	}
+	var dropped []string
+	for key, v := range ours {
+		hash := hashEntry(v)
+		was, existed := base[key]
+		if existed && hash == was {
+			continue // Unchanged here.
+		}
+		if other, exists := theirs[key]; exists {
+			otherHash := hashEntry(other)
+			if otherHash != hash && (!existed || otherHash != was) && !keepOurs(v, other) {
+				dropped = append(dropped, key)
+				continue
+			}
+		}
+		theirs[key] = v
+	}
+	for key, was := range base {
+		if _, exists := ours[key]; exists {
+			continue
+		}
+		// Removed here: remove it there too, unless it was changed there.
+		if other, exists := theirs[key]; exists && hashEntry(other) == was {
+			delete(theirs, key)
+		}
+	}
+	return theirs, dropped
+}
+
+// mergeWorkspace re-applies to theirs, a newer copy another replica saved, the
+// changes made to ours since base. A PR changed on both sides keeps the copy GitHub
+// updated last. It returns the keys of the changes made here that were dropped.
+func mergeWorkspace(theirs, ours *WorkspaceData, base fingerprint) []string {
+	var dropped, lost []string
+	keepNewer := func(ours, theirs time.Time) bool { return !ours.Before(theirs) }
+
+	theirs.PRs, lost = mergeSection(theirs.PRs, ours.PRs, base[sectionPRs], func(ours, theirs *PRState) bool {
+		if !ours.UpdatedAt.Equal(theirs.UpdatedAt) {
+			return ours.UpdatedAt.After(theirs.UpdatedAt)
+		}
+		return keepNewer(ours.LastUpdated, theirs.LastUpdated)
+	})
+	dropped = appendSection(dropped, sectionPRs, lost)
+	theirs.Users, lost = mergeSection(theirs.Users, ours.Users, base[sectionUsers], func(UserPreferences, UserPreferences) bool { return true })
+	dropped = appendSection(dropped, sectionUsers, lost)
+	theirs.Digests, lost = mergeSection(theirs.Digests, ours.Digests, base[sectionDigests], keepNewer)
+	dropped = appendSection(dropped, sectionDigests, lost)
+	theirs.Muted, lost = mergeSection(theirs.Muted, ours.Muted, base[sectionMuted], func(bool, bool) bool { return true })
+	dropped = appendSection(dropped, sectionMuted, lost)
+	theirs.Milestones, lost = mergeSection(theirs.Milestones, ours.Milestones, base[sectionMilestones], func(MilestoneSummary, MilestoneSummary) bool { return true })
+	dropped = appendSection(dropped, sectionMilestones, lost)
+	theirs.Deliveries, lost = mergeSection(theirs.Deliveries, ours.Deliveries, base[sectionDeliveries], func(ours, theirs Delivery) bool {
+		return keepNewer(ours.At, theirs.At)
+	})
+	dropped = appendSection(dropped, sectionDeliveries, lost)
+	theirs.Paused, lost = mergeSection(theirs.Paused, ours.Paused, base[sectionPaused], keepNewer)
+	dropped = appendSection(dropped, sectionPaused, lost)
+	theirs.Identities, lost = mergeSection(theirs.Identities, ours.Identities, base[sectionIdentities], func(ours, theirs Identity) bool {
+		return keepNewer(ours.ResolvedAt, theirs.ResolvedAt)
+	})
+	dropped = appendSection(dropped, sectionIdentities, lost)
+
+	outbox, lost := mergeSection(outboxByID(theirs.Outbox), outboxByID(ours.Outbox), base[sectionOutbox], func(ours, theirs OutboxItem) bool {
+		return ours.Attempts >= theirs.Attempts
+	})
+	dropped = appendSection(dropped, sectionOutbox, lost)
+	theirs.Outbox = inOrder(theirs.Outbox, ours.Outbox, outbox, func(item OutboxItem) string { return item.ID })
+	// Reminders and reviews are keyed by content, so are only ever added or removed.
+	reminders, _ := mergeSection(byContent(theirs.Reminders), byContent(ours.Reminders), base[sectionReminders], func(Reminder, Reminder) bool { return true })
+	theirs.Reminders = inOrder(theirs.Reminders, ours.Reminders, reminders, contentKey[Reminder])
+	reviews, _ := mergeSection(byContent(theirs.Reviews), byContent(ours.Reviews), base[sectionReviews], func(ReviewRecord, ReviewRecord) bool { return true })
+	theirs.Reviews = inOrder(theirs.Reviews, ours.Reviews, reviews, contentKey[ReviewRecord])
+
+	// Dashboards only grow between prunes, so take both sides' PRs.
+	if theirs.UserPRs == nil {
+		theirs.UserPRs = make(map[string][]string)
+	}
+	for userID, keys := range ours.UserPRs {
+		for _, key := range keys {
+			if _, exists := theirs.PRs[key]; exists {
+				addUserPRLocked(theirs, userID, key)
+			}
+		}
+	}
+	rebuildThreadIndex(theirs)
+	if ours.LastUpdated.After(theirs.LastUpdated) {
+		theirs.LastUpdated = ours.LastUpdated
+	}
+	return dropped
+}
+
+// appendSection appends keys of a section to dropped, qualified by the section.
+func appendSection(dropped []string, section string, keys []string) []string {
+	for _, key := range keys {
+		dropped = append(dropped, section+"/"+key)
+	}
+	return dropped
+}
+
+// outboxByID indexes outbox items by ID.
+func outboxByID(items []OutboxItem) map[string]OutboxItem {
+	byID := make(map[string]OutboxItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID
+}
+
+// contentKey keys an entry by the hash of its content.
+func contentKey[V any](v V) string {
+	return strconv.FormatUint(hashEntry(v), 36)
+}
+
+// byContent indexes entries by the hash of their content.
+func byContent[V any](entries []V) map[string]V {
+	byKey := make(map[string]V, len(entries))
+	for _, v := range entries {
+		byKey[contentKey(v)] = v
+	}
+	return byKey
+}
+
+// inOrder lists merged entries in theirs' order, followed by those only ours has in
+// ours' order.
+func inOrder[V any](theirs, ours []V, merged map[string]V, key func(V) string) []V {
+	out := make([]V, 0, len(merged))
+	seen := make(map[string]bool, len(merged))
+	for _, v := range slices.Concat(theirs, ours) {
+		k := key(v)
+		if merged, exists := merged[k]; exists && !seen[k] {
+			seen[k] = true
+			out = append(out, merged)
+		}
+	}
+	return out
+}
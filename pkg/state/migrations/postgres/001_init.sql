@@ -0,0 +1,35 @@
+-- Workspaces, their PRs, their users' preferences, and the index of the PRs on each
+-- user's dashboard. Everything else a workspace holds is kept in workspaces.data.
+CREATE TABLE workspaces (
+    id         TEXT PRIMARY KEY,
+    data       JSONB NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE prs (
+    workspace_id TEXT NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    pr_key       TEXT NOT NULL,
+    owner        TEXT NOT NULL,
+    repo         TEXT NOT NULL,
+    number       INTEGER NOT NULL,
+    state        TEXT NOT NULL,
+    data         JSONB NOT NULL,
+    PRIMARY KEY (workspace_id, pr_key)
+);
+
+CREATE TABLE user_prefs (
+    workspace_id TEXT NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    user_id      TEXT NOT NULL,
+    data         JSONB NOT NULL,
+    PRIMARY KEY (workspace_id, user_id)
+);
+
+CREATE TABLE user_prs (
+    workspace_id TEXT NOT NULL REFERENCES workspaces (id) ON DELETE CASCADE,
+    user_id      TEXT NOT NULL,
+    pr_key       TEXT NOT NULL,
+    position     INTEGER NOT NULL,
+    PRIMARY KEY (workspace_id, user_id, pr_key)
+);
+
+CREATE INDEX user_prs_by_pr ON user_prs (workspace_id, pr_key);
@@ -0,0 +1,3 @@
+-- Each save bumps a workspace's version and only applies if the version is the one
+-- the replica saving it last loaded or saved, so replicas don't overwrite each other.
+ALTER TABLE workspaces ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,3 @@
+-- Each save bumps a workspace's version and only applies if the version is the one
+-- last loaded or saved, matching the Postgres store.
+ALTER TABLE workspaces ADD COLUMN version INTEGER NOT NULL DEFAULT 1;
@@ -0,0 +1,35 @@
+package state
+
+// MilestoneSummary is a pinned channel message summarizing a milestone's progress.
+type MilestoneSummary struct {
+	ChannelID string `json:"channel_id"`
+	TS        string `json:"ts"`
+	Hash      string `json:"hash"` // Hash of the last posted text.
+}
+
+// GetMilestoneSummary returns the summary message recorded under key.
+func (m *Manager) GetMilestoneSummary(workspaceID, key string) (MilestoneSummary, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return MilestoneSummary{}, false
+	}
+	summary, exists := workspace.Milestones[key]
+	return summary, exists
+}
+
+// SetMilestoneSummary records the summary message for key.
+func (m *Manager) SetMilestoneSummary(workspaceID, key string, summary MilestoneSummary) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	if workspace.Milestones == nil {
+		workspace.Milestones = make(map[string]MilestoneSummary)
+	}
+	workspace.Milestones[key] = summary
+
+	m.queueSave(workspaceID)
+}
@@ -0,0 +1,116 @@
+package state
+
+import (
+	"log/slog"
+	"strconv"
+	"time"
+)
+
+// OutboxItem is a Slack delivery that failed and is waiting to be retried.
+type OutboxItem struct {
+	CreatedAt   time.Time `json:"created_at"`
+	NextAttempt time.Time `json:"next_attempt"`
+	ID          string    `json:"id"`
+	Kind        string    `json:"kind"` // "dm" or "thread_reply".
+	ChannelID   string    `json:"channel_id,omitempty"`
+	ThreadTS    string    `json:"thread_ts,omitempty"`
+	UserID      string    `json:"user_id,omitempty"`
+	Text        string    `json:"text"`
+	LastError   string    `json:"last_error"`
+	Attempts    int       `json:"attempts"`
+	Dead        bool      `json:"dead"` // Dead-lettered after exhausting retries.
+}
+
+// EnqueueOutbox persists a failed delivery for later retry.
+func (m *Manager) EnqueueOutbox(workspaceID string, item OutboxItem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if item.ID == "" {
+		item.ID = strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = time.Now()
+	}
+
+	workspace := m.ensureWorkspace(workspaceID)
+	workspace.Outbox = append(workspace.Outbox, item)
+
+	m.queueSave(workspaceID)
+}
+
+// ListOutbox returns a copy of the pending and dead-lettered deliveries for a workspace.
+func (m *Manager) ListOutbox(workspaceID string) []OutboxItem {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return nil
+	}
+	items := make([]OutboxItem, len(workspace.Outbox))
+	copy(items, workspace.Outbox)
+	return items
+}
+
+// UpdateOutboxItem replaces the stored item with the same ID.
+func (m *Manager) UpdateOutboxItem(workspaceID string, item OutboxItem) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace, exists := m.residentLocked(workspaceID)
+	if !exists {
+		return
+	}
+	for i := range workspace.Outbox {
+		if workspace.Outbox[i].ID == item.ID {
+			workspace.Outbox[i] = item
+			break
+		}
+	}
+
+	m.queueSave(workspaceID)
+}
+
+// RemoveOutboxItem deletes a delivery from the outbox.
+func (m *Manager) RemoveOutboxItem(workspaceID, id string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace, exists := m.residentLocked(workspaceID)
+	if !exists {
+		return
+	}
+	for i := range workspace.Outbox {
+		if workspace.Outbox[i].ID == id {
+			workspace.Outbox = append(workspace.Outbox[:i], workspace.Outbox[i+1:]...)
+			break
+		}
+	}
+
+	m.queueSave(workspaceID)
+}
+
+// Workspaces returns the IDs of the workspaces in memory, first loading those found only
+// in the store that haven't been loaded yet. Evicted workspaces are left out; they have no
+// queued deliveries or reminders.
+func (m *Manager) Workspaces() []string {
+	saved, err := m.store.Workspaces()
+	if err != nil {
+		slog.Warn("failed to list saved workspaces", "error", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, id := range saved {
+		if !m.usage.seen(id) {
+			m.ensureWorkspace(id)
+		}
+	}
+
+	ids := make([]string, 0, len(m.data))
+	for id := range m.data {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -0,0 +1,72 @@
+package state
+
+import (
+	"maps"
+	"time"
+)
+
+// AllOrgs pauses posting for every org routed to a workspace.
+const AllOrgs = "*"
+
+// Pause stops posting for an org, or for AllOrgs, from at until it is resumed. It
+// reports false if posting was already paused for it.
+func (m *Manager) Pause(workspaceID, org string, at time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	if _, exists := workspace.Paused[org]; exists {
+		return false
+	}
+	if workspace.Paused == nil {
+		workspace.Paused = make(map[string]time.Time)
+	}
+	workspace.Paused[org] = at
+	workspace.LastUpdated = time.Now()
+
+	m.queueSave(workspaceID)
+	return true
+}
+
+// Resume lifts a pause set by Pause, returning when it began and false if there was none.
+func (m *Manager) Resume(workspaceID, org string) (time.Time, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	since, exists := workspace.Paused[org]
+	if !exists {
+		return time.Time{}, false
+	}
+	delete(workspace.Paused, org)
+	workspace.LastUpdated = time.Now()
+
+	m.queueSave(workspaceID)
+	return since, true
+}
+
+// Paused reports whether posting is paused for an org, by itself or with all the workspace's orgs.
+func (m *Manager) Paused(workspaceID, org string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists || len(workspace.Paused) == 0 {
+		return false
+	}
+	_, orgPaused := workspace.Paused[org]
+	_, allPaused := workspace.Paused[AllOrgs]
+	return orgPaused || allPaused
+}
+
+// Pauses returns when each of a workspace's pauses began, by org or AllOrgs.
+func (m *Manager) Pauses(workspaceID string) map[string]time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return nil
+	}
+	return maps.Clone(workspace.Paused)
+}
@@ -0,0 +1,65 @@
+package state
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const (
+	// DirMode is the mode the data directory is created with.
+	DirMode os.FileMode = 0o700
+	// FileMode is the mode state files are created with.
+	FileMode os.FileMode = 0o600
+)
+
+// PrepareDataDir creates the data directory if needed and checks that other users
+// can't modify the state in it. A group or world writable directory is an error
+// unless allowShared is set.
+func PrepareDataDir(dataDir string, allowShared bool) error {
+	if err := os.MkdirAll(dataDir, DirMode); err != nil {
+		return fmt.Errorf("failed to create data directory: %w", err)
+	}
+	info, err := os.Stat(dataDir)
+	if err != nil {
+		return fmt.Errorf("failed to stat data directory: %w", err)
+	}
+
+	mode := info.Mode().Perm()
+	if mode&0o022 != 0 {
+		if !allowShared {
+			return fmt.Errorf("data directory %s is group or world writable (mode %#o); run chmod 700 on it", dataDir, mode)
+		}
+		slog.Warn("data directory is group or world writable", "dir", dataDir, "mode", fmt.Sprintf("%#o", mode))
+	} else if mode&0o044 != 0 {
+		slog.Warn("data directory is readable by other users", "dir", dataDir, "mode", fmt.Sprintf("%#o", mode))
+	}
+	return nil
+}
+
+// CheckStorage writes a probe file to the data directory, reads it back, and removes
+// it, reporting whether state can be persisted.
+func (m *Manager) CheckStorage() error {
+	probe := filepath.Join(m.dataDir, ".selftest")
+	want := []byte(time.Now().Format(time.RFC3339Nano))
+	if err := os.WriteFile(probe, want, FileMode); err != nil {
+		return fmt.Errorf("failed to write to data directory: %w", err)
+	}
+	defer func() {
+		if err := os.Remove(probe); err != nil {
+			slog.Warn("failed to remove storage probe", "path", probe, "error", err)
+		}
+	}()
+	got, err := os.ReadFile(probe)
+	if err != nil {
+		return fmt.Errorf("failed to read from data directory: %w", err)
+	}
+	if !bytes.Equal(got, want) {
+		return errors.New("data directory returned different contents than were written")
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package state
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// DefaultPostgresDriver is the database/sql driver name the Postgres store uses
+// unless told otherwise; it's the name pgx registers.
+const DefaultPostgresDriver = "pgx"
+
+// migrationLock is the advisory lock held while migrating, so replicas starting
+// together apply each migration once.
+const migrationLock = 0x736c6b72 // "slkr"
+
+// PostgresStore saves workspaces in Postgres, so replicas can share state without a
+// shared disk. Each save replaces the workspace's rows in one transaction, unless
+// another replica saved the workspace first.
+type PostgresStore struct {
+	*sqlStore
+}
+
+// OpenPostgres connects to the Postgres database at dsn through the named
+// database/sql driver, which the binary must register, and applies any pending
+// migrations.
+func OpenPostgres(ctx context.Context, driver, dsn string) (*PostgresStore, error) {
+	if driver == "" {
+		driver = DefaultPostgresDriver
+	}
+	store, err := openSQL(ctx, driver, dsn, "$1")
+	if err != nil {
+		return nil, err
+	}
+	s := &PostgresStore{sqlStore: store}
+	if err := s.migrate(ctx); err != nil {
+		return nil, errors.Join(err, s.Close())
+	}
+	return s, nil
+}
+
+// migrate applies pending migrations while holding the migration lock.
+func (s *PostgresStore) migrate(ctx context.Context) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migrations: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Warn("failed to release migration connection", "error", err)
+		}
+	}()
+	if _, err := conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", migrationLock); err != nil {
+		return fmt.Errorf("failed to take migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.WithoutCancel(ctx), "SELECT pg_advisory_unlock($1)", migrationLock); err != nil {
+			slog.Warn("failed to release migration lock", "error", err)
+		}
+	}()
+	return s.sqlStore.migrate(ctx, conn, "postgres")
+}
+
+// Save implements Store. The workspace's PRs, preferences, and user↔PR index are
+// replaced along with the rest of its data, in one transaction, which fails with
+// ErrSaveConflict if the workspace's version isn't the one last loaded or saved here.
+func (s *PostgresStore) Save(workspaceID string, data *WorkspaceData) (int64, error) {
+	if s.loadFailed(workspaceID) {
+		return 0, errors.New("workspace failed to load; not saving over it")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), databaseTimeout)
+	defer cancel()
+
+	workspace, err := encodeWorkspace(data)
+	if err != nil {
+		return 0, err
+	}
+	prs, err := json.Marshal(data.PRs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode PRs: %w", err)
+	}
+	users, err := json.Marshal(data.Users)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode preferences: %w", err)
+	}
+	index, err := json.Marshal(data.UserPRs)
+	if err != nil {
+		return 0, fmt.Errorf("failed to encode user PR index: %w", err)
+	}
+
+	// JSON is passed as text, since drivers send []byte as bytea.
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin save: %w", err)
+	}
+	// Write the workspace row only if no other replica saved it since it was loaded or
+	// saved here. The row lock this takes holds off other saves until commit.
+	version := s.version(workspaceID)
+	query := `INSERT INTO workspaces (id, data, updated_at, version) VALUES ($1, $2, now(), 1)
+		ON CONFLICT (id) DO NOTHING`
+	args := []any{workspaceID, string(workspace)}
+	if version > 0 {
+		query = `UPDATE workspaces SET data = $2, updated_at = now(), version = version + 1
+			WHERE id = $1 AND version = $3`
+		args = append(args, version)
+	}
+	if err := versionWritten(tx.ExecContext(ctx, query, args...)); err != nil {
+		return 0, errors.Join(fmt.Errorf("failed to save workspace: %w", err), tx.Rollback())
+	}
+	for _, statement := range []struct {
+		query string
+		args  []any
+	}{
+		{"DELETE FROM prs WHERE workspace_id = $1", []any{workspaceID}},
+		{"DELETE FROM user_prefs WHERE workspace_id = $1", []any{workspaceID}},
+		{"DELETE FROM user_prs WHERE workspace_id = $1", []any{workspaceID}},
+		{`INSERT INTO prs (workspace_id, pr_key, owner, repo, number, state, data)
+			SELECT $1, p.key, p.value->>'owner', p.value->>'repo', (p.value->>'number')::integer, p.value->>'state', p.value
+			FROM jsonb_each($2::jsonb) AS p`, []any{workspaceID, string(prs)}},
+		{`INSERT INTO user_prefs (workspace_id, user_id, data)
+			SELECT $1, u.key, u.value FROM jsonb_each($2::jsonb) AS u`, []any{workspaceID, string(users)}},
+		{`INSERT INTO user_prs (workspace_id, user_id, pr_key, position)
+			SELECT $1, u.key, k.value, k.position
+			FROM jsonb_each($2::jsonb) AS u, jsonb_array_elements_text(u.value) WITH ORDINALITY AS k (value, position)
+			ON CONFLICT DO NOTHING`, []any{workspaceID, string(index)}},
+	} {
+		if _, err := tx.ExecContext(ctx, statement.query, statement.args...); err != nil {
+			return 0, errors.Join(fmt.Errorf("failed to save workspace: %w", err), tx.Rollback())
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit save: %w", err)
+	}
+	s.setVersion(workspaceID, version+1)
+	return int64(len(workspace) + len(prs) + len(users) + len(index)), nil
+}
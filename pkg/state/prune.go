@@ -0,0 +1,121 @@
+package state
+
+import (
+	"log/slog"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+// pruneLimits caps how many PRs a workspace tracks and for how long.
+type pruneLimits struct {
+	maxPRs int
+	maxAge time.Duration
+}
+
+// SetLimits caps the PRs tracked per workspace. PRs without activity for longer than
+// maxAge are dropped, and while a workspace tracks more than maxPRs, the PRs with the
+// oldest activity are dropped, closed ones first. Zero disables either limit.
+func (m *Manager) SetLimits(maxPRs int, maxAge time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.limits = pruneLimits{maxPRs: maxPRs, maxAge: maxAge}
+}
+
+// lastActivity returns when a PR last changed, on GitHub or in the bot.
+func (pr *PRState) lastActivity() time.Time {
+	if pr.LastUpdated.After(pr.UpdatedAt) {
+		return pr.LastUpdated
+	}
+	return pr.UpdatedAt
+}
+
+// closed reports whether a PR is merged or closed.
+func (pr *PRState) closed() bool {
+	return pr.State == "pray" || pr.State == "face_palm"
+}
+
+// pruneWorkspaces applies the PR limits to the workspaces in memory and reports their sizes.
+func (m *Manager) pruneWorkspaces() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	for id, workspace := range m.data {
+		if pruned := m.pruneLocked(workspace, now); pruned > 0 {
+			workspace.LastUpdated = now
+			m.queueSave(id)
+		}
+
+		m.usage.mu.Lock()
+		size := m.usage.sizes[id]
+		m.usage.mu.Unlock()
+		metrics.SetGauge("slacker_state_prs", float64(len(workspace.PRs)), "workspace", id)
+		metrics.SetGauge("slacker_state_users", float64(len(workspace.Users)), "workspace", id)
+		metrics.SetGauge("slacker_state_bytes", float64(size), "workspace", id)
+	}
+}
+
+// pruneLocked drops a workspace's PRs past the limits, returning how many were dropped (must hold lock).
+func (m *Manager) pruneLocked(workspace *WorkspaceData, now time.Time) int {
+	limits := m.limits
+	if limits.maxPRs <= 0 && limits.maxAge <= 0 {
+		return 0
+	}
+
+	// Order PRs for dropping: closed before open, then oldest activity first.
+	keys := make([]string, 0, len(workspace.PRs))
+	for key := range workspace.PRs {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := workspace.PRs[keys[i]], workspace.PRs[keys[j]]
+		if a.closed() != b.closed() {
+			return a.closed()
+		}
+		return a.lastActivity().Before(b.lastActivity())
+	})
+
+	pruned := 0
+	for _, key := range keys {
+		pr := workspace.PRs[key]
+		reason := ""
+		switch {
+		case limits.maxAge > 0 && now.Sub(pr.lastActivity()) > limits.maxAge:
+			reason = "age"
+		case limits.maxPRs > 0 && len(workspace.PRs) > limits.maxPRs:
+			reason = "cap"
+		default:
+			continue
+		}
+		removePRLocked(workspace, key)
+		pruned++
+		metrics.IncCounter("slacker_state_pruned_total", "reason", reason)
+	}
+	if pruned > 0 {
+		slog.Info("pruned tracked PRs", "workspace", workspace.WorkspaceID, "pruned", pruned, "remaining", len(workspace.PRs),
+			"max_prs", limits.maxPRs, "max_age", limits.maxAge)
+	}
+	return pruned
+}
+
+// removePRLocked drops a PR and every reference to it (must hold lock).
+func removePRLocked(workspace *WorkspaceData, key string) {
+	if pr, exists := workspace.PRs[key]; exists {
+		unindexThreadLocked(workspace, pr)
+	}
+	delete(workspace.PRs, key)
+	for userID, keys := range workspace.UserPRs {
+		if !slices.Contains(keys, key) {
+			continue
+		}
+		keys = slices.DeleteFunc(keys, func(k string) bool { return k == key })
+		if len(keys) == 0 {
+			delete(workspace.UserPRs, userID)
+		} else {
+			workspace.UserPRs[userID] = keys
+		}
+	}
+}
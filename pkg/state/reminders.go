@@ -0,0 +1,58 @@
+package state
+
+import "time"
+
+// Reminder is a DM scheduled by a user about a PR.
+type Reminder struct {
+	Due    time.Time `json:"due"`
+	UserID string    `json:"user_id"`
+	PRKey  string    `json:"pr_key"` // owner/repo#number.
+	Text   string    `json:"text"`
+	// State, if set, is the state the PR must still be in for the reminder to be sent.
+	State string `json:"state,omitempty"`
+}
+
+// AddReminder schedules a reminder.
+func (m *Manager) AddReminder(workspaceID string, r Reminder) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	workspace.Reminders = append(workspace.Reminders, r)
+
+	m.queueSave(workspaceID)
+}
+
+// TakeDueReminders removes and returns the reminders due at or before now. Due
+// reminders tied to a state the PR has since left are removed without being returned.
+func (m *Manager) TakeDueReminders(workspaceID string, now time.Time) []Reminder {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace, exists := m.residentLocked(workspaceID)
+	if !exists {
+		return nil
+	}
+
+	var due []Reminder
+	dropped := false
+	remaining := workspace.Reminders[:0]
+	for _, r := range workspace.Reminders {
+		if r.Due.After(now) {
+			remaining = append(remaining, r)
+			continue
+		}
+		if pr, exists := workspace.PRs[r.PRKey]; r.State != "" && (!exists || pr.State != r.State) {
+			dropped = true
+			continue
+		}
+		due = append(due, r)
+	}
+	if len(due) == 0 && !dropped {
+		return nil
+	}
+	workspace.Reminders = remaining
+
+	m.queueSave(workspaceID)
+	return due
+}
@@ -0,0 +1,108 @@
+package state
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PRKey is the key a PR is stored under: owner/repo#number, lowercased, since
+// GitHub treats owner and repo names case-insensitively.
+func PRKey(owner, repo string, number int) string {
+	return strings.ToLower(owner+"/"+repo) + "#" + strconv.Itoa(number)
+}
+
+// normalizeKeysLocked moves PRs stored under keys of another case, from state saved
+// before keys were lowercased, to their PRKey. Of two PRs that now share a key, the
+// more recently updated is kept. It reports whether anything moved (must hold lock).
+func normalizeKeysLocked(workspace *WorkspaceData) bool {
+	moved := make(map[string]string)
+	for key, pr := range workspace.PRs {
+		normalized := PRKey(pr.Owner, pr.Repo, pr.Number)
+		if key == normalized {
+			continue
+		}
+		moved[key] = normalized
+		delete(workspace.PRs, key)
+		if existing, exists := workspace.PRs[normalized]; exists && existing.LastUpdated.After(pr.LastUpdated) {
+			continue
+		}
+		workspace.PRs[normalized] = pr
+	}
+	if len(moved) == 0 {
+		return false
+	}
+	rekeyUserPRsLocked(workspace, moved)
+	rebuildThreadIndex(workspace)
+	return true
+}
+
+// rekeyUserPRsLocked replaces the PR keys on users' dashboards that moved, by their
+// old key, without duplicates (must hold lock).
+func rekeyUserPRsLocked(workspace *WorkspaceData, moved map[string]string) {
+	for userID, keys := range workspace.UserPRs {
+		var rekeyed []string
+		for _, key := range keys {
+			if to, exists := moved[key]; exists {
+				key = to
+			}
+			if !slices.Contains(rekeyed, key) {
+				rekeyed = append(rekeyed, key)
+			}
+		}
+		workspace.UserPRs[userID] = rekeyed
+	}
+}
+
+// RenameRepo moves everything stored about a repo's PRs to its new owner and name,
+// after the repo is renamed or transferred on GitHub, so its PRs keep their threads.
+// It returns copies of the PRs that moved.
+func (m *Manager) RenameRepo(workspaceID, oldOwner, oldRepo, newOwner, newRepo string) []*PRState {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace, exists := m.residentLocked(workspaceID)
+	if !exists {
+		return nil
+	}
+
+	oldName := strings.ToLower(oldOwner + "/" + oldRepo)
+	moved := make(map[string]string)
+	var prs []*PRState
+	for _, key := range slices.Collect(maps.Keys(workspace.PRs)) {
+		pr := workspace.PRs[key]
+		if strings.ToLower(pr.Owner+"/"+pr.Repo) != oldName {
+			continue
+		}
+		unindexThreadLocked(workspace, pr)
+		delete(workspace.PRs, key)
+		pr.Owner, pr.Repo = newOwner, newRepo
+		newKey := PRKey(newOwner, newRepo, pr.Number)
+		workspace.PRs[newKey] = pr
+		indexThreadLocked(workspace, newKey, pr)
+		moved[key] = newKey
+		prs = append(prs, pr.Clone())
+	}
+	rekeyUserPRsLocked(workspace, moved)
+
+	// Milestone summaries are keyed owner/repo:milestone:channel.
+	for _, key := range slices.Collect(maps.Keys(workspace.Milestones)) {
+		name, rest, found := strings.Cut(key, ":")
+		if found && strings.EqualFold(name, oldName) {
+			summary := workspace.Milestones[key]
+			delete(workspace.Milestones, key)
+			workspace.Milestones[newOwner+"/"+newRepo+":"+rest] = summary
+		}
+	}
+	for i, r := range workspace.Reviews {
+		if strings.EqualFold(r.Owner+"/"+r.Repo, oldName) {
+			workspace.Reviews[i].Owner, workspace.Reviews[i].Repo = newOwner, newRepo
+		}
+	}
+	workspace.LastUpdated = time.Now()
+
+	m.queueSave(workspaceID)
+	return prs
+}
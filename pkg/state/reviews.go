@@ -0,0 +1,56 @@
+package state
+
+import (
+	"strings"
+	"time"
+)
+
+// ReviewRetention is how long submitted reviews are kept for reviewer stats.
+const ReviewRetention = 30 * 24 * time.Hour
+
+// ReviewRecord is a review submitted on a tracked PR.
+type ReviewRecord struct {
+	At       time.Time     `json:"at"`
+	Owner    string        `json:"owner"`
+	Repo     string        `json:"repo"`
+	Reviewer string        `json:"reviewer"` // GitHub login.
+	Response time.Duration `json:"response"` // Time the PR waited for review, or zero if unknown.
+	Number   int           `json:"number"`
+}
+
+// AddReview records a submitted review, dropping reviews older than ReviewRetention.
+func (m *Manager) AddReview(workspaceID string, review ReviewRecord) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	cutoff := time.Now().Add(-ReviewRetention)
+	kept := workspace.Reviews[:0]
+	for _, r := range workspace.Reviews {
+		if r.At.After(cutoff) {
+			kept = append(kept, r)
+		}
+	}
+	workspace.Reviews = append(kept, review)
+	workspace.LastUpdated = time.Now()
+
+	m.queueSave(workspaceID)
+}
+
+// ReviewTimes returns when a GitHub login submitted the reviews recorded in a workspace.
+func (m *Manager) ReviewTimes(workspaceID, login string) []time.Time {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return nil
+	}
+	var times []time.Time
+	for _, r := range workspace.Reviews {
+		if strings.EqualFold(r.Reviewer, login) {
+			times = append(times, r.At)
+		}
+	}
+	return times
+}
@@ -0,0 +1,121 @@
+package state
+
+import (
+	"maps"
+	"slices"
+	"time"
+)
+
+// Snapshot is a consistent, deep-copied view of one workspace's state. It shares
+// nothing with the stored state, so readers may hold and range over it without
+// locking, and changes made to it are never saved.
+type Snapshot struct {
+	Taken   time.Time
+	Users   map[string]UserPreferences
+	PRs     map[string]*PRState
+	UserPRs map[string][]string
+	Reviews []ReviewRecord
+}
+
+// Snapshot returns a copy of a workspace's users, PRs, and reviews as of now.
+// An unknown workspace yields an empty snapshot.
+func (m *Manager) Snapshot(workspaceID string) *Snapshot {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snap := &Snapshot{
+		Taken:   time.Now(),
+		Users:   make(map[string]UserPreferences),
+		PRs:     make(map[string]*PRState),
+		UserPRs: make(map[string][]string),
+	}
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return snap
+	}
+
+	maps.Copy(snap.Users, workspace.Users)
+	for key, pr := range workspace.PRs {
+		snap.PRs[key] = pr.Clone()
+	}
+	for userID, keys := range workspace.UserPRs {
+		snap.UserPRs[userID] = slices.Clone(keys)
+	}
+	snap.Reviews = slices.Clone(workspace.Reviews)
+	return snap
+}
+
+// ListPRs returns the snapshot's PRs.
+func (s *Snapshot) ListPRs() []*PRState {
+	prs := make([]*PRState, 0, len(s.PRs))
+	for _, pr := range s.PRs {
+		prs = append(prs, pr)
+	}
+	return prs
+}
+
+// UserPRsFor returns the snapshot's PRs associated with a user.
+func (s *Snapshot) UserPRsFor(userID string) []*PRState {
+	var prs []*PRState
+	for _, key := range s.UserPRs[userID] {
+		if pr, ok := s.PRs[key]; ok {
+			prs = append(prs, pr)
+		}
+	}
+	return prs
+}
+
+// ReviewsFor returns the snapshot's reviews on an org's PRs since a time.
+func (s *Snapshot) ReviewsFor(org string, since time.Time) []ReviewRecord {
+	var reviews []ReviewRecord
+	for _, r := range s.Reviews {
+		if r.Owner == org && r.At.After(since) {
+			reviews = append(reviews, r)
+		}
+	}
+	return reviews
+}
+
+// Clone returns a deep copy of the PR state.
+func (pr *PRState) Clone() *PRState {
+	out := *pr
+	out.BlockedOn = slices.Clone(pr.BlockedOn)
+	out.Reviewers = slices.Clone(pr.Reviewers)
+	out.ChangesRequestedBy = slices.Clone(pr.ChangesRequestedBy)
+	out.Uncertain = slices.Clone(pr.Uncertain)
+	out.Subscribers = slices.Clone(pr.Subscribers)
+	out.DeferredBy = slices.Clone(pr.DeferredBy)
+	out.StateChanges = slices.Clone(pr.StateChanges)
+	for i := range out.StateChanges {
+		out.StateChanges[i].BlockedOn = slices.Clone(out.StateChanges[i].BlockedOn)
+	}
+	out.ThreadHashes = maps.Clone(pr.ThreadHashes)
+	out.ThreadReplies = slices.Clone(pr.ThreadReplies)
+	return &out
+}
+
+// clone returns a deep copy of the workspace, for a store to encode while the
+// original goes on changing.
+func (w *WorkspaceData) clone() *WorkspaceData {
+	out := *w
+	out.Users = maps.Clone(w.Users)
+	out.PRs = make(map[string]*PRState, len(w.PRs))
+	for key, pr := range w.PRs {
+		out.PRs[key] = pr.Clone()
+	}
+	out.UserPRs = make(map[string][]string, len(w.UserPRs))
+	for userID, keys := range w.UserPRs {
+		out.UserPRs[userID] = slices.Clone(keys)
+	}
+	out.Digests = maps.Clone(w.Digests)
+	out.Muted = maps.Clone(w.Muted)
+	out.Threads = maps.Clone(w.Threads)
+	out.Milestones = maps.Clone(w.Milestones)
+	out.Outbox = slices.Clone(w.Outbox)
+	out.Reminders = slices.Clone(w.Reminders)
+	out.Reviews = slices.Clone(w.Reviews)
+	out.Deliveries = maps.Clone(w.Deliveries)
+	out.Paused = maps.Clone(w.Paused)
+	out.Identities = maps.Clone(w.Identities)
+	return &out
+}
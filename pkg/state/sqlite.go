@@ -0,0 +1,204 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// DefaultSQLiteDriver is the database/sql driver name the SQLite store uses unless
+// told otherwise; it's the name github.com/mattn/go-sqlite3 registers.
+const DefaultSQLiteDriver = "sqlite3"
+
+// SQLiteStore saves workspaces in a SQLite database file, for single-node installs
+// that want durable, queryable state without running a database server. Each save
+// replaces the workspace's rows in one transaction.
+type SQLiteStore struct {
+	*sqlStore
+}
+
+// OpenSQLite opens the SQLite database at path, creating it if needed, through the
+// named database/sql driver, which the binary must register, and applies any pending
+// migrations.
+func OpenSQLite(ctx context.Context, driver, path string) (*SQLiteStore, error) {
+	if driver == "" {
+		driver = DefaultSQLiteDriver
+	}
+	// Create the file first, so SQLite doesn't create it readable by other users.
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, FileMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create database file: %w", err)
+	}
+
+	store, err := openSQL(ctx, driver, path, "?")
+	if err != nil {
+		return nil, err
+	}
+	s := &SQLiteStore{sqlStore: store}
+	// SQLite allows one writer at a time; one connection saves waiting on a busy database.
+	s.db.SetMaxOpenConns(1)
+	for _, pragma := range []string{"PRAGMA journal_mode = WAL", "PRAGMA busy_timeout = 5000"} {
+		if _, err := s.db.ExecContext(ctx, pragma); err != nil {
+			return nil, errors.Join(fmt.Errorf("failed to configure database: %w", err), s.Close())
+		}
+	}
+	if err := s.migrate(ctx); err != nil {
+		return nil, errors.Join(err, s.Close())
+	}
+	return s, nil
+}
+
+// migrate applies pending migrations.
+func (s *SQLiteStore) migrate(ctx context.Context) error {
+	conn, err := s.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migrations: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			slog.Warn("failed to release migration connection", "error", err)
+		}
+	}()
+	return s.sqlStore.migrate(ctx, conn, "sqlite")
+}
+
+// Save implements Store. The workspace's PRs, preferences, and user↔PR index are
+// replaced along with the rest of its data, in one transaction, which fails with
+// ErrSaveConflict if the workspace was saved through another connection since it was
+// last loaded or saved here.
+func (s *SQLiteStore) Save(workspaceID string, data *WorkspaceData) (int64, error) {
+	if s.loadFailed(workspaceID) {
+		return 0, errors.New("workspace failed to load; not saving over it")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), databaseTimeout)
+	defer cancel()
+
+	workspace, err := encodeWorkspace(data)
+	if err != nil {
+		return 0, err
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin save: %w", err)
+	}
+	version := s.version(workspaceID)
+	size, err := s.saveRows(ctx, tx, workspaceID, version, workspace, data)
+	if err != nil {
+		return 0, errors.Join(fmt.Errorf("failed to save workspace: %w", err), tx.Rollback())
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit save: %w", err)
+	}
+	s.setVersion(workspaceID, version+1)
+	return size, nil
+}
+
+// saveRows replaces a workspace's rows within tx, returning their approximate size, or
+// ErrSaveConflict if its saved version isn't version.
+func (*SQLiteStore) saveRows(ctx context.Context, tx *sql.Tx, workspaceID string, version int64, workspace []byte, data *WorkspaceData) (int64, error) {
+	var err error
+	if version > 0 {
+		err = versionWritten(tx.ExecContext(ctx, `UPDATE workspaces SET data = ?, updated_at = CURRENT_TIMESTAMP, version = version + 1
+			WHERE id = ? AND version = ?`, string(workspace), workspaceID, version))
+	} else {
+		err = versionWritten(tx.ExecContext(ctx, `INSERT INTO workspaces (id, data, updated_at, version) VALUES (?, ?, CURRENT_TIMESTAMP, 1)
+			ON CONFLICT (id) DO NOTHING`, workspaceID, string(workspace)))
+	}
+	if err != nil {
+		return 0, err
+	}
+	for _, table := range []string{"prs", "user_prefs", "user_prs"} {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE workspace_id = ?", workspaceID); err != nil {
+			return 0, err
+		}
+	}
+	size := int64(len(workspace))
+
+	insertPR, err := tx.PrepareContext(ctx, `INSERT INTO prs (workspace_id, pr_key, owner, repo, number, state, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`)
+	if err != nil {
+		return 0, err
+	}
+	defer closeStatement(insertPR)
+	for key, pr := range data.PRs {
+		raw, err := json.Marshal(pr)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode PR %s: %w", key, err)
+		}
+		if _, err := insertPR.ExecContext(ctx, workspaceID, key, pr.Owner, pr.Repo, pr.Number, pr.State, string(raw)); err != nil {
+			return 0, err
+		}
+		size += int64(len(raw))
+	}
+
+	insertPrefs, err := tx.PrepareContext(ctx, "INSERT INTO user_prefs (workspace_id, user_id, data) VALUES (?, ?, ?)")
+	if err != nil {
+		return 0, err
+	}
+	defer closeStatement(insertPrefs)
+	for userID, prefs := range data.Users {
+		raw, err := json.Marshal(prefs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to encode preferences of %s: %w", userID, err)
+		}
+		if _, err := insertPrefs.ExecContext(ctx, workspaceID, userID, string(raw)); err != nil {
+			return 0, err
+		}
+		size += int64(len(raw))
+	}
+
+	insertIndex, err := tx.PrepareContext(ctx, `INSERT INTO user_prs (workspace_id, user_id, pr_key, position)
+		VALUES (?, ?, ?, ?) ON CONFLICT DO NOTHING`)
+	if err != nil {
+		return 0, err
+	}
+	defer closeStatement(insertIndex)
+	for userID, keys := range data.UserPRs {
+		for position, key := range keys {
+			if _, err := insertIndex.ExecContext(ctx, workspaceID, userID, key, position); err != nil {
+				return 0, err
+			}
+			size += int64(len(userID) + len(key))
+		}
+	}
+	return size, nil
+}
+
+// UserPRs implements UserPRIndex, reading only the user's rows through the user_prs
+// primary key.
+func (s *SQLiteStore) UserPRs(workspaceID, userID string) ([]*PRState, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), databaseTimeout)
+	defer cancel()
+
+	var prs []*PRState
+	err := s.scan(ctx, s.db, func(key string, raw []byte) error {
+		var pr PRState
+		if err := json.Unmarshal(raw, &pr); err != nil {
+			return fmt.Errorf("failed to decode PR %s: %w", key, err)
+		}
+		prs = append(prs, &pr)
+		return nil
+	}, `SELECT p.pr_key, p.data FROM user_prs u
+		JOIN prs p ON p.workspace_id = u.workspace_id AND p.pr_key = u.pr_key
+		WHERE u.workspace_id = ? AND u.user_id = ?
+		ORDER BY u.position`, workspaceID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return prs, nil
+}
+
+// closeStatement closes a prepared statement, logging any failure.
+func closeStatement(stmt *sql.Stmt) {
+	if err := stmt.Close(); err != nil {
+		slog.Warn("failed to close statement", "error", err)
+	}
+}
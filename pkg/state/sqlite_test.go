@@ -0,0 +1,205 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func openTestSQLite(t *testing.T, path string) *SQLiteStore {
+	t.Helper()
+	store, err := OpenSQLite(context.Background(), "", path)
+	if err != nil {
+		t.Fatalf("OpenSQLite: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := store.Close(); err != nil {
+			t.Errorf("Close: %v", err)
+		}
+	})
+	return store
+}
+
+func testWorkspace() *WorkspaceData {
+	return &WorkspaceData{
+		WorkspaceID: "T1",
+		Domain:      "example",
+		PRs: map[string]*PRState{
+			"acme/api#1": {Owner: "acme", Repo: "api", Number: 1, State: "awaiting_review", ThreadTS: "1.1", BlockedOn: []string{"alice"}},
+			"acme/api#2": {Owner: "acme", Repo: "api", Number: 2, State: "tests_broken", ClaimedBy: "U2"},
+		},
+		Users: map[string]UserPreferences{
+			"U1": {GitHubLogin: "alice", DailyReminders: true},
+		},
+		UserPRs: map[string][]string{
+			"U1":    {"acme/api#2", "acme/api#1"},
+			"alice": {"acme/api#1"},
+		},
+		Muted: map[string]bool{"C1": true},
+	}
+}
+
+func TestSQLiteStoreRoundTrip(t *testing.T) {
+	store := openTestSQLite(t, filepath.Join(t.TempDir(), "state.db"))
+
+	if _, _, ok := store.Load("T1"); ok {
+		t.Fatal("Load of an unsaved workspace succeeded")
+	}
+	if _, err := store.Save("T1", testWorkspace()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	data, size, ok := store.Load("T1")
+	if !ok {
+		t.Fatal("Load of a saved workspace failed")
+	}
+	if size <= 0 {
+		t.Errorf("size = %d, want > 0", size)
+	}
+	if data.Domain != "example" || !data.Muted["C1"] {
+		t.Errorf("workspace data = %+v, want domain and muted channel kept", data)
+	}
+	if len(data.PRs) != 2 || data.PRs["acme/api#1"].ThreadTS != "1.1" || data.PRs["acme/api#2"].ClaimedBy != "U2" {
+		t.Errorf("PRs = %+v, want both PRs kept", data.PRs)
+	}
+	if data.Users["U1"].GitHubLogin != "alice" {
+		t.Errorf("users = %+v, want U1 linked to alice", data.Users)
+	}
+	if got := data.UserPRs["U1"]; len(got) != 2 || got[0] != "acme/api#2" || got[1] != "acme/api#1" {
+		t.Errorf("UserPRs[U1] = %v, want dashboard order kept", got)
+	}
+
+	ids, err := store.Workspaces()
+	if err != nil || len(ids) != 1 || ids[0] != "T1" {
+		t.Errorf("Workspaces() = %v, %v, want [T1]", ids, err)
+	}
+}
+
+func TestSQLiteStoreSaveConflict(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state.db")
+	first := openTestSQLite(t, path)
+	second := openTestSQLite(t, path)
+
+	if _, err := first.Save("T1", testWorkspace()); err != nil {
+		t.Fatalf("first Save: %v", err)
+	}
+	if _, err := second.Save("T1", testWorkspace()); !errors.Is(err, ErrSaveConflict) {
+		t.Fatalf("Save without loading = %v, want ErrSaveConflict", err)
+	}
+	if _, _, ok := second.Load("T1"); !ok {
+		t.Fatal("Load failed")
+	}
+	if _, err := second.Save("T1", testWorkspace()); err != nil {
+		t.Fatalf("Save after loading: %v", err)
+	}
+	if _, err := first.Save("T1", testWorkspace()); !errors.Is(err, ErrSaveConflict) {
+		t.Fatalf("Save over a newer version = %v, want ErrSaveConflict", err)
+	}
+}
+
+func TestSQLiteStoreUserPRs(t *testing.T) {
+	store := openTestSQLite(t, filepath.Join(t.TempDir(), "state.db"))
+	if _, err := store.Save("T1", testWorkspace()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+
+	tests := []struct {
+		user string
+		want []int
+	}{
+		{user: "U1", want: []int{2, 1}},
+		{user: "alice", want: []int{1}},
+		{user: "U9", want: nil},
+	}
+	for _, tt := range tests {
+		prs, err := store.UserPRs("T1", tt.user)
+		if err != nil {
+			t.Fatalf("UserPRs(%s): %v", tt.user, err)
+		}
+		var got []int
+		for _, pr := range prs {
+			got = append(got, pr.Number)
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("UserPRs(%s) = %v, want %v", tt.user, got, tt.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Errorf("UserPRs(%s) = %v, want %v", tt.user, got, tt.want)
+				break
+			}
+		}
+	}
+}
+
+func TestManagerGetUserPRsFromIndex(t *testing.T) {
+	dir := t.TempDir()
+	store := openTestSQLite(t, filepath.Join(dir, "state.db"))
+	m := NewWithStore(dir, store)
+
+	m.SetUserPreferences("T1", "U1", UserPreferences{GitHubLogin: "alice"})
+	m.SetPRState("T1", &PRState{Owner: "acme", Repo: "api", Number: 7, State: "awaiting_review", BlockedOn: []string{"alice"}})
+
+	// The change is unsaved, so the lookup saves it before reading the index.
+	prs := m.GetUserPRs("T1", "U1")
+	if len(prs) != 1 || prs[0].Number != 7 {
+		t.Fatalf("GetUserPRs = %v, want PR 7", prs)
+	}
+	if indexed, err := store.UserPRs("T1", "U1"); err != nil || len(indexed) != 1 {
+		t.Errorf("store index = %v, %v, want PR 7 saved", indexed, err)
+	}
+}
+
+func TestManagerMergesAfterSaveConflict(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "state.db")
+	seed := openTestSQLite(t, path)
+	if _, err := seed.Save("T1", testWorkspace()); err != nil {
+		t.Fatalf("Save: %v", err)
+	}
+	first := NewWithStore(dir, openTestSQLite(t, path))
+	second := NewWithStore(dir, openTestSQLite(t, path))
+
+	now := time.Now()
+	claimed, _ := first.GetPRState("T1", "acme", "api", 1)
+	claimed.ClaimedBy = "U1"
+	first.SetPRState("T1", claimed)
+	first.SetUserPreferences("T1", "U1", UserPreferences{GitHubLogin: "alice", RealTimeNotifications: true})
+	stale, _ := first.GetPRState("T1", "acme", "api", 2)
+	stale.State = "awaiting_review"
+	stale.UpdatedAt = now
+	first.SetPRState("T1", stale)
+
+	merged, _ := second.GetPRState("T1", "acme", "api", 2)
+	merged.State = "merged"
+	merged.UpdatedAt = now.Add(time.Minute)
+	second.SetPRState("T1", merged)
+	second.SetPRState("T1", &PRState{Owner: "acme", Repo: "api", Number: 3, ThreadTS: "3.3"})
+	second.saveWorkspaceData("T1")
+
+	first.saveWorkspaceData("T1")
+	if first.usage.hasChanged("T1") {
+		t.Fatal("save after a conflict left changes unsaved")
+	}
+
+	data, _, ok := openTestSQLite(t, path).Load("T1")
+	if !ok {
+		t.Fatal("Load failed")
+	}
+	if got := data.PRs["acme/api#1"].ClaimedBy; got != "U1" {
+		t.Errorf("claim = %q, want the first replica's claim kept", got)
+	}
+	if !data.Users["U1"].RealTimeNotifications {
+		t.Error("the first replica's preference change was lost")
+	}
+	if got := data.PRs["acme/api#2"].State; got != "merged" {
+		t.Errorf("state = %q, want the more recently updated copy kept", got)
+	}
+	if got := data.PRs["acme/api#3"]; got == nil || got.ThreadTS != "3.3" {
+		t.Errorf("PR 3 = %+v, want the second replica's PR kept", got)
+	}
+}
@@ -0,0 +1,316 @@
+package state
+
+import (
+	"context"
+	"database/sql"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+)
+
+// databaseTimeout bounds each load, save, or listing.
+const databaseTimeout = 30 * time.Second
+
+//go:embed migrations/*/*.sql
+var migrations embed.FS
+
+// sqlStore is what the database stores share: workspaces keep their PRs, user
+// preferences, and user↔PR index in tables of their own, and everything else as
+// JSON in the workspaces table.
+type sqlStore struct {
+	db       *sql.DB
+	failed   map[string]bool  // Workspaces that couldn't be loaded, so mustn't be saved over.
+	versions map[string]int64 // Each workspace's version as last loaded or saved here.
+	bind     string           // The driver's placeholder for a query's only argument.
+	mu       sync.Mutex       // Guards failed and versions.
+}
+
+// queryer is a database or transaction to read rows from.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// openSQL connects to a database through the named database/sql driver, which the
+// binary must register.
+func openSQL(ctx context.Context, driver, dsn, bind string) (*sqlStore, error) {
+	if !slices.Contains(sql.Drivers(), driver) {
+		return nil, fmt.Errorf("no %q database driver is registered; build the server with one imported", driver)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+	if err := db.PingContext(ctx); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to connect to database: %w", err), db.Close())
+	}
+	return &sqlStore{db: db, bind: bind, failed: make(map[string]bool), versions: make(map[string]int64)}, nil
+}
+
+// Close closes the database connections.
+func (s *sqlStore) Close() error {
+	return s.db.Close()
+}
+
+// migrate applies the embedded migrations in dir not yet recorded in schema_migrations,
+// in order of their numeric prefix, each in its own transaction.
+func (s *sqlStore) migrate(ctx context.Context, conn *sql.Conn, dir string) error {
+	names, err := fs.Glob(migrations, path.Join("migrations", dir, "*.sql"))
+	if err != nil {
+		return fmt.Errorf("failed to list migrations: %w", err)
+	}
+	slices.Sort(names)
+
+	if _, err := conn.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER PRIMARY KEY,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+	)`); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+
+	for _, name := range names {
+		prefix, _, _ := strings.Cut(path.Base(name), "_")
+		version, err := strconv.Atoi(prefix)
+		if err != nil {
+			return fmt.Errorf("migration %s has no numeric prefix", name)
+		}
+		var applied bool
+		if err := conn.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM schema_migrations WHERE version = "+s.bind+")", version).Scan(&applied); err != nil {
+			return fmt.Errorf("failed to check migration %d: %w", version, err)
+		}
+		if applied {
+			continue
+		}
+
+		body, err := migrations.ReadFile(name)
+		if err != nil {
+			return fmt.Errorf("failed to read migration %s: %w", name, err)
+		}
+		tx, err := conn.BeginTx(ctx, nil)
+		if err != nil {
+			return fmt.Errorf("failed to begin migration %d: %w", version, err)
+		}
+		if _, err := tx.ExecContext(ctx, string(body)); err != nil {
+			return errors.Join(fmt.Errorf("failed to apply migration %s: %w", name, err), tx.Rollback())
+		}
+		if _, err := tx.ExecContext(ctx, "INSERT INTO schema_migrations (version) VALUES ("+s.bind+")", version); err != nil {
+			return errors.Join(fmt.Errorf("failed to record migration %d: %w", version, err), tx.Rollback())
+		}
+		if err := tx.Commit(); err != nil {
+			return fmt.Errorf("failed to commit migration %d: %w", version, err)
+		}
+		slog.Info("applied state migration", "migration", name)
+	}
+	return nil
+}
+
+// Load implements Store. A workspace that can't be read is never saved over, so a
+// database outage doesn't replace it with the empty workspace the manager starts.
+func (s *sqlStore) Load(workspaceID string) (*WorkspaceData, int64, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), databaseTimeout)
+	defer cancel()
+
+	var data *WorkspaceData
+	var size, version int64
+	err := retry.Do(
+		func() error {
+			var err error
+			data, size, version, err = s.load(ctx, workspaceID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return retry.Unrecoverable(err)
+			}
+			return err
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil {
+		slog.Error("failed to load state from database, changes to it won't be saved", "workspace", workspaceID, "error", err)
+		metrics.IncCounter("slacker_state_recovered_total", "source", "none")
+		s.failed[workspaceID] = true
+		return nil, 0, false
+	}
+	delete(s.failed, workspaceID)
+	s.versions[workspaceID] = version
+	return data, size, true
+}
+
+// loadFailed reports whether a workspace failed to load, so mustn't be saved.
+func (s *sqlStore) loadFailed(workspaceID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.failed[workspaceID]
+}
+
+// version returns a workspace's version as last loaded or saved here, or 0 if it
+// hasn't been.
+func (s *sqlStore) version(workspaceID string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versions[workspaceID]
+}
+
+// setVersion records the version a save left a workspace at.
+func (s *sqlStore) setVersion(workspaceID string, version int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.versions[workspaceID] = version
+}
+
+// versionWritten checks that a write to a workspace's row, conditional on its
+// version, found the version expected.
+func versionWritten(result sql.Result, err error) error {
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrSaveConflict
+	}
+	return nil
+}
+
+// load reads a workspace's rows as of one moment, returning its size and version, or
+// sql.ErrNoRows if it was never saved.
+func (s *sqlStore) load(ctx context.Context, workspaceID string) (*WorkspaceData, int64, int64, error) {
+	tx, err := s.db.BeginTx(ctx, &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true})
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to begin load: %w", err)
+	}
+	defer func() {
+		if err := tx.Rollback(); err != nil {
+			slog.Warn("failed to end load", "error", err)
+		}
+	}()
+
+	var raw []byte
+	var version int64
+	if err := tx.QueryRowContext(ctx, "SELECT data, version FROM workspaces WHERE id = "+s.bind, workspaceID).Scan(&raw, &version); err != nil {
+		return nil, 0, 0, err
+	}
+	var data WorkspaceData
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil, 0, 0, fmt.Errorf("failed to decode workspace: %w", err)
+	}
+	size := int64(len(raw))
+	data.PRs = make(map[string]*PRState)
+	data.Users = make(map[string]UserPreferences)
+	data.UserPRs = make(map[string][]string)
+
+	err = s.scan(ctx, tx, func(key string, raw []byte) error {
+		var pr PRState
+		if err := json.Unmarshal(raw, &pr); err != nil {
+			return fmt.Errorf("failed to decode PR %s: %w", key, err)
+		}
+		data.PRs[key] = &pr
+		size += int64(len(raw))
+		return nil
+	}, "SELECT pr_key, data FROM prs WHERE workspace_id = "+s.bind, workspaceID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	err = s.scan(ctx, tx, func(userID string, raw []byte) error {
+		var prefs UserPreferences
+		if err := json.Unmarshal(raw, &prefs); err != nil {
+			return fmt.Errorf("failed to decode preferences of %s: %w", userID, err)
+		}
+		data.Users[userID] = prefs
+		size += int64(len(raw))
+		return nil
+	}, "SELECT user_id, data FROM user_prefs WHERE workspace_id = "+s.bind, workspaceID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	err = s.scan(ctx, tx, func(userID string, key []byte) error {
+		data.UserPRs[userID] = append(data.UserPRs[userID], string(key))
+		size += int64(len(userID) + len(key))
+		return nil
+	}, "SELECT user_id, pr_key FROM user_prs WHERE workspace_id = "+s.bind+" ORDER BY user_id, position", workspaceID)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	return &data, size, version, nil
+}
+
+// scan runs a query for rows of two columns, calling row for each.
+func (*sqlStore) scan(ctx context.Context, q queryer, row func(string, []byte) error, query string, args ...any) error {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query workspace rows: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("failed to close rows", "error", err)
+		}
+	}()
+	for rows.Next() {
+		var key string
+		var value []byte
+		if err := rows.Scan(&key, &value); err != nil {
+			return fmt.Errorf("failed to read workspace row: %w", err)
+		}
+		if err := row(key, value); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// Workspaces implements Store.
+func (s *sqlStore) Workspaces() ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), databaseTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, "SELECT id FROM workspaces")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspaces: %w", err)
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			slog.Warn("failed to close rows", "error", err)
+		}
+	}()
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("failed to read workspace ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// encodeWorkspace splits a workspace for saving into its data apart from PRs, users,
+// and the user↔PR index, which are saved in their own tables.
+func encodeWorkspace(data *WorkspaceData) ([]byte, error) {
+	rest := *data
+	rest.PRs, rest.Users, rest.UserPRs = nil, nil, nil
+	workspace, err := json.Marshal(&rest)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode workspace: %w", err)
+	}
+	return workspace, nil
+}
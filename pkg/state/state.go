@@ -2,29 +2,43 @@
 package state
 
 import (
-	"compress/gzip"
-	"encoding/json"
-	"fmt"
+	"errors"
 	"log/slog"
 	"os"
-	"path/filepath"
 	"sync"
+	"sync/atomic"
 	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
 )
 
 // UserPreferences holds user notification preferences.
 type UserPreferences struct {
 	LastNotified          time.Time     `json:"last_notified"`
+	AwayFrom              time.Time     `json:"away_from,omitempty"`  // Start of a vacation, during which the user isn't notified.
+	AwayUntil             time.Time     `json:"away_until,omitempty"` // End of the vacation, exclusive.
 	Timezone              string        `json:"timezone"`
+	GitHubLogin           string        `json:"github_login,omitempty"`
 	ChannelNotifyDelay    time.Duration `json:"channel_notify_delay"`
 	RealTimeNotifications bool          `json:"real_time_notifications"`
+	NotifyVia             string        `json:"notify_via,omitempty"` // DeliveryThread for thread mentions only; otherwise a DM when possible.
 	DailyReminders        bool          `json:"daily_reminders"`
+	// SmartTiming holds DMs about non-urgent PRs until the hours the user usually reviews in.
+	SmartTiming bool `json:"smart_timing,omitempty"`
 }
 
+// Unknown is the state of a PR whose state can't be determined from the data
+// fetched from GitHub. It is also the name of the reaction that shows it.
+const Unknown = "question"
+
 // PRState represents the current state of a PR.
 type PRState struct {
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
 	LastUpdated  time.Time `json:"last_updated"`
 	LastNotified time.Time `json:"last_notified"`
+	ClaimedAt    time.Time `json:"claimed_at,omitempty"`
+	DeferredAt   time.Time `json:"deferred_at,omitempty"` // When the current deferral began.
 	Owner        string    `json:"owner"`
 	Repo         string    `json:"repo"`
 	Title        string    `json:"title"`
@@ -32,38 +46,103 @@ type PRState struct {
 	State        string    `json:"state"`
 	ThreadTS     string    `json:"thread_ts"`
 	ChannelID    string    `json:"channel_id"`
+	HeadRef      string    `json:"head_ref"`
+	BaseRef      string    `json:"base_ref"`
+	Milestone    string    `json:"milestone,omitempty"`
+	ClaimedBy    string    `json:"claimed_by,omitempty"` // Slack user who claimed the review.
 	BlockedOn    []string  `json:"blocked_on"`
 	Reviewers    []string  `json:"reviewers"`
-	Number       int       `json:"number"`
+	// ChangesRequestedBy lists reviewers with an outstanding change request.
+	ChangesRequestedBy []string `json:"changes_requested_by"`
+	// UnresolvedConversations counts review conversations no one has resolved yet.
+	UnresolvedConversations int `json:"unresolved_conversations,omitempty"`
+	// Uncertain names the GitHub data missing when the state was last determined, such as "reviews".
+	Uncertain []string `json:"uncertain,omitempty"`
+	// StateChanges records when the PR entered each of its recent states, oldest first.
+	StateChanges []StateChange `json:"state_changes,omitempty"`
+	// ThreadHashes holds a hash of the last update applied to the thread, by kind.
+	ThreadHashes map[string]string `json:"thread_hashes"`
+	// ThreadReplies keys the replies recently posted or queued to the thread, oldest first.
+	ThreadReplies []string `json:"thread_replies,omitempty"`
+	Number        int      `json:"number"`
+	// StackRoot is the number of the PR whose thread this stacked PR shares, or 0.
+	StackRoot int `json:"stack_root,omitempty"`
+	// Pinned is set while the PR's thread is pinned for being urgent.
+	Pinned bool `json:"pinned,omitempty"`
+	// Large is set while the PR exceeds its org's size thresholds.
+	Large bool `json:"large,omitempty"`
+	// Backport is set while the PR is labeled for backport to release branches.
+	Backport bool `json:"backport,omitempty"`
+	// BackportOf is the number of the PR this one backports, or 0.
+	BackportOf int `json:"backport_of,omitempty"`
+	// LinkedBack is set once the PR has a GitHub comment linking to its thread.
+	LinkedBack bool `json:"linked_back,omitempty"`
+	// CheckSHA is the head commit last given a check run linking to the thread.
+	CheckSHA string `json:"check_sha,omitempty"`
+	// Subscribers are Slack users who asked to be told when the PR changes state.
+	Subscribers []string `json:"subscribers,omitempty"`
+	// DeferredBy are Slack users who deferred the PR by reacting to its thread.
+	DeferredBy []string `json:"deferred_by,omitempty"`
 }
 
 // WorkspaceData holds data for a Slack workspace.
 type WorkspaceData struct {
-	LastUpdated time.Time                  `json:"last_updated"`
-	Users       map[string]UserPreferences `json:"users"`
-	PRs         map[string]*PRState        `json:"prs"`
-	UserPRs     map[string][]string        `json:"user_prs"`
-	WorkspaceID string                     `json:"workspace_id"`
+	LastUpdated time.Time                   `json:"last_updated"`
+	Users       map[string]UserPreferences  `json:"users"`
+	PRs         map[string]*PRState         `json:"prs"`
+	UserPRs     map[string][]string         `json:"user_prs"`
+	Digests     map[string]time.Time        `json:"digests"`
+	Muted       map[string]bool             `json:"muted_channels"` // Channels the bot does not post new PRs to.
+	Threads     map[string]string           `json:"threads"`        // channel/thread_ts to PR key.
+	Milestones  map[string]MilestoneSummary `json:"milestones"`
+	WorkspaceID string                      `json:"workspace_id"`
+	Domain      string                      `json:"domain,omitempty"` // Slack workspace subdomain.
+	Outbox      []OutboxItem                `json:"outbox"`
+	Reminders   []Reminder                  `json:"reminders"`
+	Reviews     []ReviewRecord              `json:"reviews,omitempty"`    // Recent reviews, for reviewer stats.
+	Deliveries  map[string]Delivery         `json:"deliveries,omitempty"` // By DeliveryKey.
+	Paused      map[string]time.Time        `json:"paused,omitempty"`     // When posting was paused, by org or AllOrgs.
+	Identities  map[string]Identity         `json:"identities,omitempty"` // Resolved Slack users, by lowercased GitHub login.
 }
 
-// Manager manages application state with file persistence.
+// Manager manages application state, keeping workspaces in memory and persisting
+// them through a Store.
 type Manager struct {
 	data     map[string]*WorkspaceData
+	loading  map[string]*workspaceLoad // Workspaces being read from or saved to the store, outside mu.
+	store    Store
 	saveChan chan string
 	dataDir  string
 	mu       sync.RWMutex
+	saving   sync.Mutex // Held while saving to the store, so saves of a workspace don't overlap.
+	usage    workspaceUsage
+	backups  atomic.Int32 // Previous copies kept of each state file.
+	limits   pruneLimits
 }
 
-// New creates a new state manager.
+// New creates a new state manager that saves workspaces as files in dataDir.
 func New(dataDir string) *Manager {
+	return NewWithStore(dataDir, nil)
+}
+
+// NewWithStore creates a new state manager that saves workspaces in store, or as
+// files in dataDir if store is nil. dataDir is still used for the storage self-test.
+func NewWithStore(dataDir string, store Store) *Manager {
 	m := &Manager{
 		dataDir:  dataDir,
+		store:    store,
 		data:     make(map[string]*WorkspaceData),
+		loading:  make(map[string]*workspaceLoad),
 		saveChan: make(chan string, 100),
+		usage:    newWorkspaceUsage(),
+	}
+	m.backups.Store(DefaultBackups)
+	if m.store == nil {
+		m.store = &fileStore{dir: dataDir, backups: &m.backups}
 	}
 
 	// Create data directory if it doesn't exist.
-	if err := os.MkdirAll(dataDir, 0o755); err != nil {
+	if err := os.MkdirAll(dataDir, DirMode); err != nil {
 		slog.Error("failed to create data directory", "error", err)
 	}
 
@@ -78,14 +157,7 @@ func (m *Manager) GetUserPreferences(workspaceID, userID string) UserPreferences
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	// Load workspace data if not in memory.
-	if _, exists := m.data[workspaceID]; !exists {
-		m.mu.RUnlock()
-		m.loadWorkspaceData(workspaceID)
-		m.mu.RLock()
-	}
-
-	workspace, exists := m.data[workspaceID]
+	workspace, exists := m.residentRLocked(workspaceID)
 	if !exists || workspace.Users == nil {
 		// Return defaults.
 		return UserPreferences{
@@ -120,30 +192,56 @@ func (m *Manager) SetUserPreferences(workspaceID, userID string, prefs UserPrefe
 	workspace.Users[userID] = prefs
 	workspace.LastUpdated = time.Now()
 
-	// Queue save.
-	select {
-	case m.saveChan <- workspaceID:
-	default:
-		// Channel full, save will happen soon anyway.
-	}
+	m.queueSave(workspaceID)
 }
 
-// GetPRState returns the state of a PR.
+// GetPRState returns a copy of the state of a PR. Changes to it are kept only once
+// written back with SetPRState, or made with UpdatePRState instead.
 func (m *Manager) GetPRState(workspaceID, owner, repo string, number int) (*PRState, bool) {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	workspace, exists := m.data[workspaceID]
+	workspace, exists := m.residentRLocked(workspaceID)
 	if !exists || workspace.PRs == nil {
 		return nil, false
 	}
 
-	key := fmt.Sprintf("%s/%s#%d", owner, repo, number)
-	pr, exists := workspace.PRs[key]
-	return pr, exists
+	pr, exists := workspace.PRs[PRKey(owner, repo, number)]
+	if !exists {
+		return nil, false
+	}
+	return pr.Clone(), true
+}
+
+// GetUserPRs returns copies of the PRs on a user's dashboard. If the store indexes
+// dashboards, they're read from the index, once any unsaved changes to the workspace
+// are saved so the index is current.
+func (m *Manager) GetUserPRs(workspaceID, userID string) []*PRState {
+	if index, ok := m.store.(UserPRIndex); ok && m.flushWorkspace(workspaceID) {
+		prs, err := index.UserPRs(workspaceID, userID)
+		if err == nil {
+			return prs
+		}
+		slog.Warn("failed to look up user PRs in store, loading workspace", "workspace", workspaceID, "user", userID, "error", err)
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return nil
+	}
+	var prs []*PRState
+	for _, key := range workspace.UserPRs[userID] {
+		if pr, ok := workspace.PRs[key]; ok {
+			prs = append(prs, pr.Clone())
+		}
+	}
+	return prs
 }
 
-// SetPRState updates the state of a PR.
+// SetPRState stores a copy of the state of a PR, replacing any stored before.
 func (m *Manager) SetPRState(workspaceID string, pr *PRState) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -153,63 +251,105 @@ func (m *Manager) SetPRState(workspaceID string, pr *PRState) {
 		workspace.PRs = make(map[string]*PRState)
 	}
 
-	key := fmt.Sprintf("%s/%s#%d", pr.Owner, pr.Repo, pr.Number)
+	key := PRKey(pr.Owner, pr.Repo, pr.Number)
+	if previous, exists := workspace.PRs[key]; exists {
+		unindexThreadLocked(workspace, previous)
+	}
+	pr = pr.Clone()
 	workspace.PRs[key] = pr
-	workspace.LastUpdated = time.Now()
+	m.indexPRLocked(workspaceID, workspace, key, pr)
+}
+
+// UpdatePRState changes a stored PR in place with update, which is called with the
+// lock held so nothing else changes the PR meanwhile, and returns a copy of the
+// result. It reports false, without calling update, if the PR isn't stored.
+func (m *Manager) UpdatePRState(workspaceID, owner, repo string, number int, update func(pr *PRState)) (*PRState, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// Update user PR mappings.
-	if workspace.UserPRs == nil {
-		workspace.UserPRs = make(map[string][]string)
+	workspace, exists := m.residentLocked(workspaceID)
+	if !exists {
+		return nil, false
+	}
+	key := PRKey(owner, repo, number)
+	pr, exists := workspace.PRs[key]
+	if !exists {
+		return nil, false
 	}
+	unindexThreadLocked(workspace, pr)
+	update(pr)
+	m.indexPRLocked(workspaceID, workspace, key, pr)
+	return pr.Clone(), true
+}
 
-	// Add to blocked users' lists.
-	for _, userID := range pr.BlockedOn {
-		// Check if PR key already exists in user's list
-		alreadyExists := false
-		for _, prKey := range workspace.UserPRs[userID] {
-			if prKey == key {
-				alreadyExists = true
-				break
-			}
-		}
-		if !alreadyExists {
-			workspace.UserPRs[userID] = append(workspace.UserPRs[userID], key)
+// indexPRLocked indexes a stored PR's thread and the dashboards it's on after it
+// changed, and queues the workspace to be saved (must hold lock).
+func (m *Manager) indexPRLocked(workspaceID string, workspace *WorkspaceData, key string, pr *PRState) {
+	indexThreadLocked(workspace, key, pr)
+	workspace.LastUpdated = time.Now()
+
+	// Add to blocked users' lists, under their Slack user too once it's known.
+	for _, login := range pr.BlockedOn {
+		addUserPRLocked(workspace, login, key)
+		if userID, found := slackUserLocked(workspace, login); found {
+			addUserPRLocked(workspace, userID, key)
 		}
 	}
 
-	// Queue save.
-	select {
-	case m.saveChan <- workspaceID:
-	default:
+	m.queueSave(workspaceID)
+}
+
+// RecordThreadHash records the hash of the last update of a kind applied to a PR's thread.
+func (m *Manager) RecordThreadHash(workspaceID string, pr *PRState, kind, hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if pr.ThreadHashes == nil {
+		pr.ThreadHashes = make(map[string]string)
 	}
+	pr.ThreadHashes[kind] = hash
+
+	// Also update the stored copy, which may be a different value than the caller's.
+	workspace := m.ensureWorkspace(workspaceID)
+	key := PRKey(pr.Owner, pr.Repo, pr.Number)
+	if stored, exists := workspace.PRs[key]; exists && stored != pr {
+		if stored.ThreadHashes == nil {
+			stored.ThreadHashes = make(map[string]string)
+		}
+		stored.ThreadHashes[kind] = hash
+	}
+
+	m.queueSave(workspaceID)
 }
 
-// GetUserPRs returns PRs associated with a user.
-func (m *Manager) GetUserPRs(workspaceID, userID string) []*PRState {
+// LastDigest returns when the digest identified by key was last posted.
+func (m *Manager) LastDigest(workspaceID, key string) time.Time {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	workspace, exists := m.data[workspaceID]
-	if !exists || workspace.UserPRs == nil {
-		return nil
-	}
-
-	prKeys, exists := workspace.UserPRs[userID]
+	workspace, exists := m.residentRLocked(workspaceID)
 	if !exists {
-		return nil
+		return time.Time{}
 	}
+	return workspace.Digests[key]
+}
 
-	var prs []*PRState
-	for _, key := range prKeys {
-		if pr, ok := workspace.PRs[key]; ok {
-			prs = append(prs, pr)
-		}
+// RecordDigest records that the digest identified by key was posted.
+func (m *Manager) RecordDigest(workspaceID, key string, sentAt time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	if workspace.Digests == nil {
+		workspace.Digests = make(map[string]time.Time)
 	}
-	return prs
+	workspace.Digests[key] = sentAt
+
+	m.queueSave(workspaceID)
 }
 
-// UpdateLastNotified updates the last notified time for a user.
-func (m *Manager) UpdateLastNotified(workspaceID, userID string) {
+// UpdateLastNotified records when a user was last notified.
+func (m *Manager) UpdateLastNotified(workspaceID, userID string, at time.Time) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
@@ -219,86 +359,63 @@ func (m *Manager) UpdateLastNotified(workspaceID, userID string) {
 	}
 
 	prefs := workspace.Users[userID]
-	prefs.LastNotified = time.Now()
+	prefs.LastNotified = at
 	workspace.Users[userID] = prefs
 
-	// Queue save.
+	m.queueSave(workspaceID)
+}
+
+// queueSave records that a workspace changed and queues it to be saved.
+func (m *Manager) queueSave(workspaceID string) {
+	m.usage.setChanged(workspaceID, true)
 	select {
 	case m.saveChan <- workspaceID:
 	default:
+		// Channel full, save will happen soon anyway.
 	}
 }
 
 // ensureWorkspace ensures a workspace exists in memory.
 func (m *Manager) ensureWorkspace(workspaceID string) *WorkspaceData {
-	if workspace, exists := m.data[workspaceID]; exists {
+	if workspace, exists := m.residentLocked(workspaceID); exists {
 		return workspace
 	}
 
-	// Try to load from disk.
-	if data := m.loadWorkspaceDataLocked(workspaceID); data != nil {
-		m.data[workspaceID] = data
-		return data
-	}
-
 	// Create new.
 	workspace := &WorkspaceData{
 		WorkspaceID: workspaceID,
 		Users:       make(map[string]UserPreferences),
 		PRs:         make(map[string]*PRState),
 		UserPRs:     make(map[string][]string),
+		Digests:     make(map[string]time.Time),
+		Threads:     make(map[string]string),
 		LastUpdated: time.Now(),
 	}
 	m.data[workspaceID] = workspace
+	m.usage.touch(workspaceID)
 	return workspace
 }
 
-// loadWorkspaceData loads workspace data from disk.
-func (m *Manager) loadWorkspaceData(workspaceID string) {
-	m.mu.Lock()
-	defer m.mu.Unlock()
-
-	if data := m.loadWorkspaceDataLocked(workspaceID); data != nil {
-		m.data[workspaceID] = data
-	}
-}
-
-// loadWorkspaceDataLocked loads workspace data from disk (must hold lock).
-func (m *Manager) loadWorkspaceDataLocked(workspaceID string) *WorkspaceData {
-	filename := filepath.Join(m.dataDir, fmt.Sprintf("%s.json.gz", workspaceID))
-
-	file, err := os.Open(filename)
-	if err != nil {
-		if !os.IsNotExist(err) {
-			slog.Error("failed to open state file", "file", filename, "error", err)
-		}
+// loadWorkspaceData loads workspace data from the store. m.mu needn't be held.
+func (m *Manager) loadWorkspaceData(workspaceID string) *WorkspaceData {
+	data, size, ok := m.store.Load(workspaceID)
+	if !ok {
 		return nil
 	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			slog.Error("failed to close file", "error", err)
-		}
-	}()
+	m.usage.setSize(workspaceID, size)
 
-	gz, err := gzip.NewReader(file)
-	if err != nil {
-		slog.Error("failed to create gzip reader", "error", err)
-		return nil
+	if data.Threads == nil {
+		// State saved before the thread index existed.
+		rebuildThreadIndex(data)
 	}
-	defer func() {
-		if err := gz.Close(); err != nil {
-			slog.Error("failed to close gzip reader", "error", err)
-		}
-	}()
-
-	var data WorkspaceData
-	if err := json.NewDecoder(gz).Decode(&data); err != nil {
-		slog.Error("failed to decode state data", "error", err)
-		return nil
+	if normalizeKeysLocked(data) {
+		slog.Info("moved PRs to case-insensitive keys", "workspace", workspaceID)
 	}
 
+	m.usage.setSaved(workspaceID, fingerprintOf(data))
+
 	slog.Info("loaded state", "workspace", workspaceID, "users", len(data.Users), "prs", len(data.PRs))
-	return &data
+	return data
 }
 
 // saveWorker handles background saves.
@@ -332,73 +449,114 @@ func (m *Manager) saveWorker() {
 					saved[id] = time.Now()
 				}
 			}
+			m.pruneWorkspaces()
+			m.evictWorkspaces()
 		}
 	}
 }
 
-// saveWorkspaceData saves workspace data to disk.
-func (m *Manager) saveWorkspaceData(workspaceID string) {
-	m.mu.RLock()
-	data, exists := m.data[workspaceID]
-	m.mu.RUnlock()
+// saveAttempts is how many times a save is tried while other replicas keep saving
+// the workspace first.
+const saveAttempts = 3
 
-	if !exists {
-		return
+// saveWorkspaceData saves workspace data to the store. If other replicas keep saving
+// it first, it's saved on a later pass.
+func (m *Manager) saveWorkspaceData(workspaceID string) {
+	if err := m.writeWorkspace(workspaceID, nil); errors.Is(err, ErrSaveConflict) {
+		slog.Warn("workspace keeps being saved by another replica; saving it again later", "workspace", workspaceID)
 	}
+}
 
-	filename := filepath.Join(m.dataDir, fmt.Sprintf("%s.json.gz", workspaceID))
-	tempFile := filename + ".tmp"
+// writeWorkspace saves a copy of a workspace to the store: the one in memory, or
+// evicted if it's been taken out of memory to be evicted. The copy is taken under
+// m.mu, so the store encodes it without racing the workspace's writers. If another
+// replica saved the workspace first, the changes made here are merged into its copy
+// and the save is tried again.
+func (m *Manager) writeWorkspace(workspaceID string, evicted *WorkspaceData) error {
+	m.saving.Lock()
+	defer m.saving.Unlock()
 
-	file, err := os.Create(tempFile)
-	if err != nil {
-		slog.Error("failed to create temp file", "error", err)
-		return
-	}
-	defer func() {
-		if err := file.Close(); err != nil {
-			slog.Error("failed to close file", "error", err)
+	for attempt := 1; ; attempt++ {
+		m.mu.RLock()
+		data, exists := m.data[workspaceID]
+		if !exists {
+			data = evicted
 		}
-	}()
-
-	gz := gzip.NewWriter(file)
-	defer func() {
-		if err := gz.Close(); err != nil {
-			slog.Error("failed to close gzip reader", "error", err)
+		if data == nil {
+			m.mu.RUnlock()
+			return nil
 		}
-	}()
+		data = data.clone()
+		// Changes from here on are saved next time.
+		m.usage.setChanged(workspaceID, false)
+		m.mu.RUnlock()
 
-	if err := json.NewEncoder(gz).Encode(data); err != nil {
-		slog.Error("failed to encode state data", "error", err)
-		if err := os.Remove(tempFile); err != nil {
-			slog.Error("failed to remove temp file", "error", err)
+		size, err := m.store.Save(workspaceID, data)
+		if err == nil {
+			m.usage.setSaved(workspaceID, fingerprintOf(data))
+			m.usage.setSize(workspaceID, size)
+			slog.Info("saved state", "workspace", workspaceID)
+			return nil
+		}
+		m.usage.setChanged(workspaceID, true)
+		if !errors.Is(err, ErrSaveConflict) {
+			slog.Error("failed to save state", "workspace", workspaceID, "error", err)
+			return err
+		}
+		metrics.IncCounter("slacker_state_conflicts_total")
+		if attempt == saveAttempts || !m.mergeSaved(workspaceID, evicted) {
+			return err
 		}
-		return
 	}
+}
 
-	if err := gz.Close(); err != nil {
-		slog.Error("failed to close gzip writer", "error", err)
-		if err := os.Remove(tempFile); err != nil {
-			slog.Error("failed to remove temp file", "error", err)
-		}
-		return
+// mergeSaved loads the copy of a workspace another replica saved and re-applies to it,
+// in place, the changes made here since the workspace was last loaded or saved. It
+// reports whether the workspace could be merged.
+func (m *Manager) mergeSaved(workspaceID string, evicted *WorkspaceData) bool {
+	base := m.usage.lastSaved(workspaceID)
+	theirs := m.loadWorkspaceData(workspaceID)
+	if theirs == nil {
+		return false
 	}
 
-	if err := file.Close(); err != nil {
-		slog.Error("failed to close file", "error", err)
-		if err := os.Remove(tempFile); err != nil {
-			slog.Error("failed to remove temp file", "error", err)
-		}
-		return
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ours, exists := m.data[workspaceID]
+	if !exists {
+		ours = evicted
+	}
+	if ours == nil {
+		return false
 	}
+	dropped := mergeWorkspace(theirs, ours, base)
+	*ours = *theirs
+	if len(dropped) > 0 {
+		slog.Warn("dropped changes another replica made differently", "workspace", workspaceID, "keys", dropped)
+	}
+	slog.Info("merged changes into workspace saved by another replica", "workspace", workspaceID)
+	return true
+}
 
-	// Atomic rename.
-	if err := os.Rename(tempFile, filename); err != nil {
-		slog.Error("failed to rename temp file", "error", err)
-		if err := os.Remove(tempFile); err != nil {
-			slog.Error("failed to remove temp file", "error", err)
+// flushWorkspace saves a workspace now if it has unsaved changes, first waiting out
+// any save for eviction, and reports whether the store is then current.
+func (m *Manager) flushWorkspace(workspaceID string) bool {
+	m.mu.Lock()
+	for {
+		load, loading := m.loading[workspaceID]
+		if !loading {
+			break
 		}
-		return
+		load.waiters++
+		m.mu.Unlock()
+		<-load.done
+		m.mu.Lock()
 	}
+	_, exists := m.data[workspaceID]
+	m.mu.Unlock()
 
-	slog.Info("saved state", "workspace", workspaceID)
+	if !exists || !m.usage.hasChanged(workspaceID) {
+		return true
+	}
+	return m.writeWorkspace(workspaceID, nil) == nil
 }
@@ -0,0 +1,96 @@
+package state
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestSaveWhileWriting(t *testing.T) {
+	m := New(t.TempDir())
+	m.SetUserPreferences("T1", "U1", UserPreferences{GitHubLogin: "alice"})
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			m.SetPRState("T1", &PRState{Owner: "acme", Repo: "api", Number: i, BlockedOn: []string{"alice"}})
+			m.SetUserPreferences("T1", "U"+strconv.Itoa(i), UserPreferences{})
+		}
+	}()
+	for range 20 {
+		m.saveWorkspaceData("T1")
+	}
+	wg.Wait()
+
+	m.saveWorkspaceData("T1")
+	data, _, ok := m.store.Load("T1")
+	if !ok {
+		t.Fatal("Load failed")
+	}
+	if len(data.PRs) != 200 {
+		t.Errorf("saved %d PRs, want 200", len(data.PRs))
+	}
+}
+
+func TestUpdatePRState(t *testing.T) {
+	m := New(t.TempDir())
+	m.SetPRState("T1", &PRState{Owner: "acme", Repo: "api", Number: 1, State: "awaiting_review", ThreadTS: "1.1", ClaimedBy: "U1"})
+
+	updated, ok := m.UpdatePRState("T1", "acme", "api", 1, func(pr *PRState) {
+		pr.State = "tests_broken"
+		pr.BlockedOn = []string{"alice"}
+	})
+	if !ok {
+		t.Fatal("UpdatePRState of a stored PR failed")
+	}
+	if updated.State != "tests_broken" || updated.ThreadTS != "1.1" || updated.ClaimedBy != "U1" {
+		t.Errorf("updated = %+v, want the new state with the thread and claim kept", updated)
+	}
+	updated.ClaimedBy = "U2"
+	if stored, _ := m.GetPRState("T1", "acme", "api", 1); stored.ClaimedBy != "U1" {
+		t.Error("changing the returned copy changed the stored PR")
+	}
+	if got := m.Snapshot("T1").UserPRs["alice"]; len(got) != 1 {
+		t.Errorf("alice's dashboard = %v, want the PR added", got)
+	}
+
+	if _, ok := m.UpdatePRState("T1", "acme", "api", 2, func(*PRState) { t.Error("update called for a missing PR") }); ok {
+		t.Error("UpdatePRState of a missing PR succeeded")
+	}
+}
+
+func TestSnapshotWhileSettingPRs(t *testing.T) {
+	m := New(t.TempDir())
+	pr := &PRState{Owner: "acme", Repo: "api", Number: 1, State: "awaiting_review"}
+	m.SetPRState("T1", pr)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := range 200 {
+			// The caller keeps its copy and goes on changing it.
+			pr.BlockedOn = append(pr.BlockedOn, "user"+strconv.Itoa(i))
+			pr.Subscribe("U" + strconv.Itoa(i))
+			m.SetPRState("T1", pr)
+		}
+	}()
+	for range 200 {
+		for _, snapped := range m.Snapshot("T1").ListPRs() {
+			if len(snapped.BlockedOn) > 200 || len(snapped.Subscribers) > 200 {
+				t.Errorf("snapshot = %+v, want at most 200 users", snapped)
+			}
+		}
+		if got, ok := m.GetPRState("T1", "acme", "api", 1); ok {
+			got.State = "merged"
+		}
+	}
+	wg.Wait()
+
+	stored, _ := m.GetPRState("T1", "acme", "api", 1)
+	if stored.State != "awaiting_review" || len(stored.BlockedOn) != 200 {
+		t.Errorf("stored = %s blocked on %d users, want the last state set", stored.State, len(stored.BlockedOn))
+	}
+}
@@ -0,0 +1,27 @@
+package state
+
+import "errors"
+
+// ErrSaveConflict is returned by Save when the workspace was saved elsewhere since
+// it was last loaded or saved through the store, so saving would overwrite that.
+var ErrSaveConflict = errors.New("workspace was saved by another writer")
+
+// Store persists workspaces. The manager keeps each workspace in memory, loading it
+// from the store on first use and saving it back whole after changes.
+type Store interface {
+	// Load returns a workspace's saved data and its approximate size in bytes, or
+	// false if nothing usable is saved.
+	Load(workspaceID string) (*WorkspaceData, int64, bool)
+	// Save replaces a workspace's saved data, returning its approximate size in bytes,
+	// or ErrSaveConflict if a store shared between replicas has a newer copy.
+	Save(workspaceID string, data *WorkspaceData) (int64, error)
+	// Workspaces returns the IDs of the saved workspaces.
+	Workspaces() ([]string, error)
+}
+
+// UserPRIndex is implemented by stores that can look up the PRs on a user's
+// dashboard without loading the whole workspace.
+type UserPRIndex interface {
+	// UserPRs returns the saved PRs on a user's dashboard, in dashboard order.
+	UserPRs(workspaceID, userID string) ([]*PRState, error)
+}
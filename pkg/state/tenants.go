@@ -0,0 +1,233 @@
+package state
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+// TenantKeySize is the length of the key that encrypts tenant credentials, for AES-256.
+const TenantKeySize = 32
+
+// Tenant holds one customer's credentials: the Slack app for a workspace, the
+// GitHub orgs routed to it, and optionally the GitHub App those orgs use instead
+// of the server's.
+type Tenant struct {
+	UpdatedAt     time.Time `json:"updated_at"`
+	Workspace     string    `json:"workspace"`
+	BotToken      string    `json:"bot_token"`
+	SigningSecret string    `json:"signing_secret"`
+	Orgs          []string  `json:"orgs"`
+	Commands      []string  `json:"commands,omitempty"`
+	Admins        []string  `json:"admins,omitempty"`
+	// GitHubAppID, GitHubPrivateKey, and GitHubInstallationID are set together, or not at all.
+	GitHubAppID          string `json:"github_app_id,omitempty"`
+	GitHubPrivateKey     string `json:"github_private_key,omitempty"`
+	GitHubInstallationID string `json:"github_installation_id,omitempty"`
+}
+
+// HasGitHubApp reports whether the tenant's orgs use their own GitHub App.
+func (t *Tenant) HasGitHubApp() bool {
+	return t.GitHubAppID != ""
+}
+
+// workspaceNamePattern matches a tenant's workspace name. The name keys the
+// workspace's state, names its state file, and is part of its Slack request URLs.
+var workspaceNamePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9_-]{0,63}$`)
+
+// Validate checks that a tenant has a usable workspace name and the credentials it needs.
+func (t *Tenant) Validate() error {
+	switch {
+	case t.Workspace == "":
+		return errors.New("workspace is required")
+	case !workspaceNamePattern.MatchString(t.Workspace):
+		return fmt.Errorf("workspace %q must be up to 64 letters, digits, dashes, or underscores, starting with a letter or digit", t.Workspace)
+	case t.BotToken == "":
+		return errors.New("bot_token is required")
+	case t.SigningSecret == "":
+		return errors.New("signing_secret is required")
+	case len(t.Orgs) == 0:
+		return errors.New("at least one org is required")
+	}
+	github := []string{t.GitHubAppID, t.GitHubPrivateKey, t.GitHubInstallationID}
+	set := 0
+	for _, v := range github {
+		if v != "" {
+			set++
+		}
+	}
+	if set != 0 && set != len(github) {
+		return errors.New("github_app_id, github_private_key, and github_installation_id must be set together")
+	}
+	return nil
+}
+
+// ParseTenantKey decodes a base64 tenant encryption key.
+func ParseTenantKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("tenant key is not base64: %w", err)
+	}
+	if len(key) != TenantKeySize {
+		return nil, fmt.Errorf("tenant key is %d bytes, want %d", len(key), TenantKeySize)
+	}
+	return key, nil
+}
+
+// TenantStore keeps tenant credentials in a file encrypted with AES-256-GCM.
+type TenantStore struct {
+	aead    cipher.AEAD
+	tenants map[string]Tenant
+	path    string
+	mu      sync.RWMutex
+}
+
+// OpenTenants opens the tenant store at path, decrypting it with key. A missing
+// file is an empty store; a file the key can't decrypt is an error.
+func OpenTenants(path string, key []byte) (*TenantStore, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant key: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant key: %w", err)
+	}
+
+	s := &TenantStore{aead: aead, path: path, tenants: make(map[string]Tenant)}
+	sealed, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenants: %w", err)
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("tenant file is truncated")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return nil, errors.New("failed to decrypt tenants: wrong key or corrupt file")
+	}
+	if err := json.Unmarshal(plain, &s.tenants); err != nil {
+		return nil, fmt.Errorf("failed to decode tenants: %w", err)
+	}
+	return s, nil
+}
+
+// List returns every tenant, sorted by workspace.
+func (s *TenantStore) List() []Tenant {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tenants := make([]Tenant, 0, len(s.tenants))
+	for _, t := range s.tenants {
+		tenants = append(tenants, t)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].Workspace < tenants[j].Workspace })
+	return tenants
+}
+
+// Get returns a workspace's tenant.
+func (s *TenantStore) Get(workspace string) (Tenant, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	t, exists := s.tenants[workspace]
+	return t, exists
+}
+
+// Put validates and stores a tenant, replacing any with the same workspace. An org
+// may belong to only one tenant.
+func (s *TenantStore) Put(t Tenant) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, other := range s.tenants {
+		if other.Workspace == t.Workspace {
+			continue
+		}
+		for _, org := range t.Orgs {
+			if slices.Contains(other.Orgs, org) {
+				return fmt.Errorf("org %q already belongs to workspace %q", org, other.Workspace)
+			}
+		}
+	}
+
+	previous, existed := s.tenants[t.Workspace]
+	t.UpdatedAt = time.Now()
+	s.tenants[t.Workspace] = t
+	if err := s.saveLocked(); err != nil {
+		if existed {
+			s.tenants[t.Workspace] = previous
+		} else {
+			delete(s.tenants, t.Workspace)
+		}
+		return err
+	}
+	return nil
+}
+
+// Delete removes a workspace's tenant, reporting whether there was one.
+func (s *TenantStore) Delete(workspace string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	previous, exists := s.tenants[workspace]
+	if !exists {
+		return false, nil
+	}
+	delete(s.tenants, workspace)
+	if err := s.saveLocked(); err != nil {
+		s.tenants[workspace] = previous
+		return false, err
+	}
+	return true, nil
+}
+
+// saveLocked encrypts the tenants and atomically replaces the file (must hold lock).
+func (s *TenantStore) saveLocked() error {
+	plain, err := json.Marshal(s.tenants)
+	if err != nil {
+		return fmt.Errorf("failed to encode tenants: %w", err)
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	sealed := s.aead.Seal(nonce, nonce, plain, nil)
+
+	tempFile := s.path + ".tmp"
+	file, err := os.OpenFile(tempFile, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, FileMode)
+	if err != nil {
+		return fmt.Errorf("failed to create tenant file: %w", err)
+	}
+	if _, err := file.Write(sealed); err != nil {
+		return errors.Join(fmt.Errorf("failed to write tenants: %w", err), file.Close(), os.Remove(tempFile))
+	}
+	if err := file.Sync(); err != nil {
+		return errors.Join(fmt.Errorf("failed to sync tenants: %w", err), file.Close(), os.Remove(tempFile))
+	}
+	if err := file.Close(); err != nil {
+		return errors.Join(fmt.Errorf("failed to close tenant file: %w", err), os.Remove(tempFile))
+	}
+	if err := os.Rename(tempFile, s.path); err != nil {
+		return errors.Join(fmt.Errorf("failed to replace tenant file: %w", err), os.Remove(tempFile))
+	}
+	syncDir(filepath.Dir(s.path))
+	return nil
+}
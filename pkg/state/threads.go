@@ -0,0 +1,106 @@
+package state
+
+import (
+	"slices"
+	"strings"
+)
+
+// maxThreadReplies bounds the reply keys remembered per PR thread.
+const maxThreadReplies = 50
+
+// threadKey is the thread index key for a thread in a channel.
+func threadKey(channelID, threadTS string) string {
+	return channelID + "/" + threadTS
+}
+
+// indexThreadLocked records the PR's thread in the reverse index (must hold lock).
+func indexThreadLocked(workspace *WorkspaceData, prKey string, pr *PRState) {
+	// Stacked PRs share their root's thread, which resolves to the root.
+	if pr.ThreadTS == "" || pr.StackRoot != 0 {
+		return
+	}
+	if workspace.Threads == nil {
+		workspace.Threads = make(map[string]string)
+	}
+	workspace.Threads[threadKey(pr.ChannelID, pr.ThreadTS)] = prKey
+}
+
+// unindexThreadLocked removes the PR's thread from the reverse index (must hold lock).
+func unindexThreadLocked(workspace *WorkspaceData, pr *PRState) {
+	if pr.ThreadTS == "" || pr.StackRoot != 0 {
+		return
+	}
+	delete(workspace.Threads, threadKey(pr.ChannelID, pr.ThreadTS))
+}
+
+// rebuildThreadIndex builds the reverse thread index from the stored PRs.
+func rebuildThreadIndex(workspace *WorkspaceData) {
+	workspace.Threads = make(map[string]string)
+	for key, pr := range workspace.PRs {
+		indexThreadLocked(workspace, key, pr)
+	}
+}
+
+// FindPRByThread returns a copy of the PR whose Slack thread starts at threadTS in a
+// channel.
+func (m *Manager) FindPRByThread(workspaceID, channelID, threadTS string) (*PRState, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists || threadTS == "" {
+		return nil, false
+	}
+
+	key, exists := workspace.Threads[threadKey(channelID, threadTS)]
+	if !exists {
+		// Threads recorded before channel IDs were stored are keyed by channel name.
+		suffix := "/" + threadTS
+		for k, v := range workspace.Threads {
+			if strings.HasSuffix(k, suffix) {
+				key, exists = v, true
+				break
+			}
+		}
+	}
+	if !exists {
+		return nil, false
+	}
+	pr, exists := workspace.PRs[key]
+	if !exists {
+		return nil, false
+	}
+	return pr.Clone(), true
+}
+
+// ClaimThreadReply records that the reply identified by key is being posted to a PR's
+// thread, reporting false if it already was. The stored PR is checked if there is one,
+// and both it and pr are updated.
+func (m *Manager) ClaimThreadReply(workspaceID string, pr *PRState, key string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	stored, exists := workspace.PRs[PRKey(pr.Owner, pr.Repo, pr.Number)]
+	if !exists {
+		stored = pr
+	}
+	if slices.Contains(stored.ThreadReplies, key) || slices.Contains(pr.ThreadReplies, key) {
+		return false
+	}
+	addThreadReply(stored, key)
+	if pr != stored {
+		addThreadReply(pr, key)
+	}
+
+	m.queueSave(workspaceID)
+	return true
+}
+
+// addThreadReply adds a reply key to a PR, dropping the oldest past maxThreadReplies.
+func addThreadReply(pr *PRState, key string) {
+	pr.ThreadReplies = append(pr.ThreadReplies, key)
+	if excess := len(pr.ThreadReplies) - maxThreadReplies; excess > 0 {
+		pr.ThreadReplies = slices.Delete(pr.ThreadReplies, 0, excess)
+	}
+}
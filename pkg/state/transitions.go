@@ -0,0 +1,48 @@
+package state
+
+import (
+	"slices"
+	"time"
+)
+
+// maxStateChanges bounds the state history kept per PR.
+const maxStateChanges = 50
+
+// StateChange records when a PR entered a state and who it was then blocked on.
+type StateChange struct {
+	At        time.Time `json:"at"`
+	State     string    `json:"state"`
+	BlockedOn []string  `json:"blocked_on,omitempty"`
+}
+
+// RecordStateChange appends the PR's current state to its history if it differs
+// from the last state recorded, returning the state it left and how long it was
+// in that state. A PR's first recorded state has no predecessor.
+func (p *PRState) RecordStateChange(at time.Time) (left string, spent time.Duration, changed bool) {
+	if p.State == "" {
+		return "", 0, false
+	}
+	if n := len(p.StateChanges); n > 0 {
+		last := p.StateChanges[n-1]
+		if last.State == p.State {
+			return "", 0, false
+		}
+		left, spent = last.State, at.Sub(last.At)
+	}
+
+	p.StateChanges = append(p.StateChanges, StateChange{At: at, State: p.State, BlockedOn: slices.Clone(p.BlockedOn)})
+	if len(p.StateChanges) > maxStateChanges {
+		p.StateChanges = p.StateChanges[len(p.StateChanges)-maxStateChanges:]
+	}
+	return left, spent, true
+}
+
+// TimeInState returns how long the PR has been in its current state, or zero if
+// the state's start was not recorded.
+func (p *PRState) TimeInState(now time.Time) time.Duration {
+	n := len(p.StateChanges)
+	if n == 0 || p.StateChanges[n-1].State != p.State {
+		return 0
+	}
+	return now.Sub(p.StateChanges[n-1].At)
+}
@@ -0,0 +1,266 @@
+package state
+
+import (
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+)
+
+// UserIDs returns the Slack user IDs referenced anywhere in a workspace's state.
+func (m *Manager) UserIDs(workspaceID string) []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	seen := make(map[string]bool)
+	for id := range workspace.Users {
+		seen[id] = true
+	}
+	for id := range workspace.UserPRs {
+		seen[id] = true
+	}
+	for i := range workspace.Outbox {
+		if id := workspace.Outbox[i].UserID; id != "" {
+			seen[id] = true
+		}
+	}
+	for i := range workspace.Reminders {
+		seen[workspace.Reminders[i].UserID] = true
+	}
+
+	ids := make([]string, 0, len(seen))
+	for id := range seen {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// DashboardUsers returns the users, and GitHub logins, whose dashboards list a PR.
+func (m *Manager) DashboardUsers(workspaceID, owner, repo string, number int) []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return nil
+	}
+	key := PRKey(owner, repo, number)
+	var users []string
+	for id, keys := range workspace.UserPRs {
+		if slices.Contains(keys, key) {
+			users = append(users, id)
+		}
+	}
+	slices.Sort(users)
+	return users
+}
+
+// RemapUser moves everything stored under oldID to newID, as needed when Slack
+// reassigns user IDs during an Enterprise Grid migration. Preferences already stored
+// under newID take precedence. It reports whether anything was moved.
+func (m *Manager) RemapUser(workspaceID, oldID, newID string) bool {
+	if oldID == "" || newID == "" || oldID == newID {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	moved := false
+
+	if prefs, exists := workspace.Users[oldID]; exists {
+		if _, taken := workspace.Users[newID]; !taken {
+			workspace.Users[newID] = prefs
+		}
+		delete(workspace.Users, oldID)
+		moved = true
+	}
+
+	if keys, exists := workspace.UserPRs[oldID]; exists {
+		for _, key := range keys {
+			if !slices.Contains(workspace.UserPRs[newID], key) {
+				workspace.UserPRs[newID] = append(workspace.UserPRs[newID], key)
+			}
+		}
+		delete(workspace.UserPRs, oldID)
+		moved = true
+	}
+
+	for _, pr := range workspace.PRs {
+		for i, id := range pr.BlockedOn {
+			if id == oldID {
+				pr.BlockedOn[i] = newID
+				moved = true
+			}
+		}
+		if pr.Unsubscribe(oldID) {
+			pr.Subscribe(newID)
+			moved = true
+		}
+		if slices.Contains(pr.DeferredBy, oldID) {
+			pr.DeferredBy = slices.DeleteFunc(slices.Clone(pr.DeferredBy), func(id string) bool { return id == oldID })
+			if !slices.Contains(pr.DeferredBy, newID) {
+				pr.DeferredBy = append(pr.DeferredBy, newID)
+			}
+			moved = true
+		}
+	}
+
+	for i := range workspace.Outbox {
+		if workspace.Outbox[i].UserID == oldID {
+			workspace.Outbox[i].UserID = newID
+			moved = true
+		}
+	}
+
+	for i := range workspace.Reminders {
+		if workspace.Reminders[i].UserID == oldID {
+			workspace.Reminders[i].UserID = newID
+			moved = true
+		}
+	}
+
+	for login, id := range workspace.Identities {
+		if id.SlackID == oldID {
+			id.SlackID = newID
+			workspace.Identities[login] = id
+			moved = true
+		}
+	}
+
+	for key, d := range workspace.Deliveries {
+		if deliveryUser(key) == oldID {
+			workspace.Deliveries[newID+strings.TrimPrefix(key, oldID)] = d
+			delete(workspace.Deliveries, key)
+			moved = true
+		}
+	}
+
+	if !moved {
+		return false
+	}
+	workspace.LastUpdated = time.Now()
+	slog.Info("remapped Slack user", "workspace", workspaceID, "old_user", oldID, "new_user", newID)
+
+	m.queueSave(workspaceID)
+	return true
+}
+
+// ForgetUser deletes everything stored about a Slack user in a workspace: their
+// preferences, PR associations, pending notifications, and reminders. The user is
+// also dropped from the PRs blocked on them. It reports whether anything was removed.
+func (m *Manager) ForgetUser(workspaceID, userID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	_, hadPrefs := workspace.Users[userID]
+	_, hadPRs := workspace.UserPRs[userID]
+	delete(workspace.Users, userID)
+	delete(workspace.UserPRs, userID)
+	removed := hadPrefs || hadPRs
+
+	for _, pr := range workspace.PRs {
+		if slices.Contains(pr.BlockedOn, userID) {
+			pr.BlockedOn = slices.DeleteFunc(slices.Clone(pr.BlockedOn), func(id string) bool { return id == userID })
+			removed = true
+		}
+		if pr.Unsubscribe(userID) {
+			removed = true
+		}
+		if pr.Undefer(userID) {
+			removed = true
+		}
+	}
+
+	outbox := len(workspace.Outbox)
+	workspace.Outbox = slices.DeleteFunc(workspace.Outbox, func(item OutboxItem) bool { return item.UserID == userID })
+	reminders := len(workspace.Reminders)
+	workspace.Reminders = slices.DeleteFunc(workspace.Reminders, func(r Reminder) bool { return r.UserID == userID })
+	removed = removed || len(workspace.Outbox) != outbox || len(workspace.Reminders) != reminders
+
+	for key := range workspace.Deliveries {
+		if deliveryUser(key) == userID {
+			delete(workspace.Deliveries, key)
+			removed = true
+		}
+	}
+
+	for login, id := range workspace.Identities {
+		if id.SlackID == userID {
+			delete(workspace.Identities, login)
+			removed = true
+		}
+	}
+
+	if !removed {
+		return false
+	}
+	workspace.LastUpdated = time.Now()
+	slog.Info("forgot Slack user", "workspace", workspaceID, "user", userID)
+
+	m.queueSave(workspaceID)
+	return true
+}
+
+// UpdateUserTimezone records a user's Slack timezone, if the user has stored preferences.
+func (m *Manager) UpdateUserTimezone(workspaceID, userID, timezone string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	prefs, exists := workspace.Users[userID]
+	if !exists || timezone == "" || prefs.Timezone == timezone {
+		return false
+	}
+	prefs.Timezone = timezone
+	workspace.Users[userID] = prefs
+	workspace.LastUpdated = time.Now()
+
+	m.queueSave(workspaceID)
+	return true
+}
+
+// SetDomain records the Slack subdomain of a workspace.
+func (m *Manager) SetDomain(workspaceID, domain string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	workspace := m.ensureWorkspace(workspaceID)
+	if workspace.Domain == domain {
+		return
+	}
+	workspace.Domain = domain
+	workspace.LastUpdated = time.Now()
+
+	m.queueSave(workspaceID)
+}
+
+// FindUserByGitHubLogin returns the Slack user who linked a GitHub login, or else
+// the one it was last resolved to.
+func (m *Manager) FindUserByGitHubLogin(workspaceID, login string) (string, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return slackUserLocked(m.ensureWorkspace(workspaceID), login)
+}
+
+// LinkedUser returns the Slack user who linked a GitHub login with their preferences.
+func (m *Manager) LinkedUser(workspaceID, login string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	workspace, exists := m.residentRLocked(workspaceID)
+	if !exists {
+		return "", false
+	}
+	for id, prefs := range workspace.Users {
+		if prefs.GitHubLogin != "" && strings.EqualFold(prefs.GitHubLogin, login) {
+			return id, true
+		}
+	}
+	return "", false
+}
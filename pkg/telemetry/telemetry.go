@@ -0,0 +1,267 @@
+// Package telemetry reports anonymized, aggregate usage to maintainers, for deployments that opt in.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/retry"
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+)
+
+// DefaultInterval is how often usage is reported when no interval is configured.
+const DefaultInterval = 24 * time.Hour
+
+// idFile holds the instance's random telemetry ID in the data directory.
+const idFile = "telemetry-id"
+
+// Report is the document posted to the telemetry endpoint. It holds only counts and
+// the names of features in use: no org, repo, channel, or user names, and no PR or
+// message content.
+type Report struct {
+	Time     time.Time `json:"time"`
+	Instance string    `json:"instance"` // Random, kept in the data directory so reports can be grouped.
+	// PeriodSeconds is how long the counts below cover: the time since the last report.
+	PeriodSeconds int64 `json:"period_seconds"`
+	// Events counts the GitHub events processed, by event type such as "pull_request".
+	Events map[string]int64 `json:"events"`
+	// EventErrors counts the events whose processing failed.
+	EventErrors int64 `json:"event_errors"`
+	// ErrorRate is EventErrors over all the events processed, or zero with none.
+	ErrorRate float64 `json:"error_rate"`
+	// Slack counts uses of the bot in Slack: "commands", "actions", "home_opens", and "mentions".
+	Slack map[string]int64 `json:"slack"`
+	// ActiveUsers is how many users used the bot in Slack in the last week.
+	ActiveUsers int64 `json:"active_users"`
+	// Server lists the server options enabled, such as "routing" or "event_sink".
+	Server []string `json:"server"`
+	// Features counts the orgs using each optional slack.yaml setting.
+	Features map[string]int `json:"features"`
+	Orgs     int            `json:"orgs"`
+}
+
+// Reporter periodically posts a Report.
+type Reporter struct {
+	client   *http.Client
+	configs  *config.Manager
+	last     map[string]float64 // Counter totals as of the last report.
+	lastAt   time.Time
+	url      string
+	instance string
+	server   []string
+	interval time.Duration
+}
+
+// New creates a reporter posting to url, identifying the instance by a random ID
+// kept in dataDir. A zero interval uses DefaultInterval.
+func New(url, dataDir string, interval time.Duration, client *http.Client) (*Reporter, error) {
+	instance, err := instanceID(dataDir)
+	if err != nil {
+		return nil, err
+	}
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Reporter{
+		client:   client,
+		last:     make(map[string]float64),
+		lastAt:   time.Now(),
+		url:      url,
+		instance: instance,
+		interval: interval,
+	}, nil
+}
+
+// SetServerFeatures sets the names of the server options enabled, reported as they are.
+func (r *Reporter) SetServerFeatures(names []string) {
+	r.server = names
+}
+
+// SetConfig sets the org configs whose settings are counted.
+func (r *Reporter) SetConfig(c *config.Manager) {
+	r.configs = c
+}
+
+// Run reports every interval until the context is cancelled.
+func (r *Reporter) Run(ctx context.Context) error {
+	slog.Info("reporting anonymized telemetry", "url", r.url, "interval", r.interval)
+	ticker := time.NewTicker(r.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			report := r.collect(time.Now())
+			if err := r.post(ctx, report); err != nil {
+				slog.Warn("failed to report telemetry", "error", err)
+			}
+		}
+	}
+}
+
+// collect builds a report of what happened since the last one.
+func (r *Reporter) collect(now time.Time) Report {
+	report := Report{
+		Time:          now,
+		Instance:      r.instance,
+		PeriodSeconds: int64(now.Sub(r.lastAt).Seconds()),
+		Events:        make(map[string]int64),
+		Slack:         make(map[string]int64),
+		Server:        r.server,
+		Features:      map[string]int{},
+	}
+	r.lastAt = now
+
+	var total int64
+	for event, n := range metrics.Totals("slacker_events_processed_total", "event") {
+		if d := r.delta("events:"+event, n); d > 0 {
+			report.Events[event] = d
+			total += d
+		}
+	}
+	report.EventErrors = r.delta("errors", metrics.Totals("slacker_events_processed_total", "result")["error"])
+	if total > 0 {
+		report.ErrorRate = float64(report.EventErrors) / float64(total)
+	}
+
+	for name, metric := range map[string]string{
+		"commands":   "slacker_slack_commands_total",
+		"actions":    "slacker_slack_actions_total",
+		"home_opens": "slacker_slack_home_opens_total",
+		"mentions":   "slacker_slack_mentions_total",
+	} {
+		report.Slack[name] = r.delta("slack:"+name, sum(metrics.Totals(metric, "")))
+	}
+	report.ActiveUsers = int64(sum(metrics.Totals("slacker_slack_active_users", "")))
+
+	if r.configs != nil {
+		report.Features = r.configs.FeatureCounts()
+		report.Orgs = len(r.configs.Orgs())
+	}
+	return report
+}
+
+// delta returns how much a counter grew since the last report, remembering its total.
+func (r *Reporter) delta(key string, total float64) int64 {
+	d := total - r.last[key]
+	r.last[key] = total
+	return int64(d)
+}
+
+// sum adds up totals.
+func sum(totals map[string]float64) float64 {
+	var n float64
+	for _, v := range totals {
+		n += v
+	}
+	return n
+}
+
+// post sends a report with retry logic.
+func (r *Reporter) post(ctx context.Context, report Report) error {
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry: %w", err)
+	}
+	slog.Debug("reporting telemetry", "report", string(body))
+
+	return retry.Do(
+		func() error {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.url, bytes.NewReader(body))
+			if err != nil {
+				return retry.Unrecoverable(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+
+			resp, err := r.client.Do(req)
+			if err != nil {
+				return err
+			}
+			if err := resp.Body.Close(); err != nil {
+				slog.Debug("failed to close response body", "error", err)
+			}
+			if resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests {
+				return fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode)
+			}
+			if resp.StatusCode >= 300 {
+				return retry.Unrecoverable(fmt.Errorf("telemetry endpoint returned status %d", resp.StatusCode))
+			}
+			return nil
+		},
+		retry.Attempts(3),
+		retry.Delay(time.Second),
+		retry.MaxDelay(30*time.Second),
+		retry.DelayType(retry.BackOffDelay),
+		retry.LastErrorOnly(true),
+		retry.Context(ctx),
+	)
+}
+
+// instanceID returns the instance's telemetry ID, creating a random one on first use.
+func instanceID(dataDir string) (string, error) {
+	path := filepath.Join(dataDir, idFile)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if id := strings.TrimSpace(string(data)); id != "" {
+			return id, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", fmt.Errorf("failed to read telemetry ID: %w", err)
+	}
+
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate telemetry ID: %w", err)
+	}
+	id := hex.EncodeToString(b)
+	if err := os.WriteFile(path, []byte(id+"\n"), state.FileMode); err != nil {
+		return "", fmt.Errorf("failed to save telemetry ID: %w", err)
+	}
+	return id, nil
+}
+
+// ServerFeatures names the server options enabled in cfg, for SetServerFeatures.
+func ServerFeatures(cfg *config.ServerConfig) []string {
+	enabled := map[string]bool{
+		"routing":        cfg.RoutingFile != "",
+		"tenants":        cfg.TenantKey != "",
+		"event_sink":     cfg.EventSinkURL != "",
+		"plugin_webhook": cfg.PluginWebhookURL != "",
+		"turn":           cfg.TurnURL != "",
+		"metrics_push":   cfg.MetricsPushURL != "",
+		"event_handoff":  cfg.EventHandoff,
+		"tls":            cfg.TLSCertFile != "",
+		"mtls":           cfg.TLSClientCAFile != "",
+		"ip_allowlist":   cfg.IPAllowlist,
+		"admin_api":      cfg.AdminToken != "",
+		"dashboard_api":  cfg.APIToken != "",
+		"proxy":          cfg.HTTPProxy != "",
+	}
+	var names []string
+	for _, name := range slices.Sorted(maps.Keys(enabled)) {
+		if enabled[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
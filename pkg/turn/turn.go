@@ -0,0 +1,112 @@
+// Package turn queries the turn server, which analyzes whose turn it is to act on a PR.
+package turn
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/github"
+)
+
+// DefaultURL is the hosted turn server.
+const DefaultURL = "https://turn.ready-to-review.dev"
+
+// kindStates maps the turn server's next action kinds to the bot's PR states.
+// When several users have actions, the first matching kind in kindPriority wins.
+var kindStates = map[string]string{
+	"fix_tests":        "broken_heart",
+	"resolve_comments": "carpentry_saw",
+	"address_feedback": "carpentry_saw",
+	"merge":            "check",
+	"review":           "hourglass",
+	"re_review":        "hourglass",
+	"approve":          "hourglass",
+	"wait":             "test_tube",
+}
+
+// kindPriority orders action kinds the way GetPRState orders its checks.
+var kindPriority = []string{"wait", "fix_tests", "resolve_comments", "address_feedback", "merge", "review", "re_review", "approve"}
+
+// Client calls the turn server's validate endpoint.
+type Client struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+}
+
+// New creates a turn client. token, if set, is sent as a bearer token.
+func New(baseURL, token string, httpClient *http.Client) *Client {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	return &Client{httpClient: httpClient, baseURL: strings.TrimSuffix(baseURL, "/"), token: token}
+}
+
+// checkResponse is the subset of the turn server's response used by the bot.
+type checkResponse struct {
+	Analysis struct {
+		NextAction map[string]struct {
+			Kind string `json:"kind"`
+		} `json:"next_action"`
+	} `json:"analysis"`
+}
+
+// GetPRState asks the turn server for a PR's state and who it is blocked on.
+func (c *Client) GetPRState(ctx context.Context, owner, repo string, number int) (*github.PRStatus, error) {
+	body, err := json.Marshal(map[string]any{
+		"url":        fmt.Sprintf("https://github.com/%s/%s/pull/%d", owner, repo, number),
+		"updated_at": time.Now().UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode turn request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/v1/validate", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("turn request failed: %w", err)
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.Debug("failed to close response body", "error", err)
+		}
+	}()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("turn server returned status %d", resp.StatusCode)
+	}
+
+	var result checkResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode turn response: %w", err)
+	}
+
+	status := &github.PRStatus{}
+	kinds := make(map[string]bool)
+	for login, action := range result.Analysis.NextAction {
+		status.BlockedOn = append(status.BlockedOn, login)
+		kinds[action.Kind] = true
+	}
+	sort.Strings(status.BlockedOn)
+	for _, kind := range kindPriority {
+		if kinds[kind] {
+			status.State = kindStates[kind]
+			break
+		}
+	}
+	return status, nil
+}
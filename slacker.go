@@ -0,0 +1,716 @@
+// Package slacker embeds the Ready-to-Review Slack bot: the sprinkler event
+// coordinator, its schedulers, and its Slack, GitHub, and admin HTTP endpoints.
+//
+// A Server listens on its own address by default. Use WithRouter to mount its
+// routes on an existing router instead.
+package slacker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/admin"
+	"github.com/codeGROOVE-dev/slacker/pkg/allowlist"
+	"github.com/codeGROOVE-dev/slacker/pkg/bot"
+	"github.com/codeGROOVE-dev/slacker/pkg/clock"
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/events"
+	"github.com/codeGROOVE-dev/slacker/pkg/github"
+	"github.com/codeGROOVE-dev/slacker/pkg/hooks"
+	"github.com/codeGROOVE-dev/slacker/pkg/httpclient"
+	"github.com/codeGROOVE-dev/slacker/pkg/identity"
+	"github.com/codeGROOVE-dev/slacker/pkg/metrics"
+	"github.com/codeGROOVE-dev/slacker/pkg/notify"
+	"github.com/codeGROOVE-dev/slacker/pkg/sink"
+	"github.com/codeGROOVE-dev/slacker/pkg/slack"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/codeGROOVE-dev/slacker/pkg/telemetry"
+	"github.com/codeGROOVE-dev/slacker/pkg/turn"
+	"github.com/gorilla/mux"
+	"golang.org/x/sync/errgroup"
+)
+
+// DefaultAddr is the address a Server listens on unless configured otherwise.
+const DefaultAddr = ":9119"
+
+// Server runs the bot and serves its HTTP endpoints.
+type Server struct {
+	cfg          *config.ServerConfig
+	stateManager *state.Manager
+	journal      *state.Journal
+	httpClient   *http.Client
+	githubClient *github.Client
+	routing      *config.Routing
+	tenants      *state.TenantStore
+	router       *mux.Router
+	httpServer   *http.Server
+	coordinator  *bot.Coordinator
+	notifier     *notify.Manager
+	sink         *sink.Sink
+	pusher       *metrics.Pusher
+	telemetry    *telemetry.Reporter
+	bus          *events.Bus
+	hooks        *hooks.Registry
+	clock        clock.Clock
+	allowlists   []*allowlist.Allowlist
+	group        *errgroup.Group
+	cancel       context.CancelFunc
+	addr         string
+	ownsJournal  bool
+	database     io.Closer // State database the server opened, if any.
+	mounted      bool
+	mu           sync.Mutex
+}
+
+// Option customizes a Server.
+type Option func(*Server)
+
+// WithStateManager stores bot state in m instead of files under cfg.DataDir.
+func WithStateManager(m *state.Manager) Option {
+	return func(s *Server) {
+		s.stateManager = m
+	}
+}
+
+// WithJournal uses j to replay events interrupted by a crash instead of a journal under cfg.DataDir.
+// The caller remains responsible for closing it.
+func WithJournal(j *state.Journal) Option {
+	return func(s *Server) {
+		s.journal = j
+	}
+}
+
+// WithHTTPClient makes outbound calls with client instead of one built from cfg.
+func WithHTTPClient(client *http.Client) Option {
+	return func(s *Server) {
+		s.httpClient = client
+	}
+}
+
+// WithGitHubClient uses an already authenticated GitHub client instead of authenticating with cfg.
+func WithGitHubClient(client *github.Client) Option {
+	return func(s *Server) {
+		s.githubClient = client
+	}
+}
+
+// WithRouting routes orgs to Slack workspaces instead of using cfg's routing file or Slack credentials.
+func WithRouting(routing *config.Routing) Option {
+	return func(s *Server) {
+		s.routing = routing
+	}
+}
+
+// WithHooks runs the hooks in r when PRs are opened, change state, or would notify a user.
+func WithHooks(r *hooks.Registry) Option {
+	return func(s *Server) {
+		s.hooks = r
+	}
+}
+
+// WithBus publishes PR state changes, newly blocked users, sent notifications, and
+// reloaded configs on b, for the embedding program to subscribe to.
+func WithBus(b *events.Bus) Option {
+	return func(s *Server) {
+		s.bus = b
+	}
+}
+
+// WithClock schedules notifications, reminders, and digests with clk instead of
+// the system clock, as tests and simulations do.
+func WithClock(clk clock.Clock) Option {
+	return func(s *Server) {
+		s.clock = clk
+	}
+}
+
+// WithRouter registers the server's routes on r, which may be a subrouter, instead
+// of serving them on the server's own listener.
+func WithRouter(r *mux.Router) Option {
+	return func(s *Server) {
+		s.router = r
+		s.mounted = true
+	}
+}
+
+// WithAddr sets the address the server listens on. It has no effect with WithRouter.
+func WithAddr(addr string) Option {
+	return func(s *Server) {
+		s.addr = addr
+	}
+}
+
+// New wires up a server from cfg and opts. It authenticates with GitHub unless a
+// client is provided, but starts nothing until Start is called.
+func New(ctx context.Context, cfg *config.ServerConfig, opts ...Option) (*Server, error) {
+	s := &Server{cfg: cfg, addr: DefaultAddr, clock: clock.Real}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	// Keep other users out of the state and journal files.
+	if s.stateManager == nil || s.journal == nil {
+		if err := state.PrepareDataDir(cfg.DataDir, cfg.AllowSharedDataDir); err != nil {
+			return nil, fmt.Errorf("unsafe data directory (set ALLOW_SHARED_DATA_DIR to override): %w", err)
+		}
+	}
+
+	// Initialize the state manager, saving to files, Postgres, or SQLite.
+	if s.stateManager == nil {
+		switch cfg.DataBackend {
+		case "", config.DataBackendFile:
+			s.stateManager = state.New(cfg.DataDir)
+		case config.DataBackendPostgres:
+			database, err := state.OpenPostgres(ctx, cfg.DatabaseDriver, cfg.DatabaseURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open state database: %w", err)
+			}
+			s.database = database
+			s.stateManager = state.NewWithStore(cfg.DataDir, database)
+		case config.DataBackendSQLite:
+			path := cfg.DatabaseURL
+			if path == "" {
+				path = filepath.Join(cfg.DataDir, "state.db")
+			}
+			database, err := state.OpenSQLite(ctx, cfg.DatabaseDriver, path)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open state database: %w", err)
+			}
+			s.database = database
+			s.stateManager = state.NewWithStore(cfg.DataDir, database)
+		default:
+			return nil, fmt.Errorf("unknown data backend %q", cfg.DataBackend)
+		}
+	}
+	s.stateManager.SetEviction(cfg.StateIdleEviction, int64(cfg.StateMemoryBudgetMB)<<20)
+	s.stateManager.SetLimits(cfg.StateMaxPRs, cfg.StateMaxPRAge)
+	if cfg.StateBackups > 0 {
+		s.stateManager.SetBackups(cfg.StateBackups)
+	}
+
+	// Open the event journal used to recover from crashes mid-processing.
+	if s.journal == nil {
+		journal, err := state.OpenJournal(filepath.Join(cfg.DataDir, "events.journal"))
+		if err != nil {
+			s.closeStorage()
+			return nil, fmt.Errorf("failed to open event journal: %w", err)
+		}
+		s.journal = journal
+		s.ownsJournal = true
+	}
+
+	// Open the encrypted tenant credentials, if any.
+	if err := s.openTenants(); err != nil {
+		s.closeStorage()
+		return nil, fmt.Errorf("failed to open tenant store: %w", err)
+	}
+
+	if err := s.wire(ctx); err != nil {
+		s.closeStorage()
+		return nil, err
+	}
+	return s, nil
+}
+
+// wire builds the clients, coordinator, and routes.
+func (s *Server) wire(ctx context.Context) error {
+	cfg := s.cfg
+
+	// Initialize config manager for repo configs.
+	configManager := config.New(ctx)
+
+	// Build the shared outbound transport.
+	httpOpts := httpclient.DefaultOptions()
+	httpOpts.ProxyURL = cfg.HTTPProxy
+	if cfg.HTTPTimeout > 0 {
+		httpOpts.Timeout = cfg.HTTPTimeout
+	}
+	if cfg.HTTPKeepAlive > 0 {
+		httpOpts.KeepAlive = cfg.HTTPKeepAlive
+	}
+	if cfg.HTTPIdleConnTimeout > 0 {
+		httpOpts.IdleConnTimeout = cfg.HTTPIdleConnTimeout
+	}
+	if s.httpClient == nil {
+		httpClient, err := httpclient.New(httpOpts)
+		if err != nil {
+			return fmt.Errorf("failed to initialize HTTP client: %w", err)
+		}
+		s.httpClient = httpClient
+	}
+	dialer, err := httpclient.NewWebsocketDialer(httpOpts)
+	if err != nil {
+		return fmt.Errorf("failed to initialize websocket dialer: %w", err)
+	}
+
+	// Initialize GitHub client.
+	if s.githubClient == nil {
+		s.githubClient, err = github.New(ctx, cfg.GitHubAppID, cfg.GitHubPrivateKey, cfg.GitHubInstallationID, s.httpClient)
+		if err != nil {
+			return fmt.Errorf("failed to initialize GitHub client: %w", err)
+		}
+	}
+
+	// Route orgs to Slack workspaces, defaulting to the single workspace from the environment.
+	if s.routing == nil {
+		s.routing = config.DefaultRouting(cfg.SlackToken, cfg.SlackSigningSecret)
+		if cfg.SlackToken == "" && s.tenants != nil {
+			// Every workspace comes from the tenant store.
+			s.routing = &config.Routing{}
+		}
+		if cfg.RoutingFile != "" {
+			s.routing, err = config.LoadRouting(cfg.RoutingFile)
+			if err != nil {
+				return fmt.Errorf("failed to load routing configuration: %w", err)
+			}
+		}
+	} else if err := s.routing.Validate(); err != nil {
+		return fmt.Errorf("invalid routing configuration: %w", err)
+	}
+	orgClients, err := s.applyTenants(ctx)
+	if err != nil {
+		return err
+	}
+	if len(s.routing.Workspaces) == 0 {
+		return errors.New("no workspaces configured: set SLACK_BOT_TOKEN or ROUTING_CONFIG until a tenant is added")
+	}
+
+	// Every routed org must have the GitHub App installed, with the permissions the bot needs.
+	for _, org := range s.routing.OrgNames() {
+		client := s.githubClient
+		if c, exists := orgClients[org]; exists {
+			client = c
+		}
+		if err := client.CheckInstallation(ctx, org); err != nil {
+			return fmt.Errorf("routed org %s is not reachable: %w", org, err)
+		}
+	}
+
+	// Initialize a Slack client per workspace.
+	slackClients := make(map[string]*slack.Client, len(s.routing.Workspaces))
+	for name, ws := range s.routing.Workspaces {
+		client := slack.New(ws.BotToken(), ws.Secret(), s.httpClient)
+		client.SetWorkspace(name)
+		if len(ws.Commands) > 0 {
+			client.SetCommands(ws.Commands)
+		} else if len(cfg.SlashCommands) > 0 {
+			client.SetCommands(cfg.SlashCommands)
+		}
+		client.SetAdmins(append(slices.Clone(cfg.SlackAdmins), ws.Admins...))
+		slackClients[name] = client
+	}
+	// Fail fast if a bot token is invalid or lacks scopes its features need.
+	if !cfg.SkipScopeCheck {
+		for _, name := range slices.Sorted(maps.Keys(slackClients)) {
+			if err := slackClients[name].CheckScopes(ctx); err != nil {
+				return fmt.Errorf("slack workspace %s (set SKIP_SCOPE_CHECK to override): %w", name, err)
+			}
+		}
+	}
+	slackClient, exists := slackClients[config.DefaultWorkspace]
+	if !exists {
+		slackClient = slackClients[s.routing.Orgs[s.routing.OrgNames()[0]]]
+	}
+
+	// Initialize notification manager.
+	s.notifier = notify.New(slackClient, s.stateManager)
+	s.notifier.SetClock(s.clock)
+	s.notifier.SetConfig(configManager)
+	for name, client := range slackClients {
+		s.notifier.SetWorkspaceClient(name, client)
+	}
+
+	// Initialize bot coordinator.
+	s.coordinator = bot.New(
+		ctx,
+		slackClient,
+		s.githubClient,
+		s.stateManager,
+		configManager,
+		s.notifier,
+		cfg.SprinklerURL,
+		dialer,
+	)
+	s.coordinator.SetClock(s.clock)
+	s.coordinator.SetJournal(s.journal)
+	if cfg.EventHandoff {
+		s.coordinator.SetHandoff(cfg.DataDir, cfg.EventHandoffWait)
+	}
+	s.coordinator.SetRouting(s.routing, slackClients)
+	for org, client := range orgClients {
+		s.coordinator.SetOrgGitHubClient(org, client)
+		configManager.SetOrgGitHubClient(org, client.GetClient())
+	}
+	for _, client := range slackClients {
+		client.SetUserEventHandler(s.coordinator)
+		client.SetMentionHandler(s.coordinator)
+		client.SetReactionHandler(s.coordinator)
+		client.SetActionHandler(s.coordinator)
+		client.SetConfigLinter(s.coordinator)
+		client.SetPreviewer(s.coordinator)
+		client.SetNotificationTester(s.notifier)
+		client.SetAwayKeeper(s.coordinator)
+		client.SetLeaderboardSource(s.coordinator)
+		client.SetMaintainer(s.coordinator)
+		client.SetHelpSource(s.coordinator)
+		client.SetChannelLister(s.coordinator)
+		client.SetSettingsStore(s.stateManager)
+	}
+	// The GitHub logins PRs wait on are resolved to Slack users to notify.
+	identities := identity.New(s.stateManager, configManager)
+	s.coordinator.SetIdentities(identities)
+	s.notifier.SetIdentities(identities)
+	s.coordinator.SetConcurrency(cfg.EventWorkers, cfg.EventQueueSize, cfg.EventWorkersPerOrg)
+	s.coordinator.SetEventFilter(cfg.EventTypes)
+
+	// Modules publish what happens to PRs, users, and configs on the bus.
+	if s.bus == nil {
+		s.bus = events.New()
+	}
+	s.coordinator.SetBus(s.bus)
+	s.notifier.SetBus(s.bus)
+	configManager.SetBus(s.bus)
+	// Users a PR newly waits on are told once, by DM or in the PR's thread.
+	s.notifier.Subscribe(s.bus)
+
+	// Optionally forward derived events to an external endpoint.
+	if cfg.EventSinkURL != "" {
+		s.sink = sink.New(cfg.EventSinkURL, cfg.EventSinkSecret, s.httpClient)
+		s.sink.Subscribe(s.bus)
+	}
+	// Optionally push metrics, for instances too short-lived to be scraped reliably.
+	if cfg.MetricsPushURL != "" {
+		pusher, err := metrics.NewPusher(cfg.MetricsPushURL, cfg.MetricsPushInterval, s.httpClient)
+		if err != nil {
+			return err
+		}
+		s.pusher = pusher
+	}
+	// Report anonymized usage only if the operator opts in.
+	if cfg.TelemetryURL != "" {
+		reporter, err := telemetry.New(cfg.TelemetryURL, cfg.DataDir, cfg.TelemetryInterval, s.httpClient)
+		if err != nil {
+			return err
+		}
+		reporter.SetServerFeatures(telemetry.ServerFeatures(cfg))
+		reporter.SetConfig(configManager)
+		s.telemetry = reporter
+	}
+	// Hooks from the embedding program run before an external plugin's.
+	if cfg.PluginWebhookURL != "" {
+		if s.hooks == nil {
+			s.hooks = hooks.New()
+		}
+		s.hooks.RegisterWebhook(cfg.PluginWebhookURL, s.httpClient)
+	}
+	s.coordinator.SetHooks(s.hooks)
+	s.notifier.SetHooks(s.hooks)
+	// Dark-launch the turn server against the local state heuristic.
+	if cfg.TurnURL != "" {
+		s.coordinator.SetTurnClient(turn.New(cfg.TurnURL, cfg.TurnToken, s.httpClient))
+	}
+	s.coordinator.SetOrgAPIRate(float64(cfg.OrgAPIRate), 2*cfg.OrgAPIRate)
+
+	tlsConfig, err := loadTLSConfig(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS configuration: %w", err)
+	}
+
+	s.routes(slackClients)
+
+	if !s.mounted {
+		s.httpServer = &http.Server{
+			Addr:         s.addr,
+			Handler:      s.router,
+			ReadTimeout:  15 * time.Second,
+			WriteTimeout: 15 * time.Second,
+			IdleTimeout:  60 * time.Second,
+			TLSConfig:    tlsConfig,
+		}
+	} else if tlsConfig != nil {
+		slog.Warn("TLS settings are ignored when routes are mounted on an existing router")
+	}
+	return nil
+}
+
+// routes registers the server's HTTP endpoints.
+func (s *Server) routes(slackClients map[string]*slack.Client) {
+	cfg := s.cfg
+	if s.router == nil {
+		s.router = mux.NewRouter()
+	}
+	router := s.router
+	router.HandleFunc("/health", healthHandler).Methods("GET")
+	router.HandleFunc("/readyz", readyHandler).Methods("GET")
+	router.Handle("/metrics", metrics.Handler()).Methods("GET")
+	router.HandleFunc("/schema/slack.json", schemaHandler).Methods("GET")
+
+	// The default workspace is served at /slack; other workspaces each have their
+	// own Slack app, served at /slack/<workspace>.
+	slackRouter := router.PathPrefix("/slack").Subrouter()
+	for name, client := range slackClients {
+		prefix := "/" + name
+		if name == config.DefaultWorkspace {
+			prefix = ""
+		}
+		slackRouter.HandleFunc(prefix+"/events", client.EventsHandler).Methods("POST")
+		slackRouter.HandleFunc(prefix+"/interactions", client.InteractionsHandler).Methods("POST")
+		slackRouter.HandleFunc(prefix+"/slash", client.SlashCommandHandler).Methods("POST")
+	}
+
+	githubRouter := router.PathPrefix("/github").Subrouter()
+	githubRouter.HandleFunc("/webhook", s.githubClient.WebhookHandler).Methods("POST")
+
+	// Optionally restrict inbound endpoints to Slack's and GitHub's IP ranges.
+	if cfg.IPAllowlist {
+		githubAllow := allowlist.New("github", allowlist.GitHubHooks(s.httpClient), cfg.TrustProxyHeaders)
+		githubRouter.Use(githubAllow.Middleware)
+		s.allowlists = append(s.allowlists, githubAllow)
+
+		if len(cfg.SlackIPRanges) > 0 {
+			slackAllow := allowlist.New("slack", allowlist.Static(cfg.SlackIPRanges), cfg.TrustProxyHeaders)
+			slackRouter.Use(slackAllow.Middleware)
+			s.allowlists = append(s.allowlists, slackAllow)
+		} else {
+			slog.Warn("IP allowlist enabled without SLACK_IP_RANGES, Slack endpoints are not restricted")
+		}
+	}
+
+	// Admin endpoints require the admin token, and a client certificate when mTLS is configured.
+	adminRouter := admin.NewRouter(router, admin.Options{
+		Token:             cfg.AdminToken,
+		RequireClientCert: cfg.TLSClientCAFile != "",
+	})
+	adminRouter.HandleFunc("/outbox", s.notifier.OutboxHandler).Methods("GET")
+	s.coordinator.PauseRoutes(adminRouter)
+	s.coordinator.SelfTestRoutes(adminRouter)
+	s.coordinator.HandoffRoutes(adminRouter)
+	if s.tenants != nil {
+		s.tenantRoutes(adminRouter)
+	}
+
+	// API endpoints feed the web dashboard, and require the API token.
+	apiRouter := admin.Mount(router, "/api", admin.Options{Token: cfg.APIToken})
+	apiRouter.HandleFunc("/users/{slackID}/prs", s.coordinator.UserPRsHandler).Methods("GET")
+}
+
+// Handler returns the server's routes, for serving them on a listener of the caller's choosing.
+func (s *Server) Handler() http.Handler {
+	return s.router
+}
+
+// SelfTest checks the server's Slack, GitHub, sprinkler, and storage access without
+// starting it, closing the event journal and state database when done; see bot.Coordinator.SelfTest.
+func (s *Server) SelfTest(ctx context.Context, org string) []bot.SelfTestResult {
+	defer s.closeStorage()
+	return s.coordinator.SelfTest(ctx, org)
+}
+
+// Start launches the bot, its schedulers, and, unless mounted on another router,
+// the HTTP listener. It returns once they are running; use Wait or Stop to end them.
+func (s *Server) Start(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.group != nil {
+		return errors.New("server already started")
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+	eg, ctx := errgroup.WithContext(ctx)
+	s.group = eg
+
+	// HTTP server.
+	if s.httpServer != nil {
+		server := s.httpServer
+		eg.Go(func() error {
+			tlsEnabled := server.TLSConfig != nil
+			slog.Info("starting server", "addr", server.Addr, "tls", tlsEnabled)
+			var err error
+			if tlsEnabled {
+				// Certificates are already loaded into TLSConfig.
+				err = server.ListenAndServeTLS("", "")
+			} else {
+				err = server.ListenAndServe()
+			}
+			if err != nil && err != http.ErrServerClosed {
+				return err
+			}
+			return nil
+		})
+
+		eg.Go(func() error {
+			<-ctx.Done()
+			shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer shutdownCancel()
+			if err := server.Shutdown(shutdownCtx); err != nil {
+				return fmt.Errorf("server shutdown failed: %w", err)
+			}
+			return nil
+		})
+	}
+
+	// Refresh IP allowlists.
+	for _, a := range s.allowlists {
+		eg.Go(func() error {
+			return a.Run(ctx, time.Hour)
+		})
+	}
+
+	// Start bot coordinator.
+	eg.Go(func() error {
+		return s.coordinator.Run(ctx)
+	})
+
+	// Start channel digest scheduler.
+	eg.Go(func() error {
+		return s.coordinator.RunDigests(ctx)
+	})
+
+	// Start milestone summary refresher.
+	eg.Go(func() error {
+		return s.coordinator.RunMilestoneSummaries(ctx)
+	})
+
+	// Start org member sync, which approve and assign check before acting.
+	eg.Go(func() error {
+		return s.coordinator.RunMemberSync(ctx)
+	})
+
+	// Start channel topic updater.
+	eg.Go(func() error {
+		return s.coordinator.RunTopicCounts(ctx)
+	})
+
+	// Start reconciliation of PRs whose state is unknown or uncertain.
+	eg.Go(func() error {
+		return s.coordinator.RunReconciliation(ctx)
+	})
+
+	// Start event sink.
+	if s.sink != nil {
+		eg.Go(func() error {
+			return s.sink.Run(ctx)
+		})
+	}
+
+	// Start metrics pusher.
+	if s.pusher != nil {
+		eg.Go(func() error {
+			return s.pusher.Run(ctx)
+		})
+	}
+
+	// Start telemetry reporter.
+	if s.telemetry != nil {
+		eg.Go(func() error {
+			return s.telemetry.Run(ctx)
+		})
+	}
+
+	// Start notification scheduler.
+	eg.Go(func() error {
+		return s.notifier.Run(ctx)
+	})
+
+	return nil
+}
+
+// Wait blocks until the server stops, returning the first error that stopped it.
+// A stop caused by cancelling the context passed to Start or by Stop is not an error.
+func (s *Server) Wait() error {
+	s.mu.Lock()
+	eg := s.group
+	s.mu.Unlock()
+	if eg == nil {
+		return errors.New("server not started")
+	}
+
+	err := eg.Wait()
+	s.closeStorage()
+	if errors.Is(err, context.Canceled) {
+		return nil
+	}
+	return err
+}
+
+// Stop shuts the server down and waits for it to finish, or for ctx to expire.
+func (s *Server) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.mu.Unlock()
+	if cancel == nil {
+		s.closeStorage()
+		return nil
+	}
+	cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- s.Wait()
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeStorage closes the event journal and the state database if the server opened them.
+func (s *Server) closeStorage() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.database != nil {
+		if err := s.database.Close(); err != nil {
+			slog.Error("failed to close state database", "error", err)
+		}
+		s.database = nil
+	}
+	if !s.ownsJournal {
+		return
+	}
+	s.ownsJournal = false
+	if err := s.journal.Close(); err != nil {
+		slog.Error("failed to close event journal", "error", err)
+	}
+}
+
+// schemaHandler publishes the JSON Schema for slack.yaml.
+func schemaHandler(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write(config.Schema); err != nil {
+		slog.Error("failed to write schema response", "error", err)
+	}
+}
+
+func healthHandler(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte("OK")); err != nil {
+		slog.Error("failed to write health response", "error", err)
+	}
+}
+
+// readyHandler reports the circuit breaker state of each upstream, failing while any is open.
+func readyHandler(w http.ResponseWriter, _ *http.Request) {
+	breakers := httpclient.BreakerStates()
+	status := http.StatusOK
+	for _, state := range breakers {
+		if state == httpclient.BreakerOpen {
+			status = http.StatusServiceUnavailable
+		}
+	}
+	admin.WriteJSON(w, status, map[string]any{
+		"ready":    status == http.StatusOK,
+		"breakers": breakers,
+	})
+}
@@ -0,0 +1,197 @@
+package slacker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"path/filepath"
+	"time"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/admin"
+	"github.com/codeGROOVE-dev/slacker/pkg/config"
+	"github.com/codeGROOVE-dev/slacker/pkg/github"
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/gorilla/mux"
+)
+
+// maxTenantBody bounds the size of a tenant record sent to the admin API.
+const maxTenantBody = 64 << 10
+
+// WithTenants serves the workspaces and orgs in store, managed through /admin/tenants,
+// instead of a store under cfg.DataDir encrypted with cfg.TenantKey.
+func WithTenants(store *state.TenantStore) Option {
+	return func(s *Server) {
+		s.tenants = store
+	}
+}
+
+// openTenants opens the tenant store under the data directory when a tenant key is configured.
+func (s *Server) openTenants() error {
+	if s.tenants != nil || s.cfg.TenantKey == "" {
+		return nil
+	}
+	key, err := state.ParseTenantKey(s.cfg.TenantKey)
+	if err != nil {
+		return err
+	}
+	s.tenants, err = state.OpenTenants(filepath.Join(s.cfg.DataDir, "tenants.enc"), key)
+	return err
+}
+
+// applyTenants adds each tenant's workspace and orgs to the routing, and creates
+// a GitHub client for every tenant with its own GitHub App, returned by org.
+func (s *Server) applyTenants(ctx context.Context) (map[string]*github.Client, error) {
+	clients := make(map[string]*github.Client)
+	if s.tenants == nil {
+		return clients, nil
+	}
+
+	tenants := s.tenants.List()
+	if len(tenants) == 0 {
+		return clients, nil
+	}
+
+	// Copy the routing rather than change one the embedding program passed in.
+	routing := &config.Routing{
+		Workspaces: maps.Clone(s.routing.Workspaces),
+		Orgs:       maps.Clone(s.routing.Orgs),
+		// A default workspace that served every org keeps serving the orgs no tenant claims.
+		DefaultForUnlisted: s.routing.DefaultForUnlisted || len(s.routing.Orgs) == 0,
+	}
+	if routing.Workspaces == nil {
+		routing.Workspaces = make(map[string]config.WorkspaceConfig)
+	}
+	if routing.Orgs == nil {
+		routing.Orgs = make(map[string]string)
+	}
+	for _, t := range tenants {
+		if _, exists := routing.Workspaces[t.Workspace]; exists {
+			return nil, fmt.Errorf("tenant workspace %q is also configured outside the tenant store", t.Workspace)
+		}
+		routing.Workspaces[t.Workspace] = config.WorkspaceConfig{
+			Token:         t.BotToken,
+			SigningSecret: t.SigningSecret,
+			Commands:      t.Commands,
+			Admins:        t.Admins,
+		}
+
+		var client *github.Client
+		if t.HasGitHubApp() {
+			var err error
+			client, err = github.New(ctx, t.GitHubAppID, t.GitHubPrivateKey, t.GitHubInstallationID, s.httpClient)
+			if err != nil {
+				return nil, fmt.Errorf("tenant %q: failed to initialize GitHub client: %w", t.Workspace, err)
+			}
+		}
+		for _, org := range t.Orgs {
+			if other, exists := routing.Orgs[org]; exists {
+				return nil, fmt.Errorf("tenant %q: org %q is already routed to workspace %q", t.Workspace, org, other)
+			}
+			routing.Orgs[org] = t.Workspace
+			if client != nil {
+				clients[org] = client
+			}
+		}
+	}
+	if err := routing.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid routing with tenants: %w", err)
+	}
+	s.routing = routing
+	slog.Info("loaded tenants", "tenants", len(tenants), "orgs_with_own_github_app", len(clients))
+	return clients, nil
+}
+
+// tenantView is a tenant as the admin API shows it, without its secrets.
+type tenantView struct {
+	UpdatedAt time.Time `json:"updated_at"`
+	Workspace string    `json:"workspace"`
+	Orgs      []string  `json:"orgs"`
+	Commands  []string  `json:"commands,omitempty"`
+	Admins    []string  `json:"admins,omitempty"`
+	GitHubApp string    `json:"github_app_id,omitempty"`
+}
+
+// viewTenant hides a tenant's secrets.
+func viewTenant(t state.Tenant) tenantView {
+	return tenantView{
+		UpdatedAt: t.UpdatedAt,
+		Workspace: t.Workspace,
+		Orgs:      t.Orgs,
+		Commands:  t.Commands,
+		Admins:    t.Admins,
+		GitHubApp: t.GitHubAppID,
+	}
+}
+
+// tenantRoutes registers the tenant admin endpoints. Changes apply when the server restarts.
+func (s *Server) tenantRoutes(router *mux.Router) {
+	router.HandleFunc("/tenants", s.listTenants).Methods("GET")
+	router.HandleFunc("/tenants/{workspace}", s.getTenant).Methods("GET")
+	router.HandleFunc("/tenants/{workspace}", s.putTenant).Methods("PUT")
+	router.HandleFunc("/tenants/{workspace}", s.deleteTenant).Methods("DELETE")
+}
+
+// listTenants lists every tenant without secrets.
+func (s *Server) listTenants(w http.ResponseWriter, _ *http.Request) {
+	tenants := s.tenants.List()
+	views := make([]tenantView, 0, len(tenants))
+	for _, t := range tenants {
+		views = append(views, viewTenant(t))
+	}
+	admin.WriteJSON(w, http.StatusOK, map[string]any{"tenants": views})
+}
+
+// getTenant shows one tenant without secrets.
+func (s *Server) getTenant(w http.ResponseWriter, r *http.Request) {
+	t, exists := s.tenants.Get(mux.Vars(r)["workspace"])
+	if !exists {
+		admin.WriteError(w, http.StatusNotFound, "no such tenant")
+		return
+	}
+	admin.WriteJSON(w, http.StatusOK, viewTenant(t))
+}
+
+// putTenant creates or replaces a tenant from a JSON record.
+func (s *Server) putTenant(w http.ResponseWriter, r *http.Request) {
+	var t state.Tenant
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxTenantBody))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&t); err != nil {
+		admin.WriteError(w, http.StatusBadRequest, "invalid tenant: "+err.Error())
+		return
+	}
+	t.Workspace = mux.Vars(r)["workspace"]
+	for _, command := range t.Commands {
+		if err := config.ValidateCommand(command); err != nil {
+			admin.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+	if err := s.tenants.Put(t); err != nil {
+		admin.WriteError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	slog.Info("stored tenant", "workspace", t.Workspace, "orgs", t.Orgs, "own_github_app", t.HasGitHubApp())
+	stored, _ := s.tenants.Get(t.Workspace)
+	admin.WriteJSON(w, http.StatusOK, map[string]any{"tenant": viewTenant(stored), "restart_required": true})
+}
+
+// deleteTenant removes a tenant and its credentials.
+func (s *Server) deleteTenant(w http.ResponseWriter, r *http.Request) {
+	workspace := mux.Vars(r)["workspace"]
+	deleted, err := s.tenants.Delete(workspace)
+	if err != nil {
+		admin.WriteError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if !deleted {
+		admin.WriteError(w, http.StatusNotFound, "no such tenant")
+		return
+	}
+	slog.Info("deleted tenant", "workspace", workspace)
+	admin.WriteJSON(w, http.StatusOK, map[string]any{"deleted": workspace, "restart_required": true})
+}
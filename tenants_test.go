@@ -0,0 +1,51 @@
+package slacker
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/codeGROOVE-dev/slacker/pkg/state"
+	"github.com/gorilla/mux"
+)
+
+func TestPutTenantWorkspaceName(t *testing.T) {
+	key := make([]byte, state.TenantKeySize)
+	store, err := state.OpenTenants(filepath.Join(t.TempDir(), "tenants.enc"), key)
+	if err != nil {
+		t.Fatalf("OpenTenants: %v", err)
+	}
+	s := &Server{tenants: store}
+
+	tests := []struct {
+		workspace string
+		want      int
+	}{
+		{workspace: "globex", want: http.StatusOK},
+		{workspace: "Globex_EU-2", want: http.StatusOK},
+		{workspace: "", want: http.StatusBadRequest},
+		{workspace: "..", want: http.StatusBadRequest},
+		{workspace: "../../etc/passwd", want: http.StatusBadRequest},
+		{workspace: "acme/globex", want: http.StatusBadRequest},
+		{workspace: "globex.json", want: http.StatusBadRequest},
+		{workspace: "-globex", want: http.StatusBadRequest},
+		{workspace: "glo bex", want: http.StatusBadRequest},
+		{workspace: strings.Repeat("g", 65), want: http.StatusBadRequest},
+	}
+	for i, tt := range tests {
+		body := `{"bot_token": "xoxb-1", "signing_secret": "s", "orgs": ["org` + strconv.Itoa(i) + `"]}`
+		r := mux.SetURLVars(httptest.NewRequest(http.MethodPut, "/admin/tenants/x", strings.NewReader(body)),
+			map[string]string{"workspace": tt.workspace})
+		w := httptest.NewRecorder()
+		s.putTenant(w, r)
+		if w.Code != tt.want {
+			t.Errorf("PUT tenant %q = %d %s, want %d", tt.workspace, w.Code, w.Body, tt.want)
+		}
+		if _, stored := store.Get(tt.workspace); stored != (tt.want == http.StatusOK) {
+			t.Errorf("tenant %q stored = %v, want %v", tt.workspace, stored, tt.want == http.StatusOK)
+		}
+	}
+}